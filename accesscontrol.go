@@ -0,0 +1,80 @@
+package onvif
+
+import "strconv"
+
+const accessControlNamespace = "http://www.onvif.org/ver10/accesscontrol/wsdl"
+
+var accessControlXMLNs = []string{
+	`xmlns:tac="http://www.onvif.org/ver10/accesscontrol/wsdl"`,
+	`xmlns:tt="http://www.onvif.org/ver10/schema"`,
+}
+
+// GetAccessPointInfoList fetch access points of access control device. The list
+// might be split into several pages, where limit is the maximum number of access
+// points in a page. Use the returned reference as startReference to fetch the
+// next page; empty reference means there are no more access points.
+func (device Device) GetAccessPointInfoList(limit int, startReference string) ([]AccessPointInfo, string, error) {
+	// Create SOAP
+	soap := SOAP{
+		XMLNs: accessControlXMLNs,
+		Body: `<tac:GetAccessPointInfoList>` +
+			listRequestXML("tac", limit, startReference) +
+			`</tac:GetAccessPointInfoList>`,
+	}
+
+	// Send SOAP request
+	response, err := device.sendRequest(accessControlNamespace, soap)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// Parse response to interface
+	ifaceList, err := response.ValueForPath("Envelope.Body.GetAccessPointInfoListResponse")
+	if err != nil {
+		return nil, "", err
+	}
+
+	// Parse interface to array of access point
+	nextReference := ""
+	accessPoints := []AccessPointInfo{}
+	if mapList, ok := ifaceList.(map[string]interface{}); ok {
+		nextReference = interfaceToString(mapList["NextStartReference"])
+
+		for _, mapInfo := range interfaceToMaps(mapList["AccessPointInfo"]) {
+			info := AccessPointInfo{}
+			info.Token = interfaceToString(mapInfo["-token"])
+			info.Name = interfaceToString(mapInfo["Name"])
+			info.Description = interfaceToString(mapInfo["Description"])
+			info.AreaFrom = interfaceToString(mapInfo["AreaFrom"])
+			info.AreaTo = interfaceToString(mapInfo["AreaTo"])
+			info.EntityType = interfaceToString(mapInfo["EntityType"])
+			info.Entity = interfaceToString(mapInfo["Entity"])
+
+			if mapCapabilities, ok := mapInfo["Capabilities"].(map[string]interface{}); ok {
+				info.Capabilities.DisableAccessPoint = interfaceToBool(mapCapabilities["-DisableAccessPoint"])
+				info.Capabilities.Duress = interfaceToBool(mapCapabilities["-Duress"])
+				info.Capabilities.AnonymousAccess = interfaceToBool(mapCapabilities["-AnonymousAccess"])
+				info.Capabilities.AccessTaken = interfaceToBool(mapCapabilities["-AccessTaken"])
+				info.Capabilities.ExternalAuthorization = interfaceToBool(mapCapabilities["-ExternalAuthorization"])
+			}
+
+			accessPoints = append(accessPoints, info)
+		}
+	}
+
+	return accessPoints, nextReference, nil
+}
+
+// listRequestXML creates optional Limit and StartReference of a paged list request
+func listRequestXML(prefix string, limit int, startReference string) string {
+	result := ""
+	if limit > 0 {
+		result += `<` + prefix + `:Limit>` + strconv.Itoa(limit) + `</` + prefix + `:Limit>`
+	}
+
+	if startReference != "" {
+		result += `<` + prefix + `:StartReference>` + escapeXML(startReference) + `</` + prefix + `:StartReference>`
+	}
+
+	return result
+}
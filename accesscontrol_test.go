@@ -0,0 +1,82 @@
+package onvif
+
+import (
+	"fmt"
+	"log"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/krabiswabbie/go-onvif/onviftest"
+)
+
+func TestGetAccessPointInfoList(t *testing.T) {
+	log.Println("Test GetAccessPointInfoList")
+
+	res, _, err := testDevice.GetAccessPointInfoList(0, "")
+	if err != nil {
+		t.Error(err)
+	}
+
+	js := prettyJSON(&res)
+	fmt.Println(js)
+}
+
+func TestAccessPointInfoList(t *testing.T) {
+	server := onviftest.NewServer()
+	defer server.Close()
+
+	server.HandleBody("GetAccessPointInfoList", `<tac:GetAccessPointInfoListResponse xmlns:tac="http://www.onvif.org/ver10/accesscontrol/wsdl">
+		<tac:NextStartReference>page2</tac:NextStartReference>
+		<tac:AccessPointInfo token="AccessPoint_1">
+			<tac:Name>Main entrance</tac:Name>
+			<tac:Description>Front door reader</tac:Description>
+			<tac:AreaFrom>Area_Outside</tac:AreaFrom>
+			<tac:AreaTo>Area_Lobby</tac:AreaTo>
+			<tac:EntityType>tdc:Door</tac:EntityType>
+			<tac:Entity>Door_1</tac:Entity>
+			<tac:Capabilities DisableAccessPoint="true" Duress="false" AnonymousAccess="true" AccessTaken="false" ExternalAuthorization="true"/>
+		</tac:AccessPointInfo>
+		<tac:AccessPointInfo token="AccessPoint_2">
+			<tac:Name>Exit</tac:Name>
+		</tac:AccessPointInfo>
+	</tac:GetAccessPointInfoListResponse>`)
+
+	device := Device{XAddr: server.XAddr()}
+	accessPoints, nextReference, err := device.GetAccessPointInfoList(2, "page<1>")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	request, _ := server.LastRequest("GetAccessPointInfoList")
+	if !strings.Contains(request.Envelope, `<tac:GetAccessPointInfoList><tac:Limit>2</tac:Limit><tac:StartReference>page&lt;1&gt;</tac:StartReference></tac:GetAccessPointInfoList>`) {
+		t.Errorf("unexpected request %s", request.Envelope)
+	}
+
+	expected := []AccessPointInfo{
+		{
+			Token:        "AccessPoint_1",
+			Name:         "Main entrance",
+			Description:  "Front door reader",
+			AreaFrom:     "Area_Outside",
+			AreaTo:       "Area_Lobby",
+			EntityType:   "tdc:Door",
+			Entity:       "Door_1",
+			Capabilities: AccessPointCapabilities{DisableAccessPoint: true, AnonymousAccess: true, ExternalAuthorization: true},
+		},
+		{Token: "AccessPoint_2", Name: "Exit"},
+	}
+	if nextReference != "page2" || !reflect.DeepEqual(accessPoints, expected) {
+		t.Errorf("expected %+v and page2, got %+v and %q", expected, accessPoints, nextReference)
+	}
+
+	// Limit and start reference are omitted for the first page of default size
+	if _, _, err := device.GetAccessPointInfoList(0, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	request, _ = server.LastRequest("GetAccessPointInfoList")
+	if !strings.Contains(request.Envelope, `<tac:GetAccessPointInfoList></tac:GetAccessPointInfoList>`) {
+		t.Errorf("unexpected request %s", request.Envelope)
+	}
+}
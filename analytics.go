@@ -0,0 +1,241 @@
+package onvif
+
+import (
+	"strings"
+
+	"github.com/deepch/mxj"
+)
+
+const analyticsNamespace = "http://www.onvif.org/ver20/analytics/wsdl"
+
+var analyticsXMLNs = []string{
+	`xmlns:tan="http://www.onvif.org/ver20/analytics/wsdl"`,
+	`xmlns:tt="http://www.onvif.org/ver10/schema"`,
+}
+
+// GetSupportedAnalyticsModules fetch analytics modules that can be
+// created in a video analytics configuration
+func (device Device) GetSupportedAnalyticsModules(configToken string) ([]AnalyticsConfigDescription, error) {
+	// Create SOAP
+	soap := SOAP{
+		XMLNs: analyticsXMLNs,
+		Body: `<tan:GetSupportedAnalyticsModules>
+			<tan:ConfigurationToken>` + escapeXML(configToken) + `</tan:ConfigurationToken>
+		</tan:GetSupportedAnalyticsModules>`,
+	}
+
+	// Send SOAP request
+	response, err := device.sendRequest(analyticsNamespace, soap)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse response to interface
+	ifaceDescriptions, err := response.ValuesForPath("Envelope.Body.GetSupportedAnalyticsModulesResponse.SupportedAnalyticsModules.AnalyticsModuleDescription")
+	if err != nil {
+		return nil, err
+	}
+
+	return parseAnalyticsConfigDescriptions(ifaceDescriptions), nil
+}
+
+// GetAnalyticsModules fetch analytics modules of a video analytics configuration
+func (device Device) GetAnalyticsModules(configToken string) ([]AnalyticsConfig, error) {
+	// Create SOAP
+	soap := SOAP{
+		XMLNs: analyticsXMLNs,
+		Body: `<tan:GetAnalyticsModules>
+			<tan:ConfigurationToken>` + escapeXML(configToken) + `</tan:ConfigurationToken>
+		</tan:GetAnalyticsModules>`,
+	}
+
+	// Send SOAP request
+	response, err := device.sendRequest(analyticsNamespace, soap)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse response to interface
+	ifaceModules, err := response.ValuesForPath("Envelope.Body.GetAnalyticsModulesResponse.AnalyticsModule")
+	if err != nil {
+		return nil, err
+	}
+
+	return parseAnalyticsConfigs(ifaceModules), nil
+}
+
+// CreateAnalyticsModules adds analytics modules to a video analytics configuration
+func (device Device) CreateAnalyticsModules(configToken string, modules []AnalyticsConfig) error {
+	return device.sendAnalyticsConfigs("CreateAnalyticsModules", "AnalyticsModule", configToken, modules)
+}
+
+// ModifyAnalyticsModules changes parameters of existing analytics modules in a video analytics configuration
+func (device Device) ModifyAnalyticsModules(configToken string, modules []AnalyticsConfig) error {
+	return device.sendAnalyticsConfigs("ModifyAnalyticsModules", "AnalyticsModule", configToken, modules)
+}
+
+// GetRules fetch rules of a video analytics configuration
+func (device Device) GetRules(configToken string) ([]AnalyticsConfig, error) {
+	// Create SOAP
+	soap := SOAP{
+		XMLNs: analyticsXMLNs,
+		Body: `<tan:GetRules>
+			<tan:ConfigurationToken>` + escapeXML(configToken) + `</tan:ConfigurationToken>
+		</tan:GetRules>`,
+	}
+
+	// Send SOAP request
+	response, err := device.sendRequest(analyticsNamespace, soap)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse response to interface
+	ifaceRules, err := response.ValuesForPath("Envelope.Body.GetRulesResponse.Rule")
+	if err != nil {
+		return nil, err
+	}
+
+	return parseAnalyticsConfigs(ifaceRules), nil
+}
+
+// CreateRules adds rules to a video analytics configuration
+func (device Device) CreateRules(configToken string, rules []AnalyticsConfig) error {
+	return device.sendAnalyticsConfigs("CreateRules", "Rule", configToken, rules)
+}
+
+// ModifyRules changes parameters of existing rules in a video analytics configuration
+func (device Device) ModifyRules(configToken string, rules []AnalyticsConfig) error {
+	return device.sendAnalyticsConfigs("ModifyRules", "Rule", configToken, rules)
+}
+
+// sendAnalyticsConfigs sends analytics modules or rules to ONVIF device
+// using the specified operation
+func (device Device) sendAnalyticsConfigs(operation, element, configToken string, configs []AnalyticsConfig) error {
+	// Create SOAP
+	body := `<tan:` + operation + `>
+		<tan:ConfigurationToken>` + escapeXML(configToken) + `</tan:ConfigurationToken>`
+	for _, config := range configs {
+		body += analyticsConfigXML("tan:"+element, config)
+	}
+	body += `</tan:` + operation + `>`
+
+	soap := SOAP{
+		XMLNs: analyticsXMLNs,
+		Body:  body,
+	}
+
+	// Send SOAP request
+	_, err := device.sendRequest(analyticsNamespace, soap)
+	return err
+}
+
+// parseAnalyticsConfigs parses list of tt:Config
+func parseAnalyticsConfigs(ifaceConfigs []interface{}) []AnalyticsConfig {
+	configs := []AnalyticsConfig{}
+	for _, ifaceConfig := range ifaceConfigs {
+		if mapConfig, ok := ifaceConfig.(map[string]interface{}); ok {
+			configs = append(configs, parseAnalyticsConfig(mapConfig))
+		}
+	}
+
+	return configs
+}
+
+// parseAnalyticsConfig parses tt:Config
+func parseAnalyticsConfig(mapConfig map[string]interface{}) AnalyticsConfig {
+	return AnalyticsConfig{
+		Name:       interfaceToString(mapConfig["-Name"]),
+		Type:       interfaceToString(mapConfig["-Type"]),
+		Parameters: parseItemList(mapConfig["Parameters"]),
+	}
+}
+
+// parseAnalyticsConfigDescriptions parses list of tt:ConfigDescription
+func parseAnalyticsConfigDescriptions(ifaceDescriptions []interface{}) []AnalyticsConfigDescription {
+	descriptions := []AnalyticsConfigDescription{}
+	for _, ifaceDescription := range ifaceDescriptions {
+		mapDescription, ok := ifaceDescription.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		description := AnalyticsConfigDescription{
+			Name:         interfaceToString(mapDescription["-Name"]),
+			SimpleItems:  []ItemDescription{},
+			ElementItems: []ItemDescription{},
+		}
+
+		if mapParameters, ok := mapDescription["Parameters"].(map[string]interface{}); ok {
+			for _, mapItem := range interfaceToMaps(mapParameters["SimpleItemDescription"]) {
+				description.SimpleItems = append(description.SimpleItems, ItemDescription{
+					Name: interfaceToString(mapItem["-Name"]),
+					Type: interfaceToString(mapItem["-Type"]),
+				})
+			}
+
+			for _, mapItem := range interfaceToMaps(mapParameters["ElementItemDescription"]) {
+				description.ElementItems = append(description.ElementItems, ItemDescription{
+					Name: interfaceToString(mapItem["-Name"]),
+					Type: interfaceToString(mapItem["-Type"]),
+				})
+			}
+		}
+
+		descriptions = append(descriptions, description)
+	}
+
+	return descriptions
+}
+
+// parseItemList parses tt:ItemList
+func parseItemList(src interface{}) ItemList {
+	itemList := ItemList{
+		SimpleItems:  interfaceToSimpleItems(src),
+		ElementItems: []ElementItem{},
+	}
+
+	if mapItems, ok := src.(map[string]interface{}); ok {
+		for _, mapItem := range interfaceToMaps(mapItems["ElementItem"]) {
+			item := ElementItem{Name: interfaceToString(mapItem["-Name"])}
+
+			// Convert the content back to XML
+			for key, value := range mapItem {
+				if strings.HasPrefix(key, "-") || key == "#text" {
+					continue
+				}
+
+				content, err := mxj.Map{key: value}.Xml()
+				if err == nil {
+					item.XML += string(content)
+				}
+			}
+
+			itemList.ElementItems = append(itemList.ElementItems, item)
+		}
+	}
+
+	return itemList
+}
+
+// analyticsConfigXML creates element of type tt:Config
+func analyticsConfigXML(element string, config AnalyticsConfig) string {
+	return `<` + element + ` Name="` + escapeXML(config.Name) + `" Type="` + escapeXML(config.Type) + `">
+		<tt:Parameters>` + itemListXML(config.Parameters) + `</tt:Parameters>
+	</` + element + `>`
+}
+
+// itemListXML creates content of tt:ItemList
+func itemListXML(itemList ItemList) string {
+	result := ""
+	for _, item := range itemList.SimpleItems {
+		result += `<tt:SimpleItem Name="` + escapeXML(item.Name) + `" Value="` + escapeXML(item.Value) + `"/>`
+	}
+
+	for _, item := range itemList.ElementItems {
+		result += `<tt:ElementItem Name="` + escapeXML(item.Name) + `" xmlns="http://www.onvif.org/ver10/schema">` +
+			item.XML + `</tt:ElementItem>`
+	}
+
+	return result
+}
@@ -0,0 +1,44 @@
+package onvif
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/deepch/mxj"
+)
+
+func TestItemList(t *testing.T) {
+	mapXML, err := mxj.NewMapXml([]byte(`<tt:Parameters xmlns:tt="http://www.onvif.org/ver10/schema">
+		<tt:SimpleItem Name="Sensitivity" Value="50"/>
+		<tt:ElementItem Name="Layout">
+			<tt:CellLayout Columns="22" Rows="18"/>
+		</tt:ElementItem>
+	</tt:Parameters>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	itemList := parseItemList(mapXML["Parameters"])
+	if len(itemList.SimpleItems) != 1 || itemList.SimpleItems[0] != (SimpleItem{Name: "Sensitivity", Value: "50"}) {
+		t.Errorf("unexpected simple items: %v", itemList.SimpleItems)
+	}
+
+	if len(itemList.ElementItems) != 1 || itemList.ElementItems[0].Name != "Layout" {
+		t.Fatalf("unexpected element items: %v", itemList.ElementItems)
+	}
+
+	expectedXML := `<CellLayout Columns="22" Rows="18"/>`
+	if itemList.ElementItems[0].XML != expectedXML {
+		t.Errorf("expected element XML %s, got %s", expectedXML, itemList.ElementItems[0].XML)
+	}
+
+	result := itemListXML(itemList)
+	for _, expected := range []string{
+		`<tt:SimpleItem Name="Sensitivity" Value="50"/>`,
+		`<tt:ElementItem Name="Layout" xmlns="http://www.onvif.org/ver10/schema">` + expectedXML + `</tt:ElementItem>`,
+	} {
+		if !strings.Contains(result, expected) {
+			t.Errorf("%s doesn't contain %s", result, expected)
+		}
+	}
+}
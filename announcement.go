@@ -0,0 +1,141 @@
+package onvif
+
+import (
+	"context"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/deepch/mxj"
+)
+
+// Types of WS-Discovery announcement
+const (
+	AnnouncementHello = "Hello"
+	AnnouncementBye   = "Bye"
+)
+
+// WS-Discovery multicast groups of IPv4 and link-local scope of IPv6
+const (
+	discoveryMulticastAddress     = "239.255.255.250:3702"
+	discoveryMulticastAddressIPv6 = "[ff02::c]:3702"
+)
+
+// ListenAnnouncements joins WS-Discovery multicast groups of IPv4 and IPv6 and sends
+// every Hello and Bye announcement of devices to the returned channel, so availability
+// of cameras can be tracked without polling. If interface is nil, the system default
+// is used. IPv6 group is joined only if it's available, e.g. it's not disabled on the
+// host. Link-local XAddr of announced device gets zone of the interface the announcement
+// came from. The channel is closed when context is done.
+func ListenAnnouncements(ctx context.Context, iface *net.Interface) (<-chan Announcement, error) {
+	multicastAddress, err := net.ResolveUDPAddr("udp4", discoveryMulticastAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := net.ListenMulticastUDP("udp4", iface, multicastAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	conns := []*net.UDPConn{conn}
+	if multicastAddress, err := net.ResolveUDPAddr("udp6", discoveryMulticastAddressIPv6); err == nil {
+		if conn, err := net.ListenMulticastUDP("udp6", iface, multicastAddress); err == nil {
+			conns = append(conns, conn)
+		}
+	}
+
+	// Close connections when context is done, which stops the reading loops
+	go func() {
+		<-ctx.Done()
+		for _, conn := range conns {
+			conn.Close()
+		}
+	}()
+
+	announcements := make(chan Announcement, 16)
+	var wg sync.WaitGroup
+	for _, conn := range conns {
+		wg.Add(1)
+		go func(conn *net.UDPConn) {
+			defer wg.Done()
+			readAnnouncements(ctx, conn, announcements)
+		}(conn)
+	}
+
+	go func() {
+		wg.Wait()
+		close(announcements)
+	}()
+
+	return announcements, nil
+}
+
+// readAnnouncements reads announcements from the connection and sends them
+// to the channel until context is done
+func readAnnouncements(ctx context.Context, conn *net.UDPConn, announcements chan<- Announcement) {
+	buffer := make([]byte, 64*1024)
+	for {
+		n, source, err := conn.ReadFromUDP(buffer)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+
+			// Wait a moment before retrying, so a broken socket doesn't spin
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(time.Second):
+			}
+			continue
+		}
+
+		zone := ""
+		if source != nil {
+			zone = source.Zone
+		}
+
+		announcement, ok := readAnnouncement(buffer[:n], zone)
+		if !ok {
+			continue
+		}
+
+		select {
+		case announcements <- announcement:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// readAnnouncement parses WS-Discovery Hello or Bye message that came through
+// the interface of the zone. Other messages, e.g. probe from other clients, are ignored.
+func readAnnouncement(buffer []byte, zone string) (Announcement, bool) {
+	mapXML, err := mxj.NewMapXml(buffer)
+	if err != nil {
+		return Announcement{}, false
+	}
+
+	for _, announcementType := range []string{AnnouncementHello, AnnouncementBye} {
+		path := "Envelope.Body." + announcementType
+		if _, err := mapXML.ValueForPath(path); err != nil {
+			continue
+		}
+
+		// Link-local IPv6 address is only reachable through the interface the announcement came from
+		device := parseDiscoveredDevice(mapXML, path)
+		device.XAddr = XAddrWithZone(device.XAddr, zone)
+
+		scopes, _ := mapXML.ValueForPathString(path + ".Scopes")
+		return Announcement{
+			Type:   announcementType,
+			Time:   time.Now(),
+			Device: device,
+			Scopes: strings.Fields(scopes),
+		}, true
+	}
+
+	return Announcement{}, false
+}
@@ -0,0 +1,70 @@
+package onvif
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestReadAnnouncement(t *testing.T) {
+	hello := []byte(`<?xml version="1.0" encoding="UTF-8"?>
+	<s:Envelope xmlns:s="http://www.w3.org/2003/05/soap-envelope" xmlns:a="http://schemas.xmlsoap.org/ws/2004/08/addressing" xmlns:d="http://schemas.xmlsoap.org/ws/2005/04/discovery">
+		<s:Header>
+			<a:Action>http://schemas.xmlsoap.org/ws/2005/04/discovery/Hello</a:Action>
+		</s:Header>
+		<s:Body>
+			<d:Hello>
+				<a:EndpointReference><a:Address>urn:uuid:2419d68a-2dd2-21b2-a205-ec0dc7de0001</a:Address></a:EndpointReference>
+				<d:Types>dn:NetworkVideoTransmitter</d:Types>
+				<d:Scopes>onvif://www.onvif.org/name/Front_Door onvif://www.onvif.org/location/garage</d:Scopes>
+				<d:XAddrs>http://192.168.1.10/onvif/device_service http://[fe80::1]/onvif/device_service</d:XAddrs>
+				<d:MetadataVersion>1</d:MetadataVersion>
+			</d:Hello>
+		</s:Body>
+	</s:Envelope>`)
+
+	announcement, ok := readAnnouncement(hello, "eth0")
+	if !ok {
+		t.Fatal("Hello message not parsed")
+	}
+
+	expected := Device{ID: "2419d68a-2dd2-21b2-a205-ec0dc7de0001", Name: "Front Door", XAddr: "http://192.168.1.10/onvif/device_service"}
+	if announcement.Type != AnnouncementHello || !reflect.DeepEqual(announcement.Device, expected) || len(announcement.Scopes) != 2 {
+		t.Errorf("unexpected announcement %+v", announcement)
+	}
+
+	bye := []byte(`<s:Envelope xmlns:s="http://www.w3.org/2003/05/soap-envelope" xmlns:a="http://schemas.xmlsoap.org/ws/2004/08/addressing" xmlns:d="http://schemas.xmlsoap.org/ws/2005/04/discovery">
+		<s:Body>
+			<d:Bye>
+				<a:EndpointReference><a:Address>urn:uuid:2419d68a-2dd2-21b2-a205-ec0dc7de0001</a:Address></a:EndpointReference>
+			</d:Bye>
+		</s:Body>
+	</s:Envelope>`)
+
+	announcement, ok = readAnnouncement(bye, "")
+	if !ok || announcement.Type != AnnouncementBye || announcement.Device.ID != expected.ID || announcement.Device.XAddr != "" {
+		t.Errorf("unexpected announcement %+v", announcement)
+	}
+
+	// Link-local XAddr gets zone of the interface the announcement came from
+	linkLocal := []byte(`<s:Envelope xmlns:s="http://www.w3.org/2003/05/soap-envelope" xmlns:a="http://schemas.xmlsoap.org/ws/2004/08/addressing" xmlns:d="http://schemas.xmlsoap.org/ws/2005/04/discovery">
+		<s:Body>
+			<d:Hello>
+				<a:EndpointReference><a:Address>urn:uuid:2419d68a-2dd2-21b2-a205-ec0dc7de0001</a:Address></a:EndpointReference>
+				<d:XAddrs>http://[fe80::1]:8080/onvif/device_service</d:XAddrs>
+			</d:Hello>
+		</s:Body>
+	</s:Envelope>`)
+
+	announcement, ok = readAnnouncement(linkLocal, "eth0")
+	if !ok || announcement.Device.XAddr != "http://[fe80::1%25eth0]:8080/onvif/device_service" {
+		t.Errorf("unexpected announcement %+v", announcement)
+	}
+
+	probe := []byte(`<s:Envelope xmlns:s="http://www.w3.org/2003/05/soap-envelope" xmlns:d="http://schemas.xmlsoap.org/ws/2005/04/discovery">
+		<s:Body><d:Probe><d:Types>dn:NetworkVideoTransmitter</d:Types></d:Probe></s:Body>
+	</s:Envelope>`)
+
+	if _, ok := readAnnouncement(probe, ""); ok {
+		t.Error("probe message must be ignored")
+	}
+}
@@ -0,0 +1,112 @@
+package onvif
+
+import "strconv"
+
+// BackchannelRequire is value of RTSP Require header to open audio backchannel
+const BackchannelRequire = "www.onvif.org/ver20/backchannel"
+
+// GetAudioOutputs fetch tokens of audio outputs of ONVIF camera
+func (device Device) GetAudioOutputs() ([]string, error) {
+	// Create SOAP
+	soap := SOAP{
+		Body:  "<trt:GetAudioOutputs/>",
+		XMLNs: mediaXMLNs,
+	}
+
+	// Send SOAP request
+	response, err := device.sendRequest(mediaNamespace, soap)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse response to interface
+	ifaceOutputs, err := response.ValuesForPath("Envelope.Body.GetAudioOutputsResponse.AudioOutputs")
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert interface to array of token
+	tokens := []string{}
+	for _, ifaceOutput := range ifaceOutputs {
+		if mapOutput, ok := ifaceOutput.(map[string]interface{}); ok {
+			tokens = append(tokens, interfaceToString(mapOutput["-token"]))
+		}
+	}
+
+	return tokens, nil
+}
+
+// GetAudioOutputConfigurations fetch all audio output configurations of ONVIF camera
+func (device Device) GetAudioOutputConfigurations() ([]AudioOutputConfig, error) {
+	// Create SOAP
+	soap := SOAP{
+		Body:  "<trt:GetAudioOutputConfigurations/>",
+		XMLNs: mediaXMLNs,
+	}
+
+	// Send SOAP request
+	response, err := device.sendRequest(mediaNamespace, soap)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse response to interface
+	ifaceConfigs, err := response.ValuesForPath("Envelope.Body.GetAudioOutputConfigurationsResponse.Configurations")
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert interface to array of audio output configuration
+	configs := []AudioOutputConfig{}
+	for _, ifaceConfig := range ifaceConfigs {
+		if mapConfig, ok := ifaceConfig.(map[string]interface{}); ok {
+			configs = append(configs, parseAudioOutputConfig(mapConfig))
+		}
+	}
+
+	return configs, nil
+}
+
+// SetAudioOutputConfiguration changes an audio output configuration. If forcePersistence
+// is true, the change will persist after ONVIF camera is rebooted.
+func (device Device) SetAudioOutputConfiguration(config AudioOutputConfig, forcePersistence bool) error {
+	// Create SOAP
+	body := `<trt:SetAudioOutputConfiguration>
+		<trt:Configuration token="` + escapeXML(config.Token) + `">
+			<tt:Name>` + escapeXML(config.Name) + `</tt:Name>
+			<tt:UseCount>` + strconv.Itoa(config.UseCount) + `</tt:UseCount>
+			<tt:OutputToken>` + escapeXML(config.OutputToken) + `</tt:OutputToken>`
+	if config.SendPrimacy != "" {
+		body += `<tt:SendPrimacy>` + escapeXML(config.SendPrimacy) + `</tt:SendPrimacy>`
+	}
+	body += `<tt:OutputLevel>` + strconv.Itoa(config.OutputLevel) + `</tt:OutputLevel>
+		</trt:Configuration>
+		<trt:ForcePersistence>` + strconv.FormatBool(forcePersistence) + `</trt:ForcePersistence>
+	</trt:SetAudioOutputConfiguration>`
+
+	soap := SOAP{
+		Body:  body,
+		XMLNs: mediaXMLNs,
+	}
+
+	// Send SOAP request
+	_, err := device.sendRequest(mediaNamespace, soap)
+	return err
+}
+
+// AddAudioOutputConfiguration adds an audio output configuration to a media profile
+func (device Device) AddAudioOutputConfiguration(profileToken, configToken string) error {
+	return device.addConfiguration("AudioOutput", profileToken, configToken)
+}
+
+// parseAudioOutputConfig parses tt:AudioOutputConfiguration
+func parseAudioOutputConfig(mapConfig map[string]interface{}) AudioOutputConfig {
+	return AudioOutputConfig{
+		Name:        interfaceToString(mapConfig["Name"]),
+		Token:       interfaceToString(mapConfig["-token"]),
+		UseCount:    interfaceToInt(mapConfig["UseCount"]),
+		OutputToken: interfaceToString(mapConfig["OutputToken"]),
+		SendPrimacy: interfaceToString(mapConfig["SendPrimacy"]),
+		OutputLevel: interfaceToInt(mapConfig["OutputLevel"]),
+	}
+}
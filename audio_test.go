@@ -0,0 +1,80 @@
+package onvif
+
+import (
+	"fmt"
+	"log"
+	"testing"
+
+	"github.com/krabiswabbie/go-onvif/onviftest"
+)
+
+func TestGetAudioOutputs(t *testing.T) {
+	log.Println("Test GetAudioOutputs")
+
+	res, err := testDevice.GetAudioOutputs()
+	if err != nil {
+		t.Error(err)
+	}
+
+	js := prettyJSON(&res)
+	fmt.Println(js)
+}
+
+func TestGetAudioOutputConfigurations(t *testing.T) {
+	log.Println("Test GetAudioOutputConfigurations")
+
+	res, err := testDevice.GetAudioOutputConfigurations()
+	if err != nil {
+		t.Error(err)
+	}
+
+	js := prettyJSON(&res)
+	fmt.Println(js)
+}
+
+func TestAudioBackchannel(t *testing.T) {
+	server := onviftest.NewServer()
+	defer server.Close()
+
+	server.HandleBody("GetProfiles", `<trt:GetProfilesResponse>
+		<trt:Profiles token="profile0" fixed="true">
+			<tt:Name>MainStream</tt:Name>
+			<tt:Extension>
+				<tt:AudioOutputConfiguration token="output0">
+					<tt:Name>Speaker</tt:Name>
+					<tt:UseCount>1</tt:UseCount>
+					<tt:OutputToken>speaker0</tt:OutputToken>
+					<tt:SendPrimacy>www.onvif.org/ver20/HalfDuplex/Auto</tt:SendPrimacy>
+					<tt:OutputLevel>80</tt:OutputLevel>
+				</tt:AudioOutputConfiguration>
+			</tt:Extension>
+		</trt:Profiles>
+	</trt:GetProfilesResponse>`)
+
+	device := Device{XAddr: server.XAddr()}
+	profiles, err := device.GetProfiles()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := AudioOutputConfig{
+		Name:        "Speaker",
+		Token:       "output0",
+		UseCount:    1,
+		OutputToken: "speaker0",
+		SendPrimacy: "www.onvif.org/ver20/HalfDuplex/Auto",
+		OutputLevel: 80,
+	}
+	if len(profiles) != 1 || profiles[0].AudioOutputConfig != expected {
+		t.Errorf("unexpected profiles %+v", profiles)
+	}
+
+	streamURI, err := device.GetBackchannelStreamURI("profile0", "RTSP")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if streamURI.URI == "" || streamURI.Require != BackchannelRequire {
+		t.Errorf("unexpected stream URI %+v", streamURI)
+	}
+}
@@ -0,0 +1,153 @@
+package onvif
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/deepch/mxj"
+)
+
+// cachedOperations are the operations whose responses rarely change,
+// so they can be cached by ResponseCache
+var cachedOperations = map[string]bool{
+	"GetCapabilities": true,
+	"GetServices":     true,
+	"GetProfiles":     true,
+}
+
+// invalidatingPrefixes are prefixes of operations that might change
+// the cached responses, so the cache is cleared after they're sent.
+// Besides configuration changes, these are operations that restart the
+// camera, e.g. with new firmware or restored configuration.
+var invalidatingPrefixes = []string{
+	"Set", "Create", "Delete", "Add", "Remove", "ConfigureReceiver",
+	"SystemReboot", "RestoreSystem", "StartSystemRestore",
+	"StartFirmwareUpgrade", "UpgradeSystemFirmware",
+}
+
+// ResponseCache caches responses of the requests that rarely change, i.e.
+// capabilities, services, profiles and configuration options, so they don't
+// have to be fetched from the camera before every command. Responses are
+// kept for TTL, or until they're invalidated if TTL is zero. The cache is
+// cleared whenever a request that might modify them, e.g. SetXXX, CreateXXX
+// or SystemReboot, is sent through the device, and after firmware upgrade. It's safe for concurrent use,
+// and it can be shared by copies of the same device.
+type ResponseCache struct {
+	TTL time.Duration
+
+	mutex   sync.Mutex
+	entries map[string]cacheEntry
+}
+
+type cacheEntry struct {
+	response mxj.Map
+	expires  time.Time
+}
+
+// NewResponseCache creates cache that keeps responses for the TTL
+func NewResponseCache(ttl time.Duration) *ResponseCache {
+	return &ResponseCache{TTL: ttl}
+}
+
+// Invalidate removes cached responses of the operations, e.g. "GetProfiles".
+// If no operation is specified, all cached responses are removed.
+func (cache *ResponseCache) Invalidate(operations ...string) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	if len(operations) == 0 {
+		cache.entries = nil
+		return
+	}
+
+	for key := range cache.entries {
+		for _, operation := range operations {
+			if strings.HasPrefix(key, operation+"\n") {
+				delete(cache.entries, key)
+				break
+			}
+		}
+	}
+}
+
+// get returns cached response of the key, if it's not expired yet
+func (cache *ResponseCache) get(key string) (mxj.Map, bool) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	entry, ok := cache.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	if !entry.expires.IsZero() && time.Now().After(entry.expires) {
+		delete(cache.entries, key)
+		return nil, false
+	}
+
+	return entry.response, true
+}
+
+// set saves response of the key
+func (cache *ResponseCache) set(key string, response mxj.Map) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	entry := cacheEntry{response: response}
+	if cache.TTL > 0 {
+		entry.expires = time.Now().Add(cache.TTL)
+	}
+
+	if cache.entries == nil {
+		cache.entries = map[string]cacheEntry{}
+	}
+	cache.entries[key] = entry
+}
+
+// sendRequest sends SOAP request through the send function, unless its response
+// is already cached. Key of the cache contains the operation, xAddr and body of
+// the request, so the same operation with different parameters is cached separately.
+func (cache *ResponseCache) sendRequest(xaddr string, soap SOAP, send func() (mxj.Map, error)) (mxj.Map, error) {
+	operation := ""
+	if match := rxOperation.FindStringSubmatch(soap.Body); match != nil {
+		operation = match[1]
+	}
+
+	if !isCachedOperation(operation) {
+		response, err := send()
+		if isInvalidatingOperation(operation) {
+			cache.Invalidate()
+		}
+		return response, err
+	}
+
+	key := operation + "\n" + xaddr + "\n" + soap.Body
+	if response, ok := cache.get(key); ok {
+		return response, nil
+	}
+
+	response, err := send()
+	if err != nil {
+		return response, err
+	}
+
+	cache.set(key, response)
+	return response, nil
+}
+
+// isCachedOperation checks if response of the operation can be cached
+func isCachedOperation(operation string) bool {
+	return cachedOperations[operation] || strings.HasSuffix(operation, "ConfigurationOptions")
+}
+
+// isInvalidatingOperation checks if the operation might change cached responses
+func isInvalidatingOperation(operation string) bool {
+	for _, prefix := range invalidatingPrefixes {
+		if strings.HasPrefix(operation, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,153 @@
+package onvif
+
+import (
+	"testing"
+	"time"
+
+	"github.com/krabiswabbie/go-onvif/onviftest"
+)
+
+func countRequests(server *onviftest.Server, operation string) int {
+	count := 0
+	for _, request := range server.Requests() {
+		if request.Operation == operation {
+			count++
+		}
+	}
+
+	return count
+}
+
+func TestResponseCache(t *testing.T) {
+	server := onviftest.NewServer()
+	defer server.Close()
+
+	device := Device{XAddr: server.XAddr(), Cache: NewResponseCache(time.Minute)}
+
+	for i := 0; i < 3; i++ {
+		profiles, err := device.GetProfiles()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(profiles) == 0 || profiles[0].Token != "Profile_1" {
+			t.Fatalf("unexpected profiles %+v", profiles)
+		}
+	}
+
+	if count := countRequests(server, "GetProfiles"); count != 1 {
+		t.Errorf("expected 1 GetProfiles request, got %d", count)
+	}
+
+	// Commands are never cached
+	for i := 0; i < 2; i++ {
+		if err := device.StopPTZ("Profile_1", true, true); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if count := countRequests(server, "Stop"); count != 2 {
+		t.Errorf("expected 2 Stop requests, got %d", count)
+	}
+
+	// Explicit invalidation
+	device.Cache.Invalidate("GetProfiles")
+	if _, err := device.GetProfiles(); err != nil {
+		t.Fatal(err)
+	}
+
+	if count := countRequests(server, "GetProfiles"); count != 2 {
+		t.Errorf("expected 2 GetProfiles requests after invalidation, got %d", count)
+	}
+
+	// Modifying profiles clears the cache
+	server.HandleBody("DeleteProfile", `<trt:DeleteProfileResponse/>`)
+	if err := device.DeleteProfile("Profile_2"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := device.GetProfiles(); err != nil {
+		t.Fatal(err)
+	}
+
+	if count := countRequests(server, "GetProfiles"); count != 3 {
+		t.Errorf("expected 3 GetProfiles requests after DeleteProfile, got %d", count)
+	}
+}
+
+func TestResponseCacheExpiry(t *testing.T) {
+	server := onviftest.NewServer()
+	defer server.Close()
+
+	device := Device{XAddr: server.XAddr(), Cache: NewResponseCache(time.Millisecond)}
+
+	if _, err := device.GetProfiles(); err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(5 * time.Millisecond)
+
+	if _, err := device.GetProfiles(); err != nil {
+		t.Fatal(err)
+	}
+
+	if count := countRequests(server, "GetProfiles"); count != 2 {
+		t.Errorf("expected expired response to be fetched again, got %d requests", count)
+	}
+}
+
+func TestResponseCacheFault(t *testing.T) {
+	server := onviftest.NewServer()
+	defer server.Close()
+
+	device := Device{XAddr: server.XAddr(), Cache: NewResponseCache(0)}
+
+	server.HandleFault("GetProfiles", onviftest.ReceiverFault("ter:Action", "Busy"))
+	if _, err := device.GetProfiles(); err == nil {
+		t.Fatal("expected fault")
+	}
+
+	// Faults aren't cached
+	if _, err := device.GetProfiles(); err == nil {
+		t.Fatal("expected fault")
+	}
+
+	if count := countRequests(server, "GetProfiles"); count != 2 {
+		t.Errorf("expected faults not to be cached, got %d requests", count)
+	}
+}
+
+func TestResponseCacheRestart(t *testing.T) {
+	server := onviftest.NewServer()
+	defer server.Close()
+
+	server.HandleBody("StartFirmwareUpgrade", `<tds:StartFirmwareUpgradeResponse>
+		<tds:UploadUri>`+server.URL+`/firmware</tds:UploadUri>
+		<tds:UploadDelay>PT0S</tds:UploadDelay>
+		<tds:ExpectedDownTime>PT60S</tds:ExpectedDownTime>
+	</tds:StartFirmwareUpgradeResponse>`)
+
+	device := Device{XAddr: server.XAddr(), Cache: NewResponseCache(0)}
+	if _, err := device.GetServices(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Camera might have other services once its firmware is upgraded
+	if _, err := device.StartFirmwareUpgrade(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := device.GetServices(); err != nil {
+		t.Fatal(err)
+	}
+
+	if count := countRequests(server, "GetServices"); count != 2 {
+		t.Errorf("expected 2 GetServices requests after firmware upgrade, got %d", count)
+	}
+
+	for _, operation := range []string{"SystemReboot", "RestoreSystem", "StartSystemRestore", "UpgradeSystemFirmware", "ConfigureReceiver"} {
+		if !isInvalidatingOperation(operation) {
+			t.Errorf("%s doesn't clear the cache", operation)
+		}
+	}
+}
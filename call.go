@@ -0,0 +1,83 @@
+package onvif
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"time"
+)
+
+var errNoSOAPBody = errors.New("Response doesn't contain SOAP body")
+
+// Call sends request to the service with specified namespace, then decodes
+// the response into response, which may be nil if it's not needed. Unlike
+// other methods, request and response are structs encoded by encoding/xml,
+// e.g. the ones generated from ONVIF WSDL by cmd/onvifgen, so operations
+// that aren't implemented by this package can be called as well. Responses
+// of Call aren't cached, though operations that might change cached responses
+// clear the cache. Responses sent as MTOM message aren't supported.
+func (device Device) Call(namespace string, request interface{}, response interface{}) error {
+	// Create SOAP
+	body, err := xml.Marshal(request)
+	if err != nil {
+		return err
+	}
+
+	soap := SOAP{Body: string(body)}
+
+	// Send SOAP request, keeping the raw response to decode it
+	var responseBody []byte
+	capture := HookFuncs{Response: func(info RequestInfo, body []byte, elapsed time.Duration) {
+		responseBody = body
+	}}
+
+	// Response isn't taken from the cache, since it has to be captured, but the
+	// cache is still cleared if the operation might change cached responses
+	cache := device.Cache
+	device.Hook = chainHooks(device.Hook, capture)
+	device.Cache = nil
+	_, err = device.sendRequest(namespace, soap)
+	if cache != nil {
+		if match := rxOperation.FindStringSubmatch(soap.Body); match != nil && isInvalidatingOperation(match[1]) {
+			cache.Invalidate()
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	if response == nil {
+		return nil
+	}
+
+	// Parse response to struct
+	return decodeSOAPBody(responseBody, response)
+}
+
+// decodeSOAPBody decodes the first child of body of SOAP envelope into v
+func decodeSOAPBody(envelope []byte, v interface{}) error {
+	decoder := xml.NewDecoder(bytes.NewReader(envelope))
+	inBody := false
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return errNoSOAPBody
+		}
+		if err != nil {
+			return err
+		}
+
+		switch element := token.(type) {
+		case xml.StartElement:
+			if inBody {
+				return decoder.DecodeElement(v, &element)
+			}
+			inBody = element.Name.Local == "Body"
+		case xml.EndElement:
+			if inBody {
+				return errNoSOAPBody
+			}
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package onvif
+
+import (
+	"encoding/xml"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/krabiswabbie/go-onvif/onviftest"
+)
+
+type testGetNode struct {
+	XMLName   xml.Name `xml:"http://www.onvif.org/ver20/ptz/wsdl GetNode"`
+	NodeToken string   `xml:"http://www.onvif.org/ver20/ptz/wsdl NodeToken"`
+}
+
+type testGetNodeResponse struct {
+	XMLName xml.Name `xml:"http://www.onvif.org/ver20/ptz/wsdl GetNodeResponse"`
+	PTZNode struct {
+		Token                  string `xml:"token,attr"`
+		Name                   string `xml:"http://www.onvif.org/ver10/schema Name"`
+		MaximumNumberOfPresets int    `xml:"http://www.onvif.org/ver10/schema MaximumNumberOfPresets"`
+	} `xml:"http://www.onvif.org/ver20/ptz/wsdl PTZNode"`
+}
+
+func TestCall(t *testing.T) {
+	server := onviftest.NewServer()
+	defer server.Close()
+
+	server.HandleBody("GetNode", `<tptz:GetNodeResponse>
+		<tptz:PTZNode token="PTZNode_1">
+			<tt:Name>PTZ</tt:Name>
+			<tt:MaximumNumberOfPresets>16</tt:MaximumNumberOfPresets>
+		</tptz:PTZNode>
+	</tptz:GetNodeResponse>`)
+
+	responses := 0
+	hook := HookFuncs{Response: func(info RequestInfo, response []byte, elapsed time.Duration) {
+		responses++
+	}}
+
+	device := Device{XAddr: server.XAddr(), Hook: hook, Cache: NewResponseCache(time.Minute)}
+	var response testGetNodeResponse
+	if err := device.Call(ptzNamespace, testGetNode{NodeToken: "PTZNode_1"}, &response); err != nil {
+		t.Fatal(err)
+	}
+
+	if response.PTZNode.Token != "PTZNode_1" || response.PTZNode.Name != "PTZ" || response.PTZNode.MaximumNumberOfPresets != 16 {
+		t.Errorf("unexpected response %+v", response)
+	}
+
+	if responses != 1 {
+		t.Errorf("hook of device notified about %d responses", responses)
+	}
+
+	request, _ := server.LastRequest("GetNode")
+	if !strings.Contains(request.Envelope, `<NodeToken xmlns="http://www.onvif.org/ver20/ptz/wsdl">PTZNode_1</NodeToken>`) {
+		t.Errorf("unexpected request %s", request.Envelope)
+	}
+
+	// Faults are returned like other methods
+	server.HandleFault("GetNode", onviftest.SenderFault("ter:InvalidArgVal/ter:NoEntity", "No such node"))
+	err := device.Call(ptzNamespace, testGetNode{NodeToken: "PTZNode_2"}, &response)
+	if !errors.Is(err, ErrNoEntity) {
+		t.Errorf("expected no entity fault, got %v", err)
+	}
+}
+
+type testSetHostname struct {
+	XMLName xml.Name `xml:"http://www.onvif.org/ver10/device/wsdl SetHostname"`
+	Name    string   `xml:"http://www.onvif.org/ver10/device/wsdl Name"`
+}
+
+func TestCallInvalidatesCache(t *testing.T) {
+	server := onviftest.NewServer()
+	defer server.Close()
+
+	server.HandleBody("SetHostname", `<tds:SetHostnameResponse/>`)
+
+	device := Device{XAddr: server.XAddr(), Cache: NewResponseCache(time.Minute)}
+	for i := 0; i < 2; i++ {
+		if _, err := device.GetProfiles(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Operation that might change the cached responses clears the cache
+	if err := device.Call(deviceNamespace, testSetHostname{Name: "camera"}, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := device.GetProfiles(); err != nil {
+		t.Fatal(err)
+	}
+
+	if count := countRequests(server, "GetProfiles"); count != 2 {
+		t.Errorf("expected 2 GetProfiles requests after Call of SetHostname, got %d", count)
+	}
+}
@@ -0,0 +1,161 @@
+package onvif
+
+import "strings"
+
+const imagingNamespace = "http://www.onvif.org/ver20/imaging/wsdl"
+
+// parseCapabilities parses tt:Capabilities
+func parseCapabilities(mapCapabilities map[string]interface{}) DeviceCapabilities {
+	capabilities := DeviceCapabilities{
+		Events:    map[string]bool{},
+		Streaming: map[string]bool{},
+	}
+
+	// Parse device capabilities
+	if mapDevice, ok := mapCapabilities["Device"].(map[string]interface{}); ok {
+		capabilities.Device.XAddr = interfaceToString(mapDevice["XAddr"])
+
+		if mapNetwork, ok := mapDevice["Network"].(map[string]interface{}); ok {
+			capabilities.Network.DynDNS = interfaceToBool(mapNetwork["DynDNS"])
+			capabilities.Network.IPFilter = interfaceToBool(mapNetwork["IPFilter"])
+			capabilities.Network.IPVersion6 = interfaceToBool(mapNetwork["IPVersion6"])
+			capabilities.Network.ZeroConfig = interfaceToBool(mapNetwork["ZeroConfiguration"])
+		}
+
+		if mapSystem, ok := mapDevice["System"].(map[string]interface{}); ok {
+			system := SystemCapabilities{
+				DiscoveryResolve:  interfaceToBool(mapSystem["DiscoveryResolve"]),
+				DiscoveryBye:      interfaceToBool(mapSystem["DiscoveryBye"]),
+				RemoteDiscovery:   interfaceToBool(mapSystem["RemoteDiscovery"]),
+				SystemBackup:      interfaceToBool(mapSystem["SystemBackup"]),
+				SystemLogging:     interfaceToBool(mapSystem["SystemLogging"]),
+				FirmwareUpgrade:   interfaceToBool(mapSystem["FirmwareUpgrade"]),
+				SupportedVersions: []string{},
+			}
+
+			for _, mapVersion := range interfaceToMaps(mapSystem["SupportedVersions"]) {
+				version := interfaceToString(mapVersion["Major"]) + "." + interfaceToString(mapVersion["Minor"])
+				system.SupportedVersions = append(system.SupportedVersions, version)
+			}
+
+			capabilities.Device.System = system
+		}
+
+		if mapIO, ok := mapDevice["IO"].(map[string]interface{}); ok {
+			capabilities.Device.IO.InputConnectors = interfaceToInt(mapIO["InputConnectors"])
+			capabilities.Device.IO.RelayOutputs = interfaceToInt(mapIO["RelayOutputs"])
+		}
+
+		if mapSecurity, ok := mapDevice["Security"].(map[string]interface{}); ok {
+			capabilities.Device.Security = SecurityCapabilities{
+				TLS11:                interfaceToBool(mapSecurity["TLS1.1"]),
+				TLS12:                interfaceToBool(mapSecurity["TLS1.2"]),
+				OnboardKeyGeneration: interfaceToBool(mapSecurity["OnboardKeyGeneration"]),
+				AccessPolicyConfig:   interfaceToBool(mapSecurity["AccessPolicyConfig"]),
+				X509Token:            interfaceToBool(mapSecurity["X.509Token"]),
+				SAMLToken:            interfaceToBool(mapSecurity["SAMLToken"]),
+				KerberosToken:        interfaceToBool(mapSecurity["KerberosToken"]),
+				RELToken:             interfaceToBool(mapSecurity["RELToken"]),
+			}
+		}
+	}
+
+	// Parse events capabilities
+	if mapEvents, ok := mapCapabilities["Events"].(map[string]interface{}); ok {
+		capabilities.EventService = EventCapabilities{
+			XAddr:                     interfaceToString(mapEvents["XAddr"]),
+			SubscriptionPolicySupport: interfaceToBool(mapEvents["WSSubscriptionPolicySupport"]),
+			PullPointSupport:          interfaceToBool(mapEvents["WSPullPointSupport"]),
+			PausableSubscriptionManagerInterfaceSupport: interfaceToBool(mapEvents["WSPausableSubscriptionManagerInterfaceSupport"]),
+		}
+
+		for key, value := range mapEvents {
+			if strings.ToLower(key) == "xaddr" {
+				continue
+			}
+
+			key = strings.Replace(key, "WS", "", 1)
+			capabilities.Events[key] = interfaceToBool(value)
+		}
+	}
+
+	// Parse media capabilities
+	if mapMedia, ok := mapCapabilities["Media"].(map[string]interface{}); ok {
+		capabilities.Media.XAddr = interfaceToString(mapMedia["XAddr"])
+
+		if mapStreaming, ok := mapMedia["StreamingCapabilities"].(map[string]interface{}); ok {
+			capabilities.Media.RTPMulticast = interfaceToBool(mapStreaming["RTPMulticast"])
+			capabilities.Media.RTPTCP = interfaceToBool(mapStreaming["RTP_TCP"])
+			capabilities.Media.RTPRTSPTCP = interfaceToBool(mapStreaming["RTP_RTSP_TCP"])
+
+			for key, value := range mapStreaming {
+				key = strings.Replace(key, "_", " ", -1)
+				capabilities.Streaming[key] = interfaceToBool(value)
+			}
+		}
+
+		if mapExtension, ok := mapMedia["Extension"].(map[string]interface{}); ok {
+			if mapProfile, ok := mapExtension["ProfileCapabilities"].(map[string]interface{}); ok {
+				capabilities.Media.MaximumNumberOfProfiles = interfaceToInt(mapProfile["MaximumNumberOfProfiles"])
+			}
+		}
+	}
+
+	// Parse analytics capabilities
+	if mapAnalytics, ok := mapCapabilities["Analytics"].(map[string]interface{}); ok {
+		capabilities.Analytics = AnalyticsCapabilities{
+			XAddr:                  interfaceToString(mapAnalytics["XAddr"]),
+			RuleSupport:            interfaceToBool(mapAnalytics["RuleSupport"]),
+			AnalyticsModuleSupport: interfaceToBool(mapAnalytics["AnalyticsModuleSupport"]),
+		}
+	}
+
+	// Parse XAddr of the other services
+	capabilities.PTZ = parseServiceCapabilities(mapCapabilities["PTZ"])
+	capabilities.Imaging = parseServiceCapabilities(mapCapabilities["Imaging"])
+
+	if mapExtension, ok := mapCapabilities["Extension"].(map[string]interface{}); ok {
+		capabilities.DeviceIO = parseServiceCapabilities(mapExtension["DeviceIO"])
+		capabilities.Recording = parseServiceCapabilities(mapExtension["Recording"])
+		capabilities.Search = parseServiceCapabilities(mapExtension["Search"])
+		capabilities.Replay = parseServiceCapabilities(mapExtension["Replay"])
+		capabilities.Receiver = parseServiceCapabilities(mapExtension["Receiver"])
+	}
+
+	return capabilities
+}
+
+// parseServiceCapabilities parses XAddr of a service in tt:Capabilities
+func parseServiceCapabilities(src interface{}) ServiceCapabilities {
+	capabilities := ServiceCapabilities{}
+	if mapService, ok := src.(map[string]interface{}); ok {
+		capabilities.XAddr = interfaceToString(mapService["XAddr"])
+	}
+
+	return capabilities
+}
+
+// serviceXAddrs returns XAddr of each service in capabilities, keyed by service namespace
+func (capabilities DeviceCapabilities) serviceXAddrs() map[string]string {
+	xaddrs := map[string]string{
+		deviceNamespace:    capabilities.Device.XAddr,
+		mediaNamespace:     capabilities.Media.XAddr,
+		eventsNamespace:    capabilities.EventService.XAddr,
+		ptzNamespace:       capabilities.PTZ.XAddr,
+		imagingNamespace:   capabilities.Imaging.XAddr,
+		analyticsNamespace: capabilities.Analytics.XAddr,
+		deviceIONamespace:  capabilities.DeviceIO.XAddr,
+		recordingNamespace: capabilities.Recording.XAddr,
+		searchNamespace:    capabilities.Search.XAddr,
+		replayNamespace:    capabilities.Replay.XAddr,
+		receiverNamespace:  capabilities.Receiver.XAddr,
+	}
+
+	for namespace, xaddr := range xaddrs {
+		if xaddr == "" {
+			delete(xaddrs, namespace)
+		}
+	}
+
+	return xaddrs
+}
@@ -0,0 +1,236 @@
+package onvif
+
+import (
+	"crypto/x509"
+	"encoding/base64"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// GetCertificates fetch certificates of ONVIF camera
+func (device Device) GetCertificates() ([]Certificate, error) {
+	// Create SOAP
+	soap := SOAP{
+		Body:  "<tds:GetCertificates/>",
+		XMLNs: deviceXMLNs,
+	}
+
+	// Send SOAP request
+	response, err := device.sendRequest(deviceNamespace, soap)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse response to interface
+	ifaceCertificates, err := response.ValuesForPath("Envelope.Body.GetCertificatesResponse.NvtCertificate")
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert interface to array of certificate
+	certificates := []Certificate{}
+	for _, ifaceCertificate := range ifaceCertificates {
+		if mapCertificate, ok := ifaceCertificate.(map[string]interface{}); ok {
+			certificates = append(certificates, parseCertificate(mapCertificate))
+		}
+	}
+
+	return certificates, nil
+}
+
+// GetCertificatesStatus fetch whether each certificate is used by ONVIF camera
+func (device Device) GetCertificatesStatus() ([]CertificateStatus, error) {
+	// Create SOAP
+	soap := SOAP{
+		Body:  "<tds:GetCertificatesStatus/>",
+		XMLNs: deviceXMLNs,
+	}
+
+	// Send SOAP request
+	response, err := device.sendRequest(deviceNamespace, soap)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse response to interface
+	ifaceStatuses, err := response.ValuesForPath("Envelope.Body.GetCertificatesStatusResponse.CertificateStatus")
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert interface to array of status
+	statuses := []CertificateStatus{}
+	for _, ifaceStatus := range ifaceStatuses {
+		if mapStatus, ok := ifaceStatus.(map[string]interface{}); ok {
+			statuses = append(statuses, CertificateStatus{
+				ID:      interfaceToString(mapStatus["CertificateID"]),
+				Enabled: interfaceToBool(mapStatus["Status"]),
+			})
+		}
+	}
+
+	return statuses, nil
+}
+
+// SetCertificatesStatus sets whether each certificate is used by ONVIF camera
+func (device Device) SetCertificatesStatus(statuses []CertificateStatus) error {
+	// Create SOAP
+	body := `<tds:SetCertificatesStatus>`
+	for _, status := range statuses {
+		body += `<tds:CertificateStatus>
+			<tt:CertificateID>` + escapeXML(status.ID) + `</tt:CertificateID>
+			<tt:Status>` + strconv.FormatBool(status.Enabled) + `</tt:Status>
+		</tds:CertificateStatus>`
+	}
+	body += `</tds:SetCertificatesStatus>`
+
+	soap := SOAP{
+		Body:  body,
+		XMLNs: deviceXMLNs,
+	}
+
+	// Send SOAP request
+	_, err := device.sendRequest(deviceNamespace, soap)
+	return err
+}
+
+// GetCertificateInformation fetch details of a certificate of ONVIF camera
+func (device Device) GetCertificateInformation(certificateID string) (CertificateInformation, error) {
+	// Create SOAP
+	soap := SOAP{
+		XMLNs: deviceXMLNs,
+		Body: `<tds:GetCertificateInformation>
+			<tds:CertificateID>` + escapeXML(certificateID) + `</tds:CertificateID>
+		</tds:GetCertificateInformation>`,
+	}
+
+	// Send SOAP request
+	response, err := device.sendRequest(deviceNamespace, soap)
+	if err != nil {
+		return CertificateInformation{}, err
+	}
+
+	// Parse response to interface
+	ifaceInfo, err := response.ValueForPath("Envelope.Body.GetCertificateInformationResponse.CertificateInformation")
+	if err != nil {
+		return CertificateInformation{}, err
+	}
+
+	// Parse interface to struct
+	info := CertificateInformation{}
+	if mapInfo, ok := ifaceInfo.(map[string]interface{}); ok {
+		info.ID = interfaceToString(mapInfo["CertificateID"])
+		info.IssuerDN = interfaceToString(mapInfo["IssuerDN"])
+		info.SubjectDN = interfaceToString(mapInfo["SubjectDN"])
+		info.KeyUsage = interfaceToText(mapInfo["KeyUsage"])
+		info.ExtendedKeyUsage = interfaceToText(mapInfo["ExtendedKeyUsage"])
+		info.KeyLength = interfaceToInt(mapInfo["KeyLength"])
+		info.Version = interfaceToString(mapInfo["Version"])
+		info.SerialNumber = interfaceToString(mapInfo["SerialNum"])
+		info.SignatureAlgorithm = interfaceToString(mapInfo["SignatureAlgorithm"])
+
+		if mapValidity, ok := mapInfo["Validity"].(map[string]interface{}); ok {
+			info.ValidFrom = interfaceToTime(mapValidity["From"])
+			info.ValidUntil = interfaceToTime(mapValidity["Until"])
+		}
+	}
+
+	return info, nil
+}
+
+// CreateCertificate asks ONVIF camera to create a self-signed certificate and its key pair.
+// Camera chooses the ID and validity of the certificate when they're empty.
+func (device Device) CreateCertificate(certificateID, subject string, validNotBefore, validNotAfter time.Time) (Certificate, error) {
+	// Create SOAP
+	body := `<tds:CreateCertificate>`
+	if certificateID != "" {
+		body += `<tds:CertificateID>` + escapeXML(certificateID) + `</tds:CertificateID>`
+	}
+	if subject != "" {
+		body += `<tds:Subject>` + escapeXML(subject) + `</tds:Subject>`
+	}
+	body += optionalTimeXML("tds:ValidNotBefore", validNotBefore) +
+		optionalTimeXML("tds:ValidNotAfter", validNotAfter) +
+		`</tds:CreateCertificate>`
+
+	soap := SOAP{
+		Body:  body,
+		XMLNs: deviceXMLNs,
+	}
+
+	// Send SOAP request
+	response, err := device.sendRequest(deviceNamespace, soap)
+	if err != nil {
+		return Certificate{}, err
+	}
+
+	// Parse response to interface
+	ifaceCertificate, err := response.ValueForPath("Envelope.Body.CreateCertificateResponse.NvtCertificate")
+	if err != nil {
+		return Certificate{}, err
+	}
+
+	// Parse interface to struct
+	mapCertificate, _ := ifaceCertificate.(map[string]interface{})
+	return parseCertificate(mapCertificate), nil
+}
+
+// LoadCertificates uploads certificates to ONVIF camera. Each certificate
+// must match a key pair created before with CreateCertificate.
+func (device Device) LoadCertificates(certificates []Certificate) error {
+	// Create SOAP
+	body := `<tds:LoadCertificates>`
+	for _, certificate := range certificates {
+		body += `<tds:NVTCertificate>
+			<tt:CertificateID>` + escapeXML(certificate.ID) + `</tt:CertificateID>
+			<tt:Certificate>
+				<tt:Data>` + base64.StdEncoding.EncodeToString(certificate.Data) + `</tt:Data>
+			</tt:Certificate>
+		</tds:NVTCertificate>`
+	}
+	body += `</tds:LoadCertificates>`
+
+	soap := SOAP{
+		Body:  body,
+		XMLNs: deviceXMLNs,
+	}
+
+	// Send SOAP request
+	_, err := device.sendRequest(deviceNamespace, soap)
+	return err
+}
+
+// DeleteCertificates removes certificates with the IDs from ONVIF camera
+func (device Device) DeleteCertificates(certificateIDs []string) error {
+	// Create SOAP
+	body := `<tds:DeleteCertificates>`
+	for _, certificateID := range certificateIDs {
+		body += `<tds:CertificateID>` + escapeXML(certificateID) + `</tds:CertificateID>`
+	}
+	body += `</tds:DeleteCertificates>`
+
+	soap := SOAP{
+		Body:  body,
+		XMLNs: deviceXMLNs,
+	}
+
+	// Send SOAP request
+	_, err := device.sendRequest(deviceNamespace, soap)
+	return err
+}
+
+// X509 parses data of the certificate
+func (certificate Certificate) X509() (*x509.Certificate, error) {
+	return x509.ParseCertificate(certificate.Data)
+}
+
+// parseCertificate parses tt:Certificate that contains base64 encoded data
+func parseCertificate(mapCertificate map[string]interface{}) Certificate {
+	certificate := Certificate{ID: interfaceToString(mapCertificate["CertificateID"])}
+	if mapData, ok := mapCertificate["Certificate"].(map[string]interface{}); ok {
+		data := strings.Join(strings.Fields(interfaceToText(mapData["Data"])), "")
+		certificate.Data, _ = base64.StdEncoding.DecodeString(data)
+	}
+	return certificate
+}
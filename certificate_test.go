@@ -0,0 +1,91 @@
+package onvif
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"fmt"
+	"log"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/krabiswabbie/go-onvif/onviftest"
+)
+
+func TestGetCertificates(t *testing.T) {
+	log.Println("Test GetCertificates")
+
+	res, err := testDevice.GetCertificates()
+	if err != nil {
+		t.Error(err)
+	}
+
+	js := prettyJSON(&res)
+	fmt.Println(js)
+}
+
+func TestGetCertificatesStatus(t *testing.T) {
+	log.Println("Test GetCertificatesStatus")
+
+	res, err := testDevice.GetCertificatesStatus()
+	if err != nil {
+		t.Error(err)
+	}
+
+	js := prettyJSON(&res)
+	fmt.Println(js)
+}
+
+func TestParseCertificate(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "camera"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := onviftest.NewServer()
+	defer server.Close()
+
+	server.HandleBody("GetCertificates", `<tds:GetCertificatesResponse>
+		<tds:NvtCertificate>
+			<tt:CertificateID>cert1</tt:CertificateID>
+			<tt:Certificate>
+				<tt:Data>`+base64.StdEncoding.EncodeToString(der)+`</tt:Data>
+			</tt:Certificate>
+		</tds:NvtCertificate>
+	</tds:GetCertificatesResponse>`)
+
+	device := Device{XAddr: server.XAddr()}
+	certificates, err := device.GetCertificates()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(certificates) != 1 || certificates[0].ID != "cert1" {
+		t.Fatalf("unexpected certificates %+v", certificates)
+	}
+
+	cert, err := certificates[0].X509()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cert.Subject.CommonName != "camera" {
+		t.Errorf("unexpected subject %s", cert.Subject)
+	}
+}
@@ -0,0 +1,317 @@
+// Command onvif is a command line tool for communicating with ONVIF camera,
+// which is useful to verify camera compatibility and to debug.
+//
+// Usage:
+//
+//	onvif [flags] <command> [arguments]
+//
+// The commands are:
+//
+//	discover                     find cameras in local network
+//	info                         show device information
+//	profiles                     show media profiles
+//	stream-uri [profile]         show stream URI of a profile
+//	snapshot [profile] <file>    save JPEG snapshot of a profile
+//	ptz move <x> <y> <zoom>      move camera continuously, until -duration elapsed
+//	ptz stop                     stop camera movement
+//	ptz preset list              show PTZ presets
+//	ptz preset goto <token>      move camera to a preset
+//	ptz preset set <name>        save current position as a preset
+//	ptz preset remove <token>    remove a preset
+//	events watch [topic]         print events until interrupted
+//
+// When profile is not specified, the first profile of camera is used.
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/signal"
+	"strconv"
+	"time"
+
+	onvif "github.com/krabiswabbie/go-onvif"
+)
+
+var (
+	flagXAddr    = flag.String("xaddr", "", "address of camera, e.g. 192.168.1.10 or http://192.168.1.10/onvif/device_service")
+	flagUser     = flag.String("user", "", "user name")
+	flagPassword = flag.String("password", "", "password")
+	flagTimeout  = flag.Duration("timeout", 5*time.Second, "timeout of each request")
+	flagInsecure = flag.Bool("insecure", false, "skip verification of camera certificate")
+	flagProfile  = flag.String("profile", "", "token of media profile, the first profile is used if empty")
+	flagProtocol = flag.String("protocol", "RTSP", "stream protocol: UDP, HTTP or RTSP")
+	flagDuration = flag.Duration("duration", time.Second, "duration of discovery or PTZ movement")
+)
+
+func main() {
+	flag.Usage = usage
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		usage()
+		os.Exit(2)
+	}
+
+	if err := run(flag.Args()); err != nil {
+		fmt.Fprintln(os.Stderr, "onvif:", err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage: onvif [flags] <command> [arguments]")
+	fmt.Fprintln(os.Stderr, "Commands: discover, info, profiles, stream-uri, snapshot, ptz, events")
+	fmt.Fprintln(os.Stderr, "Flags:")
+	flag.PrintDefaults()
+}
+
+func run(args []string) error {
+	if args[0] == "discover" {
+		devices, err := onvif.StartDiscovery(*flagDuration)
+		if err != nil {
+			return err
+		}
+		return printJSON(devices)
+	}
+
+	device, err := connect()
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "info":
+		info, err := device.GetInformation()
+		if err != nil {
+			return err
+		}
+		return printJSON(info)
+
+	case "profiles":
+		profiles, err := device.GetProfiles()
+		if err != nil {
+			return err
+		}
+		return printJSON(profiles)
+
+	case "stream-uri":
+		profile := ""
+		if len(args) > 1 {
+			profile = args[1]
+		}
+
+		profileToken, err := profileToken(device, profile)
+		if err != nil {
+			return err
+		}
+
+		uri, err := device.GetStreamURI(profileToken, *flagProtocol)
+		if err != nil {
+			return err
+		}
+		return printJSON(uri)
+
+	case "snapshot":
+		if len(args) < 2 {
+			return errors.New("snapshot requires output file")
+		}
+
+		profile := ""
+		if len(args) > 2 {
+			profile = args[1]
+		}
+
+		profileToken, err := profileToken(device, profile)
+		if err != nil {
+			return err
+		}
+
+		snapshot, err := device.GetSnapshot(profileToken)
+		if err != nil {
+			return err
+		}
+		return ioutil.WriteFile(args[len(args)-1], snapshot, 0644)
+
+	case "ptz":
+		return runPTZ(device, args[1:])
+
+	case "events":
+		if len(args) < 2 || args[1] != "watch" {
+			return errors.New("unknown events command, expected: events watch [topic]")
+		}
+
+		topic := ""
+		if len(args) > 2 {
+			topic = args[2]
+		}
+		return watchEvents(device, topic)
+	}
+
+	return errors.New("unknown command " + args[0])
+}
+
+// connect creates device from the flags and probes it
+func connect() (onvif.Device, error) {
+	if *flagXAddr == "" {
+		return onvif.Device{}, errors.New("-xaddr is required")
+	}
+
+	options := []onvif.DeviceOption{onvif.WithTimeout(*flagTimeout)}
+	if *flagInsecure {
+		options = append(options, onvif.WithInsecureSkipVerify())
+	}
+
+	return onvif.NewDevice(*flagXAddr, *flagUser, *flagPassword, options...)
+}
+
+// profileToken returns token of media profile specified in argument or by
+// -profile flag. If neither is specified, the first profile of camera is used.
+func profileToken(device onvif.Device, profile string) (string, error) {
+	if profile != "" {
+		return profile, nil
+	}
+
+	if *flagProfile != "" {
+		return *flagProfile, nil
+	}
+
+	profiles, err := device.GetProfiles()
+	if err != nil {
+		return "", err
+	}
+
+	if len(profiles) == 0 {
+		return "", errors.New("camera has no media profile")
+	}
+
+	return profiles[0].Token, nil
+}
+
+func runPTZ(device onvif.Device, args []string) error {
+	if len(args) == 0 {
+		return errors.New("ptz requires a command: move, stop or preset")
+	}
+
+	controller, err := onvif.NewPTZController(device, *flagProfile)
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "move":
+		if len(args) != 4 {
+			return errors.New("ptz move requires velocity: <x> <y> <zoom>")
+		}
+
+		velocity := onvif.PTZVector{}
+		for i, value := range []*float64{&velocity.Pan, &velocity.Tilt, &velocity.Zoom} {
+			if *value, err = strconv.ParseFloat(args[i+1], 64); err != nil {
+				return err
+			}
+		}
+
+		controller.Duration = *flagDuration
+		if err := controller.Move(velocity); err != nil {
+			return err
+		}
+
+		time.Sleep(*flagDuration)
+		return controller.Stop()
+
+	case "stop":
+		return controller.Stop()
+
+	case "preset":
+		return runPreset(device, controller.ProfileToken, args[1:])
+	}
+
+	return errors.New("unknown ptz command " + args[0])
+}
+
+func runPreset(device onvif.Device, profileToken string, args []string) error {
+	if len(args) == 0 {
+		return errors.New("ptz preset requires a command: list, goto, set or remove")
+	}
+
+	if args[0] == "list" {
+		presets, err := device.GetPresets(profileToken)
+		if err != nil {
+			return err
+		}
+		return printJSON(presets)
+	}
+
+	if len(args) != 2 {
+		return errors.New("ptz preset " + args[0] + " requires one argument")
+	}
+
+	switch args[0] {
+	case "goto":
+		return device.GotoPreset(profileToken, args[1])
+
+	case "set":
+		presetToken, err := device.SetPreset(profileToken, args[1], "")
+		if err != nil {
+			return err
+		}
+		fmt.Println(presetToken)
+		return nil
+
+	case "remove":
+		return device.RemovePreset(profileToken, args[1])
+	}
+
+	return errors.New("unknown ptz preset command " + args[0])
+}
+
+// watchEvents prints events of the camera until interrupted
+func watchEvents(device onvif.Device, topic string) error {
+	const terminationTime = time.Minute
+
+	subscription, err := device.CreatePullPointSubscription(topic, terminationTime)
+	if err != nil {
+		return err
+	}
+	defer device.Unsubscribe(subscription)
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+
+	renewed := time.Now()
+	for {
+		select {
+		case <-interrupt:
+			return nil
+		default:
+		}
+
+		if time.Since(renewed) > terminationTime/2 {
+			if err := device.Renew(subscription, terminationTime); err != nil {
+				return err
+			}
+			renewed = time.Now()
+		}
+
+		messages, err := device.PullMessages(subscription, 2*time.Second, 100)
+		if err != nil {
+			return err
+		}
+
+		for _, message := range messages {
+			if err := printJSON(message); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func printJSON(v interface{}) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
@@ -0,0 +1,833 @@
+// Command onvifgen generates typed client of an ONVIF service from its WSDL,
+// so operations and fields that aren't implemented by package onvif can be
+// used without writing SOAP bodies by hand.
+//
+// Usage:
+//
+//	onvifgen -wsdl <file> -package <name> [-out <file>]
+//
+// The official WSDL and XSD files can be downloaded from
+// https://www.onvif.org/profiles/specifications/. Schemas imported by the WSDL
+// are read from local files relative to it, so the directory layout of the
+// downloaded specification must be kept. Imports from remote locations aren't
+// downloaded, and types of those schemas are generated as RawXML.
+//
+// For each message element and each type it uses, a struct with encoding/xml
+// tags is generated, as well as Client with a method for each operation,
+// which sends the request through onvif.Device.Call, e.g.
+//
+//	//go:generate go run github.com/krabiswabbie/go-onvif/cmd/onvifgen -wsdl wsdl/ver20/ptz/wsdl/ptz.wsdl -package ptz -out ptz.go
+//
+//	nodes, err := ptz.Client{Device: device}.GetNodes(ptz.GetNodes{})
+//
+// Only the subset of XML Schema used by ONVIF is supported. Choices are
+// generated like sequences with optional elements, wildcards (any and
+// anyAttribute) are skipped, dates and durations are kept as strings, and
+// optional elements and attributes that contain zero value aren't sent.
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"flag"
+	"fmt"
+	"go/format"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+)
+
+const (
+	wsdlNamespace = "http://schemas.xmlsoap.org/wsdl/"
+	xsdNamespace  = "http://www.w3.org/2001/XMLSchema"
+	onvifImport   = "github.com/krabiswabbie/go-onvif"
+)
+
+var (
+	flagWSDL    = flag.String("wsdl", "", "WSDL file of the service")
+	flagPackage = flag.String("package", "", "name of the generated package")
+	flagOut     = flag.String("out", "", "output file, standard output if empty")
+)
+
+// builtinTypes are Go types of XML Schema built-in types,
+// other built-in types are generated as string
+var builtinTypes = map[string]string{
+	"boolean":            "bool",
+	"float":              "float64",
+	"double":             "float64",
+	"decimal":            "float64",
+	"int":                "int",
+	"integer":            "int",
+	"short":              "int",
+	"byte":               "int",
+	"nonNegativeInteger": "int",
+	"nonPositiveInteger": "int",
+	"positiveInteger":    "int",
+	"negativeInteger":    "int",
+	"unsignedInt":        "uint",
+	"unsignedShort":      "uint",
+	"unsignedByte":       "uint",
+	"long":               "int64",
+	"unsignedLong":       "uint64",
+}
+
+func main() {
+	flag.Parse()
+	if *flagWSDL == "" || *flagPackage == "" {
+		fmt.Fprintln(os.Stderr, "usage: onvifgen -wsdl <file> -package <name> [-out <file>]")
+		flag.PrintDefaults()
+		os.Exit(2)
+	}
+
+	source, warnings, err := generate(*flagWSDL, *flagPackage)
+	for _, warning := range warnings {
+		fmt.Fprintln(os.Stderr, "warning:", warning)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+
+	if *flagOut == "" {
+		os.Stdout.Write(source)
+		return
+	}
+
+	if err := ioutil.WriteFile(*flagOut, source, 0644); err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
+
+// qname is a qualified name of XML element or type
+type qname struct {
+	Space string
+	Local string
+}
+
+// node is an element of WSDL or XSD file. Unlike encoding/xml structs,
+// it keeps order of children and the namespace prefixes in scope,
+// which are needed to resolve type references.
+type node struct {
+	name     xml.Name
+	attrs    map[string]string
+	prefixes map[string]string
+	children []*node
+	text     string
+}
+
+// parseFile parses XML file into tree of nodes
+func parseFile(path string) (*node, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	root := &node{prefixes: map[string]string{}}
+	stack := []*node{root}
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", path, err)
+		}
+
+		parent := stack[len(stack)-1]
+		switch element := token.(type) {
+		case xml.StartElement:
+			child := &node{name: element.Name, attrs: map[string]string{}, prefixes: map[string]string{}}
+			for prefix, space := range parent.prefixes {
+				child.prefixes[prefix] = space
+			}
+
+			for _, attr := range element.Attr {
+				switch {
+				case attr.Name.Space == "xmlns":
+					child.prefixes[attr.Name.Local] = attr.Value
+				case attr.Name.Space == "" && attr.Name.Local == "xmlns":
+					child.prefixes[""] = attr.Value
+				case attr.Name.Space == "":
+					child.attrs[attr.Name.Local] = attr.Value
+				}
+			}
+
+			parent.children = append(parent.children, child)
+			stack = append(stack, child)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			parent.text += string(element)
+		}
+	}
+
+	if len(root.children) == 0 {
+		return nil, fmt.Errorf("%s doesn't contain XML document", path)
+	}
+
+	return root.children[0], nil
+}
+
+// all returns children with the specified name
+func (n *node) all(space, local string) []*node {
+	children := []*node{}
+	for _, child := range n.children {
+		if child.name.Space == space && child.name.Local == local {
+			children = append(children, child)
+		}
+	}
+	return children
+}
+
+// first returns the first child with the specified name, or nil
+func (n *node) first(space, local string) *node {
+	if children := n.all(space, local); len(children) > 0 {
+		return children[0]
+	}
+	return nil
+}
+
+// resolve resolves prefixed name, e.g. tt:PTZNode, using prefixes in scope
+func (n *node) resolve(name string) qname {
+	prefix, local := "", name
+	if i := strings.Index(name, ":"); i >= 0 {
+		prefix, local = name[:i], name[i+1:]
+	}
+	return qname{Space: n.prefixes[prefix], Local: local}
+}
+
+// decl is a declaration of XML Schema together with its schema
+type decl struct {
+	node      *node
+	space     string
+	qualified bool
+}
+
+// field is a field of generated struct
+type field struct {
+	name   string
+	goType string
+	tag    string
+}
+
+// generator generates Go source of typed client from WSDL
+type generator struct {
+	elements        map[qname]decl
+	complexTypes    map[qname]decl
+	simpleTypes     map[qname]decl
+	groups          map[qname]decl
+	attributeGroups map[qname]decl
+	loaded          map[string]bool
+
+	names     map[string]bool
+	structs   map[string]bool
+	typeNames map[qname]string
+	queue     []func()
+	types     bytes.Buffer
+	usesRaw   bool
+	warnings  []string
+}
+
+// generate generates source of package with typed client of the service described by WSDL file
+func generate(wsdlPath string, packageName string) ([]byte, []string, error) {
+	definitions, err := parseFile(wsdlPath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if definitions.name != (xml.Name{Space: wsdlNamespace, Local: "definitions"}) {
+		return nil, nil, fmt.Errorf("%s isn't WSDL 1.1 document", wsdlPath)
+	}
+
+	gen := &generator{
+		elements:        map[qname]decl{},
+		complexTypes:    map[qname]decl{},
+		simpleTypes:     map[qname]decl{},
+		groups:          map[qname]decl{},
+		attributeGroups: map[qname]decl{},
+		loaded:          map[string]bool{},
+		names:           map[string]bool{"Client": true, "Namespace": true, "RawXML": true},
+		structs:         map[string]bool{"RawXML": true},
+		typeNames:       map[qname]string{},
+	}
+
+	if types := definitions.first(wsdlNamespace, "types"); types != nil {
+		for _, schema := range types.all(xsdNamespace, "schema") {
+			gen.loadSchema(schema, filepath.Dir(wsdlPath))
+		}
+	}
+
+	operations, err := gen.operations(definitions)
+	if err != nil {
+		return nil, gen.warnings, err
+	}
+
+	// Message structs are named first, so they keep the names of their elements
+	for i := range operations {
+		operations[i].input = gen.messageStruct(operations[i].inputElement)
+		operations[i].output = gen.messageStruct(operations[i].outputElement)
+	}
+
+	for len(gen.queue) > 0 {
+		next := gen.queue[0]
+		gen.queue = gen.queue[1:]
+		next()
+	}
+
+	source := &bytes.Buffer{}
+	fmt.Fprintf(source, "// Code generated by onvifgen from %s. DO NOT EDIT.\n\n", filepath.Base(wsdlPath))
+	fmt.Fprintf(source, "// Package %s is a typed client of ONVIF service %s.\n", packageName, definitions.attrs["targetNamespace"])
+	fmt.Fprintf(source, "package %s\n\n", packageName)
+	fmt.Fprintf(source, "import (\n\t\"encoding/xml\"\n\n\tonvif %q\n)\n\n", onvifImport)
+	fmt.Fprintf(source, "// Namespace is the namespace of the service\n")
+	fmt.Fprintf(source, "const Namespace = %q\n\n", definitions.attrs["targetNamespace"])
+	fmt.Fprintf(source, "// Client sends requests to the service of ONVIF camera\n")
+	fmt.Fprintf(source, "type Client struct {\n\tDevice onvif.Device\n}\n\n")
+
+	for _, operation := range operations {
+		writeComment(source, operation.method+" sends "+operation.input+" request.", operation.doc)
+		if operation.output == "" {
+			fmt.Fprintf(source, "func (client Client) %s(request %s) error {\n", operation.method, operation.input)
+			fmt.Fprintf(source, "\treturn client.Device.Call(Namespace, request, nil)\n}\n\n")
+			continue
+		}
+
+		fmt.Fprintf(source, "func (client Client) %s(request %s) (%s, error) {\n", operation.method, operation.input, operation.output)
+		fmt.Fprintf(source, "\tvar response %s\n", operation.output)
+		fmt.Fprintf(source, "\terr := client.Device.Call(Namespace, request, &response)\n")
+		fmt.Fprintf(source, "\treturn response, err\n}\n\n")
+	}
+
+	source.Write(gen.types.Bytes())
+
+	if gen.usesRaw {
+		fmt.Fprintf(source, "// RawXML is an element whose type isn't generated, e.g. from remote schema\n")
+		fmt.Fprintf(source, "type RawXML struct {\n\tXMLName xml.Name\n\tAttrs []xml.Attr `xml:\",any,attr\"`\n\tContent string `xml:\",innerxml\"`\n}\n")
+	}
+
+	formatted, err := format.Source(source.Bytes())
+	if err != nil {
+		return nil, gen.warnings, fmt.Errorf("generated invalid source: %v", err)
+	}
+
+	return formatted, gen.warnings, nil
+}
+
+// operation is an operation of WSDL port type
+type operation struct {
+	method        string
+	doc           string
+	inputElement  qname
+	outputElement qname
+	input         string
+	output        string
+}
+
+// operations returns operations of all port types of WSDL
+func (gen *generator) operations(definitions *node) ([]operation, error) {
+	// Messages of document/literal style contain a single part, which refers to element
+	messages := map[qname]qname{}
+	targetNamespace := definitions.attrs["targetNamespace"]
+	for _, message := range definitions.all(wsdlNamespace, "message") {
+		parts := message.all(wsdlNamespace, "part")
+		if len(parts) != 1 || parts[0].attrs["element"] == "" {
+			gen.warn("message %s isn't document/literal, it's skipped", message.attrs["name"])
+			continue
+		}
+		messages[qname{targetNamespace, message.attrs["name"]}] = parts[0].resolve(parts[0].attrs["element"])
+	}
+
+	operations := []operation{}
+	methods := map[string]bool{}
+	for _, portType := range definitions.all(wsdlNamespace, "portType") {
+		for _, op := range portType.all(wsdlNamespace, "operation") {
+			name := op.attrs["name"]
+			input := op.first(wsdlNamespace, "input")
+			if input == nil {
+				gen.warn("operation %s doesn't have input, it's skipped", name)
+				continue
+			}
+
+			inputElement, ok := messages[input.resolve(input.attrs["message"])]
+			if !ok {
+				gen.warn("input of operation %s isn't supported, it's skipped", name)
+				continue
+			}
+
+			method := exportedName(name)
+			if methods[method] {
+				gen.warn("operation %s is defined more than once, it's skipped", name)
+				continue
+			}
+			methods[method] = true
+
+			operation := operation{method: method, inputElement: inputElement}
+			if doc := op.first(wsdlNamespace, "documentation"); doc != nil {
+				operation.doc = doc.text
+			}
+
+			if output := op.first(wsdlNamespace, "output"); output != nil {
+				if operation.outputElement, ok = messages[output.resolve(output.attrs["message"])]; !ok {
+					gen.warn("output of operation %s isn't supported, it's skipped", name)
+					continue
+				}
+			}
+
+			operations = append(operations, operation)
+		}
+	}
+
+	if len(operations) == 0 {
+		return nil, errors.New("WSDL doesn't contain any supported operation")
+	}
+
+	return operations, nil
+}
+
+// loadSchema registers declarations of schema and the schemas it imports
+func (gen *generator) loadSchema(schema *node, dir string) {
+	space := schema.attrs["targetNamespace"]
+	qualified := schema.attrs["elementFormDefault"] == "qualified"
+	for _, child := range schema.children {
+		if child.name.Space != xsdNamespace {
+			continue
+		}
+
+		name := qname{space, child.attrs["name"]}
+		switch child.name.Local {
+		case "element":
+			gen.elements[name] = decl{child, space, qualified}
+		case "complexType":
+			gen.complexTypes[name] = decl{child, space, qualified}
+		case "simpleType":
+			gen.simpleTypes[name] = decl{child, space, qualified}
+		case "group":
+			gen.groups[name] = decl{child, space, qualified}
+		case "attributeGroup":
+			gen.attributeGroups[name] = decl{child, space, qualified}
+		case "import", "include":
+			location := child.attrs["schemaLocation"]
+			if location == "" {
+				continue
+			}
+
+			if strings.Contains(location, "://") {
+				gen.warn("remote schema %s isn't loaded", location)
+				continue
+			}
+
+			path, err := filepath.Abs(filepath.Join(dir, filepath.FromSlash(location)))
+			if err != nil || gen.loaded[path] {
+				continue
+			}
+			gen.loaded[path] = true
+
+			imported, err := parseFile(path)
+			if err != nil {
+				gen.warn("schema %s isn't loaded: %v", location, err)
+				continue
+			}
+			gen.loadSchema(imported, filepath.Dir(path))
+		}
+	}
+}
+
+// messageStruct generates struct of message element and returns its name
+func (gen *generator) messageStruct(element qname) string {
+	if element == (qname{}) {
+		return ""
+	}
+
+	if name, ok := gen.typeNames[element]; ok {
+		return name
+	}
+
+	name := gen.uniqueName(exportedName(element.Local))
+	gen.typeNames[element] = name
+	gen.structs[name] = true
+
+	elementDecl, ok := gen.elements[element]
+	if !ok {
+		gen.warn("element %s isn't declared, its struct is empty", element.Local)
+	}
+
+	gen.queue = append(gen.queue, func() {
+		fields := []field{{name: "XMLName", goType: "xml.Name", tag: element.Space + " " + element.Local}}
+		if ok {
+			fields = append(fields, gen.elementFields(name, elementDecl)...)
+		}
+		gen.writeStruct(name, "is the "+element.Local+" element", fields)
+	})
+
+	return name
+}
+
+// elementFields returns fields of struct of top-level element
+func (gen *generator) elementFields(name string, element decl) []field {
+	if complexType := element.node.first(xsdNamespace, "complexType"); complexType != nil {
+		return gen.complexFields(name, decl{complexType, element.space, element.qualified})
+	}
+
+	typeName := element.node.resolve(element.node.attrs["type"])
+	if complexType, ok := gen.complexTypes[typeName]; ok {
+		return gen.complexFields(name, complexType)
+	}
+
+	if element.node.attrs["type"] != "" {
+		return []field{{name: "Value", goType: gen.goType(typeName), tag: ",chardata"}}
+	}
+
+	return nil
+}
+
+// complexFields returns fields of struct of complex type
+func (gen *generator) complexFields(name string, complexType decl) []field {
+	fields := []field{}
+	for _, child := range complexType.node.children {
+		if child.name.Space != xsdNamespace {
+			continue
+		}
+
+		switch child.name.Local {
+		case "complexContent":
+			for _, derivation := range child.children {
+				if derivation.name.Local == "extension" {
+					base := derivation.resolve(derivation.attrs["base"])
+					if baseType, ok := gen.complexTypes[base]; ok {
+						fields = append(fields, gen.complexFields(name, baseType)...)
+					} else if base.Space != xsdNamespace {
+						gen.warn("base type %s of %s isn't declared", base.Local, name)
+					}
+				}
+				fields = append(fields, gen.complexFields(name, decl{derivation, complexType.space, complexType.qualified})...)
+			}
+		case "simpleContent":
+			for _, derivation := range child.children {
+				base := derivation.resolve(derivation.attrs["base"])
+				if baseType, ok := gen.complexTypes[base]; ok {
+					fields = append(fields, gen.complexFields(name, baseType)...)
+				} else {
+					fields = append(fields, field{name: "Value", goType: gen.goType(base), tag: ",chardata"})
+				}
+				fields = append(fields, gen.complexFields(name, decl{derivation, complexType.space, complexType.qualified})...)
+			}
+		case "sequence", "choice", "all", "group":
+			fields = append(fields, gen.particleFields(name, complexType, child, false, false)...)
+		case "attribute", "attributeGroup":
+			fields = append(fields, gen.attributeFields(name, complexType, child)...)
+		}
+	}
+
+	return uniqueFields(fields)
+}
+
+// particleFields returns fields of element, sequence, choice or group.
+// Elements inside of optional or repeated particle are optional or repeated too.
+func (gen *generator) particleFields(name string, scope decl, particle *node, optional, repeated bool) []field {
+	optional = optional || particle.attrs["minOccurs"] == "0" || particle.name.Local == "choice"
+	repeated = repeated || (particle.attrs["maxOccurs"] != "" && particle.attrs["maxOccurs"] != "1")
+
+	switch particle.name.Local {
+	case "element":
+		return []field{gen.elementField(name, scope, particle, optional, repeated)}
+	case "group":
+		group, ok := gen.groups[particle.resolve(particle.attrs["ref"])]
+		if !ok {
+			gen.warn("group %s of %s isn't declared", particle.attrs["ref"], name)
+			return nil
+		}
+		particle, scope = group.node, group
+	}
+
+	fields := []field{}
+	for _, child := range particle.children {
+		switch child.name.Local {
+		case "element", "sequence", "choice", "all", "group":
+			if child.name.Space == xsdNamespace {
+				fields = append(fields, gen.particleFields(name, scope, child, optional, repeated)...)
+			}
+		}
+	}
+
+	return fields
+}
+
+// elementField returns field of local element or element reference
+func (gen *generator) elementField(name string, scope decl, element *node, optional, repeated bool) field {
+	elementName := qname{Local: element.attrs["name"]}
+	if scope.qualified || element.attrs["form"] == "qualified" {
+		elementName.Space = scope.space
+	}
+
+	if ref := element.attrs["ref"]; ref != "" {
+		elementName = element.resolve(ref)
+		if referenced, ok := gen.elements[elementName]; ok {
+			element, scope = referenced.node, referenced
+		} else {
+			gen.warn("element %s of %s isn't declared", ref, name)
+		}
+	}
+
+	fieldName := exportedName(elementName.Local)
+	goType := ""
+	switch {
+	case element.first(xsdNamespace, "complexType") != nil:
+		goType = gen.anonymousStruct(name+fieldName, decl{element.first(xsdNamespace, "complexType"), scope.space, scope.qualified})
+	case element.first(xsdNamespace, "simpleType") != nil:
+		goType = gen.simpleGoType(name+fieldName, element.first(xsdNamespace, "simpleType"))
+	case element.attrs["type"] != "":
+		goType = gen.goType(element.resolve(element.attrs["type"]))
+	default:
+		goType = gen.goType(qname{xsdNamespace, "string"})
+	}
+
+	tag := elementName.Local
+	if elementName.Space != "" {
+		tag = elementName.Space + " " + tag
+	}
+
+	switch {
+	case repeated:
+		goType = "[]" + goType
+		tag += ",omitempty"
+	case optional && gen.structs[goType]:
+		goType = "*" + goType
+		tag += ",omitempty"
+	case optional:
+		tag += ",omitempty"
+	}
+
+	return field{name: fieldName, goType: goType, tag: tag}
+}
+
+// attributeFields returns fields of attribute or attribute group
+func (gen *generator) attributeFields(name string, scope decl, attribute *node) []field {
+	if attribute.name.Local == "attributeGroup" {
+		group, ok := gen.attributeGroups[attribute.resolve(attribute.attrs["ref"])]
+		if !ok {
+			gen.warn("attribute group %s of %s isn't declared", attribute.attrs["ref"], name)
+			return nil
+		}
+
+		fields := []field{}
+		for _, child := range group.node.children {
+			if child.name.Space == xsdNamespace && (child.name.Local == "attribute" || child.name.Local == "attributeGroup") {
+				fields = append(fields, gen.attributeFields(name, group, child)...)
+			}
+		}
+		return fields
+	}
+
+	// References are attributes of other namespaces, e.g. xml:lang
+	attributeName := attribute.attrs["name"]
+	if attributeName == "" {
+		return nil
+	}
+
+	fieldName := exportedName(attributeName)
+	goType := gen.goType(qname{xsdNamespace, "string"})
+	if simpleType := attribute.first(xsdNamespace, "simpleType"); simpleType != nil {
+		goType = gen.simpleGoType(name+fieldName, simpleType)
+	} else if attribute.attrs["type"] != "" {
+		goType = gen.goType(attribute.resolve(attribute.attrs["type"]))
+	}
+
+	tag := attributeName + ",attr"
+	if attribute.attrs["use"] != "required" {
+		tag += ",omitempty"
+	}
+
+	return []field{{name: fieldName, goType: goType, tag: tag}}
+}
+
+// goType returns Go type of XML Schema type, generating it if needed
+func (gen *generator) goType(typeName qname) string {
+	if typeName.Space == xsdNamespace {
+		if goType, ok := builtinTypes[typeName.Local]; ok {
+			return goType
+		}
+		return "string"
+	}
+
+	if name, ok := gen.typeNames[typeName]; ok {
+		return name
+	}
+
+	if complexType, ok := gen.complexTypes[typeName]; ok {
+		name := gen.uniqueName(exportedName(typeName.Local))
+		gen.typeNames[typeName] = name
+		gen.structs[name] = true
+		gen.queue = append(gen.queue, func() {
+			gen.writeStruct(name, "is the "+typeName.Local+" type", gen.complexFields(name, complexType))
+		})
+		return name
+	}
+
+	if simpleType, ok := gen.simpleTypes[typeName]; ok {
+		name := gen.uniqueName(exportedName(typeName.Local))
+		gen.typeNames[typeName] = name
+		gen.queue = append(gen.queue, func() {
+			gen.writeSimpleType(name, typeName.Local, simpleType.node)
+		})
+		return name
+	}
+
+	gen.warn("type %s isn't declared, it's generated as RawXML", typeName.Local)
+	gen.typeNames[typeName] = "RawXML"
+	gen.usesRaw = true
+	return "RawXML"
+}
+
+// anonymousStruct generates struct of complex type declared inside of element
+func (gen *generator) anonymousStruct(name string, complexType decl) string {
+	name = gen.uniqueName(name)
+	gen.structs[name] = true
+	gen.queue = append(gen.queue, func() {
+		gen.writeStruct(name, "is an anonymous type", gen.complexFields(name, complexType))
+	})
+	return name
+}
+
+// simpleGoType returns Go type of simple type declared inside of element or attribute
+func (gen *generator) simpleGoType(name string, simpleType *node) string {
+	restriction := simpleType.first(xsdNamespace, "restriction")
+	if restriction == nil {
+		return "string"
+	}
+
+	if len(restriction.all(xsdNamespace, "enumeration")) == 0 {
+		return gen.goType(restriction.resolve(restriction.attrs["base"]))
+	}
+
+	name = gen.uniqueName(name)
+	gen.queue = append(gen.queue, func() {
+		gen.writeSimpleType(name, "an anonymous", simpleType)
+	})
+	return name
+}
+
+// writeStruct writes declaration of struct
+func (gen *generator) writeStruct(name, description string, fields []field) {
+	fmt.Fprintf(&gen.types, "// %s %s\n", name, description)
+	fmt.Fprintf(&gen.types, "type %s struct {\n", name)
+	for _, field := range fields {
+		fmt.Fprintf(&gen.types, "\t%s %s `xml:%q`\n", field.name, field.goType, field.tag)
+	}
+	fmt.Fprintf(&gen.types, "}\n\n")
+}
+
+// writeSimpleType writes declaration of simple type and constants of its enumeration
+func (gen *generator) writeSimpleType(name, typeName string, simpleType *node) {
+	goType := "string"
+	restriction := simpleType.first(xsdNamespace, "restriction")
+	if restriction != nil {
+		goType = gen.goType(restriction.resolve(restriction.attrs["base"]))
+	}
+
+	fmt.Fprintf(&gen.types, "// %s is the %s type\n", name, typeName)
+	fmt.Fprintf(&gen.types, "type %s %s\n\n", name, goType)
+
+	if restriction == nil || len(restriction.all(xsdNamespace, "enumeration")) == 0 {
+		return
+	}
+
+	fmt.Fprintf(&gen.types, "// Values of %s\nconst (\n", name)
+	for _, enumeration := range restriction.all(xsdNamespace, "enumeration") {
+		value := enumeration.attrs["value"]
+		constant := exportedName(value)
+		if constant == "" {
+			continue
+		}
+
+		constant = gen.uniqueName(name + constant)
+		if goType == "string" || !builtinGoType(goType) {
+			fmt.Fprintf(&gen.types, "\t%s %s = %q\n", constant, name, value)
+		} else {
+			fmt.Fprintf(&gen.types, "\t%s %s = %s\n", constant, name, value)
+		}
+	}
+	fmt.Fprintf(&gen.types, ")\n\n")
+}
+
+// uniqueName returns name that isn't used by other generated declarations
+func (gen *generator) uniqueName(name string) string {
+	unique := name
+	for i := 2; gen.names[unique]; i++ {
+		unique = fmt.Sprintf("%s%d", name, i)
+	}
+
+	gen.names[unique] = true
+	return unique
+}
+
+func (gen *generator) warn(format string, args ...interface{}) {
+	gen.warnings = append(gen.warnings, fmt.Sprintf(format, args...))
+}
+
+// uniqueFields renames fields with the same name, e.g. element and attribute
+func uniqueFields(fields []field) []field {
+	names := map[string]bool{}
+	for i := range fields {
+		name := fields[i].name
+		for n := 2; names[fields[i].name]; n++ {
+			fields[i].name = fmt.Sprintf("%s%d", name, n)
+		}
+		names[fields[i].name] = true
+	}
+	return fields
+}
+
+// builtinGoType reports whether type is a predeclared Go type
+func builtinGoType(goType string) bool {
+	switch goType {
+	case "string", "bool", "int", "int64", "uint", "uint64", "float64":
+		return true
+	}
+	return false
+}
+
+// exportedName converts XML name, e.g. ptz-node or PanTilt.Limits, to exported Go name
+func exportedName(name string) string {
+	parts := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	result := ""
+	for _, part := range parts {
+		runes := []rune(part)
+		runes[0] = unicode.ToUpper(runes[0])
+		result += string(runes)
+	}
+
+	if result != "" && !unicode.IsLetter([]rune(result)[0]) {
+		result = "X" + result
+	}
+	return result
+}
+
+// writeComment writes doc comment that contains summary and the documentation of WSDL
+func writeComment(buffer *bytes.Buffer, summary string, documentation string) {
+	fmt.Fprintf(buffer, "// %s\n", summary)
+
+	words := strings.Fields(documentation)
+	if len(words) == 0 {
+		return
+	}
+
+	fmt.Fprintf(buffer, "//\n")
+	line := "//"
+	for _, word := range words {
+		if len(line)+len(word) > 78 && line != "//" {
+			fmt.Fprintln(buffer, line)
+			line = "//"
+		}
+		line += " " + word
+	}
+	fmt.Fprintln(buffer, line)
+}
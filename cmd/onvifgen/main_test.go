@@ -0,0 +1,58 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+	source, warnings, err := generate("testdata/wsdl/ptz.wsdl", "ptz")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, declaration := range []string{
+		"package ptz",
+		`const Namespace = "http://www.onvif.org/ver20/ptz/wsdl"`,
+		"func (client Client) GetNodes(request GetNodes) (GetNodesResponse, error) {",
+		"// Get the descriptions of the available PTZ Nodes.",
+		"`xml:\"http://www.onvif.org/ver20/ptz/wsdl GotoPreset\"`",
+		"Speed        *PTZSpeed      `xml:\"http://www.onvif.org/ver20/ptz/wsdl Speed,omitempty\"`",
+		"PTZNode []PTZNode `xml:\"http://www.onvif.org/ver20/ptz/wsdl PTZNode,omitempty\"`",
+		// Base type, included schema and attributes
+		"Token                  ReferenceToken  `xml:\"token,attr\"`",
+		"FixedHomePosition      bool            `xml:\"FixedHomePosition,attr,omitempty\"`",
+		"Min float64 `xml:\"http://www.onvif.org/ver10/schema Min\"`",
+		// Enumeration
+		"type MoveStatus string",
+		`MoveStatusMOVING  MoveStatus = "MOVING"`,
+		// Type of remote schema
+		"Thumbnail              *RawXML",
+		"type RawXML struct {",
+	} {
+		if !strings.Contains(string(source), declaration) {
+			t.Errorf("generated source doesn't contain %s", declaration)
+		}
+	}
+
+	if len(warnings) != 2 || !strings.Contains(warnings[0], "remote schema") {
+		t.Errorf("unexpected warnings %q", warnings)
+	}
+
+	if _, _, err := generate("testdata/schema/onvif.xsd", "ptz"); err == nil {
+		t.Error("expected error on file that isn't WSDL")
+	}
+}
+
+func TestExportedName(t *testing.T) {
+	for name, expected := range map[string]string{
+		"token":          "Token",
+		"PanTilt.Limits": "PanTiltLimits",
+		"ptz-node":       "PtzNode",
+		"3DSpace":        "X3DSpace",
+	} {
+		if result := exportedName(name); result != expected {
+			t.Errorf("exportedName(%q) = %q, want %q", name, result, expected)
+		}
+	}
+}
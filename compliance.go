@@ -0,0 +1,339 @@
+package onvif
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+const media2Namespace = "http://www.onvif.org/ver20/media/wsdl"
+
+var media2XMLNs = []string{
+	`xmlns:tr2="http://www.onvif.org/ver20/media/wsdl"`,
+	`xmlns:tt="http://www.onvif.org/ver10/schema"`,
+}
+
+// ConformanceProfile is an ONVIF profile, i.e. a set of features that
+// conformant camera must support
+type ConformanceProfile string
+
+// ONVIF profiles that can be checked by CheckProfile
+const (
+	ProfileS ConformanceProfile = "S"
+	ProfileT ConformanceProfile = "T"
+	ProfileG ConformanceProfile = "G"
+)
+
+// profileScopes are the scopes that declare conformance to each profile
+var profileScopes = map[ConformanceProfile]string{
+	ProfileS: "onvif://www.onvif.org/Profile/Streaming",
+	ProfileT: "onvif://www.onvif.org/Profile/T",
+	ProfileG: "onvif://www.onvif.org/Profile/G",
+}
+
+var (
+	errUnknownProfile = errors.New("Unknown ONVIF profile")
+	errNoService      = errors.New("Service is not provided by device")
+	errNoPTZProfile   = errors.New("No media profile has PTZ configuration")
+	errNoStreamURI    = errors.New("Stream URI is empty")
+)
+
+// profileCheck is a feature of a profile, checked by sending read-only requests
+type profileCheck struct {
+	name      string
+	mandatory bool
+	check     func(*profileChecker) error
+}
+
+// profileChecker contains the device being checked and the data shared by the checks
+type profileChecker struct {
+	device   Device
+	profiles []MediaProfile
+}
+
+// CheckProfile checks whether ONVIF camera conforms to the profile, e.g. before
+// it's deployed. Each feature of the profile is probed with read-only requests,
+// except that event subscription is created and removed. The report lists each
+// feature and whether it's supported. It's only a quick qualification, which
+// doesn't replace the official ONVIF conformance test.
+func CheckProfile(device Device, profile ConformanceProfile) (ProfileReport, error) {
+	checks, ok := profileChecks[profile]
+	if !ok {
+		return ProfileReport{}, errUnknownProfile
+	}
+
+	report := ProfileReport{Profile: profile}
+	checker := &profileChecker{device: device}
+
+	// Device management is required by every profile
+	checks = append([]profileCheck{
+		{"Device information", true, func(checker *profileChecker) (err error) {
+			report.Device, err = checker.device.GetInformation()
+			return err
+		}},
+		{"Services", true, func(checker *profileChecker) error {
+			return checker.device.UpdateServices()
+		}},
+		{"Scopes", true, func(checker *profileChecker) error {
+			scopes, err := checker.device.GetScopes()
+			for _, scope := range scopes {
+				report.Declared = report.Declared || strings.EqualFold(scope, profileScopes[profile])
+			}
+			return err
+		}},
+		{"Discovery mode", true, func(checker *profileChecker) error {
+			_, err := checker.device.GetDiscoveryMode()
+			return err
+		}},
+		{"Hostname", true, func(checker *profileChecker) error {
+			_, err := checker.device.GetHostname()
+			return err
+		}},
+	}, checks...)
+
+	for _, check := range checks {
+		feature := FeatureCheck{Name: check.name, Mandatory: check.mandatory, Supported: true}
+		if err := check.check(checker); err != nil {
+			feature.Supported = false
+			feature.Error = err.Error()
+		}
+
+		report.Features = append(report.Features, feature)
+	}
+
+	return report, nil
+}
+
+// Compliant reports whether all mandatory features of the profile are supported
+func (report ProfileReport) Compliant() bool {
+	return len(report.Missing()) == 0
+}
+
+// Missing returns mandatory features of the profile that aren't supported
+func (report ProfileReport) Missing() []FeatureCheck {
+	missing := []FeatureCheck{}
+	for _, feature := range report.Features {
+		if feature.Mandatory && !feature.Supported {
+			missing = append(missing, feature)
+		}
+	}
+
+	return missing
+}
+
+// profileChecks are the features of each profile, besides device management
+var profileChecks = map[ConformanceProfile][]profileCheck{
+	ProfileS: {
+		{"Media profiles", true, (*profileChecker).checkMediaProfiles},
+		{"Stream URI", true, (*profileChecker).checkStreamURI},
+		{"Video sources", true, (*profileChecker).checkVideoSources},
+		{"Event subscription", true, (*profileChecker).checkEvents},
+		{"Snapshot URI", false, (*profileChecker).checkSnapshotURI},
+		{"PTZ", false, (*profileChecker).checkPTZ},
+		{"Audio outputs", false, (*profileChecker).checkAudioOutputs},
+		{"Relay outputs", false, (*profileChecker).checkRelayOutputs},
+	},
+	ProfileT: {
+		{"Media2 service", true, serviceCheck(media2Namespace)},
+		{"Imaging service", true, serviceCheck(imagingNamespace)},
+		{"Media2 profiles", true, (*profileChecker).checkMedia2Profiles},
+		{"Media2 stream URI", true, (*profileChecker).checkMedia2StreamURI},
+		{"Event subscription", true, (*profileChecker).checkEvents},
+		{"Video sources", false, (*profileChecker).checkVideoSources},
+		{"Snapshot URI", false, (*profileChecker).checkSnapshotURI},
+		{"PTZ", false, (*profileChecker).checkPTZ},
+		{"Audio outputs", false, (*profileChecker).checkAudioOutputs},
+		{"Relay outputs", false, (*profileChecker).checkRelayOutputs},
+	},
+	ProfileG: {
+		{"Recording service", true, serviceCheck(recordingNamespace)},
+		{"Recordings", true, (*profileChecker).checkRecordings},
+		{"Recording search", true, (*profileChecker).checkRecordingSearch},
+		{"Replay", true, (*profileChecker).checkReplay},
+		{"Event subscription", true, (*profileChecker).checkEvents},
+		{"Recording jobs", false, (*profileChecker).checkRecordingJobs},
+		{"Storage configurations", false, (*profileChecker).checkStorageConfigurations},
+	},
+}
+
+// serviceCheck creates check of whether the service is provided by device
+func serviceCheck(namespace string) func(*profileChecker) error {
+	return func(checker *profileChecker) error {
+		if checker.device.Services[namespace] == "" {
+			return errNoService
+		}
+		return nil
+	}
+}
+
+func (checker *profileChecker) checkMediaProfiles() error {
+	profiles, err := checker.device.GetProfiles()
+	if err != nil {
+		return err
+	}
+
+	if len(profiles) == 0 {
+		return errNoMediaProfile
+	}
+
+	checker.profiles = profiles
+	return nil
+}
+
+func (checker *profileChecker) checkStreamURI() error {
+	if len(checker.profiles) == 0 {
+		return errNoMediaProfile
+	}
+
+	_, err := checker.device.GetStreamURI(checker.profiles[0].Token, "RTSP")
+	return err
+}
+
+func (checker *profileChecker) checkSnapshotURI() error {
+	if len(checker.profiles) == 0 {
+		return errNoMediaProfile
+	}
+
+	_, err := checker.device.GetSnapshotURI(checker.profiles[0].Token)
+	return err
+}
+
+// checkMedia2Profiles fetch media profiles from Media2 service, which is
+// required by Profile T instead of Media service
+func (checker *profileChecker) checkMedia2Profiles() error {
+	// Create SOAP
+	soap := SOAP{
+		XMLNs: media2XMLNs,
+		Body: `<tr2:GetProfiles>
+			<tr2:Type>All</tr2:Type>
+		</tr2:GetProfiles>`,
+	}
+
+	// Send SOAP request
+	response, err := checker.device.sendRequest(media2Namespace, soap)
+	if err != nil {
+		return err
+	}
+
+	// Parse response to interface
+	ifaceProfiles, _ := response.ValuesForPath("Envelope.Body.GetProfilesResponse.Profiles")
+
+	// Convert interface to array of media profile. Only the data used
+	// by the other checks is parsed.
+	profiles := []MediaProfile{}
+	for _, ifaceProfile := range ifaceProfiles {
+		if mapProfile, ok := ifaceProfile.(map[string]interface{}); ok {
+			profile := MediaProfile{}
+			profile.Token = interfaceToString(mapProfile["-token"])
+			profile.Name = interfaceToString(mapProfile["Name"])
+			if mapConfigs, ok := mapProfile["Configurations"].(map[string]interface{}); ok {
+				if mapPTZ, ok := mapConfigs["PTZ"].(map[string]interface{}); ok {
+					profile.PTZConfig.Token = interfaceToString(mapPTZ["-token"])
+				}
+			}
+
+			profiles = append(profiles, profile)
+		}
+	}
+
+	if len(profiles) == 0 {
+		return errNoMediaProfile
+	}
+
+	checker.profiles = profiles
+	return nil
+}
+
+func (checker *profileChecker) checkMedia2StreamURI() error {
+	if len(checker.profiles) == 0 {
+		return errNoMediaProfile
+	}
+
+	// Create SOAP
+	soap := SOAP{
+		XMLNs: media2XMLNs,
+		Body: `<tr2:GetStreamUri>
+			<tr2:Protocol>RTSP</tr2:Protocol>
+			<tr2:ProfileToken>` + escapeXML(checker.profiles[0].Token) + `</tr2:ProfileToken>
+		</tr2:GetStreamUri>`,
+	}
+
+	// Send SOAP request
+	response, err := checker.device.sendRequest(media2Namespace, soap)
+	if err != nil {
+		return err
+	}
+
+	// Parse response
+	uri, _ := response.ValueForPathString("Envelope.Body.GetStreamUriResponse.Uri")
+	if uri == "" {
+		return errNoStreamURI
+	}
+
+	return nil
+}
+
+func (checker *profileChecker) checkVideoSources() error {
+	_, err := checker.device.GetVideoSources()
+	return err
+}
+
+func (checker *profileChecker) checkEvents() error {
+	subscription, err := checker.device.CreatePullPointSubscription("", time.Minute)
+	if err != nil {
+		return err
+	}
+
+	return checker.device.Unsubscribe(subscription)
+}
+
+func (checker *profileChecker) checkPTZ() error {
+	for _, profile := range checker.profiles {
+		if profile.PTZConfig.Token != "" {
+			_, err := checker.device.GetPresets(profile.Token)
+			return err
+		}
+	}
+
+	return errNoPTZProfile
+}
+
+func (checker *profileChecker) checkAudioOutputs() error {
+	_, err := checker.device.GetAudioOutputs()
+	return err
+}
+
+func (checker *profileChecker) checkRelayOutputs() error {
+	_, err := checker.device.GetRelayOutputs()
+	return err
+}
+
+func (checker *profileChecker) checkRecordings() error {
+	_, err := checker.device.GetRecordings()
+	return err
+}
+
+func (checker *profileChecker) checkRecordingSearch() error {
+	searchToken, err := checker.device.FindRecordings(SearchScope{}, 1, 10*time.Second)
+	if err != nil {
+		return err
+	}
+
+	_, err = checker.device.EndSearch(searchToken)
+	return err
+}
+
+func (checker *profileChecker) checkReplay() error {
+	_, err := checker.device.GetReplayConfiguration()
+	return err
+}
+
+func (checker *profileChecker) checkRecordingJobs() error {
+	_, err := checker.device.GetRecordingJobs()
+	return err
+}
+
+func (checker *profileChecker) checkStorageConfigurations() error {
+	_, err := checker.device.GetStorageConfigurations()
+	return err
+}
@@ -0,0 +1,134 @@
+package onvif
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/krabiswabbie/go-onvif/onviftest"
+)
+
+func TestCheckProfile(t *testing.T) {
+	server := onviftest.NewServer()
+	defer server.Close()
+
+	server.HandleBody("GetVideoSources", `<trt:GetVideoSourcesResponse>
+		<trt:VideoSources token="VideoSource_1">
+			<tt:Framerate>25</tt:Framerate>
+			<tt:Resolution><tt:Width>1920</tt:Width><tt:Height>1080</tt:Height></tt:Resolution>
+		</trt:VideoSources>
+	</trt:GetVideoSourcesResponse>`)
+
+	device := Device{XAddr: server.XAddr()}
+	report, err := CheckProfile(device, ProfileS)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !report.Compliant() || !report.Declared {
+		t.Errorf("expected compliant report, missing %+v", report.Missing())
+	}
+
+	if report.Device.Manufacturer == "" {
+		t.Errorf("device information not reported %+v", report.Device)
+	}
+
+	// Unsupported optional feature doesn't break compliance
+	features := map[string]FeatureCheck{}
+	for _, feature := range report.Features {
+		features[feature.Name] = feature
+	}
+
+	if feature := features["Relay outputs"]; feature.Supported || feature.Error == "" {
+		t.Errorf("expected unsupported relay outputs, got %+v", feature)
+	}
+
+	if feature := features["PTZ"]; !feature.Supported {
+		t.Errorf("expected supported PTZ, got %+v", feature)
+	}
+
+	if _, ok := server.LastRequest("Unsubscribe"); !ok {
+		t.Error("subscription created by the check is not removed")
+	}
+
+	// Camera doesn't support recording
+	report, err = CheckProfile(device, ProfileG)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if report.Compliant() || report.Declared {
+		t.Errorf("expected non-compliant report %+v", report)
+	}
+
+	if missing := report.Missing(); len(missing) == 0 || missing[0].Name != "Recording service" {
+		t.Errorf("unexpected missing features %+v", missing)
+	}
+
+	if _, err := CheckProfile(device, "X"); err != errUnknownProfile {
+		t.Errorf("expected %v, got %v", errUnknownProfile, err)
+	}
+}
+
+func TestCheckProfileMedia2(t *testing.T) {
+	server := onviftest.NewServer()
+	defer server.Close()
+
+	// Camera only implements Media2 service, which is required by Profile T
+	server.HandleBody("GetServices", `<tds:GetServicesResponse>
+		<tds:Service>
+			<tds:Namespace>`+deviceNamespace+`</tds:Namespace>
+			<tds:XAddr>`+server.XAddr()+`</tds:XAddr>
+		</tds:Service>
+		<tds:Service>
+			<tds:Namespace>`+media2Namespace+`</tds:Namespace>
+			<tds:XAddr>`+server.URL+`/onvif/media2_service</tds:XAddr>
+		</tds:Service>
+		<tds:Service>
+			<tds:Namespace>`+imagingNamespace+`</tds:Namespace>
+			<tds:XAddr>`+server.URL+`/onvif/imaging_service</tds:XAddr>
+		</tds:Service>
+		<tds:Service>
+			<tds:Namespace>`+eventsNamespace+`</tds:Namespace>
+			<tds:XAddr>`+server.URL+onviftest.EventsPath+`</tds:XAddr>
+		</tds:Service>
+	</tds:GetServicesResponse>`)
+
+	notSupported := onviftest.SenderFault("ter:ActionNotSupported", "Media service is not supported")
+	server.Handle("GetProfiles", func(request onviftest.Request) (string, error) {
+		if !strings.Contains(request.Envelope, media2Namespace) {
+			return "", notSupported
+		}
+
+		return `<tr2:GetProfilesResponse xmlns:tr2="http://www.onvif.org/ver20/media/wsdl">
+			<tr2:Profiles token="Profile_1" fixed="true">
+				<tr2:Name>mainStream</tr2:Name>
+				<tr2:Configurations><tr2:PTZ token="PTZ_1"><tt:Name>PTZ</tt:Name></tr2:PTZ></tr2:Configurations>
+			</tr2:Profiles>
+		</tr2:GetProfilesResponse>`, nil
+	})
+	server.Handle("GetStreamUri", func(request onviftest.Request) (string, error) {
+		if !strings.Contains(request.Envelope, "<tr2:ProfileToken>Profile_1</tr2:ProfileToken>") {
+			return "", notSupported
+		}
+
+		return `<tr2:GetStreamUriResponse xmlns:tr2="http://www.onvif.org/ver20/media/wsdl">
+			<tr2:Uri>rtsp://10.0.0.5/stream1</tr2:Uri>
+		</tr2:GetStreamUriResponse>`, nil
+	})
+	server.HandleFault("GetVideoSources", notSupported)
+
+	report, err := CheckProfile(Device{XAddr: server.XAddr()}, ProfileT)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !report.Compliant() {
+		t.Errorf("expected compliant report, missing %+v", report.Missing())
+	}
+
+	for _, feature := range report.Features {
+		if feature.Name == "PTZ" && !feature.Supported {
+			t.Errorf("expected supported PTZ of Media2 profile, got %+v", feature)
+		}
+	}
+}
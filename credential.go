@@ -0,0 +1,146 @@
+package onvif
+
+import (
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const credentialNamespace = "http://www.onvif.org/ver10/credential/wsdl"
+
+var credentialXMLNs = []string{
+	`xmlns:tcr="http://www.onvif.org/ver10/credential/wsdl"`,
+	`xmlns:tt="http://www.onvif.org/ver10/schema"`,
+}
+
+// GetCredentials fetch credentials with the specified tokens
+func (device Device) GetCredentials(tokens []string) ([]Credential, error) {
+	// Create SOAP
+	body := `<tcr:GetCredentials>`
+	for _, token := range tokens {
+		body += `<tcr:Token>` + escapeXML(token) + `</tcr:Token>`
+	}
+	body += `</tcr:GetCredentials>`
+
+	soap := SOAP{
+		Body:  body,
+		XMLNs: credentialXMLNs,
+	}
+
+	// Send SOAP request
+	response, err := device.sendRequest(credentialNamespace, soap)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse response to interface
+	ifaceCredentials, err := response.ValuesForPath("Envelope.Body.GetCredentialsResponse.Credential")
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert interface to array of credential
+	credentials := []Credential{}
+	for _, ifaceCredential := range ifaceCredentials {
+		mapCredential, ok := ifaceCredential.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		credential := Credential{
+			Token:                     interfaceToString(mapCredential["-token"]),
+			Description:               interfaceToString(mapCredential["Description"]),
+			CredentialHolderReference: interfaceToString(mapCredential["CredentialHolderReference"]),
+			ValidFrom:                 interfaceToTime(mapCredential["ValidFrom"]),
+			ValidTo:                   interfaceToTime(mapCredential["ValidTo"]),
+			Identifiers:               []CredentialIdentifier{},
+			AccessProfiles:            []CredentialAccessProfile{},
+		}
+
+		for _, mapIdentifier := range interfaceToMaps(mapCredential["CredentialIdentifier"]) {
+			identifier := CredentialIdentifier{}
+			identifier.ExemptedFromAuthentication = interfaceToBool(mapIdentifier["ExemptedFromAuthentication"])
+			identifier.Value, _ = hex.DecodeString(strings.TrimSpace(interfaceToString(mapIdentifier["Value"])))
+
+			if mapType, ok := mapIdentifier["Type"].(map[string]interface{}); ok {
+				identifier.Type = interfaceToString(mapType["Name"])
+				identifier.FormatType = interfaceToString(mapType["FormatType"])
+			}
+
+			credential.Identifiers = append(credential.Identifiers, identifier)
+		}
+
+		for _, mapProfile := range interfaceToMaps(mapCredential["CredentialAccessProfile"]) {
+			credential.AccessProfiles = append(credential.AccessProfiles, CredentialAccessProfile{
+				AccessProfileToken: interfaceToString(mapProfile["AccessProfileToken"]),
+				ValidFrom:          interfaceToTime(mapProfile["ValidFrom"]),
+				ValidTo:            interfaceToTime(mapProfile["ValidTo"]),
+			})
+		}
+
+		credentials = append(credentials, credential)
+	}
+
+	return credentials, nil
+}
+
+// CreateCredential creates a new credential and returns its token.
+// Token of the credential must be empty, since it's assigned by device.
+func (device Device) CreateCredential(credential Credential, enabled bool) (string, error) {
+	// Create SOAP
+	body := `<tcr:CreateCredential>
+		<tcr:Credential token="">
+			<tcr:Description>` + escapeXML(credential.Description) + `</tcr:Description>
+			<tcr:CredentialHolderReference>` + escapeXML(credential.CredentialHolderReference) + `</tcr:CredentialHolderReference>` +
+		optionalTimeXML("tcr:ValidFrom", credential.ValidFrom) +
+		optionalTimeXML("tcr:ValidTo", credential.ValidTo)
+
+	for _, identifier := range credential.Identifiers {
+		body += `<tcr:CredentialIdentifier>
+			<tcr:Type>
+				<tcr:Name>` + escapeXML(identifier.Type) + `</tcr:Name>
+				<tcr:FormatType>` + escapeXML(identifier.FormatType) + `</tcr:FormatType>
+			</tcr:Type>
+			<tcr:ExemptedFromAuthentication>` + strconv.FormatBool(identifier.ExemptedFromAuthentication) + `</tcr:ExemptedFromAuthentication>
+			<tcr:Value>` + strings.ToUpper(hex.EncodeToString(identifier.Value)) + `</tcr:Value>
+		</tcr:CredentialIdentifier>`
+	}
+
+	for _, profile := range credential.AccessProfiles {
+		body += `<tcr:CredentialAccessProfile>
+			<tcr:AccessProfileToken>` + escapeXML(profile.AccessProfileToken) + `</tcr:AccessProfileToken>` +
+			optionalTimeXML("tcr:ValidFrom", profile.ValidFrom) +
+			optionalTimeXML("tcr:ValidTo", profile.ValidTo) + `
+		</tcr:CredentialAccessProfile>`
+	}
+
+	body += `</tcr:Credential>
+		<tcr:State>
+			<tcr:Enabled>` + strconv.FormatBool(enabled) + `</tcr:Enabled>
+		</tcr:State>
+	</tcr:CreateCredential>`
+
+	soap := SOAP{
+		Body:  body,
+		XMLNs: credentialXMLNs,
+	}
+
+	// Send SOAP request
+	response, err := device.sendRequest(credentialNamespace, soap)
+	if err != nil {
+		return "", err
+	}
+
+	// Parse response
+	token, _ := response.ValueForPathString("Envelope.Body.CreateCredentialResponse.Token")
+	return token, nil
+}
+
+// optionalTimeXML creates element that contains xs:dateTime, or nothing if the time is zero
+func optionalTimeXML(element string, value time.Time) string {
+	if value.IsZero() {
+		return ""
+	}
+	return `<` + element + `>` + value.UTC().Format(time.RFC3339) + `</` + element + `>`
+}
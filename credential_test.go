@@ -0,0 +1,105 @@
+package onvif
+
+import (
+	"fmt"
+	"log"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/krabiswabbie/go-onvif/onviftest"
+)
+
+func TestGetCredentials(t *testing.T) {
+	log.Println("Test GetCredentials")
+
+	res, err := testDevice.GetCredentials(nil)
+	if err != nil {
+		t.Error(err)
+	}
+
+	js := prettyJSON(&res)
+	fmt.Println(js)
+}
+
+func TestCredential(t *testing.T) {
+	server := onviftest.NewServer()
+	defer server.Close()
+
+	server.HandleBody("GetCredentials", `<tcr:GetCredentialsResponse xmlns:tcr="http://www.onvif.org/ver10/credential/wsdl">
+		<tcr:Credential token="Credential_1">
+			<tcr:Description>Employee badge</tcr:Description>
+			<tcr:CredentialHolderReference>john.doe</tcr:CredentialHolderReference>
+			<tcr:ValidFrom>2026-01-01T00:00:00Z</tcr:ValidFrom>
+			<tcr:ValidTo>2027-01-01T00:00:00Z</tcr:ValidTo>
+			<tcr:CredentialIdentifier>
+				<tcr:Type><tcr:Name>pt:Card</tcr:Name><tcr:FormatType>WIEGAND26</tcr:FormatType></tcr:Type>
+				<tcr:ExemptedFromAuthentication>false</tcr:ExemptedFromAuthentication>
+				<tcr:Value>00A1B2</tcr:Value>
+			</tcr:CredentialIdentifier>
+			<tcr:CredentialAccessProfile>
+				<tcr:AccessProfileToken>AccessProfile_1</tcr:AccessProfileToken>
+			</tcr:CredentialAccessProfile>
+		</tcr:Credential>
+	</tcr:GetCredentialsResponse>`)
+	server.HandleBody("CreateCredential", `<tcr:CreateCredentialResponse xmlns:tcr="http://www.onvif.org/ver10/credential/wsdl">
+		<tcr:Token>Credential_2</tcr:Token>
+	</tcr:CreateCredentialResponse>`)
+
+	device := Device{XAddr: server.XAddr()}
+	credentials, err := device.GetCredentials([]string{"Credential_1", "Credential<2>"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	request, _ := server.LastRequest("GetCredentials")
+	if !strings.Contains(request.Envelope, `<tcr:GetCredentials><tcr:Token>Credential_1</tcr:Token><tcr:Token>Credential&lt;2&gt;</tcr:Token></tcr:GetCredentials>`) {
+		t.Errorf("unexpected request %s", request.Envelope)
+	}
+
+	validFrom := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	validTo := validFrom.AddDate(1, 0, 0)
+	expected := []Credential{{
+		Token:                     "Credential_1",
+		Description:               "Employee badge",
+		CredentialHolderReference: "john.doe",
+		ValidFrom:                 validFrom,
+		ValidTo:                   validTo,
+		Identifiers:               []CredentialIdentifier{{Type: "pt:Card", FormatType: "WIEGAND26", Value: []byte{0x00, 0xa1, 0xb2}}},
+		AccessProfiles:            []CredentialAccessProfile{{AccessProfileToken: "AccessProfile_1"}},
+	}}
+	if !reflect.DeepEqual(credentials, expected) {
+		t.Errorf("expected %+v, got %+v", expected, credentials)
+	}
+
+	// Identifier value is upper case hex, and zero validity of access profile is omitted
+	credential := Credential{
+		Description:               "Visitor & guest",
+		CredentialHolderReference: "visitor",
+		ValidTo:                   validTo,
+		Identifiers:               []CredentialIdentifier{{Type: "pt:PIN", FormatType: "SIMPLE_NUMBER16", ExemptedFromAuthentication: true, Value: []byte{0x12, 0xab}}},
+		AccessProfiles:            []CredentialAccessProfile{{AccessProfileToken: "AccessProfile_2", ValidFrom: validFrom}},
+	}
+	token, err := device.CreateCredential(credential, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if token != "Credential_2" {
+		t.Errorf("expected token Credential_2, got %s", token)
+	}
+
+	request, _ = server.LastRequest("CreateCredential")
+	body := `<tcr:CreateCredential><tcr:Credential token="">` +
+		`<tcr:Description>Visitor &amp; guest</tcr:Description><tcr:CredentialHolderReference>visitor</tcr:CredentialHolderReference>` +
+		`<tcr:ValidTo>2027-01-01T00:00:00Z</tcr:ValidTo>` +
+		`<tcr:CredentialIdentifier><tcr:Type><tcr:Name>pt:PIN</tcr:Name><tcr:FormatType>SIMPLE_NUMBER16</tcr:FormatType></tcr:Type>` +
+		`<tcr:ExemptedFromAuthentication>true</tcr:ExemptedFromAuthentication><tcr:Value>12AB</tcr:Value></tcr:CredentialIdentifier>` +
+		`<tcr:CredentialAccessProfile><tcr:AccessProfileToken>AccessProfile_2</tcr:AccessProfileToken>` +
+		`<tcr:ValidFrom>2026-01-01T00:00:00Z</tcr:ValidFrom></tcr:CredentialAccessProfile>` +
+		`</tcr:Credential><tcr:State><tcr:Enabled>true</tcr:Enabled></tcr:State></tcr:CreateCredential>`
+	if !strings.Contains(request.Envelope, body) {
+		t.Errorf("unexpected request %s", request.Envelope)
+	}
+}
@@ -0,0 +1,158 @@
+package onvif
+
+import (
+	"strconv"
+	"time"
+)
+
+// GetSystemDateAndTime fetch clock settings of an ONVIF camera
+func (device Device) GetSystemDateAndTime() (SystemDateAndTime, error) {
+	// Create SOAP
+	soap := SOAP{
+		Body:  "<tds:GetSystemDateAndTime/>",
+		XMLNs: deviceXMLNs,
+	}
+
+	// Send SOAP request
+	response, err := device.sendRequest(deviceNamespace, soap)
+	if err != nil {
+		return SystemDateAndTime{}, err
+	}
+
+	// Parse response to interface
+	ifaceDateTime, err := response.ValueForPath("Envelope.Body.GetSystemDateAndTimeResponse.SystemDateAndTime")
+	if err != nil {
+		return SystemDateAndTime{}, err
+	}
+
+	// Parse interface to struct
+	dateTime := SystemDateAndTime{}
+	if mapDateTime, ok := ifaceDateTime.(map[string]interface{}); ok {
+		dateTime.DateTimeType = interfaceToString(mapDateTime["DateTimeType"])
+		dateTime.DaylightSavings = interfaceToBool(mapDateTime["DaylightSavings"])
+		dateTime.UTCDateTime = parseDateTime(mapDateTime["UTCDateTime"])
+		dateTime.LocalDateTime = parseDateTime(mapDateTime["LocalDateTime"])
+
+		if mapTimeZone, ok := mapDateTime["TimeZone"].(map[string]interface{}); ok {
+			dateTime.TimeZone = interfaceToString(mapTimeZone["TZ"])
+		}
+	}
+
+	return dateTime, nil
+}
+
+// SetSystemDateAndTime sets clock settings of an ONVIF camera. UTCDateTime
+// is only sent if DateTimeType is Manual, and LocalDateTime is ignored.
+func (device Device) SetSystemDateAndTime(dateTime SystemDateAndTime) error {
+	// Create SOAP
+	body := `<tds:SetSystemDateAndTime>
+		<tds:DateTimeType>` + escapeXML(dateTime.DateTimeType) + `</tds:DateTimeType>
+		<tds:DaylightSavings>` + strconv.FormatBool(dateTime.DaylightSavings) + `</tds:DaylightSavings>`
+	if dateTime.TimeZone != "" {
+		body += `<tds:TimeZone><tt:TZ>` + escapeXML(dateTime.TimeZone) + `</tt:TZ></tds:TimeZone>`
+	}
+	if dateTime.DateTimeType == "Manual" {
+		body += `<tds:UTCDateTime>` + dateTimeXML(dateTime.UTCDateTime.UTC()) + `</tds:UTCDateTime>`
+	}
+	body += `</tds:SetSystemDateAndTime>`
+
+	soap := SOAP{
+		Body:  body,
+		XMLNs: deviceXMLNs,
+	}
+
+	// Send SOAP request
+	_, err := device.sendRequest(deviceNamespace, soap)
+	return err
+}
+
+// GetNTP fetch NTP servers used by an ONVIF camera
+func (device Device) GetNTP() (NTPInformation, error) {
+	// Create SOAP
+	soap := SOAP{
+		Body:  "<tds:GetNTP/>",
+		XMLNs: deviceXMLNs,
+	}
+
+	// Send SOAP request
+	response, err := device.sendRequest(deviceNamespace, soap)
+	if err != nil {
+		return NTPInformation{}, err
+	}
+
+	// Parse response to interface
+	ifaceNTP, err := response.ValueForPath("Envelope.Body.GetNTPResponse.NTPInformation")
+	if err != nil {
+		return NTPInformation{}, err
+	}
+
+	// Parse interface to struct
+	ntp := NTPInformation{NTPFromDHCP: []NetworkHost{}, NTPManual: []NetworkHost{}}
+	if mapNTP, ok := ifaceNTP.(map[string]interface{}); ok {
+		ntp.FromDHCP = interfaceToBool(mapNTP["FromDHCP"])
+		for _, mapHost := range interfaceToMaps(mapNTP["NTPFromDHCP"]) {
+			ntp.NTPFromDHCP = append(ntp.NTPFromDHCP, parseNetworkHost(mapHost))
+		}
+		for _, mapHost := range interfaceToMaps(mapNTP["NTPManual"]) {
+			ntp.NTPManual = append(ntp.NTPManual, parseNetworkHost(mapHost))
+		}
+	}
+
+	return ntp, nil
+}
+
+// SetNTP sets NTP servers used by an ONVIF camera. Manual servers
+// are ignored if the servers are obtained from DHCP.
+func (device Device) SetNTP(fromDHCP bool, servers []NetworkHost) error {
+	// Create SOAP
+	body := `<tds:SetNTP>
+		<tds:FromDHCP>` + strconv.FormatBool(fromDHCP) + `</tds:FromDHCP>`
+	if !fromDHCP {
+		for _, server := range servers {
+			body += `<tds:NTPManual>` + networkHostXML(server) + `</tds:NTPManual>`
+		}
+	}
+	body += `</tds:SetNTP>`
+
+	soap := SOAP{
+		Body:  body,
+		XMLNs: deviceXMLNs,
+	}
+
+	// Send SOAP request
+	_, err := device.sendRequest(deviceNamespace, soap)
+	return err
+}
+
+// parseDateTime parses tt:DateTime, which contains date and time as separate numbers
+func parseDateTime(src interface{}) time.Time {
+	mapDateTime, ok := src.(map[string]interface{})
+	if !ok {
+		return time.Time{}
+	}
+
+	mapDate, _ := mapDateTime["Date"].(map[string]interface{})
+	mapTime, _ := mapDateTime["Time"].(map[string]interface{})
+	if mapDate == nil || mapTime == nil {
+		return time.Time{}
+	}
+
+	return time.Date(
+		interfaceToInt(mapDate["Year"]), time.Month(interfaceToInt(mapDate["Month"])), interfaceToInt(mapDate["Day"]),
+		interfaceToInt(mapTime["Hour"]), interfaceToInt(mapTime["Minute"]), interfaceToInt(mapTime["Second"]),
+		0, time.UTC)
+}
+
+// dateTimeXML creates content of tt:DateTime
+func dateTimeXML(t time.Time) string {
+	return `<tt:Time>
+			<tt:Hour>` + strconv.Itoa(t.Hour()) + `</tt:Hour>
+			<tt:Minute>` + strconv.Itoa(t.Minute()) + `</tt:Minute>
+			<tt:Second>` + strconv.Itoa(t.Second()) + `</tt:Second>
+		</tt:Time>
+		<tt:Date>
+			<tt:Year>` + strconv.Itoa(t.Year()) + `</tt:Year>
+			<tt:Month>` + strconv.Itoa(int(t.Month())) + `</tt:Month>
+			<tt:Day>` + strconv.Itoa(t.Day()) + `</tt:Day>
+		</tt:Date>`
+}
@@ -0,0 +1,101 @@
+package onvif
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/krabiswabbie/go-onvif/onviftest"
+)
+
+func TestSystemDateAndTime(t *testing.T) {
+	server := onviftest.NewServer()
+	defer server.Close()
+
+	server.HandleBody("GetSystemDateAndTime", `<tds:GetSystemDateAndTimeResponse>
+		<tds:SystemDateAndTime>
+			<tt:DateTimeType>NTP</tt:DateTimeType>
+			<tt:DaylightSavings>true</tt:DaylightSavings>
+			<tt:TimeZone><tt:TZ>CET-1CEST,M3.5.0,M10.5.0/3</tt:TZ></tt:TimeZone>
+			<tt:UTCDateTime>
+				<tt:Time><tt:Hour>10</tt:Hour><tt:Minute>30</tt:Minute><tt:Second>15</tt:Second></tt:Time>
+				<tt:Date><tt:Year>2024</tt:Year><tt:Month>7</tt:Month><tt:Day>1</tt:Day></tt:Date>
+			</tt:UTCDateTime>
+			<tt:LocalDateTime>
+				<tt:Time><tt:Hour>12</tt:Hour><tt:Minute>30</tt:Minute><tt:Second>15</tt:Second></tt:Time>
+				<tt:Date><tt:Year>2024</tt:Year><tt:Month>7</tt:Month><tt:Day>1</tt:Day></tt:Date>
+			</tt:LocalDateTime>
+		</tds:SystemDateAndTime>
+	</tds:GetSystemDateAndTimeResponse>`)
+	server.HandleBody("SetSystemDateAndTime", `<tds:SetSystemDateAndTimeResponse/>`)
+
+	device := Device{XAddr: server.XAddr()}
+	dateTime, err := device.GetSystemDateAndTime()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := SystemDateAndTime{
+		DateTimeType:    "NTP",
+		DaylightSavings: true,
+		TimeZone:        "CET-1CEST,M3.5.0,M10.5.0/3",
+		UTCDateTime:     time.Date(2024, 7, 1, 10, 30, 15, 0, time.UTC),
+		LocalDateTime:   time.Date(2024, 7, 1, 12, 30, 15, 0, time.UTC),
+	}
+	if !reflect.DeepEqual(dateTime, expected) {
+		t.Errorf("got %+v, want %+v", dateTime, expected)
+	}
+
+	dateTime = SystemDateAndTime{DateTimeType: "Manual", TimeZone: "UTC0", UTCDateTime: time.Date(2024, 12, 31, 23, 59, 0, 0, time.UTC)}
+	if err := device.SetSystemDateAndTime(dateTime); err != nil {
+		t.Fatal(err)
+	}
+
+	request, _ := server.LastRequest("SetSystemDateAndTime")
+	for _, element := range []string{"<tt:TZ>UTC0</tt:TZ>", "<tt:Year>2024</tt:Year>", "<tt:Month>12</tt:Month>", "<tt:Minute>59</tt:Minute>"} {
+		if !strings.Contains(request.Envelope, element) {
+			t.Errorf("request doesn't contain %s: %s", element, request.Envelope)
+		}
+	}
+}
+
+func TestNTP(t *testing.T) {
+	server := onviftest.NewServer()
+	defer server.Close()
+
+	server.HandleBody("GetNTP", `<tds:GetNTPResponse>
+		<tds:NTPInformation>
+			<tt:FromDHCP>false</tt:FromDHCP>
+			<tt:NTPManual><tt:Type>DNS</tt:Type><tt:DNSname>pool.ntp.org</tt:DNSname></tt:NTPManual>
+			<tt:NTPManual><tt:Type>IPv4</tt:Type><tt:IPv4Address>192.168.1.1</tt:IPv4Address></tt:NTPManual>
+		</tds:NTPInformation>
+	</tds:GetNTPResponse>`)
+	server.HandleBody("SetNTP", `<tds:SetNTPResponse/>`)
+
+	device := Device{XAddr: server.XAddr()}
+	ntp, err := device.GetNTP()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := NTPInformation{
+		NTPFromDHCP: []NetworkHost{},
+		NTPManual: []NetworkHost{
+			{Type: "DNS", DNSName: "pool.ntp.org"},
+			{Type: "IPv4", IPv4Address: "192.168.1.1"},
+		},
+	}
+	if !reflect.DeepEqual(ntp, expected) {
+		t.Errorf("got %+v, want %+v", ntp, expected)
+	}
+
+	if err := device.SetNTP(false, ntp.NTPManual); err != nil {
+		t.Fatal(err)
+	}
+
+	request, _ := server.LastRequest("SetNTP")
+	if !strings.Contains(request.Envelope, "<tds:NTPManual><tt:Type>DNS</tt:Type><tt:DNSname>pool.ntp.org</tt:DNSname></tds:NTPManual>") {
+		t.Errorf("unexpected request %s", request.Envelope)
+	}
+}
@@ -1,10 +1,14 @@
 package onvif
 
 import (
-	"fmt"
+	"errors"
+	"net"
+	"net/url"
 	"strings"
 )
 
+const deviceNamespace = "http://www.onvif.org/ver10/device/wsdl"
+
 var deviceXMLNs = []string{
 	`xmlns:tds="http://www.onvif.org/ver10/device/wsdl"`,
 	`xmlns:tt="http://www.onvif.org/ver10/schema"`,
@@ -20,7 +24,7 @@ func (device Device) GetInformation() (DeviceInformation, error) {
 	}
 
 	// Send SOAP request
-	response, err := soap.SendRequest(device.XAddr)
+	response, err := device.sendRequest(deviceNamespace, soap)
 	if err != nil {
 		return DeviceInformation{}, err
 	}
@@ -44,7 +48,8 @@ func (device Device) GetInformation() (DeviceInformation, error) {
 	return result, nil
 }
 
-// GetCapabilities fetch info of ONVIF camera's capabilities
+// GetCapabilities fetch capabilities of ONVIF camera. Sections that are
+// not reported by camera are left empty.
 func (device Device) GetCapabilities() (DeviceCapabilities, error) {
 	// Create SOAP
 	soap := SOAP{
@@ -55,66 +60,230 @@ func (device Device) GetCapabilities() (DeviceCapabilities, error) {
 	}
 
 	// Send SOAP request
-	response, err := soap.SendRequest(device.XAddr)
+	response, err := device.sendRequest(deviceNamespace, soap)
 	if err != nil {
 		return DeviceCapabilities{}, err
 	}
 
-	// Get network capabilities
-	envelopeBodyPath := "Envelope.Body.GetCapabilitiesResponse.Capabilities"
-	ifaceNetCap, err := response.ValueForPath(envelopeBodyPath + ".Device.Network")
+	// Parse response to interface
+	ifaceCapabilities, err := response.ValueForPath("Envelope.Body.GetCapabilitiesResponse.Capabilities")
 	if err != nil {
 		return DeviceCapabilities{}, err
 	}
 
-	netCap := NetworkCapabilities{}
-	if mapNetCap, ok := ifaceNetCap.(map[string]interface{}); ok {
-		netCap.DynDNS = interfaceToBool(mapNetCap["DynDNS"])
-		netCap.IPFilter = interfaceToBool(mapNetCap["IPFilter"])
-		netCap.IPVersion6 = interfaceToBool(mapNetCap["IPVersion6"])
-		netCap.ZeroConfig = interfaceToBool(mapNetCap["ZeroConfiguration"])
+	mapCapabilities, _ := ifaceCapabilities.(map[string]interface{})
+	return parseCapabilities(mapCapabilities), nil
+}
+
+// GetServices fetch list of services provided by ONVIF camera
+func (device Device) GetServices() ([]Service, error) {
+	// Create SOAP
+	soap := SOAP{
+		XMLNs: deviceXMLNs,
+		Body: `<tds:GetServices>
+			<tds:IncludeCapability>false</tds:IncludeCapability>
+		</tds:GetServices>`,
 	}
 
-	// Get events capabilities
-	ifaceEventsCap, err := response.ValueForPath(envelopeBodyPath + ".Events")
+	// Send SOAP request
+	response, err := device.sendRequest(deviceNamespace, soap)
 	if err != nil {
-		return DeviceCapabilities{}, err
+		return nil, err
+	}
+
+	// Parse response to interface
+	ifaceServices, err := response.ValuesForPath("Envelope.Body.GetServicesResponse.Service")
+	if err != nil {
+		return nil, err
 	}
 
-	eventsCap := make(map[string]bool)
-	if mapEventsCap, ok := ifaceEventsCap.(map[string]interface{}); ok {
-		for key, value := range mapEventsCap {
-			if strings.ToLower(key) == "xaddr" {
-				continue
+	// Convert interface to array of service
+	services := []Service{}
+	for _, ifaceService := range ifaceServices {
+		if mapService, ok := ifaceService.(map[string]interface{}); ok {
+			service := Service{}
+			service.Namespace = interfaceToString(mapService["Namespace"])
+			service.XAddr = interfaceToString(mapService["XAddr"])
+
+			if mapVersion, ok := mapService["Version"].(map[string]interface{}); ok {
+				service.Version = interfaceToString(mapVersion["Major"]) + "." + interfaceToString(mapVersion["Minor"])
 			}
 
-			key = strings.Replace(key, "WS", "", 1)
-			eventsCap[key] = interfaceToBool(value)
+			services = append(services, service)
+		}
+	}
+
+	return services, nil
+}
+
+// UpdateServices fetch services of ONVIF camera and save their XAddr,
+// so subsequent requests are sent to the right service endpoint.
+// Host and port of each XAddr are replaced with the ones of device XAddr,
+// since camera behind NAT usually reports its local address. Old cameras that
+// don't support GetServices report XAddr of services in GetCapabilities.
+func (device *Device) UpdateServices() error {
+	xaddrs := map[string]string{}
+
+	services, err := device.GetServices()
+	var fault *SOAPFault
+	switch {
+	case errors.As(err, &fault) && !errors.Is(err, ErrNotAuthorized):
+		capabilities, err := device.GetCapabilities()
+		if err != nil {
+			return err
+		}
+
+		xaddrs = capabilities.serviceXAddrs()
+
+	case err != nil:
+		return err
+
+	default:
+		for _, service := range services {
+			if service.Namespace != "" && service.XAddr != "" {
+				xaddrs[service.Namespace] = service.XAddr
+			}
 		}
 	}
 
-	// Get streaming capabilities
-	ifaceStreamingCap, err := response.ValueForPath(envelopeBodyPath + ".Media.StreamingCapabilities")
+	device.reportedXAddr = xaddrs[deviceNamespace]
+	device.Services = make(map[string]string)
+	for namespace, xaddr := range xaddrs {
+		device.Services[namespace] = device.adjustXAddr(xaddr)
+	}
+
+	return nil
+}
+
+// adjustXAddr replaces host name of an XAddr reported by camera with the one
+// from device XAddr, since camera might report an address that isn't reachable,
+// e.g. behind NAT. Scheme and port of device XAddr are used as well, unless the
+// XAddr has another port than the one reported for the device service, since
+// services of camera might be served on other ports than the device service.
+func (device Device) adjustXAddr(xaddr string) string {
+	urlDevice, err := url.Parse(normalizeZone(device.XAddr))
 	if err != nil {
-		return DeviceCapabilities{}, err
+		return xaddr
+	}
+
+	urlXAddr, err := url.Parse(normalizeZone(xaddr))
+	if err != nil || urlXAddr.Host == "" {
+		return xaddr
 	}
 
-	streamingCap := make(map[string]bool)
-	if mapStreamingCap, ok := ifaceStreamingCap.(map[string]interface{}); ok {
-		for key, value := range mapStreamingCap {
-			key = strings.Replace(key, "_", " ", -1)
-			streamingCap[key] = interfaceToBool(value)
+	// Port of device service reported by camera, which is forwarded to device XAddr
+	devicePort := defaultPort(urlXAddr.Scheme)
+	if urlReported, err := url.Parse(normalizeZone(device.reportedXAddr)); err == nil && urlReported.Host != "" {
+		devicePort = urlReported.Port()
+		if devicePort == "" {
+			devicePort = defaultPort(urlReported.Scheme)
 		}
 	}
 
-	// Create final result
-	deviceCapabilities := DeviceCapabilities{
-		Network:   netCap,
-		Events:    eventsCap,
-		Streaming: streamingCap,
+	host := urlDevice.Hostname()
+	switch {
+	case urlXAddr.Port() != "" && urlXAddr.Port() != devicePort:
+		host = net.JoinHostPort(host, urlXAddr.Port())
+	case urlDevice.Port() != "":
+		urlXAddr.Scheme = urlDevice.Scheme
+		host = net.JoinHostPort(host, urlDevice.Port())
+	default:
+		urlXAddr.Scheme = urlDevice.Scheme
+		if strings.Contains(host, ":") {
+			host = "[" + host + "]"
+		}
 	}
 
-	return deviceCapabilities, nil
+	urlXAddr.User = urlDevice.User
+	urlXAddr.Host = host
+	return urlXAddr.String()
+}
+
+// defaultPort returns port used by URL scheme if URL doesn't specify the port
+func defaultPort(scheme string) string {
+	if strings.EqualFold(scheme, "https") {
+		return "443"
+	}
+	return "80"
+}
+
+// GetWsdlURL fetch URL of WSDL and other documentation of ONVIF camera
+func (device Device) GetWsdlURL() (string, error) {
+	// Create SOAP
+	soap := SOAP{
+		Body:  "<tds:GetWsdlUrl/>",
+		XMLNs: deviceXMLNs,
+	}
+
+	// Send SOAP request
+	response, err := device.sendRequest(deviceNamespace, soap)
+	if err != nil {
+		return "", err
+	}
+
+	// Parse response
+	wsdlURL, _ := response.ValueForPathString("Envelope.Body.GetWsdlUrlResponse.WsdlUrl")
+	return wsdlURL, nil
+}
+
+// GetEndpointReference fetch endpoint reference of ONVIF camera, i.e. the
+// GUID that identifies it in WS-Discovery
+func (device Device) GetEndpointReference() (string, error) {
+	// Create SOAP
+	soap := SOAP{
+		Body:  "<tds:GetEndpointReference/>",
+		XMLNs: deviceXMLNs,
+	}
+
+	// Send SOAP request
+	response, err := device.sendRequest(deviceNamespace, soap)
+	if err != nil {
+		return "", err
+	}
+
+	// Parse response
+	guid, _ := response.ValueForPathString("Envelope.Body.GetEndpointReferenceResponse.GUID")
+	return guid, nil
+}
+
+// GetSystemURIs fetch URIs to download system logs, support information
+// and system backup of ONVIF camera
+func (device Device) GetSystemURIs() (SystemURIs, error) {
+	// Create SOAP
+	soap := SOAP{
+		Body:  "<tds:GetSystemUris/>",
+		XMLNs: deviceXMLNs,
+	}
+
+	// Send SOAP request
+	response, err := device.sendRequest(deviceNamespace, soap)
+	if err != nil {
+		return SystemURIs{}, err
+	}
+
+	// Parse response to interface
+	ifaceURIs, err := response.ValueForPath("Envelope.Body.GetSystemUrisResponse")
+	if err != nil {
+		return SystemURIs{}, err
+	}
+
+	// Parse interface to struct
+	systemURIs := SystemURIs{SystemLogs: []SystemLogURI{}}
+	if mapURIs, ok := ifaceURIs.(map[string]interface{}); ok {
+		systemURIs.SupportInfoURI = interfaceToString(mapURIs["SupportInfoUri"])
+		systemURIs.SystemBackupURI = interfaceToString(mapURIs["SystemBackupUri"])
+
+		if mapLogs, ok := mapURIs["SystemLogUris"].(map[string]interface{}); ok {
+			for _, mapLog := range interfaceToMaps(mapLogs["SystemLog"]) {
+				systemURIs.SystemLogs = append(systemURIs.SystemLogs, SystemLogURI{
+					Type: interfaceToString(mapLog["Type"]),
+					URI:  interfaceToString(mapLog["Uri"]),
+				})
+			}
+		}
+	}
+
+	return systemURIs, nil
 }
 
 // GetDiscoveryMode fetch network discovery mode of an ONVIF camera
@@ -126,7 +295,7 @@ func (device Device) GetDiscoveryMode() (string, error) {
 	}
 
 	// Send SOAP request
-	response, err := soap.SendRequest(device.XAddr)
+	response, err := device.sendRequest(deviceNamespace, soap)
 	if err != nil {
 		return "", err
 	}
@@ -136,72 +305,158 @@ func (device Device) GetDiscoveryMode() (string, error) {
 	return discoveryMode, nil
 }
 
-// GetScopes fetch scopes of an ONVIF camera
-func (device Device) GetScopes() ([]string, error) {
+// SetDiscoveryMode sets network discovery mode of an ONVIF camera,
+// i.e. Discoverable or NonDiscoverable
+func (device Device) SetDiscoveryMode(discoveryMode string) error {
+	return device.setDiscoveryMode("SetDiscoveryMode", "DiscoveryMode", discoveryMode)
+}
+
+// GetRemoteDiscoveryMode fetch remote discovery mode of an ONVIF camera,
+// i.e. whether it announces itself to discovery proxy
+func (device Device) GetRemoteDiscoveryMode() (string, error) {
 	// Create SOAP
 	soap := SOAP{
-		Body:  "<tds:GetScopes/>",
+		Body:  "<tds:GetRemoteDiscoveryMode/>",
 		XMLNs: deviceXMLNs,
 	}
 
 	// Send SOAP request
-	response, err := soap.SendRequest(device.XAddr)
+	response, err := device.sendRequest(deviceNamespace, soap)
+	if err != nil {
+		return "", err
+	}
+
+	// Parse response
+	discoveryMode, _ := response.ValueForPathString("Envelope.Body.GetRemoteDiscoveryModeResponse.RemoteDiscoveryMode")
+	return discoveryMode, nil
+}
+
+// SetRemoteDiscoveryMode sets remote discovery mode of an ONVIF camera,
+// i.e. Discoverable or NonDiscoverable
+func (device Device) SetRemoteDiscoveryMode(discoveryMode string) error {
+	return device.setDiscoveryMode("SetRemoteDiscoveryMode", "RemoteDiscoveryMode", discoveryMode)
+}
+
+// setDiscoveryMode sends discovery mode with the operation
+func (device Device) setDiscoveryMode(operation, element, discoveryMode string) error {
+	// Create SOAP
+	soap := SOAP{
+		XMLNs: deviceXMLNs,
+		Body: `<tds:` + operation + `>
+			<tds:` + element + `>` + escapeXML(discoveryMode) + `</tds:` + element + `>
+		</tds:` + operation + `>`,
+	}
+
+	// Send SOAP request
+	_, err := device.sendRequest(deviceNamespace, soap)
+	return err
+}
+
+// GetDPAddresses fetch addresses of discovery proxy used by an ONVIF camera
+func (device Device) GetDPAddresses() ([]NetworkHost, error) {
+	// Create SOAP
+	soap := SOAP{
+		Body:  "<tds:GetDPAddresses/>",
+		XMLNs: deviceXMLNs,
+	}
+
+	// Send SOAP request
+	response, err := device.sendRequest(deviceNamespace, soap)
 	if err != nil {
 		return nil, err
 	}
 
 	// Parse response to interface
-	ifaceScopes, err := response.ValuesForPath("Envelope.Body.GetScopesResponse.Scopes")
+	ifaceAddresses, err := response.ValuesForPath("Envelope.Body.GetDPAddressesResponse.DPAddress")
 	if err != nil {
 		return nil, err
 	}
 
-	// Convert interface to array of scope
-	scopes := []string{}
-	for _, ifaceScope := range ifaceScopes {
-		if mapScope, ok := ifaceScope.(map[string]interface{}); ok {
-			scope := interfaceToString(mapScope["ScopeItem"])
-			scopes = append(scopes, scope)
+	// Convert interface to array of network host
+	addresses := []NetworkHost{}
+	for _, ifaceAddress := range ifaceAddresses {
+		if mapAddress, ok := ifaceAddress.(map[string]interface{}); ok {
+			addresses = append(addresses, parseNetworkHost(mapAddress))
 		}
 	}
 
-	return scopes, nil
+	return addresses, nil
 }
 
-// GetHostname fetch hostname of an ONVIF camera
-func (device Device) Ptz(Token, x, y, z string) error {
+// SetDPAddresses sets addresses of discovery proxy used by an ONVIF camera
+func (device Device) SetDPAddresses(addresses []NetworkHost) error {
 	// Create SOAP
+	body := `<tds:SetDPAddresses>`
+	for _, address := range addresses {
+		body += `<tds:DPAddress>` + networkHostXML(address) + `</tds:DPAddress>`
+	}
+	body += `</tds:SetDPAddresses>`
+
 	soap := SOAP{
-		Body: `<tptz:ContinuousMove>
-    <tptz:ProfileToken>` + Token + `</tptz:ProfileToken>
-    <tptz:Velocity>
-     <tt:PanTilt x="` + x + `" y="` + y + `" space="">
-     </tt:PanTilt>
-     <tt:Zoom x="` + z + `" space="">
-     </tt:Zoom>
-    </tptz:Velocity>
-   </tptz:ContinuousMove>`,
+		Body:  body,
 		XMLNs: deviceXMLNs,
 	}
 
 	// Send SOAP request
-	_, err := soap.SendRequest(device.XAddr)
+	_, err := device.sendRequest(deviceNamespace, soap)
 	return err
 }
-func (device Device) PtzStop(Token, x, y, z string) error {
+
+// parseNetworkHost parses tt:NetworkHost
+func parseNetworkHost(mapHost map[string]interface{}) NetworkHost {
+	return NetworkHost{
+		Type:        interfaceToString(mapHost["Type"]),
+		IPv4Address: interfaceToString(mapHost["IPv4Address"]),
+		IPv6Address: interfaceToString(mapHost["IPv6Address"]),
+		DNSName:     interfaceToString(mapHost["DNSname"]),
+	}
+}
+
+// networkHostXML creates content of tt:NetworkHost
+func networkHostXML(host NetworkHost) string {
+	result := `<tt:Type>` + escapeXML(host.Type) + `</tt:Type>`
+	if host.IPv4Address != "" {
+		result += `<tt:IPv4Address>` + escapeXML(host.IPv4Address) + `</tt:IPv4Address>`
+	}
+	if host.IPv6Address != "" {
+		result += `<tt:IPv6Address>` + escapeXML(host.IPv6Address) + `</tt:IPv6Address>`
+	}
+	if host.DNSName != "" {
+		result += `<tt:DNSname>` + escapeXML(host.DNSName) + `</tt:DNSname>`
+	}
+	return result
+}
+
+// GetScopes fetch scopes of an ONVIF camera
+func (device Device) GetScopes() ([]string, error) {
 	// Create SOAP
 	soap := SOAP{
-		Body: `<tptz:Stop>
-    <tptz:ProfileToken>` + Token + `</tptz:ProfileToken>
-		 <tptz:PanTilt>false</tptz:PanTilt>
-		 <tptz:Zoom>false</tptz:Zoom>
-   </tptz:Stop>`,
+		Body:  "<tds:GetScopes/>",
 		XMLNs: deviceXMLNs,
 	}
 
 	// Send SOAP request
-	_, err := soap.SendRequest(device.XAddr)
-	return err
+	response, err := device.sendRequest(deviceNamespace, soap)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse response to interface
+	ifaceScopes, err := response.ValuesForPath("Envelope.Body.GetScopesResponse.Scopes")
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert interface to array of scope
+	scopes := []string{}
+	for _, ifaceScope := range ifaceScopes {
+		if mapScope, ok := ifaceScope.(map[string]interface{}); ok {
+			scope := interfaceToString(mapScope["ScopeItem"])
+			scopes = append(scopes, scope)
+		}
+	}
+
+	return scopes, nil
 }
 
 // GetHostname fetch hostname of an ONVIF camera
@@ -213,7 +468,7 @@ func (device Device) GetHostname() (HostnameInformation, error) {
 	}
 
 	// Send SOAP request
-	response, err := soap.SendRequest(device.XAddr)
+	response, err := device.sendRequest(deviceNamespace, soap)
 	if err != nil {
 		return HostnameInformation{}, err
 	}
@@ -234,106 +489,17 @@ func (device Device) GetHostname() (HostnameInformation, error) {
 	return hostnameInfo, nil
 }
 
-// AppPTZMove move
-func AppPTZMove(action string) {
-	ip := "171.25.232.42"
-	port := "11999"
-	login := "admin"
-	password := "Ghjlern14"
-
-	var testDevice = Device{
-		User:     login,
-		Password: password,
-		XAddr:    "http://" + login + ":" + password + "@" + ip + ":" + port + "/onvif/device_service",
-		// XAddr: "http://" + login + ":" + password + "@" + ip + ":" + port + "/onvif/media_service",
-	}
-	res, err := testDevice.GetProfiles()
-	if err != nil && err.Error() == "Unknown Action" {
-		testDevice.XAddr = "http://" + login + ":" + password + "@" + ip + ":" + port + "/onvif/media_service"
-		res, err = testDevice.GetProfiles()
-		if err == nil {
-			testDevice.XAddr = "http://" + login + ":" + password + "@" + ip + ":" + port + "/onvif/ptz_service"
-		}
-	}
-	if err == nil && len(res) > 0 {
-		switch action {
-		case "up":
-			err := testDevice.Ptz(res[0].Token, "0.0", "0.1", "0.0")
-			if err != nil {
-				err = testDevice.Ptz(res[0].PTZConfig.Token, "0.0", "0.1", "0.0")
-				if err != nil {
-					fmt.Println(err)
-					// WriteFormatGIN(0, 200, err.Error(), c)
-					return
-				}
-			}
-		case "down":
-			err := testDevice.Ptz(res[0].Token, "0.0", "-0.1", "0.0")
-			if err != nil {
-				err = testDevice.Ptz(res[0].PTZConfig.Token, "0.0", "-0.1", "0.0")
-				if err != nil {
-					fmt.Println(err)
-					// WriteFormatGIN(0, 200, err.Error(), c)
-					return
-				}
-			}
-		case "left":
-			err := testDevice.Ptz(res[0].Token, "-0.1", "0.0", "0.0")
-			if err != nil {
-				err = testDevice.Ptz(res[0].PTZConfig.Token, "-0.1", "0.0", "0.0")
-				if err != nil {
-					fmt.Println(err)
-					// WriteFormatGIN(0, 200, err.Error(), c)
-					return
-				}
-			}
-		case "right":
-			err := testDevice.Ptz(res[0].Token, "0.1", "0.0", "0.0")
-			if err != nil {
-				err = testDevice.Ptz(res[0].PTZConfig.Token, "0.1", "0.0", "0.0")
-				if err != nil {
-					fmt.Println(err)
-					// WriteFormatGIN(0, 200, err.Error(), c)
-					return
-				}
-			}
-		case "zoomin":
-			err := testDevice.Ptz(res[0].Token, "0.0", "0.0", "0.1")
-			if err != nil {
-				err = testDevice.Ptz(res[0].PTZConfig.Token, "0.0", "0.0", "0.1")
-				if err != nil {
-					fmt.Println(err)
-					// WriteFormatGIN(0, 200, err.Error(), c)
-					return
-				}
-			}
-		case "zoomout":
-			err := testDevice.Ptz(res[0].Token, "0.0", "0.0", "-0.1")
-			if err != nil {
-				err = testDevice.Ptz(res[0].PTZConfig.Token, "0.0", "0.0", "-0.1")
-				if err != nil {
-					fmt.Println(err)
-					// WriteFormatGIN(0, 200, err.Error(), c)
-					return
-				}
-			}
-		case "stop":
-			testDevice.Ptz(res[0].Token, "0", "0", "0")
-			err := testDevice.PtzStop(res[0].Token, "0", "0", "0")
-			if err != nil {
-				testDevice.Ptz(res[0].PTZConfig.Token, "0", "0", "0")
-				err = testDevice.PtzStop(res[0].PTZConfig.Token, "0", "0", "0")
-				if err != nil {
-					fmt.Println(err)
-					// WriteFormatGIN(0, 200, err.Error(), c)
-					return
-				}
-			}
-		}
-		fmt.Println("SUCCESS")
-		// WriteFormatGIN(1, 200, "success", c)
-	} else {
-		fmt.Println(err)
-		// WriteFormatGIN(0, 200, err.Error(), c)
+// SetHostname sets hostname of an ONVIF camera
+func (device Device) SetHostname(name string) error {
+	// Create SOAP
+	soap := SOAP{
+		XMLNs: deviceXMLNs,
+		Body: `<tds:SetHostname>
+			<tds:Name>` + escapeXML(name) + `</tds:Name>
+		</tds:SetHostname>`,
 	}
+
+	// Send SOAP request
+	_, err := device.sendRequest(deviceNamespace, soap)
+	return err
 }
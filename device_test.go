@@ -3,8 +3,11 @@ package onvif
 import (
 	"fmt"
 	"log"
+	"reflect"
 	"testing"
 	"time"
+
+	"github.com/krabiswabbie/go-onvif/onviftest"
 )
 
 func TestGetInformation(t *testing.T) {
@@ -31,6 +34,18 @@ func TestGetCapabilities(t *testing.T) {
 	fmt.Println(js)
 }
 
+func TestGetServices(t *testing.T) {
+	log.Println("Test GetServices")
+
+	res, err := testDevice.GetServices()
+	if err != nil {
+		t.Error(err)
+	}
+
+	js := prettyJSON(&res)
+	fmt.Println(js)
+}
+
 func TestGetDiscoveryMode(t *testing.T) {
 	log.Println("Test GetDiscoveryMode")
 
@@ -42,6 +57,29 @@ func TestGetDiscoveryMode(t *testing.T) {
 	fmt.Println(res)
 }
 
+func TestGetRemoteDiscoveryMode(t *testing.T) {
+	log.Println("Test GetRemoteDiscoveryMode")
+
+	res, err := testDevice.GetRemoteDiscoveryMode()
+	if err != nil {
+		t.Error(err)
+	}
+
+	fmt.Println(res)
+}
+
+func TestGetDPAddresses(t *testing.T) {
+	log.Println("Test GetDPAddresses")
+
+	res, err := testDevice.GetDPAddresses()
+	if err != nil {
+		t.Error(err)
+	}
+
+	js := prettyJSON(&res)
+	fmt.Println(js)
+}
+
 func TestGetScopes(t *testing.T) {
 	log.Println("Test GetScopes")
 
@@ -67,7 +105,106 @@ func TestGetHostname(t *testing.T) {
 }
 
 func TestContinuousMove(t *testing.T) {
-	AppPTZMove("up")
+	controller, err := NewPTZController(testDevice, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := controller.Up(); err != nil {
+		t.Error(err)
+	}
+
 	time.Sleep(3 * time.Second)
-	AppPTZMove("stop")
+	if err := controller.Stop(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestGetWsdlURL(t *testing.T) {
+	log.Println("Test GetWsdlURL")
+
+	res, err := testDevice.GetWsdlURL()
+	if err != nil {
+		t.Error(err)
+	}
+
+	fmt.Println(res)
+}
+
+func TestGetEndpointReference(t *testing.T) {
+	log.Println("Test GetEndpointReference")
+
+	res, err := testDevice.GetEndpointReference()
+	if err != nil {
+		t.Error(err)
+	}
+
+	fmt.Println(res)
+}
+
+func TestGetSystemURIs(t *testing.T) {
+	log.Println("Test GetSystemURIs")
+
+	res, err := testDevice.GetSystemURIs()
+	if err != nil {
+		t.Error(err)
+	}
+
+	js := prettyJSON(&res)
+	fmt.Println(js)
+}
+
+func TestParseCapabilities(t *testing.T) {
+	server := onviftest.NewServer()
+	defer server.Close()
+
+	server.HandleBody("GetCapabilities", `<tds:GetCapabilitiesResponse>
+		<tds:Capabilities>
+			<tt:Device>
+				<tt:XAddr>http://192.168.1.10/onvif/device_service</tt:XAddr>
+				<tt:System>
+					<tt:DiscoveryBye>true</tt:DiscoveryBye>
+					<tt:SupportedVersions><tt:Major>2</tt:Major><tt:Minor>60</tt:Minor></tt:SupportedVersions>
+					<tt:SupportedVersions><tt:Major>2</tt:Major><tt:Minor>40</tt:Minor></tt:SupportedVersions>
+				</tt:System>
+				<tt:IO><tt:RelayOutputs>1</tt:RelayOutputs></tt:IO>
+				<tt:Security><tt:TLS1.2>true</tt:TLS1.2></tt:Security>
+			</tt:Device>
+			<tt:Analytics>
+				<tt:XAddr>http://192.168.1.10/onvif/analytics_service</tt:XAddr>
+				<tt:RuleSupport>true</tt:RuleSupport>
+				<tt:AnalyticsModuleSupport>true</tt:AnalyticsModuleSupport>
+			</tt:Analytics>
+			<tt:Imaging><tt:XAddr>http://192.168.1.10/onvif/imaging_service</tt:XAddr></tt:Imaging>
+			<tt:Extension>
+				<tt:Recording><tt:XAddr>http://192.168.1.10/onvif/recording_service</tt:XAddr></tt:Recording>
+			</tt:Extension>
+		</tds:Capabilities>
+	</tds:GetCapabilitiesResponse>`)
+
+	device := Device{XAddr: server.XAddr()}
+	capabilities, err := device.GetCapabilities()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !capabilities.Device.System.DiscoveryBye ||
+		!reflect.DeepEqual(capabilities.Device.System.SupportedVersions, []string{"2.60", "2.40"}) ||
+		capabilities.Device.IO.RelayOutputs != 1 || !capabilities.Device.Security.TLS12 {
+		t.Errorf("unexpected device capabilities %+v", capabilities.Device)
+	}
+
+	if !capabilities.Analytics.RuleSupport || capabilities.Imaging.XAddr == "" || capabilities.Recording.XAddr == "" {
+		t.Errorf("unexpected capabilities %+v", capabilities)
+	}
+
+	// Media section is missing
+	if capabilities.Media.XAddr != "" || len(capabilities.Streaming) != 0 {
+		t.Errorf("unexpected media capabilities %+v", capabilities.Media)
+	}
+
+	xaddrs := capabilities.serviceXAddrs()
+	if len(xaddrs) != 4 || xaddrs[recordingNamespace] != "http://192.168.1.10/onvif/recording_service" {
+		t.Errorf("unexpected XAddrs %v", xaddrs)
+	}
 }
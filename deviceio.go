@@ -0,0 +1,124 @@
+package onvif
+
+const deviceIONamespace = "http://www.onvif.org/ver10/deviceIO/wsdl"
+
+var deviceIOXMLNs = []string{
+	`xmlns:tmd="http://www.onvif.org/ver10/deviceIO/wsdl"`,
+	`xmlns:tt="http://www.onvif.org/ver10/schema"`,
+}
+
+// GetRelayOutputs fetch relay outputs of ONVIF device
+func (device Device) GetRelayOutputs() ([]RelayOutput, error) {
+	// Create SOAP
+	soap := SOAP{
+		Body:  "<tmd:GetRelayOutputs/>",
+		XMLNs: deviceIOXMLNs,
+	}
+
+	// Send SOAP request
+	response, err := device.sendRequest(deviceIONamespace, soap)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse response to interface
+	ifaceOutputs, err := response.ValuesForPath("Envelope.Body.GetRelayOutputsResponse.RelayOutputs")
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert interface to array of relay output
+	outputs := []RelayOutput{}
+	for _, ifaceOutput := range ifaceOutputs {
+		if mapOutput, ok := ifaceOutput.(map[string]interface{}); ok {
+			output := RelayOutput{}
+			output.Token = interfaceToString(mapOutput["-token"])
+
+			if mapSettings, ok := mapOutput["Properties"].(map[string]interface{}); ok {
+				output.Settings.Mode = interfaceToString(mapSettings["Mode"])
+				output.Settings.DelayTime, _ = parseDuration(interfaceToString(mapSettings["DelayTime"]))
+				output.Settings.IdleState = interfaceToString(mapSettings["IdleState"])
+			}
+
+			outputs = append(outputs, output)
+		}
+	}
+
+	return outputs, nil
+}
+
+// SetRelayOutputSettings changes settings of a relay output
+func (device Device) SetRelayOutputSettings(relayOutputToken string, settings RelayOutputSettings) error {
+	// Create SOAP
+	soap := SOAP{
+		XMLNs: deviceIOXMLNs,
+		Body: `<tmd:SetRelayOutputSettings>
+			<tmd:RelayOutput token="` + escapeXML(relayOutputToken) + `">
+				<tt:Properties>
+					<tt:Mode>` + escapeXML(settings.Mode) + `</tt:Mode>
+					<tt:DelayTime>` + formatDuration(settings.DelayTime) + `</tt:DelayTime>
+					<tt:IdleState>` + escapeXML(settings.IdleState) + `</tt:IdleState>
+				</tt:Properties>
+			</tmd:RelayOutput>
+		</tmd:SetRelayOutputSettings>`,
+	}
+
+	// Send SOAP request
+	_, err := device.sendRequest(deviceIONamespace, soap)
+	return err
+}
+
+// SetRelayOutputState activates or deactivates a relay output
+func (device Device) SetRelayOutputState(relayOutputToken string, active bool) error {
+	logicalState := "inactive"
+	if active {
+		logicalState = "active"
+	}
+
+	// Create SOAP
+	soap := SOAP{
+		XMLNs: deviceIOXMLNs,
+		Body: `<tmd:SetRelayOutputState>
+			<tmd:RelayOutputToken>` + escapeXML(relayOutputToken) + `</tmd:RelayOutputToken>
+			<tmd:LogicalState>` + logicalState + `</tmd:LogicalState>
+		</tmd:SetRelayOutputState>`,
+	}
+
+	// Send SOAP request
+	_, err := device.sendRequest(deviceIONamespace, soap)
+	return err
+}
+
+// GetDigitalInputs fetch digital inputs of ONVIF device
+func (device Device) GetDigitalInputs() ([]DigitalInput, error) {
+	// Create SOAP
+	soap := SOAP{
+		Body:  "<tmd:GetDigitalInputs/>",
+		XMLNs: deviceIOXMLNs,
+	}
+
+	// Send SOAP request
+	response, err := device.sendRequest(deviceIONamespace, soap)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse response to interface
+	ifaceInputs, err := response.ValuesForPath("Envelope.Body.GetDigitalInputsResponse.DigitalInputs")
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert interface to array of digital input
+	inputs := []DigitalInput{}
+	for _, ifaceInput := range ifaceInputs {
+		if mapInput, ok := ifaceInput.(map[string]interface{}); ok {
+			inputs = append(inputs, DigitalInput{
+				Token:     interfaceToString(mapInput["-token"]),
+				IdleState: interfaceToString(mapInput["-IdleState"]),
+			})
+		}
+	}
+
+	return inputs, nil
+}
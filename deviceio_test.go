@@ -0,0 +1,112 @@
+package onvif
+
+import (
+	"fmt"
+	"log"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/krabiswabbie/go-onvif/onviftest"
+)
+
+func TestGetRelayOutputs(t *testing.T) {
+	log.Println("Test GetRelayOutputs")
+
+	res, err := testDevice.GetRelayOutputs()
+	if err != nil {
+		t.Error(err)
+	}
+
+	js := prettyJSON(&res)
+	fmt.Println(js)
+}
+
+func TestGetDigitalInputs(t *testing.T) {
+	log.Println("Test GetDigitalInputs")
+
+	res, err := testDevice.GetDigitalInputs()
+	if err != nil {
+		t.Error(err)
+	}
+
+	js := prettyJSON(&res)
+	fmt.Println(js)
+}
+
+func TestDeviceIO(t *testing.T) {
+	server := onviftest.NewServer()
+	defer server.Close()
+
+	server.HandleBody("GetRelayOutputs", `<tmd:GetRelayOutputsResponse xmlns:tmd="http://www.onvif.org/ver10/deviceIO/wsdl">
+		<tmd:RelayOutputs token="RelayOutput_1">
+			<tt:Properties><tt:Mode>Monostable</tt:Mode><tt:DelayTime>PT5S</tt:DelayTime><tt:IdleState>closed</tt:IdleState></tt:Properties>
+		</tmd:RelayOutputs>
+		<tmd:RelayOutputs token="RelayOutput_2">
+			<tt:Properties><tt:Mode>Bistable</tt:Mode><tt:DelayTime>PT0S</tt:DelayTime><tt:IdleState>open</tt:IdleState></tt:Properties>
+		</tmd:RelayOutputs>
+	</tmd:GetRelayOutputsResponse>`)
+	server.HandleBody("SetRelayOutputSettings", `<tmd:SetRelayOutputSettingsResponse xmlns:tmd="http://www.onvif.org/ver10/deviceIO/wsdl"/>`)
+	server.HandleBody("SetRelayOutputState", `<tmd:SetRelayOutputStateResponse xmlns:tmd="http://www.onvif.org/ver10/deviceIO/wsdl"/>`)
+	server.HandleBody("GetDigitalInputs", `<tmd:GetDigitalInputsResponse xmlns:tmd="http://www.onvif.org/ver10/deviceIO/wsdl">
+		<tmd:DigitalInputs token="DigitalInput_1" IdleState="closed"/>
+		<tmd:DigitalInputs token="DigitalInput_2" IdleState="open"/>
+	</tmd:GetDigitalInputsResponse>`)
+
+	device := Device{XAddr: server.XAddr()}
+	outputs, err := device.GetRelayOutputs()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedOutputs := []RelayOutput{
+		{Token: "RelayOutput_1", Settings: RelayOutputSettings{Mode: "Monostable", DelayTime: 5 * time.Second, IdleState: "closed"}},
+		{Token: "RelayOutput_2", Settings: RelayOutputSettings{Mode: "Bistable", IdleState: "open"}},
+	}
+	if !reflect.DeepEqual(outputs, expectedOutputs) {
+		t.Errorf("expected %+v, got %+v", expectedOutputs, outputs)
+	}
+
+	// Token is an attribute, and properties are sent in order of the schema
+	settings := RelayOutputSettings{Mode: "Monostable", DelayTime: 2500 * time.Millisecond, IdleState: "open"}
+	if err := device.SetRelayOutputSettings(`Relay"1`, settings); err != nil {
+		t.Fatal(err)
+	}
+
+	request, _ := server.LastRequest("SetRelayOutputSettings")
+	body := `<tmd:SetRelayOutputSettings><tmd:RelayOutput token="Relay&#34;1"><tt:Properties>` +
+		`<tt:Mode>Monostable</tt:Mode><tt:DelayTime>PT2.5S</tt:DelayTime><tt:IdleState>open</tt:IdleState>` +
+		`</tt:Properties></tmd:RelayOutput></tmd:SetRelayOutputSettings>`
+	if !strings.Contains(request.Envelope, body) {
+		t.Errorf("unexpected request %s", request.Envelope)
+	}
+
+	for _, active := range []bool{true, false} {
+		if err := device.SetRelayOutputState("RelayOutput_1", active); err != nil {
+			t.Fatal(err)
+		}
+
+		state := "inactive"
+		if active {
+			state = "active"
+		}
+
+		request, _ = server.LastRequest("SetRelayOutputState")
+		body = `<tmd:SetRelayOutputState><tmd:RelayOutputToken>RelayOutput_1</tmd:RelayOutputToken>` +
+			`<tmd:LogicalState>` + state + `</tmd:LogicalState></tmd:SetRelayOutputState>`
+		if !strings.Contains(request.Envelope, body) {
+			t.Errorf("unexpected request %s", request.Envelope)
+		}
+	}
+
+	inputs, err := device.GetDigitalInputs()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedInputs := []DigitalInput{{Token: "DigitalInput_1", IdleState: "closed"}, {Token: "DigitalInput_2", IdleState: "open"}}
+	if !reflect.DeepEqual(inputs, expectedInputs) {
+		t.Errorf("expected %+v, got %+v", expectedInputs, inputs)
+	}
+}
@@ -13,7 +13,9 @@ import (
 
 var errWrongDiscoveryResponse = errors.New("Response is not related to discovery request")
 
-// StartDiscovery send a WS-Discovery message and wait for all matching device to respond
+// StartDiscovery send a WS-Discovery message and wait for all matching device to respond.
+// The message is sent to IPv4 multicast group of each interface, and to IPv6 multicast
+// group of each interface that has link-local IPv6 address, if IPv6 is available.
 func StartDiscovery(duration time.Duration) ([]Device, error) {
 	// Get list of interface address
 	addrs, err := net.InterfaceAddrs()
@@ -35,7 +37,7 @@ func StartDiscovery(duration time.Duration) ([]Device, error) {
 
 	// Discover device on each interface's network
 	for _, ipAddr := range ipAddrs {
-		devices, err := discoverDevices(ipAddr, duration)
+		devices, err := discoverDevices("udp4", ipAddr+":0", discoveryMulticastAddress, duration)
 		if err != nil {
 			return []Device{}, err
 		}
@@ -43,10 +45,53 @@ func StartDiscovery(duration time.Duration) ([]Device, error) {
 		discoveryResults = append(discoveryResults, devices...)
 	}
 
+	// Discover device through IPv6. It's skipped on failure, since IPv6
+	// might be disabled, which shouldn't break discovery through IPv4.
+	for _, ipAddr := range linkLocalIPv6Addrs() {
+		zone := ipAddr[strings.LastIndex(ipAddr, "%")+1:]
+		devices, err := discoverDevices("udp6", "["+ipAddr+"]:0", "[ff02::c%"+zone+"]:3702", duration)
+		if err == nil {
+			discoveryResults = append(discoveryResults, devices...)
+		}
+	}
+
 	return discoveryResults, nil
 }
 
-func discoverDevices(ipAddr string, duration time.Duration) ([]Device, error) {
+// linkLocalIPv6Addrs returns link-local IPv6 address, with zone, of each
+// interface that's up and supports multicast
+func linkLocalIPv6Addrs() []string {
+	interfaces, err := net.Interfaces()
+	if err != nil {
+		return nil
+	}
+
+	ipAddrs := []string{}
+	for _, iface := range interfaces {
+		if iface.Flags&net.FlagUp == 0 || iface.Flags&net.FlagMulticast == 0 || iface.Flags&net.FlagLoopback != 0 {
+			continue
+		}
+
+		addrs, err := iface.Addrs()
+		if err != nil {
+			continue
+		}
+
+		for _, addr := range addrs {
+			ipAddr, ok := addr.(*net.IPNet)
+			if ok && ipAddr.IP.To4() == nil && ipAddr.IP.IsLinkLocalUnicast() {
+				ipAddrs = append(ipAddrs, ipAddr.IP.String()+"%"+iface.Name)
+				break
+			}
+		}
+	}
+
+	return ipAddrs
+}
+
+// discoverDevices sends WS-Discovery probe from the local address to the multicast
+// address, through the network, i.e. udp4 or udp6, and waits for responses
+func discoverDevices(network, localAddr, multicastAddr string, duration time.Duration) ([]Device, error) {
 	// Create WS-Discovery request
 	requestID := "uuid:" + uuid.NewV4().String()
 	request := `
@@ -74,18 +119,18 @@ func discoverDevices(ipAddr string, duration time.Duration) ([]Device, error) {
 	request = regexp.MustCompile(`\s+`).ReplaceAllString(request, " ")
 
 	// Create UDP address for local and multicast address
-	localAddress, err := net.ResolveUDPAddr("udp4", ipAddr+":0")
+	localAddress, err := net.ResolveUDPAddr(network, localAddr)
 	if err != nil {
 		return []Device{}, err
 	}
 
-	multicastAddress, err := net.ResolveUDPAddr("udp4", "239.255.255.250:3702")
+	multicastAddress, err := net.ResolveUDPAddr(network, multicastAddr)
 	if err != nil {
 		return []Device{}, err
 	}
 
 	// Create UDP connection to listen for respond from matching device
-	conn, err := net.ListenUDP("udp", localAddress)
+	conn, err := net.ListenUDP(network, localAddress)
 	if err != nil {
 		return []Device{}, err
 	}
@@ -110,7 +155,7 @@ func discoverDevices(ipAddr string, duration time.Duration) ([]Device, error) {
 	for {
 		// Create buffer and receive UDP response
 		buffer := make([]byte, 10*1024)
-		_, _, err = conn.ReadFromUDP(buffer)
+		_, source, err := conn.ReadFromUDP(buffer)
 
 		// Check if connection timeout
 		if err != nil {
@@ -127,6 +172,11 @@ func discoverDevices(ipAddr string, duration time.Duration) ([]Device, error) {
 			return discoveryResults, err
 		}
 
+		// Link-local IPv6 address is only reachable through the interface the response came from
+		if source != nil {
+			device.XAddr = XAddrWithZone(device.XAddr, source.Zone)
+		}
+
 		// Push device to results
 		discoveryResults = append(discoveryResults, device)
 	}
@@ -151,13 +201,21 @@ func readDiscoveryResponse(messageID string, buffer []byte) (Device, error) {
 		return result, errWrongDiscoveryResponse
 	}
 
+	// Get device's ID, name and xAddrs
+	result = parseDiscoveredDevice(mapXML, "Envelope.Body.ProbeMatches.ProbeMatch")
+	return result, nil
+}
+
+// parseDiscoveredDevice parses ID, name and XAddr of device in a WS-Discovery message.
+// The path is the element that contains endpoint reference, scopes and XAddrs of device.
+func parseDiscoveredDevice(mapXML mxj.Map, path string) Device {
 	// Get device's ID and clean it
-	deviceID, _ := mapXML.ValueForPathString("Envelope.Body.ProbeMatches.ProbeMatch.EndpointReference.Address")
+	deviceID, _ := mapXML.ValueForPathString(path + ".EndpointReference.Address")
 	deviceID = strings.Replace(deviceID, "urn:uuid:", "", 1)
 
 	// Get device's name
 	deviceName := ""
-	scopes, _ := mapXML.ValueForPathString("Envelope.Body.ProbeMatches.ProbeMatch.Scopes")
+	scopes, _ := mapXML.ValueForPathString(path + ".Scopes")
 	for _, scope := range strings.Split(scopes, " ") {
 		if strings.HasPrefix(scope, "onvif://www.onvif.org/name/") {
 			deviceName = strings.Replace(scope, "onvif://www.onvif.org/name/", "", 1)
@@ -167,16 +225,10 @@ func readDiscoveryResponse(messageID string, buffer []byte) (Device, error) {
 	}
 
 	// Get device's xAddrs
-	xAddrs, _ := mapXML.ValueForPathString("Envelope.Body.ProbeMatches.ProbeMatch.XAddrs")
-	listXAddr := strings.Split(xAddrs, " ")
-	if len(listXAddr) == 0 {
-		return result, errors.New("Device does not have any xAddr")
+	xAddrs, _ := mapXML.ValueForPathString(path + ".XAddrs")
+	return Device{
+		ID:    deviceID,
+		Name:  deviceName,
+		XAddr: SelectXAddr(strings.Fields(xAddrs)),
 	}
-
-	// Finalize result
-	result.ID = deviceID
-	result.Name = deviceName
-	result.XAddr = listXAddr[0]
-
-	return result, nil
 }
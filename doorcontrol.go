@@ -0,0 +1,130 @@
+package onvif
+
+import "strconv"
+
+const doorControlNamespace = "http://www.onvif.org/ver10/doorcontrol/wsdl"
+
+var doorControlXMLNs = []string{
+	`xmlns:tdc="http://www.onvif.org/ver10/doorcontrol/wsdl"`,
+	`xmlns:tt="http://www.onvif.org/ver10/schema"`,
+}
+
+// GetDoorInfoList fetch doors of door control device. The list might be split
+// into several pages, where limit is the maximum number of doors in a page.
+// Use the returned reference as startReference to fetch the next page;
+// empty reference means there are no more doors.
+func (device Device) GetDoorInfoList(limit int, startReference string) ([]DoorInfo, string, error) {
+	// Create SOAP
+	soap := SOAP{
+		XMLNs: doorControlXMLNs,
+		Body: `<tdc:GetDoorInfoList>` +
+			listRequestXML("tdc", limit, startReference) +
+			`</tdc:GetDoorInfoList>`,
+	}
+
+	// Send SOAP request
+	response, err := device.sendRequest(doorControlNamespace, soap)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// Parse response to interface
+	ifaceList, err := response.ValueForPath("Envelope.Body.GetDoorInfoListResponse")
+	if err != nil {
+		return nil, "", err
+	}
+
+	// Parse interface to array of door
+	nextReference := ""
+	doors := []DoorInfo{}
+	if mapList, ok := ifaceList.(map[string]interface{}); ok {
+		nextReference = interfaceToString(mapList["NextStartReference"])
+
+		for _, mapInfo := range interfaceToMaps(mapList["DoorInfo"]) {
+			info := DoorInfo{}
+			info.Token = interfaceToString(mapInfo["-token"])
+			info.Name = interfaceToString(mapInfo["Name"])
+			info.Description = interfaceToString(mapInfo["Description"])
+
+			if mapCapabilities, ok := mapInfo["Capabilities"].(map[string]interface{}); ok {
+				info.Capabilities.Access = interfaceToBool(mapCapabilities["-Access"])
+				info.Capabilities.AccessTimingOverride = interfaceToBool(mapCapabilities["-AccessTimingOverride"])
+				info.Capabilities.Lock = interfaceToBool(mapCapabilities["-Lock"])
+				info.Capabilities.Unlock = interfaceToBool(mapCapabilities["-Unlock"])
+				info.Capabilities.Block = interfaceToBool(mapCapabilities["-Block"])
+				info.Capabilities.DoubleLock = interfaceToBool(mapCapabilities["-DoubleLock"])
+				info.Capabilities.LockDown = interfaceToBool(mapCapabilities["-LockDown"])
+				info.Capabilities.LockOpen = interfaceToBool(mapCapabilities["-LockOpen"])
+				info.Capabilities.DoorMonitor = interfaceToBool(mapCapabilities["-DoorMonitor"])
+				info.Capabilities.LockMonitor = interfaceToBool(mapCapabilities["-LockMonitor"])
+				info.Capabilities.DoubleLockMonitor = interfaceToBool(mapCapabilities["-DoubleLockMonitor"])
+				info.Capabilities.Alarm = interfaceToBool(mapCapabilities["-Alarm"])
+				info.Capabilities.Tamper = interfaceToBool(mapCapabilities["-Tamper"])
+				info.Capabilities.Fault = interfaceToBool(mapCapabilities["-Fault"])
+			}
+
+			doors = append(doors, info)
+		}
+	}
+
+	return doors, nextReference, nil
+}
+
+// AccessDoor grants momentary access through a door
+func (device Device) AccessDoor(doorToken string, options AccessDoorOptions) error {
+	// Create SOAP
+	body := `<tdc:AccessDoor>
+		<tdc:Token>` + escapeXML(doorToken) + `</tdc:Token>`
+
+	if options.UseExtendedTime {
+		body += `<tdc:UseExtendedTime>` + strconv.FormatBool(options.UseExtendedTime) + `</tdc:UseExtendedTime>`
+	}
+
+	if options.AccessTime > 0 {
+		body += `<tdc:AccessTime>` + formatDuration(options.AccessTime) + `</tdc:AccessTime>`
+	}
+
+	if options.OpenTooLongTime > 0 {
+		body += `<tdc:OpenTooLongTime>` + formatDuration(options.OpenTooLongTime) + `</tdc:OpenTooLongTime>`
+	}
+
+	if options.PreAlarmTime > 0 {
+		body += `<tdc:PreAlarmTime>` + formatDuration(options.PreAlarmTime) + `</tdc:PreAlarmTime>`
+	}
+
+	body += `</tdc:AccessDoor>`
+
+	soap := SOAP{
+		Body:  body,
+		XMLNs: doorControlXMLNs,
+	}
+
+	// Send SOAP request
+	_, err := device.sendRequest(doorControlNamespace, soap)
+	return err
+}
+
+// LockDoor locks a door
+func (device Device) LockDoor(doorToken string) error {
+	return device.sendDoorCommand("LockDoor", doorToken)
+}
+
+// UnlockDoor unlocks a door until it's locked again
+func (device Device) UnlockDoor(doorToken string) error {
+	return device.sendDoorCommand("UnlockDoor", doorToken)
+}
+
+// sendDoorCommand sends a door control operation that only needs token of the door
+func (device Device) sendDoorCommand(operation, doorToken string) error {
+	// Create SOAP
+	soap := SOAP{
+		XMLNs: doorControlXMLNs,
+		Body: `<tdc:` + operation + `>
+			<tdc:Token>` + escapeXML(doorToken) + `</tdc:Token>
+		</tdc:` + operation + `>`,
+	}
+
+	// Send SOAP request
+	_, err := device.sendRequest(doorControlNamespace, soap)
+	return err
+}
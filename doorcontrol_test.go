@@ -0,0 +1,92 @@
+package onvif
+
+import (
+	"fmt"
+	"log"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/krabiswabbie/go-onvif/onviftest"
+)
+
+func TestGetDoorInfoList(t *testing.T) {
+	log.Println("Test GetDoorInfoList")
+
+	res, _, err := testDevice.GetDoorInfoList(0, "")
+	if err != nil {
+		t.Error(err)
+	}
+
+	js := prettyJSON(&res)
+	fmt.Println(js)
+}
+
+func TestDoorControl(t *testing.T) {
+	server := onviftest.NewServer()
+	defer server.Close()
+
+	server.HandleBody("GetDoorInfoList", `<tdc:GetDoorInfoListResponse xmlns:tdc="http://www.onvif.org/ver10/doorcontrol/wsdl">
+		<tdc:DoorInfo token="Door_1">
+			<tdc:Name>Front door</tdc:Name>
+			<tdc:Description>Main entrance</tdc:Description>
+			<tdc:Capabilities Access="true" Lock="true" Unlock="true" DoorMonitor="true" Tamper="true"/>
+		</tdc:DoorInfo>
+	</tdc:GetDoorInfoListResponse>`)
+	server.HandleBody("AccessDoor", `<tdc:AccessDoorResponse xmlns:tdc="http://www.onvif.org/ver10/doorcontrol/wsdl"/>`)
+	server.HandleBody("LockDoor", `<tdc:LockDoorResponse xmlns:tdc="http://www.onvif.org/ver10/doorcontrol/wsdl"/>`)
+	server.HandleBody("UnlockDoor", `<tdc:UnlockDoorResponse xmlns:tdc="http://www.onvif.org/ver10/doorcontrol/wsdl"/>`)
+
+	device := Device{XAddr: server.XAddr()}
+	doors, nextReference, err := device.GetDoorInfoList(10, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	request, _ := server.LastRequest("GetDoorInfoList")
+	if !strings.Contains(request.Envelope, `<tdc:GetDoorInfoList><tdc:Limit>10</tdc:Limit></tdc:GetDoorInfoList>`) {
+		t.Errorf("unexpected request %s", request.Envelope)
+	}
+
+	expected := []DoorInfo{{
+		Token:        "Door_1",
+		Name:         "Front door",
+		Description:  "Main entrance",
+		Capabilities: DoorCapabilities{Access: true, Lock: true, Unlock: true, DoorMonitor: true, Tamper: true},
+	}}
+	if nextReference != "" || !reflect.DeepEqual(doors, expected) {
+		t.Errorf("expected %+v, got %+v and %q", expected, doors, nextReference)
+	}
+
+	// Timings are sent in order of the schema, and zero ones are omitted
+	options := AccessDoorOptions{UseExtendedTime: true, AccessTime: 5 * time.Second, PreAlarmTime: 1500 * time.Millisecond}
+	if err := device.AccessDoor("Door<1>", options); err != nil {
+		t.Fatal(err)
+	}
+
+	request, _ = server.LastRequest("AccessDoor")
+	body := `<tdc:AccessDoor><tdc:Token>Door&lt;1&gt;</tdc:Token><tdc:UseExtendedTime>true</tdc:UseExtendedTime>` +
+		`<tdc:AccessTime>PT5S</tdc:AccessTime><tdc:PreAlarmTime>PT1.5S</tdc:PreAlarmTime></tdc:AccessDoor>`
+	if !strings.Contains(request.Envelope, body) {
+		t.Errorf("unexpected request %s", request.Envelope)
+	}
+
+	if err := device.LockDoor("Door_1"); err != nil {
+		t.Fatal(err)
+	}
+
+	request, _ = server.LastRequest("LockDoor")
+	if !strings.Contains(request.Envelope, `<tdc:LockDoor><tdc:Token>Door_1</tdc:Token></tdc:LockDoor>`) {
+		t.Errorf("unexpected request %s", request.Envelope)
+	}
+
+	if err := device.UnlockDoor("Door_1"); err != nil {
+		t.Fatal(err)
+	}
+
+	request, _ = server.LastRequest("UnlockDoor")
+	if !strings.Contains(request.Envelope, `<tdc:UnlockDoor><tdc:Token>Door_1</tdc:Token></tdc:UnlockDoor>`) {
+		t.Errorf("unexpected request %s", request.Envelope)
+	}
+}
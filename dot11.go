@@ -0,0 +1,203 @@
+package onvif
+
+import (
+	"encoding/hex"
+	"strconv"
+	"unicode"
+	"unicode/utf8"
+)
+
+// GetDot11Capabilities fetch wireless capabilities of ONVIF camera
+func (device Device) GetDot11Capabilities() (Dot11Capabilities, error) {
+	// Create SOAP
+	soap := SOAP{
+		Body:  "<tds:GetDot11Capabilities/>",
+		XMLNs: deviceXMLNs,
+	}
+
+	// Send SOAP request
+	response, err := device.sendRequest(deviceNamespace, soap)
+	if err != nil {
+		return Dot11Capabilities{}, err
+	}
+
+	// Parse response to interface
+	ifaceCapabilities, err := response.ValueForPath("Envelope.Body.GetDot11CapabilitiesResponse.Capabilities")
+	if err != nil {
+		return Dot11Capabilities{}, err
+	}
+
+	// Parse interface to struct
+	capabilities := Dot11Capabilities{}
+	if mapCapabilities, ok := ifaceCapabilities.(map[string]interface{}); ok {
+		capabilities.TKIP = interfaceToBool(mapCapabilities["TKIP"])
+		capabilities.ScanAvailableNetworks = interfaceToBool(mapCapabilities["ScanAvailableNetworks"])
+		capabilities.MultipleConfiguration = interfaceToBool(mapCapabilities["MultipleConfiguration"])
+		capabilities.AdHocStationMode = interfaceToBool(mapCapabilities["AdHocStationMode"])
+		capabilities.WEP = interfaceToBool(mapCapabilities["WEP"])
+	}
+
+	return capabilities, nil
+}
+
+// GetDot11Status fetch state of wireless network interface of ONVIF camera
+func (device Device) GetDot11Status(interfaceToken string) (Dot11Status, error) {
+	// Create SOAP
+	soap := SOAP{
+		XMLNs: deviceXMLNs,
+		Body: `<tds:GetDot11Status>
+			<tds:InterfaceToken>` + escapeXML(interfaceToken) + `</tds:InterfaceToken>
+		</tds:GetDot11Status>`,
+	}
+
+	// Send SOAP request
+	response, err := device.sendRequest(deviceNamespace, soap)
+	if err != nil {
+		return Dot11Status{}, err
+	}
+
+	// Parse response to interface
+	ifaceStatus, err := response.ValueForPath("Envelope.Body.GetDot11StatusResponse.Status")
+	if err != nil {
+		return Dot11Status{}, err
+	}
+
+	// Parse interface to struct
+	status := Dot11Status{}
+	if mapStatus, ok := ifaceStatus.(map[string]interface{}); ok {
+		status.SSID = parseSSID(mapStatus["SSID"])
+		status.BSSID = interfaceToString(mapStatus["BSSID"])
+		status.PairCipher = interfaceToString(mapStatus["PairCipher"])
+		status.GroupCipher = interfaceToString(mapStatus["GroupCipher"])
+		status.SignalStrength = interfaceToString(mapStatus["SignalStrength"])
+		status.ActiveConfigAlias = interfaceToString(mapStatus["ActiveConfigAlias"])
+	}
+
+	return status, nil
+}
+
+// ScanAvailableDot11Networks scans wireless networks that can be
+// joined by wireless network interface of ONVIF camera
+func (device Device) ScanAvailableDot11Networks(interfaceToken string) ([]Dot11AvailableNetwork, error) {
+	// Create SOAP
+	soap := SOAP{
+		XMLNs: deviceXMLNs,
+		Body: `<tds:ScanAvailableDot11Networks>
+			<tds:InterfaceToken>` + escapeXML(interfaceToken) + `</tds:InterfaceToken>
+		</tds:ScanAvailableDot11Networks>`,
+	}
+
+	// Send SOAP request
+	response, err := device.sendRequest(deviceNamespace, soap)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse response to interface
+	ifaceNetworks, _ := response.ValuesForPath("Envelope.Body.ScanAvailableDot11NetworksResponse.Networks")
+
+	// Convert interface to array of network. The element of
+	// authentication suites is misspelled by ONVIF schema.
+	networks := []Dot11AvailableNetwork{}
+	for _, ifaceNetwork := range ifaceNetworks {
+		if mapNetwork, ok := ifaceNetwork.(map[string]interface{}); ok {
+			networks = append(networks, Dot11AvailableNetwork{
+				SSID:                    parseSSID(mapNetwork["SSID"]),
+				BSSID:                   interfaceToString(mapNetwork["BSSID"]),
+				AuthAndManagementSuites: interfaceToStrings(mapNetwork["AuthAndMangementSuite"]),
+				PairCiphers:             interfaceToStrings(mapNetwork["PairCipher"]),
+				GroupCiphers:            interfaceToStrings(mapNetwork["GroupCipher"]),
+				SignalStrength:          interfaceToString(mapNetwork["SignalStrength"]),
+			})
+		}
+	}
+
+	return networks, nil
+}
+
+// SetNetworkInterfaces sets wireless networks joined by network interface of
+// ONVIF camera, e.g. to connect camera to Wi-Fi during setup. Other settings
+// of the interface are left unchanged. It returns whether camera has to be
+// rebooted to apply the settings.
+func (device Device) SetNetworkInterfaces(interfaceToken string, config NetworkInterfaceSetConfiguration) (bool, error) {
+	// Create SOAP
+	body := `<tds:SetNetworkInterfaces>
+		<tds:InterfaceToken>` + escapeXML(interfaceToken) + `</tds:InterfaceToken>
+		<tds:NetworkInterface>`
+	if config.Enabled != nil {
+		body += `<tt:Enabled>` + strconv.FormatBool(*config.Enabled) + `</tt:Enabled>`
+	}
+	if len(config.Dot11) > 0 {
+		body += `<tt:Extension>`
+		for _, dot11 := range config.Dot11 {
+			body += `<tt:Dot11>` + dot11ConfigurationXML(dot11) + `</tt:Dot11>`
+		}
+		body += `</tt:Extension>`
+	}
+	body += `</tds:NetworkInterface>
+	</tds:SetNetworkInterfaces>`
+
+	soap := SOAP{
+		Body:  body,
+		XMLNs: deviceXMLNs,
+	}
+
+	// Send SOAP request
+	response, err := device.sendRequest(deviceNamespace, soap)
+	if err != nil {
+		return false, err
+	}
+
+	// Parse response
+	ifaceRebootNeeded, _ := response.ValueForPath("Envelope.Body.SetNetworkInterfacesResponse.RebootNeeded")
+	return interfaceToBool(ifaceRebootNeeded), nil
+}
+
+// dot11ConfigurationXML creates content of tt:Dot11Configuration
+func dot11ConfigurationXML(config Dot11Configuration) string {
+	security := config.Security
+	result := `<tt:SSID>` + hex.EncodeToString([]byte(config.SSID)) + `</tt:SSID>
+		<tt:Mode>` + escapeXML(config.Mode) + `</tt:Mode>
+		<tt:Alias>` + escapeXML(config.Alias) + `</tt:Alias>
+		<tt:Priority>` + strconv.Itoa(config.Priority) + `</tt:Priority>
+		<tt:Security>
+			<tt:Mode>` + escapeXML(security.Mode) + `</tt:Mode>`
+	if security.Algorithm != "" {
+		result += `<tt:Algorithm>` + escapeXML(security.Algorithm) + `</tt:Algorithm>`
+	}
+	if len(security.Key) > 0 || security.Passphrase != "" {
+		result += `<tt:PSK>`
+		if len(security.Key) > 0 {
+			result += `<tt:Key>` + hex.EncodeToString(security.Key) + `</tt:Key>`
+		}
+		if security.Passphrase != "" {
+			result += `<tt:Passphrase>` + escapeXML(security.Passphrase) + `</tt:Passphrase>`
+		}
+		result += `</tt:PSK>`
+	}
+	if security.Dot1X != "" {
+		result += `<tt:Dot1X>` + escapeXML(security.Dot1X) + `</tt:Dot1X>`
+	}
+	result += `</tt:Security>`
+	return result
+}
+
+// parseSSID parses SSID, which is encoded as xs:hexBinary. Some cameras
+// send SSID that isn't hex encoded, which is kept as is. Since such SSID
+// might be valid hex too, e.g. "cafe", it's only decoded if the result is
+// printable UTF-8 text.
+func parseSSID(src interface{}) string {
+	ssid := interfaceToString(src)
+	decoded, err := hex.DecodeString(ssid)
+	if err != nil || !utf8.Valid(decoded) {
+		return ssid
+	}
+
+	for _, r := range string(decoded) {
+		if !unicode.IsPrint(r) {
+			return ssid
+		}
+	}
+
+	return string(decoded)
+}
@@ -0,0 +1,170 @@
+package onvif
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/krabiswabbie/go-onvif/onviftest"
+)
+
+func TestDot11(t *testing.T) {
+	server := onviftest.NewServer()
+	defer server.Close()
+
+	server.HandleBody("GetDot11Capabilities", `<tds:GetDot11CapabilitiesResponse>
+		<tds:Capabilities>
+			<tt:TKIP>true</tt:TKIP>
+			<tt:ScanAvailableNetworks>true</tt:ScanAvailableNetworks>
+			<tt:MultipleConfiguration>false</tt:MultipleConfiguration>
+			<tt:AdHocStationMode>false</tt:AdHocStationMode>
+			<tt:WEP>false</tt:WEP>
+		</tds:Capabilities>
+	</tds:GetDot11CapabilitiesResponse>`)
+	server.HandleBody("GetDot11Status", `<tds:GetDot11StatusResponse>
+		<tds:Status>
+			<tt:SSID>4f6666696365</tt:SSID>
+			<tt:BSSID>00:11:22:33:44:55</tt:BSSID>
+			<tt:PairCipher>CCMP</tt:PairCipher>
+			<tt:GroupCipher>CCMP</tt:GroupCipher>
+			<tt:SignalStrength>Good</tt:SignalStrength>
+			<tt:ActiveConfigAlias>office</tt:ActiveConfigAlias>
+		</tds:Status>
+	</tds:GetDot11StatusResponse>`)
+	server.HandleBody("ScanAvailableDot11Networks", `<tds:ScanAvailableDot11NetworksResponse>
+		<tds:Networks>
+			<tt:SSID>4f6666696365</tt:SSID>
+			<tt:AuthAndMangementSuite>PSK</tt:AuthAndMangementSuite>
+			<tt:PairCipher>CCMP</tt:PairCipher>
+			<tt:PairCipher>TKIP</tt:PairCipher>
+			<tt:GroupCipher>TKIP</tt:GroupCipher>
+			<tt:SignalStrength>Very Good</tt:SignalStrength>
+		</tds:Networks>
+		<tds:Networks>
+			<tt:SSID>Guest</tt:SSID>
+			<tt:AuthAndMangementSuite>None</tt:AuthAndMangementSuite>
+		</tds:Networks>
+	</tds:ScanAvailableDot11NetworksResponse>`)
+	server.HandleBody("SetNetworkInterfaces", `<tds:SetNetworkInterfacesResponse>
+		<tds:RebootNeeded>true</tds:RebootNeeded>
+	</tds:SetNetworkInterfacesResponse>`)
+
+	device := Device{XAddr: server.XAddr()}
+	capabilities, err := device.GetDot11Capabilities()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if capabilities != (Dot11Capabilities{TKIP: true, ScanAvailableNetworks: true}) {
+		t.Errorf("unexpected capabilities %+v", capabilities)
+	}
+
+	status, err := device.GetDot11Status("wlan0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedStatus := Dot11Status{
+		SSID:              "Office",
+		BSSID:             "00:11:22:33:44:55",
+		PairCipher:        "CCMP",
+		GroupCipher:       "CCMP",
+		SignalStrength:    "Good",
+		ActiveConfigAlias: "office",
+	}
+	if status != expectedStatus {
+		t.Errorf("got %+v, want %+v", status, expectedStatus)
+	}
+
+	networks, err := device.ScanAvailableDot11Networks("wlan0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedNetworks := []Dot11AvailableNetwork{{
+		SSID:                    "Office",
+		AuthAndManagementSuites: []string{"PSK"},
+		PairCiphers:             []string{"CCMP", "TKIP"},
+		GroupCiphers:            []string{"TKIP"},
+		SignalStrength:          "Very Good",
+	}, {
+		// SSID that isn't hex encoded is kept as is
+		SSID:                    "Guest",
+		AuthAndManagementSuites: []string{"None"},
+	}}
+	if !reflect.DeepEqual(networks, expectedNetworks) {
+		t.Errorf("got %+v, want %+v", networks, expectedNetworks)
+	}
+
+	enabled := true
+	rebootNeeded, err := device.SetNetworkInterfaces("wlan0", NetworkInterfaceSetConfiguration{
+		Enabled: &enabled,
+		Dot11: []Dot11Configuration{{
+			SSID:     "Office",
+			Mode:     "Infrastructure",
+			Alias:    "office",
+			Priority: 1,
+			Security: Dot11SecurityConfiguration{Mode: "PSK", Algorithm: "CCMP", Passphrase: "secret & safe"},
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !rebootNeeded {
+		t.Error("expected reboot to be needed")
+	}
+
+	request, _ := server.LastRequest("SetNetworkInterfaces")
+	for _, element := range []string{
+		"<tds:InterfaceToken>wlan0</tds:InterfaceToken>",
+		"<tt:Enabled>true</tt:Enabled>",
+		"<tt:Extension><tt:Dot11><tt:SSID>4f6666696365</tt:SSID>",
+		"<tt:Algorithm>CCMP</tt:Algorithm><tt:PSK><tt:Passphrase>secret &amp; safe</tt:Passphrase></tt:PSK>",
+	} {
+		if !strings.Contains(request.Envelope, element) {
+			t.Errorf("request doesn't contain %s: %s", element, request.Envelope)
+		}
+	}
+}
+
+func TestSetNetworkInterfacesDot11Only(t *testing.T) {
+	server := onviftest.NewServer()
+	defer server.Close()
+
+	server.HandleBody("SetNetworkInterfaces", `<tds:SetNetworkInterfacesResponse>
+		<tds:RebootNeeded>false</tds:RebootNeeded>
+	</tds:SetNetworkInterfacesResponse>`)
+
+	// Interface isn't disabled if only wireless settings are set
+	device := Device{XAddr: server.XAddr()}
+	_, err := device.SetNetworkInterfaces("wlan0", NetworkInterfaceSetConfiguration{
+		Dot11: []Dot11Configuration{{SSID: "Office", Mode: "Infrastructure", Security: Dot11SecurityConfiguration{Mode: "None"}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	request, _ := server.LastRequest("SetNetworkInterfaces")
+	if strings.Contains(request.Envelope, "Enabled") || !strings.Contains(request.Envelope, "<tt:Dot11>") {
+		t.Errorf("unexpected request %s", request.Envelope)
+	}
+}
+
+func TestParseSSID(t *testing.T) {
+	tests := map[string]string{
+		"4f6666696365":           "Office",
+		"436166c3a92057692d4669": "Café Wi-Fi",
+		"Guest":                  "Guest",
+		"abc":                    "abc",
+		// SSID that isn't hex encoded but looks like hex is kept as is
+		"cafe":     "cafe",
+		"12345678": "12345678",
+	}
+
+	for src, expected := range tests {
+		if ssid := parseSSID(src); ssid != expected {
+			t.Errorf("parseSSID(%q) = %q, want %q", src, ssid, expected)
+		}
+	}
+}
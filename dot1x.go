@@ -0,0 +1,133 @@
+package onvif
+
+import "strconv"
+
+// EAP method type numbers commonly supported by ONVIF camera
+const (
+	EAPMethodMD5  = 4
+	EAPMethodTLS  = 13
+	EAPMethodTTLS = 21
+	EAPMethodPEAP = 25
+)
+
+// GetDot1XConfigurations fetch IEEE 802.1X configurations of ONVIF camera
+func (device Device) GetDot1XConfigurations() ([]Dot1XConfig, error) {
+	// Create SOAP
+	soap := SOAP{
+		Body:  "<tds:GetDot1XConfigurations/>",
+		XMLNs: deviceXMLNs,
+	}
+
+	// Send SOAP request
+	response, err := device.sendRequest(deviceNamespace, soap)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse response to interface
+	ifaceConfigs, err := response.ValuesForPath("Envelope.Body.GetDot1XConfigurationsResponse.Dot1XConfiguration")
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert interface to array of configuration
+	configs := []Dot1XConfig{}
+	for _, ifaceConfig := range ifaceConfigs {
+		mapConfig, ok := ifaceConfig.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		config := Dot1XConfig{
+			Token:            interfaceToString(mapConfig["Dot1XConfigurationToken"]),
+			Identity:         interfaceToString(mapConfig["Identity"]),
+			AnonymousID:      interfaceToString(mapConfig["AnonymousID"]),
+			EAPMethod:        interfaceToInt(mapConfig["EAPMethod"]),
+			CACertificateIDs: interfaceToStrings(mapConfig["CACertificateID"]),
+		}
+
+		if mapMethod, ok := mapConfig["EAPMethodConfiguration"].(map[string]interface{}); ok {
+			if mapTLS, ok := mapMethod["TLSConfiguration"].(map[string]interface{}); ok {
+				config.TLSCertificateID = interfaceToString(mapTLS["CertificateID"])
+			}
+		}
+
+		if config.CACertificateIDs == nil {
+			config.CACertificateIDs = []string{}
+		}
+
+		configs = append(configs, config)
+	}
+
+	return configs, nil
+}
+
+// CreateDot1XConfiguration creates IEEE 802.1X configuration in ONVIF camera
+func (device Device) CreateDot1XConfiguration(config Dot1XConfig) error {
+	return device.sendDot1XConfig("CreateDot1XConfiguration", config)
+}
+
+// SetDot1XConfiguration modifies IEEE 802.1X configuration of ONVIF camera
+func (device Device) SetDot1XConfiguration(config Dot1XConfig) error {
+	return device.sendDot1XConfig("SetDot1XConfiguration", config)
+}
+
+// DeleteDot1XConfiguration removes IEEE 802.1X configurations with the tokens from ONVIF camera
+func (device Device) DeleteDot1XConfiguration(tokens []string) error {
+	// Create SOAP
+	body := `<tds:DeleteDot1XConfiguration>`
+	for _, token := range tokens {
+		body += `<tds:Dot1XConfigurationToken>` + escapeXML(token) + `</tds:Dot1XConfigurationToken>`
+	}
+	body += `</tds:DeleteDot1XConfiguration>`
+
+	soap := SOAP{
+		Body:  body,
+		XMLNs: deviceXMLNs,
+	}
+
+	// Send SOAP request
+	_, err := device.sendRequest(deviceNamespace, soap)
+	return err
+}
+
+// sendDot1XConfig sends IEEE 802.1X configuration with the operation
+func (device Device) sendDot1XConfig(operation string, config Dot1XConfig) error {
+	// Create SOAP
+	body := `<tds:` + operation + `>
+		<tds:Dot1XConfiguration>
+			<tt:Dot1XConfigurationToken>` + escapeXML(config.Token) + `</tt:Dot1XConfigurationToken>
+			<tt:Identity>` + escapeXML(config.Identity) + `</tt:Identity>`
+	if config.AnonymousID != "" {
+		body += `<tt:AnonymousID>` + escapeXML(config.AnonymousID) + `</tt:AnonymousID>`
+	}
+	body += `<tt:EAPMethod>` + strconv.Itoa(config.EAPMethod) + `</tt:EAPMethod>`
+	for _, certificateID := range config.CACertificateIDs {
+		body += `<tt:CACertificateID>` + escapeXML(certificateID) + `</tt:CACertificateID>`
+	}
+
+	if config.TLSCertificateID != "" || config.Password != "" {
+		body += `<tt:EAPMethodConfiguration>`
+		if config.TLSCertificateID != "" {
+			body += `<tt:TLSConfiguration>
+				<tt:CertificateID>` + escapeXML(config.TLSCertificateID) + `</tt:CertificateID>
+			</tt:TLSConfiguration>`
+		}
+		if config.Password != "" {
+			body += `<tt:Password>` + escapeXML(config.Password) + `</tt:Password>`
+		}
+		body += `</tt:EAPMethodConfiguration>`
+	}
+
+	body += `</tds:Dot1XConfiguration>
+	</tds:` + operation + `>`
+
+	soap := SOAP{
+		Body:  body,
+		XMLNs: deviceXMLNs,
+	}
+
+	// Send SOAP request
+	_, err := device.sendRequest(deviceNamespace, soap)
+	return err
+}
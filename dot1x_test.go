@@ -0,0 +1,108 @@
+package onvif
+
+import (
+	"fmt"
+	"log"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/krabiswabbie/go-onvif/onviftest"
+)
+
+func TestGetDot1XConfigurations(t *testing.T) {
+	log.Println("Test GetDot1XConfigurations")
+
+	res, err := testDevice.GetDot1XConfigurations()
+	if err != nil {
+		t.Error(err)
+	}
+
+	js := prettyJSON(&res)
+	fmt.Println(js)
+}
+
+func TestDot1XConfiguration(t *testing.T) {
+	server := onviftest.NewServer()
+	defer server.Close()
+
+	server.HandleBody("GetDot1XConfigurations", `<tds:GetDot1XConfigurationsResponse>
+		<tds:Dot1XConfiguration>
+			<tt:Dot1XConfigurationToken>dot1x0</tt:Dot1XConfigurationToken>
+			<tt:Identity>camera</tt:Identity>
+			<tt:AnonymousID>anonymous</tt:AnonymousID>
+			<tt:EAPMethod>13</tt:EAPMethod>
+			<tt:CACertificateID>ca0</tt:CACertificateID>
+			<tt:CACertificateID>ca1</tt:CACertificateID>
+			<tt:EAPMethodConfiguration>
+				<tt:TLSConfiguration><tt:CertificateID>cert0</tt:CertificateID></tt:TLSConfiguration>
+			</tt:EAPMethodConfiguration>
+		</tds:Dot1XConfiguration>
+		<tds:Dot1XConfiguration>
+			<tt:Dot1XConfigurationToken>dot1x1</tt:Dot1XConfigurationToken>
+			<tt:Identity>guest</tt:Identity>
+			<tt:EAPMethod>25</tt:EAPMethod>
+		</tds:Dot1XConfiguration>
+	</tds:GetDot1XConfigurationsResponse>`)
+	server.HandleBody("CreateDot1XConfiguration", `<tds:CreateDot1XConfigurationResponse/>`)
+	server.HandleBody("SetDot1XConfiguration", `<tds:SetDot1XConfigurationResponse/>`)
+	server.HandleBody("DeleteDot1XConfiguration", `<tds:DeleteDot1XConfigurationResponse/>`)
+
+	device := Device{XAddr: server.XAddr()}
+	configs, err := device.GetDot1XConfigurations()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []Dot1XConfig{
+		{Token: "dot1x0", Identity: "camera", AnonymousID: "anonymous", EAPMethod: EAPMethodTLS,
+			CACertificateIDs: []string{"ca0", "ca1"}, TLSCertificateID: "cert0"},
+		{Token: "dot1x1", Identity: "guest", EAPMethod: EAPMethodPEAP, CACertificateIDs: []string{}},
+	}
+	if !reflect.DeepEqual(configs, expected) {
+		t.Errorf("expected %+v, got %+v", expected, configs)
+	}
+
+	// Certificate is sent with EAP-TLS, and optional elements are omitted
+	config := Dot1XConfig{Token: "dot1x0", Identity: "camera", EAPMethod: EAPMethodTLS, CACertificateIDs: []string{"ca0"}, TLSCertificateID: "cert0"}
+	if err := device.CreateDot1XConfiguration(config); err != nil {
+		t.Fatal(err)
+	}
+
+	request, _ := server.LastRequest("CreateDot1XConfiguration")
+	body := `<tds:CreateDot1XConfiguration><tds:Dot1XConfiguration>` +
+		`<tt:Dot1XConfigurationToken>dot1x0</tt:Dot1XConfigurationToken><tt:Identity>camera</tt:Identity>` +
+		`<tt:EAPMethod>13</tt:EAPMethod><tt:CACertificateID>ca0</tt:CACertificateID>` +
+		`<tt:EAPMethodConfiguration><tt:TLSConfiguration><tt:CertificateID>cert0</tt:CertificateID></tt:TLSConfiguration></tt:EAPMethodConfiguration>` +
+		`</tds:Dot1XConfiguration></tds:CreateDot1XConfiguration>`
+	if !strings.Contains(request.Envelope, body) {
+		t.Errorf("unexpected request %s", request.Envelope)
+	}
+
+	// Password is sent with PEAP, and values are escaped
+	config = Dot1XConfig{Token: "dot1x1", Identity: "guest", AnonymousID: "a&b", EAPMethod: EAPMethodPEAP, Password: "<secret>"}
+	if err := device.SetDot1XConfiguration(config); err != nil {
+		t.Fatal(err)
+	}
+
+	request, _ = server.LastRequest("SetDot1XConfiguration")
+	body = `<tds:SetDot1XConfiguration><tds:Dot1XConfiguration>` +
+		`<tt:Dot1XConfigurationToken>dot1x1</tt:Dot1XConfigurationToken><tt:Identity>guest</tt:Identity>` +
+		`<tt:AnonymousID>a&amp;b</tt:AnonymousID><tt:EAPMethod>25</tt:EAPMethod>` +
+		`<tt:EAPMethodConfiguration><tt:Password>&lt;secret&gt;</tt:Password></tt:EAPMethodConfiguration>` +
+		`</tds:Dot1XConfiguration></tds:SetDot1XConfiguration>`
+	if !strings.Contains(request.Envelope, body) {
+		t.Errorf("unexpected request %s", request.Envelope)
+	}
+
+	if err := device.DeleteDot1XConfiguration([]string{"dot1x0", "dot1x1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	request, _ = server.LastRequest("DeleteDot1XConfiguration")
+	body = `<tds:DeleteDot1XConfiguration><tds:Dot1XConfigurationToken>dot1x0</tds:Dot1XConfigurationToken>` +
+		`<tds:Dot1XConfigurationToken>dot1x1</tds:Dot1XConfigurationToken></tds:DeleteDot1XConfiguration>`
+	if !strings.Contains(request.Envelope, body) {
+		t.Errorf("unexpected request %s", request.Envelope)
+	}
+}
@@ -0,0 +1,176 @@
+package onvif
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"hash"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+var rxDigestParam = regexp.MustCompile(`(\w+)\s*=\s*(?:"([^"]*)"|([^\s,]*))`)
+
+// download fetch content of URI provided by ONVIF camera, e.g. snapshot or system log.
+// Credentials of the device are sent using HTTP digest or basic authentication,
+// depending on which one is requested by camera.
+func (device Device) download(uri string) ([]byte, error) {
+	return device.downloadContext(context.Background(), uri)
+}
+
+// downloadContext is like download, but the download is cancelled when context is done
+func (device Device) downloadContext(ctx context.Context, uri string) ([]byte, error) {
+	// Make sure URL valid and get its credentials
+	urlDownload, err := url.Parse(device.adjustXAddr(uri))
+	if err != nil {
+		return nil, err
+	}
+
+	user, password := device.User, device.Password
+	if urlDownload.User != nil && urlDownload.User.Username() != "" {
+		user = urlDownload.User.Username()
+		password, _ = urlDownload.User.Password()
+	}
+	urlDownload.User = nil
+
+	// Send request, then authenticate if camera asks for it
+	client := device.httpClient()
+	req, err := http.NewRequestWithContext(ctx, "GET", urlDownload.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusUnauthorized && user != "" {
+		challenges := resp.Header.Values("WWW-Authenticate")
+		resp.Body.Close()
+
+		req, err := http.NewRequestWithContext(ctx, "GET", urlDownload.String(), nil)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := authorize(req, challenges, user, password); err != nil {
+			return nil, err
+		}
+
+		resp, err = client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, errors.New("Failed to download " + urlDownload.Path + ": " + resp.Status)
+	}
+
+	return ioutil.ReadAll(resp.Body)
+}
+
+// authorize sets credentials of the request using the challenges sent by
+// camera. Digest authentication is preferred if camera offers several schemes,
+// otherwise basic authentication is used.
+func authorize(req *http.Request, challenges []string, user, password string) error {
+	var digestErr error
+	offersBasic := false
+	for _, challenge := range challenges {
+		scheme := strings.ToLower(challenge)
+		if strings.HasPrefix(scheme, "basic") {
+			offersBasic = true
+		}
+		if !strings.HasPrefix(scheme, "digest ") {
+			continue
+		}
+
+		authorization, err := digestAuthorization(challenge, req.Method, req.URL.RequestURI(), user, password)
+		if err != nil {
+			digestErr = err
+			continue
+		}
+
+		req.Header.Set("Authorization", authorization)
+		return nil
+	}
+
+	// Basic authentication is only used instead of unsupported digest if camera accepts it
+	if digestErr != nil && !offersBasic {
+		return digestErr
+	}
+
+	req.SetBasicAuth(user, password)
+	return nil
+}
+
+// digestAuthorization creates value of Authorization header that
+// responds to the challenge of HTTP digest authentication
+func digestAuthorization(challenge, method, uri, user, password string) (string, error) {
+	// Parse parameters of the challenge
+	params := make(map[string]string)
+	for _, match := range rxDigestParam.FindAllStringSubmatch(challenge[len("digest "):], -1) {
+		params[strings.ToLower(match[1])] = match[2] + match[3]
+	}
+
+	var newHash func() hash.Hash
+	algorithm := params["algorithm"]
+	switch strings.ToUpper(algorithm) {
+	case "", "MD5":
+		newHash = md5.New
+	case "SHA-256":
+		newHash = sha256.New
+	default:
+		return "", errors.New("Unsupported digest algorithm: " + algorithm)
+	}
+
+	digest := func(text string) string {
+		h := newHash()
+		h.Write([]byte(text))
+		return hex.EncodeToString(h.Sum(nil))
+	}
+
+	realm, nonce := params["realm"], params["nonce"]
+	ha1 := digest(user + ":" + realm + ":" + password)
+	ha2 := digest(method + ":" + uri)
+
+	authorization := `Digest username="` + user + `", realm="` + realm + `", nonce="` + nonce + `", uri="` + uri + `"`
+
+	// Use qop=auth if camera supports it, otherwise fallback to RFC 2069
+	qopAuth := false
+	for _, qop := range strings.Split(params["qop"], ",") {
+		qopAuth = qopAuth || strings.TrimSpace(qop) == "auth"
+	}
+
+	if qopAuth {
+		cnonceBytes := make([]byte, 8)
+		if _, err := rand.Read(cnonceBytes); err != nil {
+			return "", err
+		}
+
+		cnonce := hex.EncodeToString(cnonceBytes)
+		nc := "00000001"
+		response := digest(ha1 + ":" + nonce + ":" + nc + ":" + cnonce + ":auth:" + ha2)
+		authorization += `, qop=auth, nc=` + nc + `, cnonce="` + cnonce + `", response="` + response + `"`
+	} else {
+		authorization += `, response="` + digest(ha1+":"+nonce+":"+ha2) + `"`
+	}
+
+	if algorithm != "" {
+		authorization += `, algorithm=` + algorithm
+	}
+
+	if opaque, ok := params["opaque"]; ok {
+		authorization += `, opaque="` + opaque + `"`
+	}
+
+	return authorization, nil
+}
@@ -0,0 +1,265 @@
+package onvif
+
+import (
+	"bytes"
+	"encoding/xml"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const eventsNamespace = "http://www.onvif.org/ver10/events/wsdl"
+
+var eventsXMLNs = []string{
+	`xmlns:tev="http://www.onvif.org/ver10/events/wsdl"`,
+	`xmlns:tt="http://www.onvif.org/ver10/schema"`,
+	`xmlns:wsnt="http://docs.oasis-open.org/wsn/b-2"`,
+	`xmlns:wsa="http://www.w3.org/2005/08/addressing"`,
+	`xmlns:tns1="http://www.onvif.org/ver10/topics"`,
+}
+
+// CreatePullPointSubscription subscribes to events of ONVIF device. TopicFilter
+// is an optional topic expression, e.g. tns1:VideoSource/MotionAlarm, which
+// can be created using TopicFilter and TopicSubtree. Subscription expires after
+// terminationTime unless it's renewed.
+func (device Device) CreatePullPointSubscription(topicFilter string, terminationTime time.Duration) (PullPointSubscription, error) {
+	// Create SOAP
+	body := `<tev:CreatePullPointSubscription>`
+	if topicFilter != "" {
+		body += `<tev:Filter>
+			<wsnt:TopicExpression Dialect="http://www.onvif.org/ver10/tev/topicExpression/ConcreteSet">` +
+			escapeXML(topicFilter) + `</wsnt:TopicExpression>
+		</tev:Filter>`
+	}
+	body += `<tev:InitialTerminationTime>` + formatDuration(terminationTime) + `</tev:InitialTerminationTime>
+	</tev:CreatePullPointSubscription>`
+
+	soap := SOAP{
+		XMLNs: eventsXMLNs,
+		Body:  body,
+	}
+
+	// Send SOAP request, keeping the raw response to echo reference parameters
+	var responseBody []byte
+	capture := HookFuncs{Response: func(info RequestInfo, body []byte, elapsed time.Duration) {
+		responseBody = body
+	}}
+
+	device.Hook = chainHooks(device.Hook, capture)
+	response, err := device.sendRequest(eventsNamespace, soap)
+	if err != nil {
+		return PullPointSubscription{}, err
+	}
+
+	// Parse response to interface
+	ifaceSubscription, err := response.ValueForPath("Envelope.Body.CreatePullPointSubscriptionResponse")
+	if err != nil {
+		return PullPointSubscription{}, err
+	}
+
+	// Parse interface to struct
+	subscription := PullPointSubscription{}
+	if mapSubscription, ok := ifaceSubscription.(map[string]interface{}); ok {
+		subscription.CurrentTime = interfaceToTime(mapSubscription["CurrentTime"])
+		subscription.TerminationTime = interfaceToTime(mapSubscription["TerminationTime"])
+
+		if mapReference, ok := mapSubscription["SubscriptionReference"].(map[string]interface{}); ok {
+			subscription.Address = device.adjustXAddr(interfaceToString(mapReference["Address"]))
+			subscription.ReferenceParameters = referenceParametersXML(responseBody)
+		}
+	}
+
+	return subscription, nil
+}
+
+// PullMessages fetch event messages of a subscription. If there are no
+// messages, device waits up to timeout before responding.
+func (device Device) PullMessages(subscription PullPointSubscription, timeout time.Duration, messageLimit int) ([]NotificationMessage, error) {
+	// Create SOAP
+	soap := SOAP{
+		XMLNs:  eventsXMLNs,
+		Header: subscription.header("http://www.onvif.org/ver10/events/wsdl/PullPointSubscription/PullMessagesRequest"),
+		Body: `<tev:PullMessages>
+			<tev:Timeout>` + formatDuration(timeout) + `</tev:Timeout>
+			<tev:MessageLimit>` + strconv.Itoa(messageLimit) + `</tev:MessageLimit>
+		</tev:PullMessages>`,
+	}
+
+	// Send SOAP request
+	response, err := device.sendRequestTo(subscription.Address, soap)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse response to interface
+	ifaceMessages, err := response.ValuesForPath("Envelope.Body.PullMessagesResponse.NotificationMessage")
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert interface to array of message
+	messages := []NotificationMessage{}
+	for _, ifaceMessage := range ifaceMessages {
+		messages = append(messages, parseNotificationMessage(ifaceMessage))
+	}
+
+	return messages, nil
+}
+
+// Renew extends termination time of a subscription
+func (device Device) Renew(subscription PullPointSubscription, terminationTime time.Duration) error {
+	// Create SOAP
+	soap := SOAP{
+		XMLNs:  eventsXMLNs,
+		Header: subscription.header("http://docs.oasis-open.org/wsn/bw-2/SubscriptionManager/RenewRequest"),
+		Body: `<wsnt:Renew>
+			<wsnt:TerminationTime>` + formatDuration(terminationTime) + `</wsnt:TerminationTime>
+		</wsnt:Renew>`,
+	}
+
+	// Send SOAP request
+	_, err := device.sendRequestTo(subscription.Address, soap)
+	return err
+}
+
+// Unsubscribe terminates a subscription
+func (device Device) Unsubscribe(subscription PullPointSubscription) error {
+	// Create SOAP
+	soap := SOAP{
+		XMLNs:  eventsXMLNs,
+		Header: subscription.header("http://docs.oasis-open.org/wsn/bw-2/SubscriptionManager/UnsubscribeRequest"),
+		Body:   `<wsnt:Unsubscribe/>`,
+	}
+
+	// Send SOAP request
+	_, err := device.sendRequestTo(subscription.Address, soap)
+	return err
+}
+
+// header creates WS-Addressing header for requests sent to the subscription
+func (subscription PullPointSubscription) header(action string) string {
+	return `<wsa:Action>` + action + `</wsa:Action>
+		<wsa:To>` + escapeXML(subscription.Address) + `</wsa:To>` +
+		subscription.ReferenceParameters
+}
+
+// parseNotificationMessage parses wsnt:NotificationMessage
+func parseNotificationMessage(src interface{}) NotificationMessage {
+	message := NotificationMessage{}
+	mapNotification, ok := src.(map[string]interface{})
+	if !ok {
+		return message
+	}
+
+	switch topic := mapNotification["Topic"].(type) {
+	case string:
+		message.Topic = strings.TrimSpace(topic)
+	case map[string]interface{}:
+		message.Topic = strings.TrimSpace(interfaceToString(topic["#text"]))
+	}
+
+	if mapMessage, ok := mapNotification["Message"].(map[string]interface{}); ok {
+		if mapMessage, ok := mapMessage["Message"].(map[string]interface{}); ok {
+			message.UtcTime = interfaceToTime(mapMessage["-UtcTime"])
+			message.PropertyOperation = interfaceToString(mapMessage["-PropertyOperation"])
+			message.Source = interfaceToSimpleItems(mapMessage["Source"])
+			message.Data = interfaceToSimpleItems(mapMessage["Data"])
+		}
+	}
+
+	return message
+}
+
+// referenceParametersXML extracts content of wsa:ReferenceParameters from
+// raw response, so it's echoed back unchanged in header of requests sent to
+// the subscription. Namespaces declared by ancestors of the parameters, e.g.
+// in SOAP envelope, are declared in each parameter, unless it declares them.
+func referenceParametersXML(response []byte) string {
+	decoder := xml.NewDecoder(bytes.NewReader(response))
+	scopes := []map[string]string{}
+	parametersDepth := -1
+	contentStart := int64(0)
+
+	// Offset of each parameter, and namespaces it declares
+	offsets := []int64{}
+	declared := []map[string]string{}
+
+	for {
+		offset := decoder.InputOffset()
+		token, err := decoder.Token()
+		if err != nil {
+			return ""
+		}
+
+		switch element := token.(type) {
+		case xml.StartElement:
+			declarations := map[string]string{}
+			for _, attr := range element.Attr {
+				switch {
+				case attr.Name.Space == "xmlns":
+					declarations[attr.Name.Local] = attr.Value
+				case attr.Name.Space == "" && attr.Name.Local == "xmlns":
+					declarations[""] = attr.Value
+				}
+			}
+
+			if parametersDepth >= 0 && len(scopes) == parametersDepth+1 {
+				offsets = append(offsets, offset)
+				declared = append(declared, declarations)
+			}
+
+			scopes = append(scopes, declarations)
+			if parametersDepth < 0 && element.Name.Local == "ReferenceParameters" {
+				parametersDepth = len(scopes) - 1
+				contentStart = decoder.InputOffset()
+			}
+
+		case xml.EndElement:
+			if len(scopes)-1 != parametersDepth {
+				scopes = scopes[:len(scopes)-1]
+				continue
+			}
+
+			// Namespaces in scope of the parameters
+			inScope := map[string]string{}
+			for _, declarations := range scopes {
+				for prefix, namespace := range declarations {
+					inScope[prefix] = namespace
+				}
+			}
+
+			prefixes := []string{}
+			for prefix := range inScope {
+				prefixes = append(prefixes, prefix)
+			}
+			sort.Strings(prefixes)
+
+			// Declare the namespaces after name of each parameter
+			result := ""
+			last := contentStart
+			for i, parameterOffset := range offsets {
+				nameEnd := parameterOffset + 1
+				for nameEnd < offset && !strings.ContainsRune(" \t\r\n/>", rune(response[nameEnd])) {
+					nameEnd++
+				}
+
+				result += string(response[last:nameEnd])
+				for _, prefix := range prefixes {
+					if _, ok := declared[i][prefix]; ok {
+						continue
+					}
+
+					attr := "xmlns"
+					if prefix != "" {
+						attr += ":" + prefix
+					}
+					result += ` ` + attr + `="` + escapeXML(inScope[prefix]) + `"`
+				}
+				last = nameEnd
+			}
+
+			return strings.TrimSpace(result + string(response[last:offset]))
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package onvif
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/krabiswabbie/go-onvif/onviftest"
+)
+
+func TestReferenceParameters(t *testing.T) {
+	server := onviftest.NewServer()
+	defer server.Close()
+
+	server.Handle("CreatePullPointSubscription", func(onviftest.Request) (string, error) {
+		return `<tev:CreatePullPointSubscriptionResponse xmlns:dom="http://example.com/dom">
+			<tev:SubscriptionReference>
+				<wsa:Address>` + server.URL + onviftest.SubscriptionPath + `</wsa:Address>
+				<wsa:ReferenceParameters xmlns:ext="http://example.com/ext">
+					<dom:SubscriptionId wsa:IsReferenceParameter="true">42</dom:SubscriptionId>
+					<ext:Link Href="http://example.com/link"><ext:Id>7</ext:Id></ext:Link>
+					<own:Key xmlns:own="http://example.com/own" xmlns:dom="http://example.com/other">abc</own:Key>
+				</wsa:ReferenceParameters>
+			</tev:SubscriptionReference>
+			<wsnt:CurrentTime>2020-01-01T00:00:00Z</wsnt:CurrentTime>
+			<wsnt:TerminationTime>2020-01-01T00:01:00Z</wsnt:TerminationTime>
+		</tev:CreatePullPointSubscriptionResponse>`, nil
+	})
+
+	device := Device{XAddr: server.XAddr()}
+	subscription, err := device.CreatePullPointSubscription("", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Parameters are kept as is, with namespaces declared by their ancestors
+	parameters := subscription.ReferenceParameters
+	expected := []string{
+		`<dom:SubscriptionId `,
+		` xmlns:dom="http://example.com/dom"`,
+		` xmlns:ext="http://example.com/ext"`,
+		` wsa:IsReferenceParameter="true">42</dom:SubscriptionId>`,
+		` Href="http://example.com/link"><ext:Id>7</ext:Id></ext:Link>`,
+		` xmlns:own="http://example.com/own" xmlns:dom="http://example.com/other">abc</own:Key>`,
+	}
+	for _, substring := range expected {
+		if !strings.Contains(parameters, substring) {
+			t.Errorf("reference parameters don't contain %q: %s", substring, parameters)
+		}
+	}
+	if strings.Count(parameters, `xmlns:dom=`) != 3 {
+		t.Errorf("namespace redeclared by parameter: %s", parameters)
+	}
+
+	// Header of requests sent to the subscription is well-formed
+	if err := device.Renew(subscription, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+
+	request, _ := server.LastRequest("Renew")
+	if !strings.Contains(request.Envelope, ">42</dom:SubscriptionId>") {
+		t.Errorf("reference parameters not sent: %s", request.Envelope)
+	}
+
+	decoder := xml.NewDecoder(bytes.NewReader([]byte(request.Envelope)))
+	for {
+		if _, err := decoder.Token(); err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatalf("invalid request %s: %v", request.Envelope, err)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package onvif
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/deepch/mxj"
+)
+
+// Errors that can be matched against SOAP fault returned by ONVIF camera
+// using errors.Is, e.g. errors.Is(err, onvif.ErrNotAuthorized)
+var (
+	ErrNotAuthorized       = &SOAPFault{Subcode: "ter:NotAuthorized"}
+	ErrActionNotSupported  = &SOAPFault{Subcode: "ter:ActionNotSupported"}
+	ErrOperationProhibited = &SOAPFault{Subcode: "ter:OperationProhibited"}
+	ErrInvalidArgs         = &SOAPFault{Subcode: "ter:InvalidArgs"}
+	ErrInvalidArgVal       = &SOAPFault{Subcode: "ter:InvalidArgVal"}
+	ErrNoProfile           = &SOAPFault{Subcode: "ter:NoProfile"}
+	ErrNoConfig            = &SOAPFault{Subcode: "ter:NoConfig"}
+	ErrNoEntity            = &SOAPFault{Subcode: "ter:NoEntity"}
+	ErrNotFound            = &SOAPFault{Subcode: "ter:NotFound"}
+)
+
+// SOAPFault is the error returned when ONVIF camera responds with SOAP fault.
+// Code is the generic fault code, e.g. env:Sender. Subcodes contains the
+// nested subcodes, outermost first, and Subcode is the most specific one,
+// e.g. ter:NotAuthorized. Detail contains raw content of fault detail.
+type SOAPFault struct {
+	Code     string   `json:"code" xml:"code"`
+	Subcode  string   `json:"subcode" xml:"subcode"`
+	Subcodes []string `json:"subcodes" xml:"subcodes"`
+	Reason   string   `json:"reason" xml:"reason"`
+	Detail   string   `json:"detail" xml:"detail"`
+}
+
+// Error returns reason of the fault, or its code if reason is empty
+func (fault *SOAPFault) Error() string {
+	switch {
+	case fault.Reason != "":
+		return fault.Reason
+	case fault.Subcode != "":
+		return fault.Subcode
+	default:
+		return fault.Code
+	}
+}
+
+// Is reports whether the fault matches target fault. Target matches if its
+// subcode equals any subcode of the fault, or if it has no subcode and its
+// code equals code of the fault. Namespace prefixes are ignored, since they
+// differ between cameras.
+func (fault *SOAPFault) Is(target error) bool {
+	targetFault, ok := target.(*SOAPFault)
+	if !ok {
+		return false
+	}
+
+	if targetFault.Subcode == "" {
+		return targetFault.Code != "" && localName(targetFault.Code) == localName(fault.Code)
+	}
+
+	for _, subcode := range fault.Subcodes {
+		if localName(subcode) == localName(targetFault.Subcode) {
+			return true
+		}
+	}
+
+	return localName(fault.Subcode) == localName(targetFault.Subcode)
+}
+
+// parseFault returns SOAP fault contained in the response, or nil if there is none
+func parseFault(response mxj.Map, statusCode int) *SOAPFault {
+	ifaceFault, err := response.ValueForPath("Envelope.Body.Fault")
+	if err != nil {
+		if statusCode == http.StatusUnauthorized {
+			return notAuthorizedFault()
+		}
+		return nil
+	}
+
+	mapFault, ok := ifaceFault.(map[string]interface{})
+	if !ok {
+		return &SOAPFault{}
+	}
+
+	fault := &SOAPFault{Subcodes: []string{}}
+
+	// SOAP 1.1 fault
+	if _, ok := mapFault["faultcode"]; ok {
+		fault.Code = interfaceToString(mapFault["faultcode"])
+		fault.Reason = interfaceToString(mapFault["faultstring"])
+		fault.Detail = faultDetail(mapFault["detail"])
+		return fault
+	}
+
+	// SOAP 1.2 fault, where subcodes are nested in the code
+	if mapCode, ok := mapFault["Code"].(map[string]interface{}); ok {
+		fault.Code = interfaceToString(mapCode["Value"])
+
+		mapSubcode, ok := mapCode["Subcode"].(map[string]interface{})
+		for ok {
+			fault.Subcodes = append(fault.Subcodes, interfaceToString(mapSubcode["Value"]))
+			mapSubcode, ok = mapSubcode["Subcode"].(map[string]interface{})
+		}
+
+		if len(fault.Subcodes) > 0 {
+			fault.Subcode = fault.Subcodes[len(fault.Subcodes)-1]
+		}
+	}
+
+	// Reason might be written in several languages, use the first one
+	if mapReason, ok := mapFault["Reason"].(map[string]interface{}); ok {
+		for _, text := range interfaceToMaps(mapReason["Text"]) {
+			fault.Reason = interfaceToString(text["#text"])
+			break
+		}
+		if fault.Reason == "" {
+			fault.Reason = interfaceToString(mapReason["Text"])
+		}
+	}
+
+	fault.Detail = faultDetail(mapFault["Detail"])
+	return fault
+}
+
+// notAuthorizedFault creates fault for response with HTTP 401 status,
+// which is returned by cameras that use HTTP authentication
+func notAuthorizedFault() *SOAPFault {
+	return &SOAPFault{
+		Code:     "env:Sender",
+		Subcode:  "ter:NotAuthorized",
+		Subcodes: []string{"ter:NotAuthorized"},
+		Reason:   "Sender not Authorized",
+	}
+}
+
+// faultDetail returns content of fault detail as string
+func faultDetail(src interface{}) string {
+	switch detail := src.(type) {
+	case nil:
+		return ""
+	case map[string]interface{}:
+		if text, ok := detail["Text"]; ok && len(detail) == 1 {
+			return interfaceToString(text)
+		}
+
+		content := ""
+		for key, value := range detail {
+			if strings.HasPrefix(key, "-") || key == "#text" {
+				continue
+			}
+			xml, _ := mxj.Map{key: value}.Xml()
+			content += string(xml)
+		}
+		return content
+	default:
+		return interfaceToString(detail)
+	}
+}
+
+// localName returns name without its namespace prefix
+func localName(name string) string {
+	if idx := strings.LastIndex(name, ":"); idx >= 0 {
+		return name[idx+1:]
+	}
+	return name
+}
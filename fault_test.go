@@ -0,0 +1,88 @@
+package onvif
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/deepch/mxj"
+)
+
+const testFaultResponse = `<?xml version="1.0" encoding="UTF-8"?>
+<env:Envelope xmlns:env="http://www.w3.org/2003/05/soap-envelope" xmlns:ter="http://www.onvif.org/ver10/error">
+	<env:Body>
+		<env:Fault>
+			<env:Code>
+				<env:Value>env:Sender</env:Value>
+				<env:Subcode>
+					<env:Value>ter:InvalidArgVal</env:Value>
+					<env:Subcode>
+						<env:Value>ter:NoProfile</env:Value>
+					</env:Subcode>
+				</env:Subcode>
+			</env:Code>
+			<env:Reason>
+				<env:Text xml:lang="en">Profile token does not exist</env:Text>
+			</env:Reason>
+			<env:Detail>
+				<env:Text>Profile_3</env:Text>
+			</env:Detail>
+		</env:Fault>
+	</env:Body>
+</env:Envelope>`
+
+func TestParseFault(t *testing.T) {
+	response, err := mxj.NewMapXml([]byte(testFaultResponse))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fault := parseFault(response, http.StatusBadRequest)
+	if fault == nil {
+		t.Fatal("fault not found")
+	}
+
+	if fault.Code != "env:Sender" || fault.Subcode != "ter:NoProfile" || len(fault.Subcodes) != 2 {
+		t.Errorf("unexpected fault codes %+v", fault)
+	}
+
+	if fault.Error() != "Profile token does not exist" {
+		t.Errorf("unexpected reason %q", fault.Error())
+	}
+
+	if fault.Detail != "Profile_3" {
+		t.Errorf("unexpected detail %q", fault.Detail)
+	}
+
+	var faultErr error = fault
+	if !errors.Is(faultErr, ErrNoProfile) || !errors.Is(faultErr, ErrInvalidArgVal) {
+		t.Error("fault doesn't match its subcodes")
+	}
+
+	if errors.Is(faultErr, ErrNotAuthorized) {
+		t.Error("fault matches unrelated subcode")
+	}
+
+	if !errors.Is(faultErr, &SOAPFault{Code: "s:Sender"}) {
+		t.Error("fault doesn't match its code")
+	}
+
+	var soapFault *SOAPFault
+	if !errors.As(faultErr, &soapFault) || soapFault.Subcode != "ter:NoProfile" {
+		t.Error("fault can't be extracted with errors.As")
+	}
+}
+
+func TestNotAuthorizedStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	device := Device{XAddr: server.URL + "/onvif/device_service"}
+	_, err := device.GetHostname()
+	if !errors.Is(err, ErrNotAuthorized) {
+		t.Errorf("expected not authorized fault, got %v", err)
+	}
+}
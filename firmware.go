@@ -0,0 +1,215 @@
+package onvif
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// firmwareHTTPClient is used for firmware upload, which takes far longer than a regular SOAP request
+var firmwareHTTPClient = &http.Client{}
+
+const firmwareContentID = "firmware@go-onvif"
+
+var errNoUploadURI = errors.New("Camera didn't return URI to upload firmware to")
+
+// ProgressFunc is called while firmware is being uploaded to ONVIF camera
+type ProgressFunc func(sent, total int64)
+
+// progressReader reports the number of bytes read so far to a ProgressFunc
+type progressReader struct {
+	reader   io.Reader
+	sent     int64
+	total    int64
+	progress ProgressFunc
+}
+
+func (r *progressReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.sent += int64(n)
+	if r.progress != nil && n > 0 {
+		r.progress(r.sent, r.total)
+	}
+	return n, err
+}
+
+// StartFirmwareUpgrade asks ONVIF camera to prepare for firmware upgrade
+// and returns the URI that firmware image must be uploaded to
+func (device Device) StartFirmwareUpgrade() (FirmwareUpgradeInfo, error) {
+	// Create SOAP
+	soap := SOAP{
+		Body:  "<tds:StartFirmwareUpgrade/>",
+		XMLNs: deviceXMLNs,
+	}
+
+	// Send SOAP request
+	response, err := device.sendRequest(deviceNamespace, soap)
+	if err != nil {
+		return FirmwareUpgradeInfo{}, err
+	}
+
+	// Parse response to interface
+	ifaceUpgrade, err := response.ValueForPath("Envelope.Body.StartFirmwareUpgradeResponse")
+	if err != nil {
+		return FirmwareUpgradeInfo{}, err
+	}
+
+	// Parse interface to struct
+	result := FirmwareUpgradeInfo{}
+	if mapUpgrade, ok := ifaceUpgrade.(map[string]interface{}); ok {
+		result.UploadURI = interfaceToString(mapUpgrade["UploadUri"])
+		result.UploadDelay, _ = parseDuration(interfaceToString(mapUpgrade["UploadDelay"]))
+		result.ExpectedDownTime, _ = parseDuration(interfaceToString(mapUpgrade["ExpectedDownTime"]))
+	}
+
+	return result, nil
+}
+
+// UploadFirmware sends firmware image to upload URI returned by StartFirmwareUpgrade
+func (device Device) UploadFirmware(uploadURI string, firmware io.Reader, size int64, progress ProgressFunc) error {
+	// Make sure URL valid
+	urlUpload, err := url.Parse(uploadURI)
+	if err != nil {
+		return err
+	}
+
+	// Create HTTP request
+	body := &progressReader{reader: firmware, total: size, progress: progress}
+	req, err := http.NewRequest("POST", urlUpload.String(), body)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	req.Header.Set("Content-Type", "application/octet-stream")
+
+	if device.User != "" && urlUpload.User == nil {
+		req.SetBasicAuth(device.User, device.Password)
+	}
+
+	// Send request, then clear the cache, since camera
+	// might have other capabilities once it's upgraded
+	resp, err := device.firmwareClient().Do(req)
+	if device.Cache != nil {
+		device.Cache.Invalidate()
+	}
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return errors.New("Failed to upload firmware: " + resp.Status)
+	}
+
+	return nil
+}
+
+// UpgradeSystemFirmware upgrades firmware using the legacy operation, where
+// firmware image is sent as MTOM attachment of the SOAP request.
+// It returns message from ONVIF camera, e.g. expected down time.
+func (device Device) UpgradeSystemFirmware(firmware []byte, progress ProgressFunc) (string, error) {
+	// Create SOAP
+	soap := SOAP{
+		XMLNs: append(deviceXMLNs,
+			`xmlns:xop="http://www.w3.org/2004/08/xop/include"`,
+			`xmlns:xmime="http://www.w3.org/2005/05/xmlmime"`),
+		Body: `<tds:UpgradeSystemFirmware>
+			<tds:Firmware xmime:contentType="application/octet-stream">
+				<xop:Include href="cid:` + firmwareContentID + `"/>
+			</tds:Firmware>
+		</tds:UpgradeSystemFirmware>`,
+	}
+
+	soap.User = device.User
+	soap.Password = device.Password
+	request, urlXAddr, err := soap.prepareRequest(device.serviceXAddr(deviceNamespace))
+	if err != nil {
+		return "", err
+	}
+
+	// Create MTOM message that contains SOAP envelope and firmware image
+	buffer := &bytes.Buffer{}
+	writer := multipart.NewWriter(buffer)
+
+	rootHeader := textproto.MIMEHeader{}
+	rootHeader.Set("Content-Type", `application/xop+xml; charset=UTF-8; type="application/soap+xml"`)
+	rootHeader.Set("Content-Transfer-Encoding", "8bit")
+	rootHeader.Set("Content-ID", "<root@go-onvif>")
+	rootPart, err := writer.CreatePart(rootHeader)
+	if err != nil {
+		return "", err
+	}
+	rootPart.Write([]byte(request))
+
+	firmwareHeader := textproto.MIMEHeader{}
+	firmwareHeader.Set("Content-Type", "application/octet-stream")
+	firmwareHeader.Set("Content-Transfer-Encoding", "binary")
+	firmwareHeader.Set("Content-ID", "<"+firmwareContentID+">")
+	firmwarePart, err := writer.CreatePart(firmwareHeader)
+	if err != nil {
+		return "", err
+	}
+	firmwarePart.Write(firmware)
+	writer.Close()
+
+	// Create HTTP request
+	size := int64(buffer.Len())
+	body := &progressReader{reader: buffer, total: size, progress: progress}
+	req, err := http.NewRequest("POST", urlXAddr, body)
+	if err != nil {
+		return "", err
+	}
+	req.ContentLength = size
+	req.Header.Set("Content-Type", `multipart/related; type="application/xop+xml"; `+
+		`start="<root@go-onvif>"; start-info="application/soap+xml"; boundary=`+strconv.Quote(writer.Boundary()))
+
+	// Send request. The request isn't sent through the cache, which is
+	// cleared since camera might have other capabilities once it's upgraded.
+	response, err := sendHTTPRequest(device.firmwareClient(), req, device.Hook, newRequestInfo(soap, urlXAddr, request))
+	if device.Cache != nil {
+		device.Cache.Invalidate()
+	}
+	if err != nil {
+		return "", err
+	}
+
+	// Parse response
+	message, _ := response.ValueForPathString("Envelope.Body.UpgradeSystemFirmwareResponse.Message")
+	return message, nil
+}
+
+// firmwareClient returns HTTP client used to upload firmware.
+// HTTP client of the device is preferred if it's specified.
+func (device Device) firmwareClient() *http.Client {
+	return device.clientFor(firmwareHTTPClient)
+}
+
+// UpgradeFirmware uploads firmware image to ONVIF camera. It uses StartFirmwareUpgrade
+// and falls back to the legacy UpgradeSystemFirmware if camera doesn't support it.
+func (device Device) UpgradeFirmware(firmware []byte, progress ProgressFunc) error {
+	// Prepare for upgrade
+	info, err := device.StartFirmwareUpgrade()
+	if errors.Is(err, ErrActionNotSupported) {
+		_, err = device.UpgradeSystemFirmware(firmware, progress)
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	if info.UploadURI == "" {
+		return errNoUploadURI
+	}
+
+	// Camera might need some time before it's ready to receive firmware
+	time.Sleep(info.UploadDelay)
+
+	// Upload the firmware
+	return device.UploadFirmware(info.UploadURI, bytes.NewReader(firmware), int64(len(firmware)), progress)
+}
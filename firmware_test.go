@@ -0,0 +1,47 @@
+package onvif
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/krabiswabbie/go-onvif/onviftest"
+)
+
+func TestUpgradeFirmwareErrors(t *testing.T) {
+	server := onviftest.NewServer()
+	defer server.Close()
+
+	// Mock camera doesn't parse MTOM request, so requests are recorded by hook
+	operations := map[string]bool{}
+	hook := HookFuncs{Request: func(info RequestInfo) {
+		operations[info.Operation] = true
+	}}
+
+	device := Device{XAddr: server.XAddr(), Hook: hook}
+
+	// Firmware isn't sent again through the legacy operation if the request fails for other reasons
+	server.HandleFault("StartFirmwareUpgrade", onviftest.SenderFault("ter:NotAuthorized", "Sender not Authorized"))
+	if err := device.UpgradeFirmware([]byte("firmware"), nil); !errors.Is(err, ErrNotAuthorized) {
+		t.Errorf("expected ter:NotAuthorized fault, got %v", err)
+	}
+
+	if operations["UpgradeSystemFirmware"] {
+		t.Error("unexpected fallback to UpgradeSystemFirmware")
+	}
+
+	server.HandleBody("StartFirmwareUpgrade", `<tds:StartFirmwareUpgradeResponse>
+		<tds:UploadUri></tds:UploadUri>
+		<tds:UploadDelay>PT0S</tds:UploadDelay>
+		<tds:ExpectedDownTime>PT60S</tds:ExpectedDownTime>
+	</tds:StartFirmwareUpgradeResponse>`)
+	if err := device.UpgradeFirmware([]byte("firmware"), nil); err != errNoUploadURI {
+		t.Errorf("expected %v, got %v", errNoUploadURI, err)
+	}
+
+	// Legacy operation is used if camera doesn't support StartFirmwareUpgrade
+	server.HandleFault("StartFirmwareUpgrade", onviftest.SenderFault("ter:ActionNotSupported", "Not supported"))
+	device.UpgradeFirmware([]byte("firmware"), nil)
+	if !operations["UpgradeSystemFirmware"] {
+		t.Error("expected fallback to UpgradeSystemFirmware")
+	}
+}
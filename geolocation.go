@@ -0,0 +1,141 @@
+package onvif
+
+import "strconv"
+
+// GetGeoLocation fetch geolocation of ONVIF camera and its entities
+func (device Device) GetGeoLocation() ([]LocationEntity, error) {
+	// Create SOAP
+	soap := SOAP{
+		Body:  "<tds:GetGeoLocation/>",
+		XMLNs: deviceXMLNs,
+	}
+
+	// Send SOAP request
+	response, err := device.sendRequest(deviceNamespace, soap)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse response to interface
+	ifaceLocations, err := response.ValuesForPath("Envelope.Body.GetGeoLocationResponse.Location")
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert interface to array of location
+	locations := []LocationEntity{}
+	for _, ifaceLocation := range ifaceLocations {
+		mapLocation, ok := ifaceLocation.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		location := LocationEntity{
+			Entity:    interfaceToString(mapLocation["-Entity"]),
+			Token:     interfaceToString(mapLocation["-Token"]),
+			Fixed:     interfaceToBool(mapLocation["-Fixed"]),
+			GeoSource: interfaceToString(mapLocation["-GeoSource"]),
+			AutoGeo:   interfaceToBool(mapLocation["-AutoGeo"]),
+		}
+
+		if mapGeo, ok := mapLocation["GeoLocation"].(map[string]interface{}); ok {
+			location.GeoLocation = GeoLocation{
+				Lon:       interfaceToFloat(mapGeo["-lon"]),
+				Lat:       interfaceToFloat(mapGeo["-lat"]),
+				Elevation: interfaceToFloat(mapGeo["-elevation"]),
+			}
+		}
+
+		if mapOrientation, ok := mapLocation["GeoOrientation"].(map[string]interface{}); ok {
+			location.GeoOrientation = GeoOrientation{
+				Roll:  interfaceToFloat(mapOrientation["-roll"]),
+				Pitch: interfaceToFloat(mapOrientation["-pitch"]),
+				Yaw:   interfaceToFloat(mapOrientation["-yaw"]),
+			}
+		}
+
+		locations = append(locations, location)
+	}
+
+	return locations, nil
+}
+
+// SetGeoLocation sets geolocation of ONVIF camera and its entities
+func (device Device) SetGeoLocation(locations []LocationEntity) error {
+	return device.sendGeoLocation("SetGeoLocation", locations)
+}
+
+// DeleteGeoLocation removes geolocation of entities of ONVIF camera.
+// Only Entity and Token of the locations are used.
+func (device Device) DeleteGeoLocation(locations []LocationEntity) error {
+	return device.sendGeoLocation("DeleteGeoLocation", locations)
+}
+
+// sendGeoLocation sends locations with the operation
+func (device Device) sendGeoLocation(operation string, locations []LocationEntity) error {
+	// Create SOAP
+	body := `<tds:` + operation + `>`
+	for _, location := range locations {
+		body += `<tds:Location` +
+			` Entity="` + escapeXML(location.Entity) + `"` +
+			` Token="` + escapeXML(location.Token) + `"` +
+			` Fixed="` + strconv.FormatBool(location.Fixed) + `"`
+		if location.GeoSource != "" {
+			body += ` GeoSource="` + escapeXML(location.GeoSource) + `"`
+		}
+		body += ` AutoGeo="` + strconv.FormatBool(location.AutoGeo) + `">`
+
+		if operation != "DeleteGeoLocation" {
+			body += geoLocationXML("tt:GeoLocation", location.GeoLocation) + `
+				<tt:GeoOrientation` +
+				` roll="` + formatFloat(location.GeoOrientation.Roll) + `"` +
+				` pitch="` + formatFloat(location.GeoOrientation.Pitch) + `"` +
+				` yaw="` + formatFloat(location.GeoOrientation.Yaw) + `"/>`
+		}
+
+		body += `</tds:Location>`
+	}
+	body += `</tds:` + operation + `>`
+
+	soap := SOAP{
+		Body:  body,
+		XMLNs: deviceXMLNs,
+	}
+
+	// Send SOAP request
+	_, err := device.sendRequest(deviceNamespace, soap)
+	return err
+}
+
+// GeoMove moves PTZ camera to look at the geolocation. Area width and height,
+// in meters, are optional and used by camera to zoom to the area.
+func (device Device) GeoMove(profileToken string, target GeoLocation, areaWidth, areaHeight float64) error {
+	// Create SOAP
+	body := `<tptz:GeoMove>
+		<tptz:ProfileToken>` + escapeXML(profileToken) + `</tptz:ProfileToken>` +
+		geoLocationXML("tptz:Target", target)
+	if areaHeight > 0 {
+		body += `<tptz:AreaHeight>` + formatFloat(areaHeight) + `</tptz:AreaHeight>`
+	}
+	if areaWidth > 0 {
+		body += `<tptz:AreaWidth>` + formatFloat(areaWidth) + `</tptz:AreaWidth>`
+	}
+	body += `</tptz:GeoMove>`
+
+	soap := SOAP{
+		Body:  body,
+		XMLNs: ptzXMLNs,
+	}
+
+	// Send SOAP request
+	_, err := device.sendRequest(ptzNamespace, soap)
+	return err
+}
+
+// geoLocationXML creates element with attributes of the geolocation
+func geoLocationXML(element string, location GeoLocation) string {
+	return `<` + element +
+		` lon="` + formatFloat(location.Lon) + `"` +
+		` lat="` + formatFloat(location.Lat) + `"` +
+		` elevation="` + formatFloat(location.Elevation) + `"/>`
+}
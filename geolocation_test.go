@@ -0,0 +1,73 @@
+package onvif
+
+import (
+	"fmt"
+	"log"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/krabiswabbie/go-onvif/onviftest"
+)
+
+func TestGetGeoLocation(t *testing.T) {
+	log.Println("Test GetGeoLocation")
+
+	res, err := testDevice.GetGeoLocation()
+	if err != nil {
+		t.Error(err)
+	}
+
+	js := prettyJSON(&res)
+	fmt.Println(js)
+}
+
+func TestGeoLocation(t *testing.T) {
+	server := onviftest.NewServer()
+	defer server.Close()
+
+	server.HandleBody("SetGeoLocation", `<tds:SetGeoLocationResponse/>`)
+	server.HandleBody("GeoMove", `<tptz:GeoMoveResponse/>`)
+	server.HandleBody("GetGeoLocation", `<tds:GetGeoLocationResponse>
+		<tds:Location Entity="VideoSource" Token="source0" Fixed="true" AutoGeo="false">
+			<tt:GeoLocation lon="106.8456" lat="-6.2088" elevation="12.5"/>
+			<tt:GeoOrientation roll="0" pitch="-15" yaw="270"/>
+		</tds:Location>
+	</tds:GetGeoLocationResponse>`)
+
+	device := Device{XAddr: server.XAddr()}
+	expected := LocationEntity{
+		Entity:         "VideoSource",
+		Token:          "source0",
+		Fixed:          true,
+		GeoLocation:    GeoLocation{Lon: 106.8456, Lat: -6.2088, Elevation: 12.5},
+		GeoOrientation: GeoOrientation{Pitch: -15, Yaw: 270},
+	}
+
+	if err := device.SetGeoLocation([]LocationEntity{expected}); err != nil {
+		t.Fatal(err)
+	}
+
+	request, _ := server.LastRequest("SetGeoLocation")
+	if !strings.Contains(request.Envelope, `<tt:GeoLocation lon="106.8456" lat="-6.2088" elevation="12.5"/>`) {
+		t.Errorf("unexpected request %s", request.Envelope)
+	}
+
+	locations, err := device.GetGeoLocation()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(locations, []LocationEntity{expected}) {
+		t.Errorf("unexpected locations %+v", locations)
+	}
+
+	if err := device.GeoMove("profile0", expected.GeoLocation, 0, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	request, _ = server.LastRequest("GeoMove")
+	if !strings.Contains(request.Envelope, `<tptz:Target lon="106.8456"`) {
+		t.Errorf("unexpected request %s", request.Envelope)
+	}
+}
@@ -0,0 +1,123 @@
+package onvif
+
+import (
+	"net/url"
+	"regexp"
+	"time"
+)
+
+var (
+	rxOperation   = regexp.MustCompile(`<\s*(?:[\w.-]+:)?([\w.-]+)`)
+	rxCredentials = regexp.MustCompile(`(<(?:[\w.-]+:)?(?:Password|Passphrase|Key|Nonce)\b[^>]*>)[^<]*(</(?:[\w.-]+:)?(?:Password|Passphrase|Key|Nonce)\s*>)`)
+)
+
+// RequestInfo contains data of a SOAP request sent to ONVIF camera
+type RequestInfo struct {
+	XAddr     string
+	Operation string
+	Envelope  string
+}
+
+// RequestHook is notified about SOAP requests sent to ONVIF camera,
+// e.g. to log the raw envelopes or to trace the latency.
+// OnResponse is called whenever a response is received, including the
+// ones that contain SOAP fault. OnError is called whenever the request
+// fails, including because of SOAP fault.
+type RequestHook interface {
+	OnRequest(info RequestInfo)
+	OnResponse(info RequestInfo, response []byte, elapsed time.Duration)
+	OnError(info RequestInfo, err error, elapsed time.Duration)
+}
+
+// HookFuncs is a RequestHook that calls the specified functions.
+// Nil functions are ignored.
+type HookFuncs struct {
+	Request  func(info RequestInfo)
+	Response func(info RequestInfo, response []byte, elapsed time.Duration)
+	Error    func(info RequestInfo, err error, elapsed time.Duration)
+}
+
+// OnRequest calls Request function
+func (hook HookFuncs) OnRequest(info RequestInfo) {
+	if hook.Request != nil {
+		hook.Request(info)
+	}
+}
+
+// OnResponse calls Response function
+func (hook HookFuncs) OnResponse(info RequestInfo, response []byte, elapsed time.Duration) {
+	if hook.Response != nil {
+		hook.Response(info, response, elapsed)
+	}
+}
+
+// OnError calls Error function
+func (hook HookFuncs) OnError(info RequestInfo, err error, elapsed time.Duration) {
+	if hook.Error != nil {
+		hook.Error(info, err, elapsed)
+	}
+}
+
+// RedactEnvelope replaces content of password, nonce and wireless key
+// elements in SOAP envelope, so it can be logged safely
+func RedactEnvelope(envelope string) string {
+	return rxCredentials.ReplaceAllString(envelope, "${1}***${2}")
+}
+
+// newRequestInfo creates info of a SOAP request. Credentials are removed from xAddr.
+func newRequestInfo(soap SOAP, xaddr string, envelope string) RequestInfo {
+	if urlXAddr, err := url.Parse(normalizeZone(xaddr)); err == nil {
+		urlXAddr.User = nil
+		xaddr = urlXAddr.String()
+	}
+
+	operation := ""
+	if match := rxOperation.FindStringSubmatch(soap.Body); match != nil {
+		operation = match[1]
+	}
+
+	return RequestInfo{
+		XAddr:     xaddr,
+		Operation: operation,
+		Envelope:  envelope,
+	}
+}
+
+// hookChain is a RequestHook that notifies each of the hooks in order
+type hookChain []RequestHook
+
+// chainHooks combines hooks into one, ignoring nil hooks
+func chainHooks(hooks ...RequestHook) RequestHook {
+	chain := hookChain{}
+	for _, hook := range hooks {
+		if hook != nil {
+			chain = append(chain, hook)
+		}
+	}
+
+	if len(chain) == 1 {
+		return chain[0]
+	}
+	return chain
+}
+
+// OnRequest notifies each hook about the request
+func (chain hookChain) OnRequest(info RequestInfo) {
+	for _, hook := range chain {
+		hook.OnRequest(info)
+	}
+}
+
+// OnResponse notifies each hook about the response
+func (chain hookChain) OnResponse(info RequestInfo, response []byte, elapsed time.Duration) {
+	for _, hook := range chain {
+		hook.OnResponse(info, response, elapsed)
+	}
+}
+
+// OnError notifies each hook about the error
+func (chain hookChain) OnError(info RequestInfo, err error, elapsed time.Duration) {
+	for _, hook := range chain {
+		hook.OnError(info, err, elapsed)
+	}
+}
@@ -0,0 +1,74 @@
+package onvif
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRequestHook(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/soap+xml")
+		fmt.Fprint(w, testHostnameResponse)
+	}))
+	defer server.Close()
+
+	var requests, responses []RequestInfo
+	hook := HookFuncs{
+		Request: func(info RequestInfo) {
+			requests = append(requests, info)
+		},
+		Response: func(info RequestInfo, response []byte, elapsed time.Duration) {
+			responses = append(responses, info)
+			if !strings.Contains(string(response), "GetHostnameResponse") {
+				t.Errorf("unexpected response %s", response)
+			}
+		},
+		Error: func(info RequestInfo, err error, elapsed time.Duration) {
+			t.Errorf("unexpected error %v", err)
+		},
+	}
+
+	device := Device{XAddr: server.URL + "/onvif/device_service", User: "admin", Password: "secret", Hook: hook}
+	if _, err := device.GetHostname(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(requests) != 1 || len(responses) != 1 {
+		t.Fatalf("hook called %d times on request and %d times on response", len(requests), len(responses))
+	}
+
+	info := requests[0]
+	if info.Operation != "GetHostname" {
+		t.Errorf("unexpected operation %q", info.Operation)
+	}
+	if info.XAddr != device.XAddr {
+		t.Errorf("unexpected xaddr %q", info.XAddr)
+	}
+	if !strings.Contains(info.Envelope, "<Username>admin</Username>") {
+		t.Errorf("envelope doesn't contain user token: %s", info.Envelope)
+	}
+}
+
+func TestRedactEnvelope(t *testing.T) {
+	envelope := `<Security><UsernameToken><Username>admin</Username>` +
+		`<Password Type="digest">c2VjcmV0</Password><Nonce EncodingType="base64">bm9uY2U=</Nonce>` +
+		`</UsernameToken></Security><s:Body><tds:CreateUsers><tds:User><tt:Username>user</tt:Username>` +
+		`<tt:Password>hunter2</tt:Password></tds:User></tds:CreateUsers>` +
+		`<tt:PSK><tt:Key>0a1b2c</tt:Key><tt:Passphrase>wifi-secret</tt:Passphrase></tt:PSK></s:Body>`
+
+	redacted := RedactEnvelope(envelope)
+	for _, secret := range []string{"c2VjcmV0", "bm9uY2U=", "hunter2", "0a1b2c", "wifi-secret"} {
+		if strings.Contains(redacted, secret) {
+			t.Errorf("redacted envelope still contains %q: %s", secret, redacted)
+		}
+	}
+
+	expected := `<Password Type="digest">***</Password>`
+	if !strings.Contains(redacted, expected) {
+		t.Errorf("redacted envelope doesn't contain %q: %s", expected, redacted)
+	}
+}
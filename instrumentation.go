@@ -0,0 +1,143 @@
+package onvif
+
+import (
+	"errors"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// Instrumentation is notified about each SOAP request sent to ONVIF camera,
+// so metrics and traces can be collected, e.g. with Prometheus or OpenTelemetry,
+// without this package depending on them. StartRequest is called before the
+// request is sent, and End of the returned span once it completes, after all
+// of its retries. Unlike RequestHook, each retry isn't reported separately.
+type Instrumentation interface {
+	StartRequest(info RequestInfo) RequestSpan
+}
+
+// RequestSpan tracks a single SOAP request, including its retries
+type RequestSpan interface {
+	End(result RequestResult)
+}
+
+// RequestResult contains result of a SOAP request.
+// Err is nil if the request succeeded.
+type RequestResult struct {
+	Attempts int
+	Elapsed  time.Duration
+	Err      error
+}
+
+// IsFault reports whether the request failed because of SOAP fault,
+// as opposed to network failure
+func (result RequestResult) IsFault() bool {
+	var fault *SOAPFault
+	return errors.As(result.Err, &fault)
+}
+
+// MetricsKey identifies the camera, by host of its XAddr, and operation of requests
+type MetricsKey struct {
+	Host      string `json:"host" xml:"host"`
+	Operation string `json:"operation" xml:"operation"`
+}
+
+// OperationMetrics contains metrics of requests of an operation sent to a camera.
+// Requests is the number of completed requests, of which Faults failed because
+// of SOAP fault and Errors because of any other reason. Retries is the number
+// of additional attempts. Latency is sum of durations of all the requests.
+type OperationMetrics struct {
+	Requests   int64         `json:"requests" xml:"requests"`
+	Faults     int64         `json:"faults" xml:"faults"`
+	Errors     int64         `json:"errors" xml:"errors"`
+	Retries    int64         `json:"retries" xml:"retries"`
+	Latency    time.Duration `json:"latency" xml:"latency"`
+	MaxLatency time.Duration `json:"maxLatency" xml:"maxLatency"`
+}
+
+// MetricsCollector is an Instrumentation that collects metrics of requests
+// per camera and operation. Snapshot of the metrics can be exported
+// periodically, e.g. by a Prometheus collector. It's safe for concurrent use,
+// and its zero value is ready to use.
+type MetricsCollector struct {
+	mutex   sync.Mutex
+	metrics map[MetricsKey]*OperationMetrics
+}
+
+// NewMetricsCollector creates empty metrics collector
+func NewMetricsCollector() *MetricsCollector {
+	return &MetricsCollector{metrics: map[MetricsKey]*OperationMetrics{}}
+}
+
+// StartRequest starts tracking the request
+func (collector *MetricsCollector) StartRequest(info RequestInfo) RequestSpan {
+	key := MetricsKey{Operation: info.Operation, Host: info.XAddr}
+	if urlXAddr, err := url.Parse(info.XAddr); err == nil && urlXAddr.Host != "" {
+		key.Host = urlXAddr.Host
+	}
+
+	return metricsSpan{collector: collector, key: key}
+}
+
+// Snapshot returns copy of the current metrics
+func (collector *MetricsCollector) Snapshot() map[MetricsKey]OperationMetrics {
+	collector.mutex.Lock()
+	defer collector.mutex.Unlock()
+
+	snapshot := make(map[MetricsKey]OperationMetrics, len(collector.metrics))
+	for key, metrics := range collector.metrics {
+		snapshot[key] = *metrics
+	}
+
+	return snapshot
+}
+
+// Reset removes all collected metrics
+func (collector *MetricsCollector) Reset() {
+	collector.mutex.Lock()
+	defer collector.mutex.Unlock()
+	collector.metrics = map[MetricsKey]*OperationMetrics{}
+}
+
+// record adds the result of a request to metrics of the key
+func (collector *MetricsCollector) record(key MetricsKey, result RequestResult) {
+	collector.mutex.Lock()
+	defer collector.mutex.Unlock()
+
+	if collector.metrics == nil {
+		collector.metrics = map[MetricsKey]*OperationMetrics{}
+	}
+
+	metrics, ok := collector.metrics[key]
+	if !ok {
+		metrics = &OperationMetrics{}
+		collector.metrics[key] = metrics
+	}
+
+	metrics.Requests++
+	if result.Attempts > 1 {
+		metrics.Retries += int64(result.Attempts - 1)
+	}
+
+	if result.IsFault() {
+		metrics.Faults++
+	} else if result.Err != nil {
+		metrics.Errors++
+	}
+
+	metrics.Latency += result.Elapsed
+	if result.Elapsed > metrics.MaxLatency {
+		metrics.MaxLatency = result.Elapsed
+	}
+}
+
+// metricsSpan records result of a request into MetricsCollector
+type metricsSpan struct {
+	collector *MetricsCollector
+	key       MetricsKey
+}
+
+// End records result of the request
+func (span metricsSpan) End(result RequestResult) {
+	span.collector.record(span.key, result)
+}
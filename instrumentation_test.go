@@ -0,0 +1,87 @@
+package onvif
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/krabiswabbie/go-onvif/onviftest"
+)
+
+func TestMetricsCollector(t *testing.T) {
+	server := onviftest.NewServer()
+	defer server.Close()
+
+	collector := NewMetricsCollector()
+	device := Device{XAddr: server.XAddr(), Instrumentation: collector}
+
+	for i := 0; i < 2; i++ {
+		if _, err := device.GetProfiles(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	server.HandleFault("GetHostname", onviftest.SenderFault("ter:ActionNotSupported", "Not supported"))
+	if _, err := device.GetHostname(); err == nil {
+		t.Fatal("expected fault")
+	}
+
+	// Request that fails because of network is retried, but reported once
+	broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if conn, _, err := w.(http.Hijacker).Hijack(); err == nil {
+			conn.Close()
+		}
+	}))
+	defer broken.Close()
+
+	brokenDevice := Device{
+		XAddr:           broken.URL + "/onvif/device_service",
+		Instrumentation: collector,
+		Retry:           RetryPolicy{MaxRetries: 2, InitialBackoff: time.Millisecond},
+	}
+	if _, err := brokenDevice.GetHostname(); err == nil {
+		t.Fatal("expected error")
+	}
+
+	urlServer, _ := url.Parse(server.URL)
+	urlBroken, _ := url.Parse(broken.URL)
+	snapshot := collector.Snapshot()
+
+	profiles := snapshot[MetricsKey{Host: urlServer.Host, Operation: "GetProfiles"}]
+	if profiles.Requests != 2 || profiles.Faults != 0 || profiles.Errors != 0 || profiles.Latency <= 0 {
+		t.Errorf("unexpected GetProfiles metrics %+v", profiles)
+	}
+
+	hostname := snapshot[MetricsKey{Host: urlServer.Host, Operation: "GetHostname"}]
+	if hostname.Requests != 1 || hostname.Faults != 1 || hostname.Errors != 0 {
+		t.Errorf("unexpected GetHostname metrics %+v", hostname)
+	}
+
+	failed := snapshot[MetricsKey{Host: urlBroken.Host, Operation: "GetHostname"}]
+	if failed.Requests != 1 || failed.Errors != 1 || failed.Retries != 2 {
+		t.Errorf("unexpected metrics of broken camera %+v", failed)
+	}
+
+	collector.Reset()
+	if len(collector.Snapshot()) != 0 {
+		t.Error("metrics not reset")
+	}
+}
+
+func TestMetricsCollectorZeroValue(t *testing.T) {
+	server := onviftest.NewServer()
+	defer server.Close()
+
+	collector := &MetricsCollector{}
+	device := Device{XAddr: server.XAddr(), Instrumentation: collector}
+	if _, err := device.GetProfiles(); err != nil {
+		t.Fatal(err)
+	}
+
+	urlServer, _ := url.Parse(server.URL)
+	if metrics := collector.Snapshot()[MetricsKey{Host: urlServer.Host, Operation: "GetProfiles"}]; metrics.Requests != 1 {
+		t.Errorf("unexpected GetProfiles metrics %+v", metrics)
+	}
+}
@@ -0,0 +1,121 @@
+package onvif
+
+import (
+	"net"
+	"strconv"
+	"strings"
+)
+
+// Types of IP address filter
+const (
+	IPAddressFilterAllow = "Allow"
+	IPAddressFilterDeny  = "Deny"
+)
+
+// GetIPAddressFilter fetch IP address filter of ONVIF camera
+func (device Device) GetIPAddressFilter() (IPAddressFilter, error) {
+	// Create SOAP
+	soap := SOAP{
+		Body:  "<tds:GetIPAddressFilter/>",
+		XMLNs: deviceXMLNs,
+	}
+
+	// Send SOAP request
+	response, err := device.sendRequest(deviceNamespace, soap)
+	if err != nil {
+		return IPAddressFilter{}, err
+	}
+
+	// Parse response to interface
+	ifaceFilter, err := response.ValueForPath("Envelope.Body.GetIPAddressFilterResponse.IPAddressFilter")
+	if err != nil {
+		return IPAddressFilter{}, err
+	}
+
+	// Parse interface to struct
+	filter := IPAddressFilter{Addresses: []string{}}
+	if mapFilter, ok := ifaceFilter.(map[string]interface{}); ok {
+		filter.Type = interfaceToString(mapFilter["Type"])
+
+		for _, element := range []string{"IPv4Address", "IPv6Address"} {
+			for _, mapAddress := range interfaceToMaps(mapFilter[element]) {
+				address := interfaceToString(mapAddress["Address"])
+				if prefixLength := interfaceToString(mapAddress["PrefixLength"]); prefixLength != "" {
+					address += "/" + prefixLength
+				}
+				filter.Addresses = append(filter.Addresses, address)
+			}
+		}
+	}
+
+	return filter, nil
+}
+
+// SetIPAddressFilter replaces IP address filter of ONVIF camera
+func (device Device) SetIPAddressFilter(filter IPAddressFilter) error {
+	return device.sendIPAddressFilter("SetIPAddressFilter", filter)
+}
+
+// AddIPAddressFilter adds addresses to IP address filter of ONVIF camera
+func (device Device) AddIPAddressFilter(filter IPAddressFilter) error {
+	return device.sendIPAddressFilter("AddIPAddressFilter", filter)
+}
+
+// RemoveIPAddressFilter removes addresses from IP address filter of ONVIF camera
+func (device Device) RemoveIPAddressFilter(filter IPAddressFilter) error {
+	return device.sendIPAddressFilter("RemoveIPAddressFilter", filter)
+}
+
+// sendIPAddressFilter sends IP address filter with the operation
+func (device Device) sendIPAddressFilter(operation string, filter IPAddressFilter) error {
+	addressesXML, err := ipAddressFilterXML(filter.Addresses)
+	if err != nil {
+		return err
+	}
+
+	// Create SOAP
+	soap := SOAP{
+		XMLNs: deviceXMLNs,
+		Body: `<tds:` + operation + `>
+			<tds:IPAddressFilter>
+				<tt:Type>` + escapeXML(filter.Type) + `</tt:Type>` + addressesXML + `
+			</tds:IPAddressFilter>
+		</tds:` + operation + `>`,
+	}
+
+	// Send SOAP request
+	_, err = device.sendRequest(deviceNamespace, soap)
+	return err
+}
+
+// ipAddressFilterXML creates tt:IPv4Address and tt:IPv6Address elements of the
+// addresses. Address without prefix length is treated as a single host.
+func ipAddressFilterXML(addresses []string) (string, error) {
+	ipv4, ipv6 := "", ""
+	for _, address := range addresses {
+		if !strings.Contains(address, "/") {
+			if ip := net.ParseIP(address); ip != nil && ip.To4() != nil {
+				address += "/32"
+			} else {
+				address += "/128"
+			}
+		}
+
+		ip, network, err := net.ParseCIDR(address)
+		if err != nil {
+			return "", err
+		}
+
+		prefixLength, _ := network.Mask.Size()
+		element := `<tt:Address>` + ip.String() + `</tt:Address>
+			<tt:PrefixLength>` + strconv.Itoa(prefixLength) + `</tt:PrefixLength>`
+
+		if ip.To4() != nil {
+			ipv4 += `<tt:IPv4Address>` + element + `</tt:IPv4Address>`
+		} else {
+			ipv6 += `<tt:IPv6Address>` + element + `</tt:IPv6Address>`
+		}
+	}
+
+	return ipv4 + ipv6, nil
+}
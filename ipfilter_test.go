@@ -0,0 +1,68 @@
+package onvif
+
+import (
+	"fmt"
+	"log"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/krabiswabbie/go-onvif/onviftest"
+)
+
+func TestGetIPAddressFilter(t *testing.T) {
+	log.Println("Test GetIPAddressFilter")
+
+	res, err := testDevice.GetIPAddressFilter()
+	if err != nil {
+		t.Error(err)
+	}
+
+	js := prettyJSON(&res)
+	fmt.Println(js)
+}
+
+func TestIPAddressFilter(t *testing.T) {
+	server := onviftest.NewServer()
+	defer server.Close()
+
+	server.HandleBody("AddIPAddressFilter", `<tds:AddIPAddressFilterResponse/>`)
+	server.HandleBody("GetIPAddressFilter", `<tds:GetIPAddressFilterResponse>
+		<tds:IPAddressFilter>
+			<tt:Type>Allow</tt:Type>
+			<tt:IPv4Address><tt:Address>192.168.1.0</tt:Address><tt:PrefixLength>24</tt:PrefixLength></tt:IPv4Address>
+			<tt:IPv6Address><tt:Address>fe80::1</tt:Address><tt:PrefixLength>128</tt:PrefixLength></tt:IPv6Address>
+		</tds:IPAddressFilter>
+	</tds:GetIPAddressFilterResponse>`)
+
+	device := Device{XAddr: server.XAddr()}
+	filter := IPAddressFilter{Type: IPAddressFilterAllow, Addresses: []string{"192.168.1.0/24", "10.0.0.5", "fe80::1"}}
+	if err := device.AddIPAddressFilter(filter); err != nil {
+		t.Fatal(err)
+	}
+
+	request, _ := server.LastRequest("AddIPAddressFilter")
+	expected := []string{
+		"<tt:IPv4Address><tt:Address>192.168.1.0</tt:Address><tt:PrefixLength>24</tt:PrefixLength></tt:IPv4Address>",
+		"<tt:IPv4Address><tt:Address>10.0.0.5</tt:Address><tt:PrefixLength>32</tt:PrefixLength></tt:IPv4Address>",
+		"<tt:IPv6Address><tt:Address>fe80::1</tt:Address><tt:PrefixLength>128</tt:PrefixLength></tt:IPv6Address>",
+	}
+	for _, element := range expected {
+		if !strings.Contains(request.Envelope, element) {
+			t.Errorf("request doesn't contain %s", element)
+		}
+	}
+
+	if err := device.AddIPAddressFilter(IPAddressFilter{Type: IPAddressFilterDeny, Addresses: []string{"invalid"}}); err == nil {
+		t.Error("expected error on invalid address")
+	}
+
+	filter, err := device.GetIPAddressFilter()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(filter, IPAddressFilter{Type: "Allow", Addresses: []string{"192.168.1.0/24", "fe80::1/128"}}) {
+		t.Errorf("unexpected filter %+v", filter)
+	}
+}
@@ -0,0 +1,91 @@
+package onvif
+
+import (
+	"net"
+	"net/url"
+	"strings"
+)
+
+// IsLinkLocalXAddr reports whether host of the XAddr is a link-local address,
+// e.g. 169.254.10.20 or fe80::1, which is only reachable from the same network
+// link, and in case of IPv6 only through the interface specified by the zone.
+func IsLinkLocalXAddr(xaddr string) bool {
+	ip, _ := xaddrIP(xaddr)
+	return ip != nil && ip.IsLinkLocalUnicast()
+}
+
+// XAddrWithZone adds the zone, i.e. name of the network interface, to link-local
+// IPv6 address in the XAddr, so requests can be routed to it. Cameras report their
+// link-local address without zone, since it's only meaningful to the client.
+// XAddr that already has a zone, or that has any other host, is returned as it is.
+func XAddrWithZone(xaddr, zone string) string {
+	ip, currentZone := xaddrIP(xaddr)
+	if ip == nil || ip.To4() != nil || !ip.IsLinkLocalUnicast() || currentZone != "" || zone == "" {
+		return xaddr
+	}
+
+	urlXAddr, err := url.Parse(normalizeZone(xaddr))
+	if err != nil {
+		return xaddr
+	}
+
+	host := "[" + ip.String() + "%" + zone + "]"
+	if port := urlXAddr.Port(); port != "" {
+		host += ":" + port
+	}
+	urlXAddr.Host = host
+
+	return urlXAddr.String()
+}
+
+// SelectXAddr returns the XAddr that's most likely reachable among the ones
+// reported by a device, preferring routable addresses over link-local ones.
+// It returns empty string if there's no XAddr.
+func SelectXAddr(xaddrs []string) string {
+	for _, xaddr := range xaddrs {
+		if !IsLinkLocalXAddr(xaddr) {
+			return xaddr
+		}
+	}
+
+	if len(xaddrs) > 0 {
+		return xaddrs[0]
+	}
+
+	return ""
+}
+
+// xaddrIP returns IP address and zone of host of the XAddr,
+// or nil if the host isn't an IP address
+func xaddrIP(xaddr string) (net.IP, string) {
+	urlXAddr, err := url.Parse(normalizeZone(xaddr))
+	if err != nil {
+		return nil, ""
+	}
+
+	host, zone := urlXAddr.Hostname(), ""
+	if i := strings.LastIndex(host, "%"); i >= 0 {
+		host, zone = host[:i], host[i+1:]
+	}
+
+	return net.ParseIP(host), zone
+}
+
+// normalizeZone escapes the percent sign that separates zone from IPv6 address
+// in the XAddr, e.g. http://[fe80::1%eth0]/, as it's required by URL syntax.
+// Zone that's already escaped is left as it is.
+func normalizeZone(xaddr string) string {
+	start := strings.Index(xaddr, "[")
+	end := strings.Index(xaddr, "]")
+	if start < 0 || end < start {
+		return xaddr
+	}
+
+	host := xaddr[start+1 : end]
+	i := strings.Index(host, "%")
+	if i < 0 || strings.HasPrefix(host[i:], "%25") {
+		return xaddr
+	}
+
+	return xaddr[:start+1] + host[:i] + "%25" + host[i+1:] + xaddr[end:]
+}
@@ -0,0 +1,66 @@
+package onvif
+
+import "testing"
+
+func TestXAddrWithZone(t *testing.T) {
+	tests := []struct {
+		xaddr string
+		zone  string
+		want  string
+	}{
+		{"http://[fe80::1]/onvif/device_service", "eth0", "http://[fe80::1%25eth0]/onvif/device_service"},
+		{"http://[fe80::1]:8080/onvif/device_service", "eth0", "http://[fe80::1%25eth0]:8080/onvif/device_service"},
+		{"http://[fe80::1%eth1]/onvif/device_service", "eth0", "http://[fe80::1%eth1]/onvif/device_service"},
+		{"http://[2001:db8::1]/onvif/device_service", "eth0", "http://[2001:db8::1]/onvif/device_service"},
+		{"http://192.168.1.10/onvif/device_service", "eth0", "http://192.168.1.10/onvif/device_service"},
+		{"http://[fe80::1]/onvif/device_service", "", "http://[fe80::1]/onvif/device_service"},
+	}
+
+	for _, test := range tests {
+		if got := XAddrWithZone(test.xaddr, test.zone); got != test.want {
+			t.Errorf("%s %s: got %s, want %s", test.xaddr, test.zone, got, test.want)
+		}
+	}
+}
+
+func TestSelectXAddr(t *testing.T) {
+	xaddrs := []string{
+		"http://169.254.10.20/onvif/device_service",
+		"http://[fe80::1]/onvif/device_service",
+		"http://192.168.1.10/onvif/device_service",
+	}
+
+	if got := SelectXAddr(xaddrs); got != xaddrs[2] {
+		t.Errorf("expected routable address, got %s", got)
+	}
+
+	if got := SelectXAddr(xaddrs[:2]); got != xaddrs[0] {
+		t.Errorf("expected first link-local address, got %s", got)
+	}
+
+	if got := SelectXAddr(nil); got != "" {
+		t.Errorf("expected empty address, got %s", got)
+	}
+
+	if !IsLinkLocalXAddr("http://[fe80::1%eth0]:80/onvif/device_service") {
+		t.Error("expected link-local address with zone")
+	}
+}
+
+func TestLinkLocalRequest(t *testing.T) {
+	soap := SOAP{Body: "<tds:GetHostname/>", User: "admin", Password: "secret"}
+	_, urlXAddr, err := soap.prepareRequest("http://[fe80::1%eth0]:8080/onvif/device_service")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if urlXAddr != "http://admin:secret@[fe80::1%25eth0]:8080/onvif/device_service" {
+		t.Errorf("unexpected URL %s", urlXAddr)
+	}
+
+	// Services reported by camera are reached through the zone of device
+	device := Device{XAddr: "http://[fe80::1%25eth0]/onvif/device_service"}
+	if xaddr := device.adjustXAddr("http://[fe80::1]/onvif/media_service"); xaddr != "http://[fe80::1%25eth0]/onvif/media_service" {
+		t.Errorf("unexpected service XAddr %s", xaddr)
+	}
+}
@@ -0,0 +1,319 @@
+package onvif
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// Durations are encoded in JSON as ISO 8601 duration, e.g. PT10S, like ONVIF
+// does, instead of number of nanoseconds, so they're readable by clients of
+// REST services that return the structs as they are.
+
+// MarshalJSON encodes upload delay and expected down time as ISO 8601 duration
+func (info FirmwareUpgradeInfo) MarshalJSON() ([]byte, error) {
+	type alias FirmwareUpgradeInfo
+	return json.Marshal(struct {
+		alias
+		UploadDelay      string `json:"uploadDelay"`
+		ExpectedDownTime string `json:"expectedDownTime"`
+	}{alias(info), formatDuration(info.UploadDelay), formatDuration(info.ExpectedDownTime)})
+}
+
+// UnmarshalJSON decodes upload delay and expected down time from ISO 8601 duration
+func (info *FirmwareUpgradeInfo) UnmarshalJSON(data []byte) error {
+	type alias FirmwareUpgradeInfo
+	value := struct {
+		*alias
+		UploadDelay      string `json:"uploadDelay"`
+		ExpectedDownTime string `json:"expectedDownTime"`
+	}{alias: (*alias)(info)}
+
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	return unmarshalDurations(map[*time.Duration]string{
+		&info.UploadDelay:      value.UploadDelay,
+		&info.ExpectedDownTime: value.ExpectedDownTime,
+	})
+}
+
+// MarshalJSON encodes maximum retention time as ISO 8601 duration
+func (config RecordingConfig) MarshalJSON() ([]byte, error) {
+	type alias RecordingConfig
+	return json.Marshal(struct {
+		alias
+		MaximumRetentionTime string `json:"maximumRetentionTime"`
+	}{alias(config), formatDuration(config.MaximumRetentionTime)})
+}
+
+// UnmarshalJSON decodes maximum retention time from ISO 8601 duration
+func (config *RecordingConfig) UnmarshalJSON(data []byte) error {
+	type alias RecordingConfig
+	value := struct {
+		*alias
+		MaximumRetentionTime string `json:"maximumRetentionTime"`
+	}{alias: (*alias)(config)}
+
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	return unmarshalDurations(map[*time.Duration]string{
+		&config.MaximumRetentionTime: value.MaximumRetentionTime,
+	})
+}
+
+// MarshalJSON encodes session timeout as ISO 8601 duration
+func (config ReplayConfig) MarshalJSON() ([]byte, error) {
+	type alias ReplayConfig
+	return json.Marshal(struct {
+		alias
+		SessionTimeout string `json:"sessionTimeout"`
+	}{alias(config), formatDuration(config.SessionTimeout)})
+}
+
+// UnmarshalJSON decodes session timeout from ISO 8601 duration
+func (config *ReplayConfig) UnmarshalJSON(data []byte) error {
+	type alias ReplayConfig
+	value := struct {
+		*alias
+		SessionTimeout string `json:"sessionTimeout"`
+	}{alias: (*alias)(config)}
+
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	return unmarshalDurations(map[*time.Duration]string{
+		&config.SessionTimeout: value.SessionTimeout,
+	})
+}
+
+// MarshalJSON encodes delay time as ISO 8601 duration
+func (settings RelayOutputSettings) MarshalJSON() ([]byte, error) {
+	type alias RelayOutputSettings
+	return json.Marshal(struct {
+		alias
+		DelayTime string `json:"delayTime"`
+	}{alias(settings), formatDuration(settings.DelayTime)})
+}
+
+// UnmarshalJSON decodes delay time from ISO 8601 duration
+func (settings *RelayOutputSettings) UnmarshalJSON(data []byte) error {
+	type alias RelayOutputSettings
+	value := struct {
+		*alias
+		DelayTime string `json:"delayTime"`
+	}{alias: (*alias)(settings)}
+
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	return unmarshalDurations(map[*time.Duration]string{
+		&settings.DelayTime: value.DelayTime,
+	})
+}
+
+// MarshalJSON encodes access, open too long and pre-alarm time as ISO 8601 duration
+func (options AccessDoorOptions) MarshalJSON() ([]byte, error) {
+	type alias AccessDoorOptions
+	return json.Marshal(struct {
+		alias
+		AccessTime      string `json:"accessTime"`
+		OpenTooLongTime string `json:"openTooLongTime"`
+		PreAlarmTime    string `json:"preAlarmTime"`
+	}{alias(options), formatDuration(options.AccessTime), formatDuration(options.OpenTooLongTime),
+		formatDuration(options.PreAlarmTime)})
+}
+
+// UnmarshalJSON decodes access, open too long and pre-alarm time from ISO 8601 duration
+func (options *AccessDoorOptions) UnmarshalJSON(data []byte) error {
+	type alias AccessDoorOptions
+	value := struct {
+		*alias
+		AccessTime      string `json:"accessTime"`
+		OpenTooLongTime string `json:"openTooLongTime"`
+		PreAlarmTime    string `json:"preAlarmTime"`
+	}{alias: (*alias)(options)}
+
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	return unmarshalDurations(map[*time.Duration]string{
+		&options.AccessTime:      value.AccessTime,
+		&options.OpenTooLongTime: value.OpenTooLongTime,
+		&options.PreAlarmTime:    value.PreAlarmTime,
+	})
+}
+
+// MarshalJSON encodes TTL as ISO 8601 duration
+func (info DynamicDNSInformation) MarshalJSON() ([]byte, error) {
+	type alias DynamicDNSInformation
+	return json.Marshal(struct {
+		alias
+		TTL string `json:"ttl"`
+	}{alias(info), formatDuration(info.TTL)})
+}
+
+// UnmarshalJSON decodes TTL from ISO 8601 duration
+func (info *DynamicDNSInformation) UnmarshalJSON(data []byte) error {
+	type alias DynamicDNSInformation
+	value := struct {
+		*alias
+		TTL string `json:"ttl"`
+	}{alias: (*alias)(info)}
+
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	return unmarshalDurations(map[*time.Duration]string{
+		&info.TTL: value.TTL,
+	})
+}
+
+// MarshalJSON encodes error as its message
+func (result DeviceResult) MarshalJSON() ([]byte, error) {
+	type alias DeviceResult
+	return json.Marshal(struct {
+		alias
+		Error string `json:"error,omitempty"`
+	}{alias(result), errorMessage(result.Err)})
+}
+
+// UnmarshalJSON decodes error from its message
+func (result *DeviceResult) UnmarshalJSON(data []byte) error {
+	type alias DeviceResult
+	value := struct {
+		*alias
+		Error string `json:"error"`
+	}{alias: (*alias)(result)}
+
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	result.Err = messageError(value.Error)
+	return nil
+}
+
+// MarshalJSON encodes error as its message
+func (deviceErr DeviceError) MarshalJSON() ([]byte, error) {
+	type alias DeviceError
+	return json.Marshal(struct {
+		alias
+		Error string `json:"error,omitempty"`
+	}{alias(deviceErr), errorMessage(deviceErr.Err)})
+}
+
+// UnmarshalJSON decodes error from its message
+func (deviceErr *DeviceError) UnmarshalJSON(data []byte) error {
+	type alias DeviceError
+	value := struct {
+		*alias
+		Error string `json:"error"`
+	}{alias: (*alias)(deviceErr)}
+
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	deviceErr.Err = messageError(value.Error)
+	return nil
+}
+
+// MarshalJSON encodes latency as ISO 8601 duration and error as its message
+func (health DeviceHealth) MarshalJSON() ([]byte, error) {
+	type alias DeviceHealth
+	return json.Marshal(struct {
+		alias
+		Latency string `json:"latency"`
+		Error   string `json:"error,omitempty"`
+	}{alias(health), formatDuration(health.Latency), errorMessage(health.Err)})
+}
+
+// UnmarshalJSON decodes latency from ISO 8601 duration and error from its message
+func (health *DeviceHealth) UnmarshalJSON(data []byte) error {
+	type alias DeviceHealth
+	value := struct {
+		*alias
+		Latency string `json:"latency"`
+		Error   string `json:"error"`
+	}{alias: (*alias)(health)}
+
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	health.Err = messageError(value.Error)
+	return unmarshalDurations(map[*time.Duration]string{
+		&health.Latency: value.Latency,
+	})
+}
+
+// MarshalJSON encodes latency and maximum latency as ISO 8601 duration
+func (metrics OperationMetrics) MarshalJSON() ([]byte, error) {
+	type alias OperationMetrics
+	return json.Marshal(struct {
+		alias
+		Latency    string `json:"latency"`
+		MaxLatency string `json:"maxLatency"`
+	}{alias(metrics), formatDuration(metrics.Latency), formatDuration(metrics.MaxLatency)})
+}
+
+// UnmarshalJSON decodes latency and maximum latency from ISO 8601 duration
+func (metrics *OperationMetrics) UnmarshalJSON(data []byte) error {
+	type alias OperationMetrics
+	value := struct {
+		*alias
+		Latency    string `json:"latency"`
+		MaxLatency string `json:"maxLatency"`
+	}{alias: (*alias)(metrics)}
+
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	return unmarshalDurations(map[*time.Duration]string{
+		&metrics.Latency:    value.Latency,
+		&metrics.MaxLatency: value.MaxLatency,
+	})
+}
+
+// errorMessage returns message of the error, or empty string if there's none
+func errorMessage(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
+
+// messageError returns error with the message, or nil if the message is empty
+func messageError(message string) error {
+	if message == "" {
+		return nil
+	}
+	return errors.New(message)
+}
+
+// unmarshalDurations parses each ISO 8601 duration into its destination.
+// Empty duration, i.e. the field is missing in JSON, is left as zero.
+func unmarshalDurations(durations map[*time.Duration]string) error {
+	for destination, src := range durations {
+		if src == "" {
+			continue
+		}
+
+		duration, err := parseDuration(src)
+		if err != nil {
+			return err
+		}
+		*destination = duration
+	}
+
+	return nil
+}
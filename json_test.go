@@ -0,0 +1,129 @@
+package onvif
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMarshalJSON(t *testing.T) {
+	device := Device{ID: "camera", XAddr: "http://192.168.1.10/onvif/device_service", User: "admin", Password: "secret"}
+	data, err := json.Marshal(device)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(data) != `{"id":"camera","name":"","xAddr":"http://192.168.1.10/onvif/device_service","user":"admin","services":null}` {
+		t.Errorf("unexpected device JSON %s", data)
+	}
+
+	// Durations are encoded as ISO 8601 duration
+	options := AccessDoorOptions{UseExtendedTime: true, AccessTime: 5 * time.Second, PreAlarmTime: 1500 * time.Millisecond}
+	data, err = json.Marshal(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, field := range []string{`"useExtendedTime":true`, `"accessTime":"PT5S"`, `"openTooLongTime":"PT0S"`, `"preAlarmTime":"PT1.5S"`} {
+		if !strings.Contains(string(data), field) {
+			t.Errorf("%s doesn't contain %s", data, field)
+		}
+	}
+
+	var decoded AccessDoorOptions
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(decoded, options) {
+		t.Errorf("got %+v, want %+v", decoded, options)
+	}
+
+	// Durations are also encoded in nested struct
+	data, err = json.Marshal(RelayOutput{Token: "Relay_1", Settings: RelayOutputSettings{Mode: "Monostable", DelayTime: time.Minute}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(data) != `{"token":"Relay_1","settings":{"mode":"Monostable","idleState":"","delayTime":"PT60S"}}` {
+		t.Errorf("unexpected relay output JSON %s", data)
+	}
+
+	if err := json.Unmarshal([]byte(`{"ttl":"invalid"}`), &DynamicDNSInformation{}); err == nil {
+		t.Error("expected error on invalid duration")
+	}
+}
+
+func TestMarshalJSONResults(t *testing.T) {
+	// Errors are encoded as their message, and latency as ISO 8601 duration
+	health := DeviceHealth{DeviceID: "camera", Latency: 250 * time.Millisecond, Err: errors.New("Connection refused")}
+	data, err := json.Marshal(health)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, field := range []string{`"deviceId":"camera"`, `"online":false`, `"latency":"PT0.25S"`, `"error":"Connection refused"`} {
+		if !strings.Contains(string(data), field) {
+			t.Errorf("%s doesn't contain %s", data, field)
+		}
+	}
+
+	var decoded DeviceHealth
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	if decoded.Latency != health.Latency || decoded.Err == nil || decoded.Err.Error() != "Connection refused" {
+		t.Errorf("got %+v, want %+v", decoded, health)
+	}
+
+	// Error is omitted if there's none
+	data, err = json.Marshal(DeviceResults{{DeviceID: "camera", Value: "1.0"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(data) != `[{"deviceId":"camera","value":"1.0"}]` {
+		t.Errorf("unexpected results JSON %s", data)
+	}
+
+	data, err = json.Marshal(DeviceErrors{{DeviceID: "camera", Err: ErrNotAuthorized}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(data) != `[{"deviceId":"camera","error":"`+ErrNotAuthorized.Error()+`"}]` {
+		t.Errorf("unexpected errors JSON %s", data)
+	}
+
+	data, err = json.Marshal(OperationMetrics{Requests: 2, Latency: 3 * time.Second, MaxLatency: 2 * time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(data) != `{"requests":2,"faults":0,"errors":0,"retries":0,"latency":"PT3S","maxLatency":"PT2S"}` {
+		t.Errorf("unexpected metrics JSON %s", data)
+	}
+}
+
+func TestMarshalXML(t *testing.T) {
+	capabilities := DeviceCapabilities{Events: map[string]bool{"WSPullPointSupport": true}}
+	capabilities.Media.XAddr = "http://192.168.1.10/onvif/media_service"
+
+	data, err := xml.Marshal(capabilities)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(string(data), "<media><xAddr>http://192.168.1.10/onvif/media_service</xAddr>") {
+		t.Errorf("unexpected capabilities XML %s", data)
+	}
+
+	if _, err := xml.Marshal(Device{Services: map[string]string{}}); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,94 @@
+package onvif
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+var errLimiterTimeout = errors.New("Timeout while waiting for other requests to the camera")
+
+// RequestLimiter limits SOAP requests sent to a camera, since many cameras
+// crash or respond with fault under concurrent requests. At most MaxConcurrent
+// requests are in flight at once, so 1 serializes them, and starts of successive
+// requests are at least Interval apart. Zero value of either disables the limit.
+// Each retry of a request is limited separately, and the time spent waiting
+// counts towards timeout of the request. It's safe for concurrent use,
+// and it must be shared by all copies of the device to be effective.
+type RequestLimiter struct {
+	slots    chan struct{}
+	interval time.Duration
+
+	mutex sync.Mutex
+	next  time.Time
+}
+
+// NewRequestLimiter creates limiter that allows maxConcurrent requests
+// in flight, started at least interval apart
+func NewRequestLimiter(maxConcurrent int, interval time.Duration) *RequestLimiter {
+	limiter := &RequestLimiter{interval: interval}
+	if maxConcurrent > 0 {
+		limiter.slots = make(chan struct{}, maxConcurrent)
+	}
+
+	return limiter
+}
+
+// acquire waits until a request can be sent, for at most timeout unless it's
+// zero. The returned function must be called once the request completes.
+func (limiter *RequestLimiter) acquire(timeout time.Duration) (func(), error) {
+	if limiter == nil {
+		return func() {}, nil
+	}
+
+	var expired <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		expired = timer.C
+	}
+
+	if limiter.slots != nil {
+		select {
+		case limiter.slots <- struct{}{}:
+		case <-expired:
+			return nil, errLimiterTimeout
+		}
+	}
+
+	release := func() {
+		if limiter.slots != nil {
+			<-limiter.slots
+		}
+	}
+
+	if limiter.interval > 0 {
+		wait := time.NewTimer(limiter.reserve())
+		defer wait.Stop()
+
+		select {
+		case <-wait.C:
+		case <-expired:
+			release()
+			return nil, errLimiterTimeout
+		}
+	}
+
+	return release, nil
+}
+
+// reserve reserves the earliest start time of a request,
+// and returns duration to wait until then
+func (limiter *RequestLimiter) reserve() time.Duration {
+	limiter.mutex.Lock()
+	defer limiter.mutex.Unlock()
+
+	now := time.Now()
+	start := limiter.next
+	if start.Before(now) {
+		start = now
+	}
+
+	limiter.next = start.Add(limiter.interval)
+	return start.Sub(now)
+}
@@ -0,0 +1,100 @@
+package onvif
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRequestLimiterSerializes(t *testing.T) {
+	var inFlight, maxInFlight int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		current := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+
+		for {
+			max := atomic.LoadInt32(&maxInFlight)
+			if current <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, current) {
+				break
+			}
+		}
+
+		time.Sleep(10 * time.Millisecond)
+		w.Header().Set("Content-Type", "application/soap+xml")
+		fmt.Fprint(w, testHostnameResponse)
+	}))
+	defer server.Close()
+
+	device := Device{XAddr: server.URL + "/onvif/device_service", Limiter: NewRequestLimiter(1, 0)}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := device.GetHostname(); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if max := atomic.LoadInt32(&maxInFlight); max != 1 {
+		t.Errorf("expected requests to be serialized, got %d in flight", max)
+	}
+}
+
+func TestRequestLimiterInterval(t *testing.T) {
+	limiter := NewRequestLimiter(0, 20*time.Millisecond)
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		release, err := limiter.acquire(0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		release()
+	}
+
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Errorf("expected requests to be at least 20ms apart, 3 requests took %v", elapsed)
+	}
+
+	// Nil limiter doesn't limit anything
+	var nilLimiter *RequestLimiter
+	if release, err := nilLimiter.acquire(time.Millisecond); err != nil {
+		t.Error(err)
+	} else {
+		release()
+	}
+}
+
+func TestRequestLimiterTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/soap+xml")
+		fmt.Fprint(w, testHostnameResponse)
+	}))
+	defer server.Close()
+
+	// Request waiting behind a hung request times out, like the hung request
+	limiter := NewRequestLimiter(1, 0)
+	release, err := limiter.acquire(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer release()
+
+	device := Device{XAddr: server.URL + "/onvif/device_service", Limiter: limiter, Timeout: 50 * time.Millisecond}
+	start := time.Now()
+	if _, err := device.GetHostname(); err != errLimiterTimeout {
+		t.Errorf("expected %v, got %v", errLimiterTimeout, err)
+	}
+
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("request waited %v for the limiter", elapsed)
+	}
+}
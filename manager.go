@@ -0,0 +1,261 @@
+package onvif
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultParallelism is the number of concurrent operations of DeviceManager
+// when its parallelism is not specified
+const DefaultParallelism = 8
+
+// DeviceManager holds many ONVIF cameras and runs operations on them
+// concurrently, with bounded parallelism. Each camera is identified by
+// its ID, or by its XAddr if the ID is empty.
+type DeviceManager struct {
+	parallelism int
+
+	mutex   sync.RWMutex
+	devices map[string]Device
+	health  map[string]DeviceHealth
+}
+
+// DeviceResult contains result of an operation on a device
+type DeviceResult struct {
+	DeviceID string      `json:"deviceId" xml:"deviceId"`
+	Value    interface{} `json:"value" xml:"value"`
+	Err      error       `json:"-" xml:"-"`
+}
+
+// DeviceResults contains results of an operation on many devices
+type DeviceResults []DeviceResult
+
+// DeviceError is an error returned by an operation on a device
+type DeviceError struct {
+	DeviceID string `json:"deviceId" xml:"deviceId"`
+	Err      error  `json:"-" xml:"-"`
+}
+
+// DeviceErrors contains errors of an operation on many devices
+type DeviceErrors []DeviceError
+
+// DeviceHealth contains health of a device, as checked by DeviceManager
+type DeviceHealth struct {
+	DeviceID  string        `json:"deviceId" xml:"deviceId"`
+	Online    bool          `json:"online" xml:"online"`
+	Latency   time.Duration `json:"latency" xml:"latency"`
+	LastCheck time.Time     `json:"lastCheck" xml:"lastCheck"`
+	LastSeen  time.Time     `json:"lastSeen" xml:"lastSeen"`
+	Err       error         `json:"-" xml:"-"`
+}
+
+// NewDeviceManager creates manager that runs at most parallelism
+// operations at the same time. Zero parallelism means DefaultParallelism.
+func NewDeviceManager(parallelism int) *DeviceManager {
+	if parallelism <= 0 {
+		parallelism = DefaultParallelism
+	}
+
+	return &DeviceManager{
+		parallelism: parallelism,
+		devices:     make(map[string]Device),
+		health:      make(map[string]DeviceHealth),
+	}
+}
+
+// Add adds device to the manager, replacing the one with same ID
+func (manager *DeviceManager) Add(device Device) {
+	manager.mutex.Lock()
+	defer manager.mutex.Unlock()
+	manager.devices[deviceID(device)] = device
+}
+
+// Remove removes device with the ID from the manager,
+// and closes its connections that are kept alive
+func (manager *DeviceManager) Remove(id string) {
+	manager.mutex.Lock()
+	device, ok := manager.devices[id]
+	delete(manager.devices, id)
+	delete(manager.health, id)
+	manager.mutex.Unlock()
+
+	if ok {
+		device.CloseIdleConnections()
+	}
+}
+
+// Device returns device with the ID, and whether it's found
+func (manager *DeviceManager) Device(id string) (Device, bool) {
+	manager.mutex.RLock()
+	defer manager.mutex.RUnlock()
+	device, ok := manager.devices[id]
+	return device, ok
+}
+
+// Devices returns all devices in the manager
+func (manager *DeviceManager) Devices() []Device {
+	manager.mutex.RLock()
+	defer manager.mutex.RUnlock()
+
+	devices := make([]Device, 0, len(manager.devices))
+	for _, device := range manager.devices {
+		devices = append(devices, device)
+	}
+	return devices
+}
+
+// Run runs operation on all devices concurrently and waits until they're finished.
+// If context is cancelled, operations that haven't been started fail with its error.
+func (manager *DeviceManager) Run(ctx context.Context, operation func(Device) (interface{}, error)) DeviceResults {
+	devices := manager.Devices()
+	results := make(DeviceResults, len(devices))
+	semaphore := make(chan struct{}, manager.parallelism)
+	wg := sync.WaitGroup{}
+
+	for i, device := range devices {
+		results[i].DeviceID = deviceID(device)
+
+		select {
+		case semaphore <- struct{}{}:
+		case <-ctx.Done():
+			results[i].Err = ctx.Err()
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, device Device) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+			results[i].Value, results[i].Err = operation(device)
+		}(i, device)
+	}
+
+	wg.Wait()
+	return results
+}
+
+// Health returns the last known health of device with the ID,
+// and whether the device has been checked
+func (manager *DeviceManager) Health(id string) (DeviceHealth, bool) {
+	manager.mutex.RLock()
+	defer manager.mutex.RUnlock()
+	health, ok := manager.health[id]
+	return health, ok
+}
+
+// CheckHealth checks whether each device is reachable by fetching its
+// information, and returns health of each device
+func (manager *DeviceManager) CheckHealth(ctx context.Context) []DeviceHealth {
+	results := manager.Run(ctx, func(device Device) (interface{}, error) {
+		start := time.Now()
+		_, err := device.GetInformation()
+		return time.Since(start), err
+	})
+
+	manager.mutex.Lock()
+	defer manager.mutex.Unlock()
+
+	healths := make([]DeviceHealth, 0, len(results))
+	for _, result := range results {
+		if _, ok := manager.devices[result.DeviceID]; !ok {
+			continue
+		}
+
+		health := manager.health[result.DeviceID]
+		health.DeviceID = result.DeviceID
+		health.LastCheck = time.Now()
+		health.Online = result.Err == nil
+		health.Err = result.Err
+
+		if latency, ok := result.Value.(time.Duration); ok && result.Err == nil {
+			health.Latency = latency
+			health.LastSeen = health.LastCheck
+		}
+
+		manager.health[result.DeviceID] = health
+		healths = append(healths, health)
+	}
+
+	return healths
+}
+
+// PollHealth checks health of the devices periodically until context is
+// cancelled. Health of a device is sent to the channel on the first check,
+// and whenever the device goes online or offline.
+func (manager *DeviceManager) PollHealth(ctx context.Context, interval time.Duration) <-chan DeviceHealth {
+	changes := make(chan DeviceHealth, 16)
+	go func() {
+		defer close(changes)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		online := make(map[string]bool)
+		for {
+			for _, health := range manager.CheckHealth(ctx) {
+				if wasOnline, ok := online[health.DeviceID]; ok && wasOnline == health.Online {
+					continue
+				}
+				online[health.DeviceID] = health.Online
+
+				select {
+				case changes <- health:
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return changes
+}
+
+// Err returns errors of the results, or nil if all operations succeed
+func (results DeviceResults) Err() error {
+	errs := DeviceErrors{}
+	for _, result := range results {
+		if result.Err != nil {
+			errs = append(errs, DeviceError{DeviceID: result.DeviceID, Err: result.Err})
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// Error returns ID of the device and its error message
+func (err DeviceError) Error() string {
+	return err.DeviceID + ": " + err.Err.Error()
+}
+
+// Unwrap returns the underlying error
+func (err DeviceError) Unwrap() error {
+	return err.Err
+}
+
+// Error returns message of each error
+func (errs DeviceErrors) Error() string {
+	messages := make([]string, len(errs))
+	for i, err := range errs {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+// deviceID returns ID of the device, or its XAddr if the ID is empty
+func deviceID(device Device) string {
+	if device.ID != "" {
+		return device.ID
+	}
+	return device.XAddr
+}
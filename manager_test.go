@@ -0,0 +1,98 @@
+package onvif
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/krabiswabbie/go-onvif/onviftest"
+)
+
+func TestDeviceManagerRun(t *testing.T) {
+	servers := []*onviftest.Server{}
+	for i := 0; i < 5; i++ {
+		server := onviftest.NewServer()
+		defer server.Close()
+		servers = append(servers, server)
+	}
+
+	manager := NewDeviceManager(2)
+	for _, server := range servers {
+		manager.Add(Device{XAddr: server.XAddr()})
+	}
+
+	// Camera that's not reachable
+	offline := onviftest.NewServer()
+	offline.Close()
+	manager.Add(Device{ID: "offline", XAddr: offline.XAddr()})
+
+	mutex := sync.Mutex{}
+	running, maxRunning := 0, 0
+	results := manager.Run(context.Background(), func(device Device) (interface{}, error) {
+		mutex.Lock()
+		running++
+		if running > maxRunning {
+			maxRunning = running
+		}
+		mutex.Unlock()
+
+		defer func() {
+			mutex.Lock()
+			running--
+			mutex.Unlock()
+		}()
+
+		time.Sleep(10 * time.Millisecond)
+		return device.GetHostname()
+	})
+
+	if maxRunning > 2 {
+		t.Errorf("%d operations run at the same time, want at most 2", maxRunning)
+	}
+
+	if len(results) != 6 {
+		t.Fatalf("received %d results, want 6", len(results))
+	}
+
+	err := results.Err()
+	var deviceErrs DeviceErrors
+	if !errors.As(err, &deviceErrs) || len(deviceErrs) != 1 || deviceErrs[0].DeviceID != "offline" {
+		t.Errorf("unexpected errors %v", err)
+	}
+
+	for _, result := range results {
+		if result.Err == nil && result.Value.(HostnameInformation).Name != "onviftest" {
+			t.Errorf("unexpected result %+v", result)
+		}
+	}
+}
+
+func TestDeviceManagerHealth(t *testing.T) {
+	server := onviftest.NewServer()
+	defer server.Close()
+
+	manager := NewDeviceManager(0)
+	manager.Add(Device{ID: "camera", XAddr: server.XAddr()})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	changes := manager.PollHealth(ctx, 10*time.Millisecond)
+	health := <-changes
+	if health.DeviceID != "camera" || !health.Online {
+		t.Fatalf("unexpected health %+v", health)
+	}
+
+	// Camera goes offline
+	server.HandleFault("GetDeviceInformation", onviftest.ReceiverFault("ter:Action", "Camera is broken"))
+	health = <-changes
+	if health.Online || health.Err == nil {
+		t.Errorf("unexpected health %+v", health)
+	}
+
+	if stored, ok := manager.Health("camera"); !ok || stored.Online || stored.LastSeen.IsZero() {
+		t.Errorf("unexpected stored health %+v", stored)
+	}
+}
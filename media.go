@@ -1,9 +1,17 @@
 package onvif
 
+import (
+	"strconv"
+	"strings"
+)
+
+const mediaNamespace = "http://www.onvif.org/ver10/media/wsdl"
+
 var mediaXMLNs = []string{
 	`xmlns:trt="http://www.onvif.org/ver10/media/wsdl"`,
 	`xmlns:tt="http://www.onvif.org/ver10/schema"`,
 	`xmlns:tptz="http://www.onvif.org/ver20/ptz/wsdl"`,
+	`xmlns:wsnt="http://docs.oasis-open.org/wsn/b-2"`,
 }
 
 // GetProfiles fetch available media profiles of ONVIF camera
@@ -15,7 +23,7 @@ func (device Device) GetProfiles() ([]MediaProfile, error) {
 	}
 
 	// Send SOAP request
-	response, err := soap.SendRequest(device.XAddr)
+	response, err := device.sendRequest(mediaNamespace, soap)
 	if err != nil {
 		return []MediaProfile{}, err
 	}
@@ -32,88 +40,7 @@ func (device Device) GetProfiles() ([]MediaProfile, error) {
 	// Parse each available profile
 	for _, ifaceProfile := range ifaceProfiles {
 		if mapProfile, ok := ifaceProfile.(map[string]interface{}); ok {
-			// Parse name and token
-			profile := MediaProfile{}
-			profile.Name = interfaceToString(mapProfile["Name"])
-			profile.Token = interfaceToString(mapProfile["-token"])
-
-			// Parse video source configuration
-			videoSource := MediaSourceConfig{}
-			if mapVideoSource, ok := mapProfile["VideoSourceConfiguration"].(map[string]interface{}); ok {
-				videoSource.Name = interfaceToString(mapVideoSource["Name"])
-				videoSource.Token = interfaceToString(mapVideoSource["-token"])
-				videoSource.SourceToken = interfaceToString(mapVideoSource["SourceToken"])
-
-				// Parse video bounds
-				bounds := MediaBounds{}
-				if mapVideoBounds, ok := mapVideoSource["Bounds"].(map[string]interface{}); ok {
-					bounds.Height = interfaceToInt(mapVideoBounds["-height"])
-					bounds.Width = interfaceToInt(mapVideoBounds["-width"])
-				}
-				videoSource.Bounds = bounds
-			}
-			profile.VideoSourceConfig = videoSource
-
-			// Parse video encoder configuration
-			videoEncoder := VideoEncoderConfig{}
-			if mapVideoEncoder, ok := mapProfile["VideoEncoderConfiguration"].(map[string]interface{}); ok {
-				videoEncoder.Name = interfaceToString(mapVideoEncoder["Name"])
-				videoEncoder.Token = interfaceToString(mapVideoEncoder["-token"])
-				videoEncoder.Encoding = interfaceToString(mapVideoEncoder["Encoding"])
-				videoEncoder.Quality = interfaceToInt(mapVideoEncoder["Quality"])
-				videoEncoder.SessionTimeout = interfaceToString(mapVideoEncoder["SessionTimeout"])
-
-				// Parse video rate control
-				rateControl := VideoRateControl{}
-				if mapVideoRate, ok := mapVideoEncoder["RateControl"].(map[string]interface{}); ok {
-					rateControl.BitrateLimit = interfaceToInt(mapVideoRate["BitrateLimit"])
-					rateControl.EncodingInterval = interfaceToInt(mapVideoRate["EncodingInterval"])
-					rateControl.FrameRateLimit = interfaceToInt(mapVideoRate["FrameRateLimit"])
-				}
-				videoEncoder.RateControl = rateControl
-
-				// Parse video resolution
-				resolution := MediaBounds{}
-				if mapVideoRes, ok := mapVideoEncoder["Resolution"].(map[string]interface{}); ok {
-					resolution.Height = interfaceToInt(mapVideoRes["Height"])
-					resolution.Width = interfaceToInt(mapVideoRes["Width"])
-				}
-				videoEncoder.Resolution = resolution
-			}
-			profile.VideoEncoderConfig = videoEncoder
-
-			// Parse audio source configuration
-			audioSource := MediaSourceConfig{}
-			if mapAudioSource, ok := mapProfile["AudioSourceConfiguration"].(map[string]interface{}); ok {
-				audioSource.Name = interfaceToString(mapAudioSource["Name"])
-				audioSource.Token = interfaceToString(mapAudioSource["-token"])
-				audioSource.SourceToken = interfaceToString(mapAudioSource["SourceToken"])
-			}
-			profile.AudioSourceConfig = audioSource
-
-			// Parse audio encoder configuration
-			audioEncoder := AudioEncoderConfig{}
-			if mapAudioEncoder, ok := mapProfile["AudioEncoderConfiguration"].(map[string]interface{}); ok {
-				audioEncoder.Name = interfaceToString(mapAudioEncoder["Name"])
-				audioEncoder.Token = interfaceToString(mapAudioEncoder["-token"])
-				audioEncoder.Encoding = interfaceToString(mapAudioEncoder["Encoding"])
-				audioEncoder.Bitrate = interfaceToInt(mapAudioEncoder["Bitrate"])
-				audioEncoder.SampleRate = interfaceToInt(mapAudioEncoder["SampleRate"])
-				audioEncoder.SessionTimeout = interfaceToString(mapAudioEncoder["SessionTimeout"])
-			}
-			profile.AudioEncoderConfig = audioEncoder
-
-			// Parse PTZ configuration
-			ptzConfig := PTZConfig{}
-			if mapPTZ, ok := mapProfile["PTZConfiguration"].(map[string]interface{}); ok {
-				ptzConfig.Name = interfaceToString(mapPTZ["Name"])
-				ptzConfig.Token = interfaceToString(mapPTZ["-token"])
-				ptzConfig.NodeToken = interfaceToString(mapPTZ["NodeToken"])
-			}
-			profile.PTZConfig = ptzConfig
-
-			// Push profile to result
-			result = append(result, profile)
+			result = append(result, parseMediaProfile(mapProfile))
 		}
 	}
 
@@ -129,14 +56,14 @@ func (device Device) GetStreamURI(profileToken, protocol string) (MediaURI, erro
 		Body: `<trt:GetStreamUri>
 			<trt:StreamSetup>
 				<tt:Stream>RTP-Unicast</tt:Stream>
-				<tt:Transport><tt:Protocol>` + protocol + `</tt:Protocol></tt:Transport>
+				<tt:Transport><tt:Protocol>` + escapeXML(protocol) + `</tt:Protocol></tt:Transport>
 			</trt:StreamSetup>
-			<trt:ProfileToken>` + profileToken + `</trt:ProfileToken>
+			<trt:ProfileToken>` + escapeXML(profileToken) + `</trt:ProfileToken>
 		</trt:GetStreamUri>`,
 	}
 
 	// Send SOAP request
-	response, err := soap.SendRequest(device.XAddr)
+	response, err := device.sendRequest(mediaNamespace, soap)
 	if err != nil {
 		return MediaURI{}, err
 	}
@@ -158,3 +85,330 @@ func (device Device) GetStreamURI(profileToken, protocol string) (MediaURI, erro
 
 	return streamURI, nil
 }
+
+// GetBackchannelStreamURI fetch stream URI of a media profile that is used to
+// send audio to camera. The profile must have an audio output configuration,
+// and RTSP client must send Require header of the returned URI to open the backchannel.
+func (device Device) GetBackchannelStreamURI(profileToken, protocol string) (MediaURI, error) {
+	streamURI, err := device.GetStreamURI(profileToken, protocol)
+	if err != nil {
+		return MediaURI{}, err
+	}
+
+	streamURI.Require = BackchannelRequire
+	return streamURI, nil
+}
+
+// GetSnapshotURI fetch URI of JPEG snapshot of a media profile
+func (device Device) GetSnapshotURI(profileToken string) (MediaURI, error) {
+	// Create SOAP
+	soap := SOAP{
+		XMLNs: mediaXMLNs,
+		Body: `<trt:GetSnapshotUri>
+			<trt:ProfileToken>` + escapeXML(profileToken) + `</trt:ProfileToken>
+		</trt:GetSnapshotUri>`,
+	}
+
+	// Send SOAP request
+	response, err := device.sendRequest(mediaNamespace, soap)
+	if err != nil {
+		return MediaURI{}, err
+	}
+
+	// Parse response to interface
+	ifaceURI, err := response.ValueForPath("Envelope.Body.GetSnapshotUriResponse.MediaUri")
+	if err != nil {
+		return MediaURI{}, err
+	}
+
+	// Parse interface to struct
+	snapshotURI := MediaURI{}
+	if mapURI, ok := ifaceURI.(map[string]interface{}); ok {
+		snapshotURI.URI = interfaceToString(mapURI["Uri"])
+		snapshotURI.Timeout = interfaceToString(mapURI["Timeout"])
+		snapshotURI.InvalidAfterConnect = interfaceToBool(mapURI["InvalidAfterConnect"])
+		snapshotURI.InvalidAfterReboot = interfaceToBool(mapURI["InvalidAfterReboot"])
+	}
+
+	return snapshotURI, nil
+}
+
+// GetMetadataConfigurations fetch all metadata configurations of ONVIF camera
+func (device Device) GetMetadataConfigurations() ([]MetadataConfig, error) {
+	// Create SOAP
+	soap := SOAP{
+		Body:  "<trt:GetMetadataConfigurations/>",
+		XMLNs: mediaXMLNs,
+	}
+
+	// Send SOAP request
+	response, err := device.sendRequest(mediaNamespace, soap)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse response to interface
+	ifaceConfigs, err := response.ValuesForPath("Envelope.Body.GetMetadataConfigurationsResponse.Configurations")
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert interface to array of metadata configuration
+	configs := []MetadataConfig{}
+	for _, ifaceConfig := range ifaceConfigs {
+		if mapConfig, ok := ifaceConfig.(map[string]interface{}); ok {
+			configs = append(configs, parseMetadataConfig(mapConfig))
+		}
+	}
+
+	return configs, nil
+}
+
+// GetMetadataConfigurationOptions fetch options for configuring metadata stream.
+// Both configToken and profileToken are optional.
+func (device Device) GetMetadataConfigurationOptions(configToken, profileToken string) (MetadataConfigOptions, error) {
+	// Create SOAP
+	body := `<trt:GetMetadataConfigurationOptions>`
+	if configToken != "" {
+		body += `<trt:ConfigurationToken>` + escapeXML(configToken) + `</trt:ConfigurationToken>`
+	}
+	if profileToken != "" {
+		body += `<trt:ProfileToken>` + escapeXML(profileToken) + `</trt:ProfileToken>`
+	}
+	body += `</trt:GetMetadataConfigurationOptions>`
+
+	soap := SOAP{
+		Body:  body,
+		XMLNs: mediaXMLNs,
+	}
+
+	// Send SOAP request
+	response, err := device.sendRequest(mediaNamespace, soap)
+	if err != nil {
+		return MetadataConfigOptions{}, err
+	}
+
+	// Parse response to interface
+	ifaceOptions, err := response.ValueForPath("Envelope.Body.GetMetadataConfigurationOptionsResponse.Options")
+	if err != nil {
+		return MetadataConfigOptions{}, err
+	}
+
+	// Parse interface to struct
+	options := MetadataConfigOptions{CompressionTypes: []string{}}
+	if mapOptions, ok := ifaceOptions.(map[string]interface{}); ok {
+		if mapPTZ, ok := mapOptions["PTZStatusFilterOptions"].(map[string]interface{}); ok {
+			options.PanTiltStatusSupported = interfaceToBool(mapPTZ["PanTiltStatusSupported"])
+			options.ZoomStatusSupported = interfaceToBool(mapPTZ["ZoomStatusSupported"])
+			options.PanTiltPositionSupported = interfaceToBool(mapPTZ["PanTiltPositionSupported"])
+			options.ZoomPositionSupported = interfaceToBool(mapPTZ["ZoomPositionSupported"])
+		}
+
+		if mapExtension, ok := mapOptions["Extension"].(map[string]interface{}); ok {
+			options.CompressionTypes = interfaceToStrings(mapExtension["CompressionType"])
+		}
+	}
+
+	return options, nil
+}
+
+// SetMetadataConfiguration changes a metadata configuration. If forcePersistence
+// is true, the change will persist after ONVIF camera is rebooted.
+func (device Device) SetMetadataConfiguration(config MetadataConfig, forcePersistence bool) error {
+	// Create SOAP
+	body := `<trt:SetMetadataConfiguration>
+		<trt:Configuration token="` + escapeXML(config.Token) + `">
+			<tt:Name>` + escapeXML(config.Name) + `</tt:Name>
+			<tt:UseCount>` + strconv.Itoa(config.UseCount) + `</tt:UseCount>
+			<tt:PTZStatus>
+				<tt:Status>` + strconv.FormatBool(config.PTZStatus) + `</tt:Status>
+				<tt:Position>` + strconv.FormatBool(config.PTZPosition) + `</tt:Position>
+			</tt:PTZStatus>`
+
+	if config.Events {
+		body += `<tt:Events>`
+		if config.EventsFilter != "" {
+			body += `<tt:Filter>
+				<wsnt:TopicExpression Dialect="http://www.onvif.org/ver10/tev/topicExpression/ConcreteSet">` +
+				escapeXML(config.EventsFilter) + `</wsnt:TopicExpression>
+			</tt:Filter>`
+		}
+		body += `</tt:Events>`
+	}
+
+	body += `<tt:Analytics>` + strconv.FormatBool(config.Analytics) + `</tt:Analytics>
+			<tt:Multicast>` + multicastConfigXML(config.Multicast) + `</tt:Multicast>
+			<tt:SessionTimeout>` + escapeXML(config.SessionTimeout) + `</tt:SessionTimeout>
+		</trt:Configuration>
+		<trt:ForcePersistence>` + strconv.FormatBool(forcePersistence) + `</trt:ForcePersistence>
+	</trt:SetMetadataConfiguration>`
+
+	soap := SOAP{
+		Body:  body,
+		XMLNs: mediaXMLNs,
+	}
+
+	// Send SOAP request
+	_, err := device.sendRequest(mediaNamespace, soap)
+	return err
+}
+
+// AddMetadataConfiguration adds a metadata configuration to a media profile
+func (device Device) AddMetadataConfiguration(profileToken, configToken string) error {
+	return device.addConfiguration("Metadata", profileToken, configToken)
+}
+
+// parseMediaProfile parses trt:Profiles
+func parseMediaProfile(mapProfile map[string]interface{}) MediaProfile {
+	// Parse name and token
+	profile := MediaProfile{}
+	profile.Name = interfaceToString(mapProfile["Name"])
+	profile.Token = interfaceToString(mapProfile["-token"])
+	profile.Fixed = interfaceToBool(mapProfile["-fixed"])
+
+	// Parse video source configuration
+	if mapVideoSource, ok := mapProfile["VideoSourceConfiguration"].(map[string]interface{}); ok {
+		profile.VideoSourceConfig = parseVideoSourceConfig(mapVideoSource)
+	}
+
+	// Parse video encoder configuration
+	videoEncoder := VideoEncoderConfig{}
+	if mapVideoEncoder, ok := mapProfile["VideoEncoderConfiguration"].(map[string]interface{}); ok {
+		videoEncoder.Name = interfaceToString(mapVideoEncoder["Name"])
+		videoEncoder.Token = interfaceToString(mapVideoEncoder["-token"])
+		videoEncoder.Encoding = interfaceToString(mapVideoEncoder["Encoding"])
+		videoEncoder.Quality = interfaceToInt(mapVideoEncoder["Quality"])
+		videoEncoder.SessionTimeout = interfaceToString(mapVideoEncoder["SessionTimeout"])
+
+		// Parse video rate control
+		rateControl := VideoRateControl{}
+		if mapVideoRate, ok := mapVideoEncoder["RateControl"].(map[string]interface{}); ok {
+			rateControl.BitrateLimit = interfaceToInt(mapVideoRate["BitrateLimit"])
+			rateControl.EncodingInterval = interfaceToInt(mapVideoRate["EncodingInterval"])
+			rateControl.FrameRateLimit = interfaceToInt(mapVideoRate["FrameRateLimit"])
+		}
+		videoEncoder.RateControl = rateControl
+
+		// Parse video resolution
+		resolution := MediaBounds{}
+		if mapVideoRes, ok := mapVideoEncoder["Resolution"].(map[string]interface{}); ok {
+			resolution.Height = interfaceToInt(mapVideoRes["Height"])
+			resolution.Width = interfaceToInt(mapVideoRes["Width"])
+		}
+		videoEncoder.Resolution = resolution
+	}
+	profile.VideoEncoderConfig = videoEncoder
+
+	// Parse audio source configuration
+	audioSource := MediaSourceConfig{}
+	if mapAudioSource, ok := mapProfile["AudioSourceConfiguration"].(map[string]interface{}); ok {
+		audioSource.Name = interfaceToString(mapAudioSource["Name"])
+		audioSource.Token = interfaceToString(mapAudioSource["-token"])
+		audioSource.SourceToken = interfaceToString(mapAudioSource["SourceToken"])
+	}
+	profile.AudioSourceConfig = audioSource
+
+	// Parse audio encoder configuration
+	audioEncoder := AudioEncoderConfig{}
+	if mapAudioEncoder, ok := mapProfile["AudioEncoderConfiguration"].(map[string]interface{}); ok {
+		audioEncoder.Name = interfaceToString(mapAudioEncoder["Name"])
+		audioEncoder.Token = interfaceToString(mapAudioEncoder["-token"])
+		audioEncoder.Encoding = interfaceToString(mapAudioEncoder["Encoding"])
+		audioEncoder.Bitrate = interfaceToInt(mapAudioEncoder["Bitrate"])
+		audioEncoder.SampleRate = interfaceToInt(mapAudioEncoder["SampleRate"])
+		audioEncoder.SessionTimeout = interfaceToString(mapAudioEncoder["SessionTimeout"])
+	}
+	profile.AudioEncoderConfig = audioEncoder
+
+	// Parse PTZ configuration
+	ptzConfig := PTZConfig{}
+	if mapPTZ, ok := mapProfile["PTZConfiguration"].(map[string]interface{}); ok {
+		ptzConfig.Name = interfaceToString(mapPTZ["Name"])
+		ptzConfig.Token = interfaceToString(mapPTZ["-token"])
+		ptzConfig.NodeToken = interfaceToString(mapPTZ["NodeToken"])
+	}
+	profile.PTZConfig = ptzConfig
+
+	// Parse video analytics configuration
+	if mapVideoAnalytics, ok := mapProfile["VideoAnalyticsConfiguration"].(map[string]interface{}); ok {
+		profile.VideoAnalyticsConfig = parseVideoAnalyticsConfig(mapVideoAnalytics)
+	}
+
+	// Parse metadata configuration
+	if mapMetadata, ok := mapProfile["MetadataConfiguration"].(map[string]interface{}); ok {
+		profile.MetadataConfig = parseMetadataConfig(mapMetadata)
+	}
+
+	// Parse audio output configuration
+	if mapExtension, ok := mapProfile["Extension"].(map[string]interface{}); ok {
+		if mapAudioOutput, ok := mapExtension["AudioOutputConfiguration"].(map[string]interface{}); ok {
+			profile.AudioOutputConfig = parseAudioOutputConfig(mapAudioOutput)
+		}
+	}
+
+	return profile
+}
+
+// parseMetadataConfig parses tt:MetadataConfiguration
+func parseMetadataConfig(mapConfig map[string]interface{}) MetadataConfig {
+	config := MetadataConfig{}
+	config.Name = interfaceToString(mapConfig["Name"])
+	config.Token = interfaceToString(mapConfig["-token"])
+	config.UseCount = interfaceToInt(mapConfig["UseCount"])
+	config.Analytics = interfaceToBool(mapConfig["Analytics"])
+	config.SessionTimeout = interfaceToString(mapConfig["SessionTimeout"])
+	config.Multicast = parseMulticastConfig(mapConfig["Multicast"])
+
+	if mapPTZ, ok := mapConfig["PTZStatus"].(map[string]interface{}); ok {
+		config.PTZStatus = interfaceToBool(mapPTZ["Status"])
+		config.PTZPosition = interfaceToBool(mapPTZ["Position"])
+	}
+
+	if ifaceEvents, ok := mapConfig["Events"]; ok {
+		config.Events = true
+		if mapEvents, ok := ifaceEvents.(map[string]interface{}); ok {
+			if mapFilter, ok := mapEvents["Filter"].(map[string]interface{}); ok {
+				switch topic := mapFilter["TopicExpression"].(type) {
+				case string:
+					config.EventsFilter = topic
+				case map[string]interface{}:
+					config.EventsFilter = interfaceToString(topic["#text"])
+				}
+			}
+		}
+	}
+
+	return config
+}
+
+// parseMulticastConfig parses tt:MulticastConfiguration
+func parseMulticastConfig(src interface{}) MulticastConfig {
+	multicast := MulticastConfig{}
+	if mapMulticast, ok := src.(map[string]interface{}); ok {
+		multicast.Port = interfaceToInt(mapMulticast["Port"])
+		multicast.TTL = interfaceToInt(mapMulticast["TTL"])
+		multicast.AutoStart = interfaceToBool(mapMulticast["AutoStart"])
+
+		if mapAddress, ok := mapMulticast["Address"].(map[string]interface{}); ok {
+			multicast.Address = interfaceToString(mapAddress["IPv4Address"])
+			if multicast.Address == "" {
+				multicast.Address = interfaceToString(mapAddress["IPv6Address"])
+			}
+		}
+	}
+
+	return multicast
+}
+
+// multicastConfigXML creates content of tt:MulticastConfiguration
+func multicastConfigXML(multicast MulticastConfig) string {
+	address := `<tt:Type>IPv4</tt:Type><tt:IPv4Address>` + escapeXML(multicast.Address) + `</tt:IPv4Address>`
+	if strings.Contains(multicast.Address, ":") {
+		address = `<tt:Type>IPv6</tt:Type><tt:IPv6Address>` + escapeXML(multicast.Address) + `</tt:IPv6Address>`
+	}
+
+	return `<tt:Address>` + address + `</tt:Address>
+		<tt:Port>` + strconv.Itoa(multicast.Port) + `</tt:Port>
+		<tt:TTL>` + strconv.Itoa(multicast.TTL) + `</tt:TTL>
+		<tt:AutoStart>` + strconv.FormatBool(multicast.AutoStart) + `</tt:AutoStart>`
+}
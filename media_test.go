@@ -29,3 +29,39 @@ func TestGetStreamURI(t *testing.T) {
 	js := prettyJSON(&res)
 	fmt.Println(js)
 }
+
+func TestGetMetadataConfigurations(t *testing.T) {
+	log.Println("Test GetMetadataConfigurations")
+
+	res, err := testDevice.GetMetadataConfigurations()
+	if err != nil {
+		t.Error(err)
+	}
+
+	js := prettyJSON(&res)
+	fmt.Println(js)
+}
+
+func TestGetMetadataConfigurationOptions(t *testing.T) {
+	log.Println("Test GetMetadataConfigurationOptions")
+
+	res, err := testDevice.GetMetadataConfigurationOptions("", "")
+	if err != nil {
+		t.Error(err)
+	}
+
+	js := prettyJSON(&res)
+	fmt.Println(js)
+}
+
+func TestGetSnapshotURI(t *testing.T) {
+	log.Println("Test GetSnapshotURI")
+
+	res, err := testDevice.GetSnapshotURI("IPCProfilesToken0")
+	if err != nil {
+		t.Error(err)
+	}
+
+	js := prettyJSON(&res)
+	fmt.Println(js)
+}
@@ -0,0 +1,137 @@
+package onvif
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/krabiswabbie/go-onvif/onviftest"
+)
+
+func TestMockDevice(t *testing.T) {
+	server := onviftest.NewServer()
+	defer server.Close()
+
+	device := Device{XAddr: server.XAddr()}
+	if err := device.UpdateServices(); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := device.GetInformation()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Manufacturer != "onviftest" || info.HardwareID != "1" {
+		t.Errorf("unexpected device information %+v", info)
+	}
+
+	profiles, err := device.GetProfiles()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(profiles) != 1 || profiles[0].Token != "Profile_1" || profiles[0].VideoEncoderConfig.Resolution.Width != 1920 {
+		t.Fatalf("unexpected profiles %+v", profiles)
+	}
+
+	if _, err := device.GetStreamURI(profiles[0].Token, "RTSP"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Media requests must be routed to media service
+	request, ok := server.LastRequest("GetStreamUri")
+	if !ok {
+		t.Fatal("GetStreamUri not requested")
+	}
+	if request.Path != onviftest.MediaPath {
+		t.Errorf("GetStreamUri sent to %s, want %s", request.Path, onviftest.MediaPath)
+	}
+
+	snapshot, err := device.GetSnapshot(profiles[0].Token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(snapshot) < 2 || snapshot[0] != 0xff || snapshot[1] != 0xd8 {
+		t.Errorf("snapshot is not JPEG image")
+	}
+}
+
+func TestMockFault(t *testing.T) {
+	server := onviftest.NewServer()
+	defer server.Close()
+
+	server.HandleFault("GetProfiles", onviftest.SenderFault("ter:InvalidArgVal/ter:NoProfile", "No such profile"))
+
+	device := Device{XAddr: server.XAddr()}
+	_, err := device.GetProfiles()
+	if !errors.Is(err, ErrNoProfile) {
+		t.Errorf("expected ter:NoProfile fault, got %v", err)
+	}
+
+	// Operation that's not handled by the server isn't supported
+	_, err = device.GetRecordings()
+	if !errors.Is(err, ErrActionNotSupported) {
+		t.Errorf("expected ter:ActionNotSupported fault, got %v", err)
+	}
+}
+
+func TestMockAuthentication(t *testing.T) {
+	server := onviftest.NewServer()
+	defer server.Close()
+
+	server.SetCredentials("admin", "secret")
+
+	device := Device{XAddr: server.XAddr(), User: "admin", Password: "wrong"}
+	if _, err := device.GetHostname(); !errors.Is(err, ErrNotAuthorized) {
+		t.Errorf("expected ter:NotAuthorized fault, got %v", err)
+	}
+
+	device.Password = "secret"
+	if _, err := device.GetHostname(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestMockEvents(t *testing.T) {
+	server := onviftest.NewServer()
+	defer server.Close()
+
+	device := Device{XAddr: server.XAddr()}
+	if err := device.UpdateServices(); err != nil {
+		t.Fatal(err)
+	}
+
+	subscription, err := device.CreatePullPointSubscription("tns1:VideoSource/MotionAlarm", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server.PushEvent(onviftest.Event{
+		Topic:             "tns1:VideoSource/MotionAlarm",
+		PropertyOperation: "Changed",
+		Source:            []onviftest.SimpleItem{{Name: "Source", Value: "VideoSource_1"}},
+		Data:              []onviftest.SimpleItem{{Name: "State", Value: "true"}},
+	})
+
+	messages, err := device.PullMessages(subscription, time.Second, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(messages) != 1 {
+		t.Fatalf("received %d messages, want 1", len(messages))
+	}
+
+	message := messages[0]
+	if message.Topic != "tns1:VideoSource/MotionAlarm" || len(message.Data) != 1 || message.Data[0].Value != "true" {
+		t.Errorf("unexpected message %+v", message)
+	}
+
+	if err := device.Unsubscribe(subscription); err != nil {
+		t.Error(err)
+	}
+
+	request, _ := server.LastRequest("Unsubscribe")
+	if request.Path != onviftest.SubscriptionPath {
+		t.Errorf("Unsubscribe sent to %s, want %s", request.Path, onviftest.SubscriptionPath)
+	}
+}
@@ -1,108 +1,1118 @@
 package onvif
 
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"time"
+)
+
 // Device contains data of ONVIF camera
 type Device struct {
-	ID       string
-	Name     string
-	XAddr    string
-	User     string
-	Password string
+	ID       string `json:"id" xml:"id"`
+	Name     string `json:"name" xml:"name"`
+	XAddr    string `json:"xAddr" xml:"xAddr"`
+	User     string `json:"user" xml:"user"`
+	Password string `json:"-" xml:"-"`
+
+	// Services contains XAddr of each service of the camera, keyed by
+	// service namespace. It's populated by UpdateServices.
+	Services map[string]string `json:"services" xml:"-"`
+
+	// RootCAs, Certificates and InsecureSkipVerify configure TLS when
+	// the camera is accessed through HTTPS. RootCAs are used to verify
+	// the camera certificate, or system pool if it's nil. Certificates
+	// are presented to cameras that require client authentication.
+	// InsecureSkipVerify disables verification entirely, which is needed
+	// for cameras that ship with self-signed certificates.
+	RootCAs            *x509.CertPool    `json:"-" xml:"-"`
+	Certificates       []tls.Certificate `json:"-" xml:"-"`
+	InsecureSkipVerify bool              `json:"-" xml:"-"`
+
+	// HTTPClient is used to send requests to the camera, e.g. to go through
+	// a proxy or to use custom connection pool. If it's specified, TLS
+	// configuration above is ignored and must be set in its transport.
+	HTTPClient *http.Client `json:"-" xml:"-"`
+
+	// Hook, if any, is notified about each SOAP request sent to the camera
+	Hook RequestHook `json:"-" xml:"-"`
+
+	// Timeout limits duration of each request sent to the camera.
+	// If it's zero, timeout of the HTTP client is used.
+	Timeout time.Duration `json:"-" xml:"-"`
+
+	// Retry configures how requests are retried after transient network failure
+	Retry RetryPolicy `json:"-" xml:"-"`
+
+	// Instrumentation, if any, collects metrics or traces of requests sent to the camera
+	Instrumentation Instrumentation `json:"-" xml:"-"`
+
+	// Limiter, if any, serializes or rate-limits requests sent to the camera
+	Limiter *RequestLimiter `json:"-" xml:"-"`
+
+	// Cache, if any, keeps responses that rarely change, e.g. profiles,
+	// so they aren't fetched from the camera on every call
+	Cache *ResponseCache `json:"-" xml:"-"`
+
+	// transport keeps connections to the camera alive between requests.
+	// It's created by NewDevice and shared by copies of the device.
+	transport *http.Transport
+
+	// reportedXAddr is XAddr of device service reported by the camera. Other
+	// XAddrs reported on the same port are reached through device XAddr.
+	// It's set by UpdateServices.
+	reportedXAddr string
+}
+
+// Service contains data of a service provided by ONVIF camera
+type Service struct {
+	Namespace string `json:"namespace" xml:"namespace"`
+	XAddr     string `json:"xAddr" xml:"xAddr"`
+	Version   string `json:"version" xml:"version"`
 }
 
 // DeviceInformation contains information of ONVIF camera
 type DeviceInformation struct {
-	FirmwareVersion string
-	HardwareID      string
-	Manufacturer    string
-	Model           string
-	SerialNumber    string
+	FirmwareVersion string `json:"firmwareVersion" xml:"firmwareVersion"`
+	HardwareID      string `json:"hardwareID" xml:"hardwareID"`
+	Manufacturer    string `json:"manufacturer" xml:"manufacturer"`
+	Model           string `json:"model" xml:"model"`
+	SerialNumber    string `json:"serialNumber" xml:"serialNumber"`
 }
 
 // NetworkCapabilities contains networking capabilities of ONVIF camera
 type NetworkCapabilities struct {
-	DynDNS     bool
-	IPFilter   bool
-	IPVersion6 bool
-	ZeroConfig bool
+	DynDNS     bool `json:"dynDNS" xml:"dynDNS"`
+	IPFilter   bool `json:"ipFilter" xml:"ipFilter"`
+	IPVersion6 bool `json:"ipVersion6" xml:"ipVersion6"`
+	ZeroConfig bool `json:"zeroConfig" xml:"zeroConfig"`
 }
 
-// DeviceCapabilities contains capabilities of an ONVIF camera
+// ServiceCapabilities contains XAddr of a service of ONVIF camera.
+// XAddr is empty if the service is not supported.
+type ServiceCapabilities struct {
+	XAddr string `json:"xAddr" xml:"xAddr"`
+}
+
+// SystemCapabilities contains system capabilities of ONVIF camera
+type SystemCapabilities struct {
+	DiscoveryResolve  bool     `json:"discoveryResolve" xml:"discoveryResolve"`
+	DiscoveryBye      bool     `json:"discoveryBye" xml:"discoveryBye"`
+	RemoteDiscovery   bool     `json:"remoteDiscovery" xml:"remoteDiscovery"`
+	SystemBackup      bool     `json:"systemBackup" xml:"systemBackup"`
+	SystemLogging     bool     `json:"systemLogging" xml:"systemLogging"`
+	FirmwareUpgrade   bool     `json:"firmwareUpgrade" xml:"firmwareUpgrade"`
+	SupportedVersions []string `json:"supportedVersions" xml:"supportedVersions"`
+}
+
+// IOCapabilities contains number of IO connectors of ONVIF camera
+type IOCapabilities struct {
+	InputConnectors int `json:"inputConnectors" xml:"inputConnectors"`
+	RelayOutputs    int `json:"relayOutputs" xml:"relayOutputs"`
+}
+
+// SecurityCapabilities contains security capabilities of ONVIF camera
+type SecurityCapabilities struct {
+	TLS11                bool `json:"tls11" xml:"tls11"`
+	TLS12                bool `json:"tls12" xml:"tls12"`
+	OnboardKeyGeneration bool `json:"onboardKeyGeneration" xml:"onboardKeyGeneration"`
+	AccessPolicyConfig   bool `json:"accessPolicyConfig" xml:"accessPolicyConfig"`
+	X509Token            bool `json:"x509Token" xml:"x509Token"`
+	SAMLToken            bool `json:"samlToken" xml:"samlToken"`
+	KerberosToken        bool `json:"kerberosToken" xml:"kerberosToken"`
+	RELToken             bool `json:"relToken" xml:"relToken"`
+}
+
+// DeviceServiceCapabilities contains capabilities of device service
+type DeviceServiceCapabilities struct {
+	XAddr    string               `json:"xAddr" xml:"xAddr"`
+	System   SystemCapabilities   `json:"system" xml:"system"`
+	IO       IOCapabilities       `json:"io" xml:"io"`
+	Security SecurityCapabilities `json:"security" xml:"security"`
+}
+
+// MediaCapabilities contains capabilities of media service
+type MediaCapabilities struct {
+	XAddr                   string `json:"xAddr" xml:"xAddr"`
+	RTPMulticast            bool   `json:"rtpMulticast" xml:"rtpMulticast"`
+	RTPTCP                  bool   `json:"rtpTcp" xml:"rtpTcp"`
+	RTPRTSPTCP              bool   `json:"rtpRtspTcp" xml:"rtpRtspTcp"`
+	MaximumNumberOfProfiles int    `json:"maximumNumberOfProfiles" xml:"maximumNumberOfProfiles"`
+}
+
+// EventCapabilities contains capabilities of events service
+type EventCapabilities struct {
+	XAddr                                       string `json:"xAddr" xml:"xAddr"`
+	SubscriptionPolicySupport                   bool   `json:"subscriptionPolicySupport" xml:"subscriptionPolicySupport"`
+	PullPointSupport                            bool   `json:"pullPointSupport" xml:"pullPointSupport"`
+	PausableSubscriptionManagerInterfaceSupport bool   `json:"pausableSubscriptionManagerInterfaceSupport" xml:"pausableSubscriptionManagerInterfaceSupport"`
+}
+
+// AnalyticsCapabilities contains capabilities of analytics service
+type AnalyticsCapabilities struct {
+	XAddr                  string `json:"xAddr" xml:"xAddr"`
+	RuleSupport            bool   `json:"ruleSupport" xml:"ruleSupport"`
+	AnalyticsModuleSupport bool   `json:"analyticsModuleSupport" xml:"analyticsModuleSupport"`
+}
+
+// DeviceCapabilities contains capabilities of an ONVIF camera. Network, Events
+// and Streaming are kept for compatibility, while the other fields contain
+// capabilities and XAddr of each service. Service that is not reported by
+// camera has empty XAddr.
 type DeviceCapabilities struct {
-	Network   NetworkCapabilities
-	Events    map[string]bool
-	Streaming map[string]bool
+	Network   NetworkCapabilities `json:"network" xml:"network"`
+	Events    map[string]bool     `json:"events" xml:"-"`
+	Streaming map[string]bool     `json:"streaming" xml:"-"`
+
+	Device       DeviceServiceCapabilities `json:"device" xml:"device"`
+	Media        MediaCapabilities         `json:"media" xml:"media"`
+	EventService EventCapabilities         `json:"eventService" xml:"eventService"`
+	PTZ          ServiceCapabilities       `json:"ptz" xml:"ptz"`
+	Imaging      ServiceCapabilities       `json:"imaging" xml:"imaging"`
+	Analytics    AnalyticsCapabilities     `json:"analytics" xml:"analytics"`
+	DeviceIO     ServiceCapabilities       `json:"deviceIO" xml:"deviceIO"`
+	Recording    ServiceCapabilities       `json:"recording" xml:"recording"`
+	Search       ServiceCapabilities       `json:"search" xml:"search"`
+	Replay       ServiceCapabilities       `json:"replay" xml:"replay"`
+	Receiver     ServiceCapabilities       `json:"receiver" xml:"receiver"`
+}
+
+// NetworkHost contains address of a host in network. Type is IPv4, IPv6 or DNS,
+// which specifies the field that contains the address.
+type NetworkHost struct {
+	Type        string `json:"type" xml:"type"`
+	IPv4Address string `json:"ipv4Address" xml:"ipv4Address"`
+	IPv6Address string `json:"ipv6Address" xml:"ipv6Address"`
+	DNSName     string `json:"dnsName" xml:"dnsName"`
 }
 
 // HostnameInformation contains hostname info of an ONVIF camera
 type HostnameInformation struct {
-	Name     string
-	FromDHCP bool
+	Name     string `json:"name" xml:"name"`
+	FromDHCP bool   `json:"fromDHCP" xml:"fromDHCP"`
+}
+
+// User is an account of ONVIF camera. UserLevel is Administrator, Operator,
+// User, Anonymous or Extended. Password is only sent to camera, which
+// never returns it.
+type User struct {
+	Username  string `json:"username" xml:"username"`
+	Password  string `json:"password,omitempty" xml:"password,omitempty"`
+	UserLevel string `json:"userLevel" xml:"userLevel"`
+}
+
+// NTPInformation contains NTP servers used by ONVIF camera, either
+// obtained from DHCP or set manually
+type NTPInformation struct {
+	FromDHCP    bool          `json:"fromDHCP" xml:"fromDHCP"`
+	NTPFromDHCP []NetworkHost `json:"ntpFromDHCP" xml:"ntpFromDHCP"`
+	NTPManual   []NetworkHost `json:"ntpManual" xml:"ntpManual"`
+}
+
+// SystemDateAndTime contains clock settings of ONVIF camera. DateTimeType is
+// Manual or NTP. TimeZone is POSIX TZ string, e.g. CET-1CEST,M3.5.0,M10.5.0/3.
+// LocalDateTime is the clock in the time zone, although its location is UTC.
+type SystemDateAndTime struct {
+	DateTimeType    string    `json:"dateTimeType" xml:"dateTimeType"`
+	DaylightSavings bool      `json:"daylightSavings" xml:"daylightSavings"`
+	TimeZone        string    `json:"timeZone" xml:"timeZone"`
+	UTCDateTime     time.Time `json:"utcDateTime" xml:"utcDateTime"`
+	LocalDateTime   time.Time `json:"localDateTime" xml:"localDateTime"`
 }
 
-// MediaBounds contains resolution of a video media
+// MediaBounds contains resolution of a video media. X and Y are only used
+// by bounds of video source configuration, which crop the video source.
 type MediaBounds struct {
-	Height int
-	Width  int
+	X      int `json:"x" xml:"x"`
+	Y      int `json:"y" xml:"y"`
+	Height int `json:"height" xml:"height"`
+	Width  int `json:"width" xml:"width"`
 }
 
-// MediaSourceConfig contains configuration of a media source
+// Rotation contains rotation of a video source. Mode is OFF, ON or AUTO,
+// and Degree is only used if Mode is ON.
+type Rotation struct {
+	Mode   string `json:"mode" xml:"mode"`
+	Degree int    `json:"degree" xml:"degree"`
+}
+
+// MediaSourceConfig contains configuration of a media source.
+// Bounds and Rotate are only used by video source.
 type MediaSourceConfig struct {
-	Name        string
-	Token       string
-	SourceToken string
-	Bounds      MediaBounds
+	Name        string      `json:"name" xml:"name"`
+	Token       string      `json:"token" xml:"token"`
+	UseCount    int         `json:"useCount" xml:"useCount"`
+	SourceToken string      `json:"sourceToken" xml:"sourceToken"`
+	Bounds      MediaBounds `json:"bounds" xml:"bounds"`
+	Rotate      Rotation    `json:"rotate" xml:"rotate"`
+}
+
+// VideoSource contains data of a video source of ONVIF camera
+type VideoSource struct {
+	Token      string      `json:"token" xml:"token"`
+	Framerate  float64     `json:"framerate" xml:"framerate"`
+	Resolution MediaBounds `json:"resolution" xml:"resolution"`
+}
+
+// IntRange contains range of an integer option
+type IntRange struct {
+	Min int `json:"min" xml:"min"`
+	Max int `json:"max" xml:"max"`
+}
+
+// VideoSourceConfigOptions contains options for configuring video source
+type VideoSourceConfigOptions struct {
+	XRange            IntRange `json:"xRange" xml:"xRange"`
+	YRange            IntRange `json:"yRange" xml:"yRange"`
+	WidthRange        IntRange `json:"widthRange" xml:"widthRange"`
+	HeightRange       IntRange `json:"heightRange" xml:"heightRange"`
+	VideoSourceTokens []string `json:"videoSourceTokens" xml:"videoSourceTokens"`
+	RotateModes       []string `json:"rotateModes" xml:"rotateModes"`
+	RotateDegrees     []int    `json:"rotateDegrees" xml:"rotateDegrees"`
 }
 
 // VideoRateControl contains rate control of a video
 type VideoRateControl struct {
-	BitrateLimit     int
-	EncodingInterval int
-	FrameRateLimit   int
+	BitrateLimit     int `json:"bitrateLimit" xml:"bitrateLimit"`
+	EncodingInterval int `json:"encodingInterval" xml:"encodingInterval"`
+	FrameRateLimit   int `json:"frameRateLimit" xml:"frameRateLimit"`
 }
 
 // VideoEncoderConfig contains configuration of a video encoder
 type VideoEncoderConfig struct {
-	Name           string
-	Token          string
-	Encoding       string
-	Quality        int
-	RateControl    VideoRateControl
-	Resolution     MediaBounds
-	SessionTimeout string
+	Name           string           `json:"name" xml:"name"`
+	Token          string           `json:"token" xml:"token"`
+	Encoding       string           `json:"encoding" xml:"encoding"`
+	Quality        int              `json:"quality" xml:"quality"`
+	RateControl    VideoRateControl `json:"rateControl" xml:"rateControl"`
+	Resolution     MediaBounds      `json:"resolution" xml:"resolution"`
+	SessionTimeout string           `json:"sessionTimeout" xml:"sessionTimeout"`
 }
 
 // AudioEncoderConfig contains configuration of an audio encoder
 type AudioEncoderConfig struct {
-	Name           string
-	Token          string
-	Encoding       string
-	Bitrate        int
-	SampleRate     int
-	SessionTimeout string
+	Name           string `json:"name" xml:"name"`
+	Token          string `json:"token" xml:"token"`
+	Encoding       string `json:"encoding" xml:"encoding"`
+	Bitrate        int    `json:"bitrate" xml:"bitrate"`
+	SampleRate     int    `json:"sampleRate" xml:"sampleRate"`
+	SessionTimeout string `json:"sessionTimeout" xml:"sessionTimeout"`
+}
+
+// AudioOutputConfig contains configuration of an audio output, which is used
+// to play audio sent by client through audio backchannel.
+// SendPrimacy is URI of the half duplex mode, if camera supports it.
+type AudioOutputConfig struct {
+	Name        string `json:"name" xml:"name"`
+	Token       string `json:"token" xml:"token"`
+	UseCount    int    `json:"useCount" xml:"useCount"`
+	OutputToken string `json:"outputToken" xml:"outputToken"`
+	SendPrimacy string `json:"sendPrimacy" xml:"sendPrimacy"`
+	OutputLevel int    `json:"outputLevel" xml:"outputLevel"`
+}
+
+// PTZVector contains pan, tilt and zoom of PTZ camera, e.g. velocity of
+// continuous move. PanTiltSpace and ZoomSpace are optional URIs of the
+// coordinate spaces. If they're empty, the default space of PTZ node is used,
+// where each value is normalized between -1 and 1, except absolute zoom which
+// is between 0 and 1. Pan and tilt, or zoom, are only sent if they aren't zero
+// or their space is specified, so the other axes are left as they are, e.g.
+// AbsoluteMove of pan and tilt doesn't zoom out. IncludePanTilt and IncludeZoom
+// send them anyway, e.g. to move to absolute position 0.
+type PTZVector struct {
+	Pan            float64 `json:"pan" xml:"pan"`
+	Tilt           float64 `json:"tilt" xml:"tilt"`
+	Zoom           float64 `json:"zoom" xml:"zoom"`
+	PanTiltSpace   string  `json:"panTiltSpace" xml:"panTiltSpace"`
+	ZoomSpace      string  `json:"zoomSpace" xml:"zoomSpace"`
+	IncludePanTilt bool    `json:"includePanTilt,omitempty" xml:"includePanTilt,omitempty"`
+	IncludeZoom    bool    `json:"includeZoom,omitempty" xml:"includeZoom,omitempty"`
+}
+
+// FloatRange contains range of a float option
+type FloatRange struct {
+	Min float64 `json:"min" xml:"min"`
+	Max float64 `json:"max" xml:"max"`
+}
+
+// PTZSpace contains a coordinate space supported by PTZ node, identified by
+// its URI. YRange is only used by pan/tilt spaces.
+type PTZSpace struct {
+	URI    string     `json:"uri" xml:"uri"`
+	XRange FloatRange `json:"xRange" xml:"xRange"`
+	YRange FloatRange `json:"yRange" xml:"yRange"`
+}
+
+// PTZSpaces contains coordinate spaces supported by PTZ node for each kind of move
+type PTZSpaces struct {
+	AbsolutePanTiltPosition    []PTZSpace `json:"absolutePanTiltPosition" xml:"absolutePanTiltPosition"`
+	AbsoluteZoomPosition       []PTZSpace `json:"absoluteZoomPosition" xml:"absoluteZoomPosition"`
+	RelativePanTiltTranslation []PTZSpace `json:"relativePanTiltTranslation" xml:"relativePanTiltTranslation"`
+	RelativeZoomTranslation    []PTZSpace `json:"relativeZoomTranslation" xml:"relativeZoomTranslation"`
+	ContinuousPanTiltVelocity  []PTZSpace `json:"continuousPanTiltVelocity" xml:"continuousPanTiltVelocity"`
+	ContinuousZoomVelocity     []PTZSpace `json:"continuousZoomVelocity" xml:"continuousZoomVelocity"`
+	PanTiltSpeed               []PTZSpace `json:"panTiltSpeed" xml:"panTiltSpeed"`
+	ZoomSpeed                  []PTZSpace `json:"zoomSpeed" xml:"zoomSpeed"`
+}
+
+// PTZNode contains capabilities of a PTZ node, i.e. a PTZ capable device or
+// mechanism of camera. AuxiliaryCommands are the auxiliary commands supported
+// by the node, e.g. tt:Wiper|On.
+type PTZNode struct {
+	Token                  string    `json:"token" xml:"token"`
+	Name                   string    `json:"name" xml:"name"`
+	FixedHomePosition      bool      `json:"fixedHomePosition" xml:"fixedHomePosition"`
+	GeoMove                bool      `json:"geoMove" xml:"geoMove"`
+	SupportedSpaces        PTZSpaces `json:"supportedSpaces" xml:"supportedSpaces"`
+	MaximumNumberOfPresets int       `json:"maximumNumberOfPresets" xml:"maximumNumberOfPresets"`
+	HomeSupported          bool      `json:"homeSupported" xml:"homeSupported"`
+	AuxiliaryCommands      []string  `json:"auxiliaryCommands" xml:"auxiliaryCommands"`
+}
+
+// PTZPreset contains a saved position of PTZ camera
+type PTZPreset struct {
+	Token string `json:"token" xml:"token"`
+	Name  string `json:"name" xml:"name"`
 }
 
 // PTZConfig contains configuration of a PTZ control in camera
 type PTZConfig struct {
-	Name      string
-	Token     string
-	NodeToken string
+	Name      string `json:"name" xml:"name"`
+	Token     string `json:"token" xml:"token"`
+	NodeToken string `json:"nodeToken" xml:"nodeToken"`
+}
+
+// VideoAnalyticsConfig contains configuration of video analytics,
+// with analytics modules and rules that are run by camera
+type VideoAnalyticsConfig struct {
+	Name             string            `json:"name" xml:"name"`
+	Token            string            `json:"token" xml:"token"`
+	UseCount         int               `json:"useCount" xml:"useCount"`
+	AnalyticsModules []AnalyticsConfig `json:"analyticsModules" xml:"analyticsModules"`
+	Rules            []AnalyticsConfig `json:"rules" xml:"rules"`
+}
+
+// MulticastConfig contains multicast settings of a media stream
+type MulticastConfig struct {
+	Address   string `json:"address" xml:"address"`
+	Port      int    `json:"port" xml:"port"`
+	TTL       int    `json:"ttl" xml:"ttl"`
+	AutoStart bool   `json:"autoStart" xml:"autoStart"`
+}
+
+// MetadataConfig contains configuration of metadata stream.
+// EventsFilter is an optional topic expression of the events included in
+// the stream, which is only used if Events is true.
+type MetadataConfig struct {
+	Name           string          `json:"name" xml:"name"`
+	Token          string          `json:"token" xml:"token"`
+	UseCount       int             `json:"useCount" xml:"useCount"`
+	PTZStatus      bool            `json:"ptzStatus" xml:"ptzStatus"`
+	PTZPosition    bool            `json:"ptzPosition" xml:"ptzPosition"`
+	Events         bool            `json:"events" xml:"events"`
+	EventsFilter   string          `json:"eventsFilter" xml:"eventsFilter"`
+	Analytics      bool            `json:"analytics" xml:"analytics"`
+	Multicast      MulticastConfig `json:"multicast" xml:"multicast"`
+	SessionTimeout string          `json:"sessionTimeout" xml:"sessionTimeout"`
 }
 
-// MediaProfile contains media profile of an ONVIF camera
+// MetadataConfigOptions contains options for configuring metadata stream
+type MetadataConfigOptions struct {
+	PanTiltStatusSupported   bool     `json:"panTiltStatusSupported" xml:"panTiltStatusSupported"`
+	ZoomStatusSupported      bool     `json:"zoomStatusSupported" xml:"zoomStatusSupported"`
+	PanTiltPositionSupported bool     `json:"panTiltPositionSupported" xml:"panTiltPositionSupported"`
+	ZoomPositionSupported    bool     `json:"zoomPositionSupported" xml:"zoomPositionSupported"`
+	CompressionTypes         []string `json:"compressionTypes" xml:"compressionTypes"`
+}
+
+// MediaProfile contains media profile of an ONVIF camera.
+// Fixed profile can't be deleted.
 type MediaProfile struct {
-	Name               string
-	Token              string
-	VideoSourceConfig  MediaSourceConfig
-	VideoEncoderConfig VideoEncoderConfig
-	AudioSourceConfig  MediaSourceConfig
-	AudioEncoderConfig AudioEncoderConfig
-	PTZConfig          PTZConfig
+	Name                 string               `json:"name" xml:"name"`
+	Token                string               `json:"token" xml:"token"`
+	Fixed                bool                 `json:"fixed" xml:"fixed"`
+	VideoSourceConfig    MediaSourceConfig    `json:"videoSourceConfig" xml:"videoSourceConfig"`
+	VideoEncoderConfig   VideoEncoderConfig   `json:"videoEncoderConfig" xml:"videoEncoderConfig"`
+	AudioSourceConfig    MediaSourceConfig    `json:"audioSourceConfig" xml:"audioSourceConfig"`
+	AudioEncoderConfig   AudioEncoderConfig   `json:"audioEncoderConfig" xml:"audioEncoderConfig"`
+	PTZConfig            PTZConfig            `json:"ptzConfig" xml:"ptzConfig"`
+	VideoAnalyticsConfig VideoAnalyticsConfig `json:"videoAnalyticsConfig" xml:"videoAnalyticsConfig"`
+	MetadataConfig       MetadataConfig       `json:"metadataConfig" xml:"metadataConfig"`
+	AudioOutputConfig    AudioOutputConfig    `json:"audioOutputConfig" xml:"audioOutputConfig"`
 }
 
-// MediaURI contains streaming URI of an ONVIF camera
+// MediaURI contains streaming URI of an ONVIF camera.
+// Require, if any, must be sent by RTSP client in Require header of its requests.
 type MediaURI struct {
-	URI                 string
-	Timeout             string
-	InvalidAfterConnect bool
-	InvalidAfterReboot  bool
+	URI                 string `json:"uri" xml:"uri"`
+	Timeout             string `json:"timeout" xml:"timeout"`
+	InvalidAfterConnect bool   `json:"invalidAfterConnect" xml:"invalidAfterConnect"`
+	InvalidAfterReboot  bool   `json:"invalidAfterReboot" xml:"invalidAfterReboot"`
+	Require             string `json:"require" xml:"require"`
+}
+
+// FirmwareUpgradeInfo contains information for uploading firmware to ONVIF camera
+type FirmwareUpgradeInfo struct {
+	UploadURI        string        `json:"uploadURI" xml:"uploadURI"`
+	UploadDelay      time.Duration `json:"uploadDelay" xml:"uploadDelay"`
+	ExpectedDownTime time.Duration `json:"expectedDownTime" xml:"expectedDownTime"`
+}
+
+// RecordingSource contains information of the source of a recording
+type RecordingSource struct {
+	SourceID    string `json:"sourceID" xml:"sourceID"`
+	Name        string `json:"name" xml:"name"`
+	Location    string `json:"location" xml:"location"`
+	Description string `json:"description" xml:"description"`
+	Address     string `json:"address" xml:"address"`
+}
+
+// RecordingConfig contains configuration of a recording
+type RecordingConfig struct {
+	Source               RecordingSource `json:"source" xml:"source"`
+	Content              string          `json:"content" xml:"content"`
+	MaximumRetentionTime time.Duration   `json:"maximumRetentionTime" xml:"maximumRetentionTime"`
+}
+
+// RecordingTrack contains data of a track in a recording
+type RecordingTrack struct {
+	Token       string `json:"token" xml:"token"`
+	TrackType   string `json:"trackType" xml:"trackType"`
+	Description string `json:"description" xml:"description"`
+}
+
+// Recording contains data of a recording in ONVIF device
+type Recording struct {
+	Token  string           `json:"token" xml:"token"`
+	Config RecordingConfig  `json:"config" xml:"config"`
+	Tracks []RecordingTrack `json:"tracks" xml:"tracks"`
+}
+
+// RecordingJobTrack maps a track of the job source to a track of the recording
+type RecordingJobTrack struct {
+	SourceTag   string `json:"sourceTag" xml:"sourceTag"`
+	Destination string `json:"destination" xml:"destination"`
+}
+
+// RecordingJobSource contains source of a recording job
+type RecordingJobSource struct {
+	SourceToken        string              `json:"sourceToken" xml:"sourceToken"`
+	SourceType         string              `json:"sourceType" xml:"sourceType"`
+	AutoCreateReceiver bool                `json:"autoCreateReceiver" xml:"autoCreateReceiver"`
+	Tracks             []RecordingJobTrack `json:"tracks" xml:"tracks"`
+}
+
+// RecordingJobConfig contains configuration of a recording job
+type RecordingJobConfig struct {
+	RecordingToken string               `json:"recordingToken" xml:"recordingToken"`
+	Mode           string               `json:"mode" xml:"mode"`
+	Priority       int                  `json:"priority" xml:"priority"`
+	Sources        []RecordingJobSource `json:"sources" xml:"sources"`
+}
+
+// RecordingJob contains data of a recording job in ONVIF device
+type RecordingJob struct {
+	Token  string             `json:"token" xml:"token"`
+	Config RecordingJobConfig `json:"config" xml:"config"`
+}
+
+// RecordingJobState contains state of a recording job, which is
+// Idle, Active, PartiallyActive or Error
+type RecordingJobState struct {
+	RecordingToken string `json:"recordingToken" xml:"recordingToken"`
+	State          string `json:"state" xml:"state"`
+}
+
+// StorageConfig contains configuration of a storage used by ONVIF device
+// to store recordings, e.g. NFS or CIFS share. Password is only sent to
+// device and never returned by it.
+type StorageConfig struct {
+	Token      string `json:"token" xml:"token"`
+	Type       string `json:"type" xml:"type"`
+	LocalPath  string `json:"localPath" xml:"localPath"`
+	StorageURI string `json:"storageURI" xml:"storageURI"`
+	User       string `json:"user" xml:"user"`
+	Password   string `json:"password,omitempty" xml:"password,omitempty"`
+	Region     string `json:"region" xml:"region"`
+}
+
+// EdgeStorageStatus contains status of recording to storage of ONVIF device,
+// e.g. its SD card. FailedJobs contains tokens of recording jobs in error state.
+type EdgeStorageStatus struct {
+	Present    bool     `json:"present" xml:"present"`
+	Healthy    bool     `json:"healthy" xml:"healthy"`
+	Recording  bool     `json:"recording" xml:"recording"`
+	FailedJobs []string `json:"failedJobs" xml:"failedJobs"`
+}
+
+// ReplayConfig contains configuration of replay service
+type ReplayConfig struct {
+	SessionTimeout time.Duration `json:"sessionTimeout" xml:"sessionTimeout"`
+}
+
+// SimpleItem contains a name-value pair used in event messages and analytics configuration
+type SimpleItem struct {
+	Name  string `json:"name" xml:"name"`
+	Value string `json:"value" xml:"value"`
+}
+
+// SearchScope limits the recordings included in a search
+type SearchScope struct {
+	IncludedSources            []string `json:"includedSources" xml:"includedSources"`
+	IncludedRecordings         []string `json:"includedRecordings" xml:"includedRecordings"`
+	RecordingInformationFilter string   `json:"recordingInformationFilter" xml:"recordingInformationFilter"`
+}
+
+// TrackInformation contains information of a track found by search
+type TrackInformation struct {
+	TrackToken  string    `json:"trackToken" xml:"trackToken"`
+	TrackType   string    `json:"trackType" xml:"trackType"`
+	Description string    `json:"description" xml:"description"`
+	DataFrom    time.Time `json:"dataFrom" xml:"dataFrom"`
+	DataTo      time.Time `json:"dataTo" xml:"dataTo"`
+}
+
+// RecordingInformation contains information of a recording found by search
+type RecordingInformation struct {
+	RecordingToken    string             `json:"recordingToken" xml:"recordingToken"`
+	Source            RecordingSource    `json:"source" xml:"source"`
+	EarliestRecording time.Time          `json:"earliestRecording" xml:"earliestRecording"`
+	LatestRecording   time.Time          `json:"latestRecording" xml:"latestRecording"`
+	Content           string             `json:"content" xml:"content"`
+	Tracks            []TrackInformation `json:"tracks" xml:"tracks"`
+	RecordingStatus   string             `json:"recordingStatus" xml:"recordingStatus"`
+}
+
+// FindRecordingResult contains results of a recording search session
+type FindRecordingResult struct {
+	SearchState          string                 `json:"searchState" xml:"searchState"`
+	RecordingInformation []RecordingInformation `json:"recordingInformation" xml:"recordingInformation"`
+}
+
+// FindEventResult contains an event found by search
+type FindEventResult struct {
+	RecordingToken  string       `json:"recordingToken" xml:"recordingToken"`
+	TrackToken      string       `json:"trackToken" xml:"trackToken"`
+	Time            time.Time    `json:"time" xml:"time"`
+	Topic           string       `json:"topic" xml:"topic"`
+	Source          []SimpleItem `json:"source" xml:"source"`
+	Data            []SimpleItem `json:"data" xml:"data"`
+	StartStateEvent bool         `json:"startStateEvent" xml:"startStateEvent"`
+}
+
+// FindEventResults contains results of an event search session
+type FindEventResults struct {
+	SearchState string            `json:"searchState" xml:"searchState"`
+	Results     []FindEventResult `json:"results" xml:"results"`
+}
+
+// RelayOutputSettings contains settings of a relay output.
+// Mode is Monostable or Bistable, IdleState is open or closed
+type RelayOutputSettings struct {
+	Mode      string        `json:"mode" xml:"mode"`
+	DelayTime time.Duration `json:"delayTime" xml:"delayTime"`
+	IdleState string        `json:"idleState" xml:"idleState"`
+}
+
+// RelayOutput contains data of a relay output of ONVIF device
+type RelayOutput struct {
+	Token    string              `json:"token" xml:"token"`
+	Settings RelayOutputSettings `json:"settings" xml:"settings"`
+}
+
+// DigitalInput contains data of a digital input of ONVIF device
+type DigitalInput struct {
+	Token     string `json:"token" xml:"token"`
+	IdleState string `json:"idleState" xml:"idleState"`
+}
+
+// ReceiverConfig contains configuration of a receiver.
+// Possible mode is AutoConnect, AlwaysConnect or NeverConnect.
+type ReceiverConfig struct {
+	Mode     string `json:"mode" xml:"mode"`
+	MediaURI string `json:"mediaURI" xml:"mediaURI"`
+	Stream   string `json:"stream" xml:"stream"`
+	Protocol string `json:"protocol" xml:"protocol"`
+}
+
+// Receiver contains data of a receiver in ONVIF device
+type Receiver struct {
+	Token  string         `json:"token" xml:"token"`
+	Config ReceiverConfig `json:"config" xml:"config"`
+}
+
+// ElementItem contains a complex parameter of analytics module or rule.
+// XML is the content of the element, e.g. <tt:CellLayout ...>...</tt:CellLayout>.
+// Elements without prefix are treated as part of ONVIF schema namespace.
+type ElementItem struct {
+	Name string `json:"name" xml:"name"`
+	XML  string `json:"xml" xml:"xml"`
+}
+
+// ItemList contains parameters of analytics module or rule
+type ItemList struct {
+	SimpleItems  []SimpleItem  `json:"simpleItems" xml:"simpleItems"`
+	ElementItems []ElementItem `json:"elementItems" xml:"elementItems"`
+}
+
+// AnalyticsConfig contains configuration of an analytics module or rule
+type AnalyticsConfig struct {
+	Name       string   `json:"name" xml:"name"`
+	Type       string   `json:"type" xml:"type"`
+	Parameters ItemList `json:"parameters" xml:"parameters"`
+}
+
+// ItemDescription describes a parameter of analytics module or rule
+type ItemDescription struct {
+	Name string `json:"name" xml:"name"`
+	Type string `json:"type" xml:"type"`
+}
+
+// AnalyticsConfigDescription describes an analytics module or rule supported by ONVIF device
+type AnalyticsConfigDescription struct {
+	Name         string            `json:"name" xml:"name"`
+	SimpleItems  []ItemDescription `json:"simpleItems" xml:"simpleItems"`
+	ElementItems []ItemDescription `json:"elementItems" xml:"elementItems"`
+}
+
+// PullPointSubscription contains data of an event subscription in ONVIF device
+type PullPointSubscription struct {
+	Address             string    `json:"address" xml:"address"`
+	ReferenceParameters string    `json:"referenceParameters" xml:"referenceParameters"`
+	CurrentTime         time.Time `json:"currentTime" xml:"currentTime"`
+	TerminationTime     time.Time `json:"terminationTime" xml:"terminationTime"`
+}
+
+// NotificationMessage contains an event message from ONVIF device
+type NotificationMessage struct {
+	Topic             string       `json:"topic" xml:"topic"`
+	UtcTime           time.Time    `json:"utcTime" xml:"utcTime"`
+	PropertyOperation string       `json:"propertyOperation" xml:"propertyOperation"`
+	Source            []SimpleItem `json:"source" xml:"source"`
+	Data              []SimpleItem `json:"data" xml:"data"`
+}
+
+// MotionRegion is a rectangle area of video in normalized coordinate,
+// where (0, 0) is the top left and (1, 1) is the bottom right of video
+type MotionRegion struct {
+	X      float64 `json:"x" xml:"x"`
+	Y      float64 `json:"y" xml:"y"`
+	Width  float64 `json:"width" xml:"width"`
+	Height float64 `json:"height" xml:"height"`
+}
+
+// MotionEvent contains a change of motion state detected by ONVIF camera
+type MotionEvent struct {
+	Time   time.Time `json:"time" xml:"time"`
+	Source string    `json:"source" xml:"source"`
+	Rule   string    `json:"rule" xml:"rule"`
+	Motion bool      `json:"motion" xml:"motion"`
+}
+
+// Announcement contains a WS-Discovery Hello or Bye message, which is sent by
+// device when it joins or leaves the network. XAddr of device might be empty,
+// especially in Bye message.
+type Announcement struct {
+	Type   string    `json:"type" xml:"type"`
+	Time   time.Time `json:"time" xml:"time"`
+	Device Device    `json:"device" xml:"device"`
+	Scopes []string  `json:"scopes" xml:"scopes"`
+}
+
+// AccessPointCapabilities contains capabilities of an access point
+type AccessPointCapabilities struct {
+	DisableAccessPoint    bool `json:"disableAccessPoint" xml:"disableAccessPoint"`
+	Duress                bool `json:"duress" xml:"duress"`
+	AnonymousAccess       bool `json:"anonymousAccess" xml:"anonymousAccess"`
+	AccessTaken           bool `json:"accessTaken" xml:"accessTaken"`
+	ExternalAuthorization bool `json:"externalAuthorization" xml:"externalAuthorization"`
+}
+
+// AccessPointInfo contains information of an access point of access control device
+type AccessPointInfo struct {
+	Token        string                  `json:"token" xml:"token"`
+	Name         string                  `json:"name" xml:"name"`
+	Description  string                  `json:"description" xml:"description"`
+	AreaFrom     string                  `json:"areaFrom" xml:"areaFrom"`
+	AreaTo       string                  `json:"areaTo" xml:"areaTo"`
+	EntityType   string                  `json:"entityType" xml:"entityType"`
+	Entity       string                  `json:"entity" xml:"entity"`
+	Capabilities AccessPointCapabilities `json:"capabilities" xml:"capabilities"`
+}
+
+// DoorCapabilities contains capabilities of a door
+type DoorCapabilities struct {
+	Access               bool `json:"access" xml:"access"`
+	AccessTimingOverride bool `json:"accessTimingOverride" xml:"accessTimingOverride"`
+	Lock                 bool `json:"lock" xml:"lock"`
+	Unlock               bool `json:"unlock" xml:"unlock"`
+	Block                bool `json:"block" xml:"block"`
+	DoubleLock           bool `json:"doubleLock" xml:"doubleLock"`
+	LockDown             bool `json:"lockDown" xml:"lockDown"`
+	LockOpen             bool `json:"lockOpen" xml:"lockOpen"`
+	DoorMonitor          bool `json:"doorMonitor" xml:"doorMonitor"`
+	LockMonitor          bool `json:"lockMonitor" xml:"lockMonitor"`
+	DoubleLockMonitor    bool `json:"doubleLockMonitor" xml:"doubleLockMonitor"`
+	Alarm                bool `json:"alarm" xml:"alarm"`
+	Tamper               bool `json:"tamper" xml:"tamper"`
+	Fault                bool `json:"fault" xml:"fault"`
+}
+
+// DoorInfo contains information of a door of door control device
+type DoorInfo struct {
+	Token        string           `json:"token" xml:"token"`
+	Name         string           `json:"name" xml:"name"`
+	Description  string           `json:"description" xml:"description"`
+	Capabilities DoorCapabilities `json:"capabilities" xml:"capabilities"`
+}
+
+// AccessDoorOptions contains optional timings for granting access through a door.
+// Zero duration means the default of door control device is used.
+type AccessDoorOptions struct {
+	UseExtendedTime bool          `json:"useExtendedTime" xml:"useExtendedTime"`
+	AccessTime      time.Duration `json:"accessTime" xml:"accessTime"`
+	OpenTooLongTime time.Duration `json:"openTooLongTime" xml:"openTooLongTime"`
+	PreAlarmTime    time.Duration `json:"preAlarmTime" xml:"preAlarmTime"`
+}
+
+// ThermalColorPalette contains a color palette of thermal camera
+type ThermalColorPalette struct {
+	Token string `json:"token" xml:"token"`
+	Type  string `json:"type" xml:"type"`
+	Name  string `json:"name" xml:"name"`
+}
+
+// ThermalNUCTable contains a non-uniformity correction table of thermal camera
+type ThermalNUCTable struct {
+	Token           string  `json:"token" xml:"token"`
+	Name            string  `json:"name" xml:"name"`
+	LowTemperature  float64 `json:"lowTemperature" xml:"lowTemperature"`
+	HighTemperature float64 `json:"highTemperature" xml:"highTemperature"`
+}
+
+// ThermalCooler contains state of the cooler of thermal sensor.
+// RunTime is the number of hours the cooler has been running.
+type ThermalCooler struct {
+	Enabled bool    `json:"enabled" xml:"enabled"`
+	RunTime float64 `json:"runTime" xml:"runTime"`
+}
+
+// ThermalConfig contains thermal configuration of a video source.
+// Polarity is WhiteHot or BlackHot. NUCTable is only set if Token is not empty,
+// and Cooler is nil if the sensor has no cooler.
+type ThermalConfig struct {
+	VideoSourceToken string              `json:"videoSourceToken" xml:"videoSourceToken"`
+	ColorPalette     ThermalColorPalette `json:"colorPalette" xml:"colorPalette"`
+	Polarity         string              `json:"polarity" xml:"polarity"`
+	NUCTable         ThermalNUCTable     `json:"nucTable" xml:"nucTable"`
+	Cooler           *ThermalCooler      `json:"cooler,omitempty" xml:"cooler,omitempty"`
+}
+
+// RadiometryConfig contains global parameters for radiometric measurement of thermal camera
+type RadiometryConfig struct {
+	ReflectedAmbientTemperature float64 `json:"reflectedAmbientTemperature" xml:"reflectedAmbientTemperature"`
+	Emissivity                  float64 `json:"emissivity" xml:"emissivity"`
+	DistanceToObject            float64 `json:"distanceToObject" xml:"distanceToObject"`
+	RelativeHumidity            float64 `json:"relativeHumidity" xml:"relativeHumidity"`
+	AtmosphericTemperature      float64 `json:"atmosphericTemperature" xml:"atmosphericTemperature"`
+	AtmosphericTransmittance    float64 `json:"atmosphericTransmittance" xml:"atmosphericTransmittance"`
+	ExtOpticsTemperature        float64 `json:"extOpticsTemperature" xml:"extOpticsTemperature"`
+	ExtOpticsTransmittance      float64 `json:"extOpticsTransmittance" xml:"extOpticsTransmittance"`
+}
+
+// CredentialIdentifier contains an identifier of a credential, e.g. card number.
+// Type is the identifier type such as pt:Card, with its FormatType such as WIEGAND26
+type CredentialIdentifier struct {
+	Type                       string `json:"type" xml:"type"`
+	FormatType                 string `json:"formatType" xml:"formatType"`
+	ExemptedFromAuthentication bool   `json:"exemptedFromAuthentication" xml:"exemptedFromAuthentication"`
+	Value                      []byte `json:"value" xml:"value"`
+}
+
+// CredentialAccessProfile links a credential to an access profile
+type CredentialAccessProfile struct {
+	AccessProfileToken string    `json:"accessProfileToken" xml:"accessProfileToken"`
+	ValidFrom          time.Time `json:"validFrom" xml:"validFrom"`
+	ValidTo            time.Time `json:"validTo" xml:"validTo"`
+}
+
+// Credential contains data of a credential in access control device
+type Credential struct {
+	Token                     string                    `json:"token" xml:"token"`
+	Description               string                    `json:"description" xml:"description"`
+	CredentialHolderReference string                    `json:"credentialHolderReference" xml:"credentialHolderReference"`
+	ValidFrom                 time.Time                 `json:"validFrom" xml:"validFrom"`
+	ValidTo                   time.Time                 `json:"validTo" xml:"validTo"`
+	Identifiers               []CredentialIdentifier    `json:"identifiers" xml:"identifiers"`
+	AccessProfiles            []CredentialAccessProfile `json:"accessProfiles" xml:"accessProfiles"`
+}
+
+// TimeRange contains a period of time in a day, e.g. 08:00:00 until 17:00:00
+type TimeRange struct {
+	From  string `json:"from" xml:"from"`
+	Until string `json:"until" xml:"until"`
+}
+
+// SpecialDaysSchedule overrides a schedule on the days of a special day group
+type SpecialDaysSchedule struct {
+	GroupToken string      `json:"groupToken" xml:"groupToken"`
+	TimeRanges []TimeRange `json:"timeRanges" xml:"timeRanges"`
+}
+
+// Schedule contains data of a schedule in access control device.
+// Standard is the schedule definition in iCalendar format.
+type Schedule struct {
+	Token       string                `json:"token" xml:"token"`
+	Name        string                `json:"name" xml:"name"`
+	Description string                `json:"description" xml:"description"`
+	Standard    string                `json:"standard" xml:"standard"`
+	SpecialDays []SpecialDaysSchedule `json:"specialDays" xml:"specialDays"`
+}
+
+// ScopeInfo contains typed fields of the scopes of ONVIF camera.
+// Scopes that are not recognized are kept in Others.
+type ScopeInfo struct {
+	Name      string   `json:"name" xml:"name"`
+	Hardware  string   `json:"hardware" xml:"hardware"`
+	Locations []string `json:"locations" xml:"locations"`
+	Profiles  []string `json:"profiles" xml:"profiles"`
+	Types     []string `json:"types" xml:"types"`
+	Others    []string `json:"others" xml:"others"`
+}
+
+// Certificate contains a certificate of ONVIF camera, with its DER encoded data
+type Certificate struct {
+	ID   string `json:"id" xml:"id"`
+	Data []byte `json:"data" xml:"data"`
+}
+
+// CertificateStatus contains whether a certificate is used by ONVIF camera
+type CertificateStatus struct {
+	ID      string `json:"id" xml:"id"`
+	Enabled bool   `json:"enabled" xml:"enabled"`
+}
+
+// CertificateInformation contains details of a certificate of ONVIF camera
+type CertificateInformation struct {
+	ID                 string    `json:"id" xml:"id"`
+	IssuerDN           string    `json:"issuerDN" xml:"issuerDN"`
+	SubjectDN          string    `json:"subjectDN" xml:"subjectDN"`
+	KeyUsage           string    `json:"keyUsage" xml:"keyUsage"`
+	ExtendedKeyUsage   string    `json:"extendedKeyUsage" xml:"extendedKeyUsage"`
+	KeyLength          int       `json:"keyLength" xml:"keyLength"`
+	Version            string    `json:"version" xml:"version"`
+	SerialNumber       string    `json:"serialNumber" xml:"serialNumber"`
+	SignatureAlgorithm string    `json:"signatureAlgorithm" xml:"signatureAlgorithm"`
+	ValidFrom          time.Time `json:"validFrom" xml:"validFrom"`
+	ValidUntil         time.Time `json:"validUntil" xml:"validUntil"`
+}
+
+// Dot1XConfig contains IEEE 802.1X configuration of ONVIF camera. EAPMethod
+// is the EAP method type number, e.g. 13 for EAP-TLS. TLSCertificateID is
+// the client certificate used by EAP-TLS, while Password is used by the
+// password based methods and never returned by camera.
+type Dot1XConfig struct {
+	Token            string   `json:"token" xml:"token"`
+	Identity         string   `json:"identity" xml:"identity"`
+	AnonymousID      string   `json:"anonymousID" xml:"anonymousID"`
+	EAPMethod        int      `json:"eapMethod" xml:"eapMethod"`
+	CACertificateIDs []string `json:"caCertificateIDs" xml:"caCertificateIDs"`
+	TLSCertificateID string   `json:"tlsCertificateID" xml:"tlsCertificateID"`
+	Password         string   `json:"password,omitempty" xml:"password,omitempty"`
+}
+
+// IPAddressFilter contains the addresses that are allowed or denied to access
+// ONVIF camera. Type is Allow or Deny. Each address is written in CIDR
+// notation, e.g. 192.168.1.0/24, and can be either IPv4 or IPv6.
+type IPAddressFilter struct {
+	Type      string   `json:"type" xml:"type"`
+	Addresses []string `json:"addresses" xml:"addresses"`
+}
+
+// NetworkProtocol contains configuration of a network protocol served by
+// ONVIF camera. Name is HTTP, HTTPS or RTSP.
+type NetworkProtocol struct {
+	Name    string `json:"name" xml:"name"`
+	Enabled bool   `json:"enabled" xml:"enabled"`
+	Ports   []int  `json:"ports" xml:"ports"`
+}
+
+// DynamicDNSInformation contains dynamic DNS configuration of ONVIF camera.
+// Type is NoUpdate, ClientUpdates or ServerUpdates.
+type DynamicDNSInformation struct {
+	Type string        `json:"type" xml:"type"`
+	Name string        `json:"name" xml:"name"`
+	TTL  time.Duration `json:"ttl" xml:"ttl"`
+}
+
+// ZeroConfiguration contains zero-configuration (link-local addressing)
+// state of a network interface of ONVIF camera
+type ZeroConfiguration struct {
+	InterfaceToken string   `json:"interfaceToken" xml:"interfaceToken"`
+	Enabled        bool     `json:"enabled" xml:"enabled"`
+	Addresses      []string `json:"addresses" xml:"addresses"`
+}
+
+// Dot11Capabilities contains wireless capabilities of ONVIF camera
+type Dot11Capabilities struct {
+	TKIP                  bool `json:"tkip" xml:"tkip"`
+	ScanAvailableNetworks bool `json:"scanAvailableNetworks" xml:"scanAvailableNetworks"`
+	MultipleConfiguration bool `json:"multipleConfiguration" xml:"multipleConfiguration"`
+	AdHocStationMode      bool `json:"adHocStationMode" xml:"adHocStationMode"`
+	WEP                   bool `json:"wep" xml:"wep"`
+}
+
+// Dot11Status contains state of wireless network interface. Cipher is CCMP,
+// TKIP, Any or Extended. SignalStrength is None, Very Bad, Bad, Good,
+// Very Good or Extended.
+type Dot11Status struct {
+	SSID              string `json:"ssid" xml:"ssid"`
+	BSSID             string `json:"bssid" xml:"bssid"`
+	PairCipher        string `json:"pairCipher" xml:"pairCipher"`
+	GroupCipher       string `json:"groupCipher" xml:"groupCipher"`
+	SignalStrength    string `json:"signalStrength" xml:"signalStrength"`
+	ActiveConfigAlias string `json:"activeConfigAlias" xml:"activeConfigAlias"`
+}
+
+// Dot11AvailableNetwork is a wireless network found by ONVIF camera.
+// AuthAndManagementSuites contains None, PSK, Dot1X or Extended.
+type Dot11AvailableNetwork struct {
+	SSID                    string   `json:"ssid" xml:"ssid"`
+	BSSID                   string   `json:"bssid" xml:"bssid"`
+	AuthAndManagementSuites []string `json:"authAndManagementSuites" xml:"authAndManagementSuites"`
+	PairCiphers             []string `json:"pairCiphers" xml:"pairCiphers"`
+	GroupCiphers            []string `json:"groupCiphers" xml:"groupCiphers"`
+	SignalStrength          string   `json:"signalStrength" xml:"signalStrength"`
+}
+
+// Dot11Configuration is a wireless network joined by network interface.
+// Mode is Ad-hoc or Infrastructure. Alias identifies the configuration, and
+// the one with highest priority is used if several networks are available.
+type Dot11Configuration struct {
+	SSID     string                     `json:"ssid" xml:"ssid"`
+	Mode     string                     `json:"mode" xml:"mode"`
+	Alias    string                     `json:"alias" xml:"alias"`
+	Priority int                        `json:"priority" xml:"priority"`
+	Security Dot11SecurityConfiguration `json:"security" xml:"security"`
+}
+
+// Dot11SecurityConfiguration is security of wireless network. Mode is None,
+// WEP, PSK, Dot1X or Extended, and Algorithm is CCMP, TKIP or Any. PSK mode
+// uses either 256-bit Key or Passphrase, and Dot1X mode uses the 802.1X
+// configuration with token Dot1X.
+type Dot11SecurityConfiguration struct {
+	Mode       string `json:"mode" xml:"mode"`
+	Algorithm  string `json:"algorithm,omitempty" xml:"algorithm,omitempty"`
+	Key        []byte `json:"-" xml:"-"`
+	Passphrase string `json:"-" xml:"-"`
+	Dot1X      string `json:"dot1X,omitempty" xml:"dot1X,omitempty"`
+}
+
+// NetworkInterfaceSetConfiguration contains settings of network interface set
+// by SetNetworkInterfaces. Only wireless settings are supported. If Enabled is
+// nil, the interface is left enabled or disabled as it is.
+type NetworkInterfaceSetConfiguration struct {
+	Enabled *bool                `json:"enabled,omitempty" xml:"enabled,omitempty"`
+	Dot11   []Dot11Configuration `json:"dot11" xml:"dot11"`
+}
+
+// GeoLocation contains WGS84 position, in degrees for longitude and latitude
+// and in meters above sea level for elevation
+type GeoLocation struct {
+	Lon       float64 `json:"lon" xml:"lon"`
+	Lat       float64 `json:"lat" xml:"lat"`
+	Elevation float64 `json:"elevation" xml:"elevation"`
+}
+
+// GeoOrientation contains orientation of an entity in degrees
+type GeoOrientation struct {
+	Roll  float64 `json:"roll" xml:"roll"`
+	Pitch float64 `json:"pitch" xml:"pitch"`
+	Yaw   float64 `json:"yaw" xml:"yaw"`
+}
+
+// LocationEntity contains geolocation of an entity of ONVIF camera,
+// e.g. the device itself or one of its video sources
+type LocationEntity struct {
+	Entity         string         `json:"entity" xml:"entity"`
+	Token          string         `json:"token" xml:"token"`
+	Fixed          bool           `json:"fixed" xml:"fixed"`
+	GeoSource      string         `json:"geoSource" xml:"geoSource"`
+	AutoGeo        bool           `json:"autoGeo" xml:"autoGeo"`
+	GeoLocation    GeoLocation    `json:"geoLocation" xml:"geoLocation"`
+	GeoOrientation GeoOrientation `json:"geoOrientation" xml:"geoOrientation"`
+}
+
+// Snapshot contains a JPEG snapshot fetched by SnapshotPoller.
+// If it couldn't be fetched, Err is set and Image is nil.
+type Snapshot struct {
+	ProfileToken string    `json:"profileToken" xml:"profileToken"`
+	Time         time.Time `json:"time" xml:"time"`
+	Image        []byte    `json:"image" xml:"image"`
+	Err          error     `json:"-" xml:"-"`
+}
+
+// SystemLogURI contains URI to download a system log of ONVIF camera.
+// Type is System or Access.
+type SystemLogURI struct {
+	Type string `json:"type" xml:"type"`
+	URI  string `json:"uri" xml:"uri"`
+}
+
+// SystemURIs contains URIs to download diagnostic data of ONVIF camera
+type SystemURIs struct {
+	SystemLogs      []SystemLogURI `json:"systemLogs" xml:"systemLogs"`
+	SupportInfoURI  string         `json:"supportInfoURI" xml:"supportInfoURI"`
+	SystemBackupURI string         `json:"systemBackupURI" xml:"systemBackupURI"`
+}
+
+// SystemLog contains a system log of ONVIF camera, which is either text
+// or binary data, e.g. compressed archive
+type SystemLog struct {
+	String string `json:"string" xml:"string"`
+	Binary []byte `json:"binary" xml:"binary"`
+}
+
+// FeatureCheck contains result of checking a feature of an ONVIF profile.
+// Error contains the reason why the feature is not supported, if any.
+type FeatureCheck struct {
+	Name      string `json:"name" xml:"name"`
+	Mandatory bool   `json:"mandatory" xml:"mandatory"`
+	Supported bool   `json:"supported" xml:"supported"`
+	Error     string `json:"error,omitempty" xml:"error,omitempty"`
+}
+
+// ProfileReport contains result of checking whether ONVIF camera conforms to
+// a profile. Declared is true if camera declares the profile in its scopes.
+type ProfileReport struct {
+	Profile  ConformanceProfile `json:"profile" xml:"profile"`
+	Device   DeviceInformation  `json:"device" xml:"device"`
+	Declared bool               `json:"declared" xml:"declared"`
+	Features []FeatureCheck     `json:"features" xml:"features"`
+}
+
+// ProvisionConfig is the initial configuration set by ProvisionDevice. Empty
+// fields are left unchanged. Password is set for Username, which is created
+// as administrator if it doesn't exist. NTPServers are host names or IP
+// addresses. TimeZone is POSIX TZ string, e.g. CET-1CEST,M3.5.0,M10.5.0/3.
+type ProvisionConfig struct {
+	Username        string   `json:"username" xml:"username"`
+	Password        string   `json:"-" xml:"-"`
+	Hostname        string   `json:"hostname" xml:"hostname"`
+	NTPServers      []string `json:"ntpServers" xml:"ntpServers"`
+	TimeZone        string   `json:"timeZone" xml:"timeZone"`
+	DaylightSavings bool     `json:"daylightSavings" xml:"daylightSavings"`
+	Scopes          []string `json:"scopes" xml:"scopes"`
+}
+
+// ProvisionStep contains result of a step of ProvisionDevice.
+// Error contains the reason why the step failed, if any.
+type ProvisionStep struct {
+	Name  string `json:"name" xml:"name"`
+	Error string `json:"error,omitempty" xml:"error,omitempty"`
+}
+
+// ProvisionReport contains result of each step of ProvisionDevice
+type ProvisionReport struct {
+	Steps []ProvisionStep `json:"steps" xml:"steps"`
 }
@@ -0,0 +1,328 @@
+package onvif
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/deepch/mxj"
+)
+
+const (
+	motionModuleName = "MyCellMotionModule"
+	motionRuleName   = "MyMotionDetectorRule"
+
+	motionPullTimeout        = 2 * time.Second
+	motionSubscriptionPeriod = time.Minute
+
+	defaultCellColumns = 22
+	defaultCellRows    = 18
+)
+
+var (
+	motionTopics = TopicFilter(TopicCellMotion, TopicMotionAlarm)
+
+	errNoVideoAnalytics = errors.New("No media profile has video analytics configuration")
+)
+
+// EnableMotionDetection configures cell motion detection of ONVIF camera, so motion
+// is only detected inside the region. Sensitivity is between 0 and 100.
+// The analytics module and rule are created if they don't exist yet.
+func (device Device) EnableMotionDetection(region MotionRegion, sensitivity int) error {
+	// Validate parameters
+	if sensitivity < 0 || sensitivity > 100 {
+		return errors.New("Sensitivity must be between 0 and 100")
+	}
+
+	if region.Width <= 0 || region.Height <= 0 || region.X < 0 || region.Y < 0 ||
+		region.X+region.Width > 1 || region.Y+region.Height > 1 {
+		return errors.New("Motion region must be inside the video")
+	}
+
+	// Find video analytics configuration
+	configToken, err := device.videoAnalyticsToken()
+	if err != nil {
+		return err
+	}
+
+	// Configure cell motion engine
+	modules, err := device.GetAnalyticsModules(configToken)
+	if err != nil {
+		return err
+	}
+
+	columns, rows := defaultCellColumns, defaultCellRows
+	module, found := findAnalyticsConfig(modules, "CellMotionEngine")
+	if found {
+		columns, rows = cellLayoutSize(module.Parameters, columns, rows)
+		module.Parameters.SimpleItems = setSimpleItem(module.Parameters.SimpleItems, "Sensitivity", strconv.Itoa(sensitivity))
+		err = device.ModifyAnalyticsModules(configToken, []AnalyticsConfig{module})
+	} else {
+		module = AnalyticsConfig{
+			Name: motionModuleName,
+			Type: "tt:CellMotionEngine",
+			Parameters: ItemList{
+				SimpleItems: []SimpleItem{{Name: "Sensitivity", Value: strconv.Itoa(sensitivity)}},
+				ElementItems: []ElementItem{{
+					Name: "Layout",
+					XML:  cellLayoutXML(columns, rows),
+				}},
+			},
+		}
+		err = device.CreateAnalyticsModules(configToken, []AnalyticsConfig{module})
+	}
+	if err != nil {
+		return err
+	}
+
+	// Configure cell motion detector rule
+	rules, err := device.GetRules(configToken)
+	if err != nil {
+		return err
+	}
+
+	activeCells := encodeActiveCells(region, columns, rows)
+	rule, found := findAnalyticsConfig(rules, "CellMotionDetector")
+	if found {
+		rule.Parameters.SimpleItems = setSimpleItem(rule.Parameters.SimpleItems, "ActiveCells", activeCells)
+		return device.ModifyRules(configToken, []AnalyticsConfig{rule})
+	}
+
+	rule = AnalyticsConfig{
+		Name: motionRuleName,
+		Type: "tt:CellMotionDetector",
+		Parameters: ItemList{SimpleItems: []SimpleItem{
+			{Name: "MinCount", Value: "1"},
+			{Name: "AlarmOnDelay", Value: "1000"},
+			{Name: "AlarmOffDelay", Value: "1000"},
+			{Name: "ActiveCells", Value: activeCells},
+		}},
+	}
+	return device.CreateRules(configToken, []AnalyticsConfig{rule})
+}
+
+// WatchMotion subscribes to motion events of ONVIF camera and sends every change of
+// motion state to the returned channel. The channel is closed when context is done.
+func (device Device) WatchMotion(ctx context.Context) (<-chan MotionEvent, error) {
+	subscription, err := device.CreatePullPointSubscription(motionTopics, motionSubscriptionPeriod)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make(chan MotionEvent, 16)
+	go func() {
+		defer close(events)
+		defer func() { device.Unsubscribe(subscription) }()
+
+		lastRenew := time.Now()
+		for ctx.Err() == nil {
+			// Keep the subscription alive
+			var err error
+			if time.Since(lastRenew) > motionSubscriptionPeriod/2 {
+				err = device.Renew(subscription, motionSubscriptionPeriod)
+				lastRenew = time.Now()
+			}
+
+			var messages []NotificationMessage
+			if err == nil {
+				messages, err = device.PullMessages(subscription, motionPullTimeout, 32)
+			}
+
+			if err != nil {
+				// Subscription is lost, e.g. camera rebooted, so it's replaced right away.
+				// The old one is unsubscribed in case camera still keeps it.
+				device.Unsubscribe(subscription)
+				if newSubscription, err := device.CreatePullPointSubscription(motionTopics, motionSubscriptionPeriod); err == nil {
+					subscription = newSubscription
+					lastRenew = time.Now()
+					continue
+				}
+
+				// Wait a moment before retrying, so a broken camera isn't flooded
+				select {
+				case <-ctx.Done():
+				case <-time.After(time.Second):
+				}
+				continue
+			}
+
+			for _, message := range messages {
+				event, ok := parseMotionEvent(message)
+				if !ok {
+					continue
+				}
+
+				select {
+				case events <- event:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return events, nil
+}
+
+// videoAnalyticsToken returns token of the first video analytics configuration used in media profiles
+func (device Device) videoAnalyticsToken() (string, error) {
+	profiles, err := device.GetProfiles()
+	if err != nil {
+		return "", err
+	}
+
+	for _, profile := range profiles {
+		if profile.VideoAnalyticsConfig.Token != "" {
+			return profile.VideoAnalyticsConfig.Token, nil
+		}
+	}
+
+	return "", errNoVideoAnalytics
+}
+
+// parseMotionEvent converts an event message to motion event
+func parseMotionEvent(message NotificationMessage) (MotionEvent, bool) {
+	event := MotionEvent{Time: message.UtcTime}
+
+	stateName := ""
+	switch {
+	case message.MatchTopic(TopicCellMotion):
+		stateName = "IsMotion"
+	case message.MatchTopic(TopicMotionAlarm):
+		stateName = "State"
+	default:
+		return event, false
+	}
+
+	for _, item := range message.Source {
+		switch item.Name {
+		case "VideoSourceConfigurationToken", "Source":
+			event.Source = item.Value
+		case "Rule":
+			event.Rule = item.Value
+		}
+	}
+
+	state, ok := message.DataValue(stateName)
+	if !ok {
+		return event, false
+	}
+
+	event.Motion = strings.ToLower(state) == "true"
+	return event, true
+}
+
+// findAnalyticsConfig finds analytics module or rule with the specified type,
+// regardless of the namespace prefix used by device
+func findAnalyticsConfig(configs []AnalyticsConfig, configType string) (AnalyticsConfig, bool) {
+	for _, config := range configs {
+		if config.Type == configType || strings.HasSuffix(config.Type, ":"+configType) {
+			return config, true
+		}
+	}
+
+	return AnalyticsConfig{}, false
+}
+
+// setSimpleItem changes value of a simple item, or adds it if it doesn't exist
+func setSimpleItem(items []SimpleItem, name, value string) []SimpleItem {
+	for i := range items {
+		if items[i].Name == name {
+			items[i].Value = value
+			return items
+		}
+	}
+
+	return append(items, SimpleItem{Name: name, Value: value})
+}
+
+// cellLayoutSize returns number of columns and rows in cell layout of cell motion engine
+func cellLayoutSize(parameters ItemList, defaultColumns, defaultRows int) (int, int) {
+	for _, item := range parameters.ElementItems {
+		if item.Name != "Layout" {
+			continue
+		}
+
+		mapLayout, err := mxj.NewMapXml([]byte(item.XML))
+		if err != nil {
+			break
+		}
+
+		columns := interfaceToInt(mapLayout.ValueOrEmptyForPathString("CellLayout.-Columns"))
+		rows := interfaceToInt(mapLayout.ValueOrEmptyForPathString("CellLayout.-Rows"))
+		if columns > 0 && rows > 0 {
+			return columns, rows
+		}
+	}
+
+	return defaultColumns, defaultRows
+}
+
+// cellLayoutXML creates tt:CellLayout that covers the whole video
+func cellLayoutXML(columns, rows int) string {
+	scaleX := strconv.FormatFloat(2/float64(columns), 'f', 6, 64)
+	scaleY := strconv.FormatFloat(2/float64(rows), 'f', 6, 64)
+
+	return `<tt:CellLayout Columns="` + strconv.Itoa(columns) + `" Rows="` + strconv.Itoa(rows) + `">
+		<tt:Transformation>
+			<tt:Translate x="-1.0" y="-1.0"/>
+			<tt:Scale x="` + scaleX + `" y="` + scaleY + `"/>
+		</tt:Transformation>
+	</tt:CellLayout>`
+}
+
+// encodeActiveCells creates ActiveCells parameter of cell motion detector, which is
+// a base64 encoded, PackBits compressed bitmap with one bit per cell, row by row.
+// A cell is active if its center is inside the region.
+func encodeActiveCells(region MotionRegion, columns, rows int) string {
+	bitmap := make([]byte, (columns*rows+7)/8)
+	for row := 0; row < rows; row++ {
+		for column := 0; column < columns; column++ {
+			x := (float64(column) + 0.5) / float64(columns)
+			y := (float64(row) + 0.5) / float64(rows)
+			if x < region.X || x > region.X+region.Width || y < region.Y || y > region.Y+region.Height {
+				continue
+			}
+
+			bit := row*columns + column
+			bitmap[bit/8] |= 0x80 >> uint(bit%8)
+		}
+	}
+
+	return base64.StdEncoding.EncodeToString(packBits(bitmap))
+}
+
+// packBits compresses data using PackBits algorithm
+func packBits(data []byte) []byte {
+	result := []byte{}
+	for i := 0; i < len(data); {
+		// Count repeated bytes
+		run := 1
+		for i+run < len(data) && run < 128 && data[i+run] == data[i] {
+			run++
+		}
+
+		if run > 1 {
+			result = append(result, byte(257-run), data[i])
+			i += run
+			continue
+		}
+
+		// Collect literal bytes until the next repetition
+		start := i
+		for i < len(data) && i-start < 128 {
+			if i+1 < len(data) && data[i] == data[i+1] {
+				break
+			}
+			i++
+		}
+
+		result = append(result, byte(i-start-1))
+		result = append(result, data[start:i]...)
+	}
+
+	return result
+}
@@ -0,0 +1,124 @@
+package onvif
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/krabiswabbie/go-onvif/onviftest"
+)
+
+func TestPackBits(t *testing.T) {
+	// Example from Apple TIFF PackBits specification
+	data := []byte{
+		0xAA, 0xAA, 0xAA, 0x80, 0x00, 0x2A, 0xAA, 0xAA, 0xAA, 0xAA, 0x80, 0x00,
+		0x2A, 0x22, 0xAA, 0xAA, 0xAA, 0xAA, 0xAA, 0xAA, 0xAA, 0xAA, 0xAA, 0xAA,
+	}
+	expected := []byte{0xFE, 0xAA, 0x02, 0x80, 0x00, 0x2A, 0xFD, 0xAA, 0x03, 0x80, 0x00, 0x2A, 0x22, 0xF7, 0xAA}
+
+	if result := packBits(data); !bytes.Equal(result, expected) {
+		t.Errorf("expected % X, got % X", expected, result)
+	}
+}
+
+func TestEncodeActiveCells(t *testing.T) {
+	// Whole video of 4x2 cells, which is one byte of 0xFF
+	result := encodeActiveCells(MotionRegion{Width: 1, Height: 1}, 4, 2)
+	if expected := base64.StdEncoding.EncodeToString([]byte{0x00, 0xFF}); result != expected {
+		t.Errorf("expected %s, got %s", expected, result)
+	}
+
+	// Left half of video of 4x2 cells
+	result = encodeActiveCells(MotionRegion{Width: 0.5, Height: 1}, 4, 2)
+	if expected := base64.StdEncoding.EncodeToString([]byte{0x00, 0xCC}); result != expected {
+		t.Errorf("expected %s, got %s", expected, result)
+	}
+}
+
+func TestParseMotionEvent(t *testing.T) {
+	utcTime := time.Date(2019, 1, 1, 10, 0, 0, 0, time.UTC)
+	message := NotificationMessage{
+		Topic:   "tns1:RuleEngine/CellMotionDetector/Motion",
+		UtcTime: utcTime,
+		Source: []SimpleItem{
+			{Name: "VideoSourceConfigurationToken", Value: "VideoSourceToken"},
+			{Name: "Rule", Value: "MyMotionDetectorRule"},
+		},
+		Data: []SimpleItem{{Name: "IsMotion", Value: "true"}},
+	}
+
+	event, ok := parseMotionEvent(message)
+	expected := MotionEvent{Time: utcTime, Source: "VideoSourceToken", Rule: "MyMotionDetectorRule", Motion: true}
+	if !ok || event != expected {
+		t.Errorf("expected %v, got %v", expected, event)
+	}
+
+	message.Topic = "tt:RuleEngine/CellMotionDetector/Motion"
+	if _, ok := parseMotionEvent(message); !ok {
+		t.Error("motion event with other namespace prefix must be parsed")
+	}
+
+	message.Topic = "tns1:Device/Trigger/DigitalInput"
+	if _, ok := parseMotionEvent(message); ok {
+		t.Error("non motion event must be ignored")
+	}
+}
+
+func TestWatchMotionResubscribe(t *testing.T) {
+	server := onviftest.NewServer()
+	defer server.Close()
+
+	// The first pull fails, e.g. because camera rebooted and lost the subscription
+	var mutex sync.Mutex
+	pulls := 0
+	server.Handle("PullMessages", func(onviftest.Request) (string, error) {
+		mutex.Lock()
+		pulls++
+		first := pulls == 1
+		mutex.Unlock()
+
+		if first {
+			return "", onviftest.SenderFault("wsrf-rw:ResourceUnknownFault", "Unknown subscription")
+		}
+
+		time.Sleep(10 * time.Millisecond)
+		return `<tev:PullMessagesResponse/>`, nil
+	})
+
+	device := Device{XAddr: server.XAddr()}
+	if err := device.UpdateServices(); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events, err := device.WatchMotion(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Subscription is replaced right away, and the old one is unsubscribed
+	deadline := time.Now().Add(2 * time.Second)
+	for countRequests(server, "CreatePullPointSubscription") < 2 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	operations := []string{}
+	for _, request := range server.Requests() {
+		if request.Operation != "GetServices" && request.Operation != "GetCapabilities" {
+			operations = append(operations, request.Operation)
+		}
+	}
+
+	expected := []string{"CreatePullPointSubscription", "PullMessages", "Unsubscribe", "CreatePullPointSubscription", "PullMessages"}
+	if len(operations) < len(expected) || !reflect.DeepEqual(operations[:len(expected)], expected) {
+		t.Errorf("unexpected requests %v, want %v", operations, expected)
+	}
+
+	cancel()
+	for range events {
+	}
+}
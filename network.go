@@ -0,0 +1,194 @@
+package onvif
+
+import "strconv"
+
+// Names of network protocol
+const (
+	NetworkProtocolHTTP  = "HTTP"
+	NetworkProtocolHTTPS = "HTTPS"
+	NetworkProtocolRTSP  = "RTSP"
+)
+
+// Types of dynamic DNS
+const (
+	DynamicDNSNoUpdate      = "NoUpdate"
+	DynamicDNSClientUpdates = "ClientUpdates"
+	DynamicDNSServerUpdates = "ServerUpdates"
+)
+
+// GetNetworkProtocols fetch network protocols served by ONVIF camera
+func (device Device) GetNetworkProtocols() ([]NetworkProtocol, error) {
+	// Create SOAP
+	soap := SOAP{
+		Body:  "<tds:GetNetworkProtocols/>",
+		XMLNs: deviceXMLNs,
+	}
+
+	// Send SOAP request
+	response, err := device.sendRequest(deviceNamespace, soap)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse response to interface
+	ifaceProtocols, err := response.ValuesForPath("Envelope.Body.GetNetworkProtocolsResponse.NetworkProtocols")
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert interface to array of protocol
+	protocols := []NetworkProtocol{}
+	for _, ifaceProtocol := range ifaceProtocols {
+		mapProtocol, ok := ifaceProtocol.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		protocol := NetworkProtocol{
+			Name:    interfaceToString(mapProtocol["Name"]),
+			Enabled: interfaceToBool(mapProtocol["Enabled"]),
+			Ports:   []int{},
+		}
+
+		for _, port := range interfaceToStrings(mapProtocol["Port"]) {
+			if number, err := strconv.Atoi(port); err == nil {
+				protocol.Ports = append(protocol.Ports, number)
+			}
+		}
+
+		protocols = append(protocols, protocol)
+	}
+
+	return protocols, nil
+}
+
+// SetNetworkProtocols enables or disables network protocols of ONVIF camera
+// and sets their ports. Protocols that are not specified are left unchanged.
+func (device Device) SetNetworkProtocols(protocols []NetworkProtocol) error {
+	// Create SOAP
+	body := `<tds:SetNetworkProtocols>`
+	for _, protocol := range protocols {
+		body += `<tds:NetworkProtocols>
+			<tt:Name>` + escapeXML(protocol.Name) + `</tt:Name>
+			<tt:Enabled>` + strconv.FormatBool(protocol.Enabled) + `</tt:Enabled>`
+		for _, port := range protocol.Ports {
+			body += `<tt:Port>` + strconv.Itoa(port) + `</tt:Port>`
+		}
+		body += `</tds:NetworkProtocols>`
+	}
+	body += `</tds:SetNetworkProtocols>`
+
+	soap := SOAP{
+		Body:  body,
+		XMLNs: deviceXMLNs,
+	}
+
+	// Send SOAP request
+	_, err := device.sendRequest(deviceNamespace, soap)
+	return err
+}
+
+// GetDynamicDNS fetch dynamic DNS configuration of ONVIF camera
+func (device Device) GetDynamicDNS() (DynamicDNSInformation, error) {
+	// Create SOAP
+	soap := SOAP{
+		Body:  "<tds:GetDynamicDNS/>",
+		XMLNs: deviceXMLNs,
+	}
+
+	// Send SOAP request
+	response, err := device.sendRequest(deviceNamespace, soap)
+	if err != nil {
+		return DynamicDNSInformation{}, err
+	}
+
+	// Parse response to interface
+	ifaceDNS, err := response.ValueForPath("Envelope.Body.GetDynamicDNSResponse.DynamicDNSInformation")
+	if err != nil {
+		return DynamicDNSInformation{}, err
+	}
+
+	// Parse interface to struct
+	dynamicDNS := DynamicDNSInformation{}
+	if mapDNS, ok := ifaceDNS.(map[string]interface{}); ok {
+		dynamicDNS.Type = interfaceToString(mapDNS["Type"])
+		dynamicDNS.Name = interfaceToString(mapDNS["Name"])
+		dynamicDNS.TTL, _ = parseDuration(interfaceToString(mapDNS["TTL"]))
+	}
+
+	return dynamicDNS, nil
+}
+
+// SetDynamicDNS sets dynamic DNS configuration of ONVIF camera.
+// Name and TTL are only sent if they're not empty.
+func (device Device) SetDynamicDNS(dynamicDNS DynamicDNSInformation) error {
+	// Create SOAP
+	body := `<tds:SetDynamicDNS>
+		<tds:Type>` + escapeXML(dynamicDNS.Type) + `</tds:Type>`
+	if dynamicDNS.Name != "" {
+		body += `<tds:Name>` + escapeXML(dynamicDNS.Name) + `</tds:Name>`
+	}
+	if dynamicDNS.TTL != 0 {
+		body += `<tds:TTL>` + formatDuration(dynamicDNS.TTL) + `</tds:TTL>`
+	}
+	body += `</tds:SetDynamicDNS>`
+
+	soap := SOAP{
+		Body:  body,
+		XMLNs: deviceXMLNs,
+	}
+
+	// Send SOAP request
+	_, err := device.sendRequest(deviceNamespace, soap)
+	return err
+}
+
+// GetZeroConfiguration fetch zero-configuration state of ONVIF camera
+func (device Device) GetZeroConfiguration() (ZeroConfiguration, error) {
+	// Create SOAP
+	soap := SOAP{
+		Body:  "<tds:GetZeroConfiguration/>",
+		XMLNs: deviceXMLNs,
+	}
+
+	// Send SOAP request
+	response, err := device.sendRequest(deviceNamespace, soap)
+	if err != nil {
+		return ZeroConfiguration{}, err
+	}
+
+	// Parse response to interface
+	ifaceZeroConfig, err := response.ValueForPath("Envelope.Body.GetZeroConfigurationResponse.ZeroConfiguration")
+	if err != nil {
+		return ZeroConfiguration{}, err
+	}
+
+	// Parse interface to struct
+	zeroConfig := ZeroConfiguration{Addresses: []string{}}
+	if mapZeroConfig, ok := ifaceZeroConfig.(map[string]interface{}); ok {
+		zeroConfig.InterfaceToken = interfaceToString(mapZeroConfig["InterfaceToken"])
+		zeroConfig.Enabled = interfaceToBool(mapZeroConfig["Enabled"])
+		if addresses := interfaceToStrings(mapZeroConfig["Addresses"]); addresses != nil {
+			zeroConfig.Addresses = addresses
+		}
+	}
+
+	return zeroConfig, nil
+}
+
+// SetZeroConfiguration enables or disables zero-configuration
+// of a network interface of ONVIF camera
+func (device Device) SetZeroConfiguration(interfaceToken string, enabled bool) error {
+	// Create SOAP
+	soap := SOAP{
+		XMLNs: deviceXMLNs,
+		Body: `<tds:SetZeroConfiguration>
+			<tds:InterfaceToken>` + escapeXML(interfaceToken) + `</tds:InterfaceToken>
+			<tds:Enabled>` + strconv.FormatBool(enabled) + `</tds:Enabled>
+		</tds:SetZeroConfiguration>`,
+	}
+
+	// Send SOAP request
+	_, err := device.sendRequest(deviceNamespace, soap)
+	return err
+}
@@ -0,0 +1,167 @@
+package onvif
+
+import (
+	"fmt"
+	"log"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/krabiswabbie/go-onvif/onviftest"
+)
+
+func TestGetNetworkProtocols(t *testing.T) {
+	log.Println("Test GetNetworkProtocols")
+
+	res, err := testDevice.GetNetworkProtocols()
+	if err != nil {
+		t.Error(err)
+	}
+
+	js := prettyJSON(&res)
+	fmt.Println(js)
+}
+
+func TestGetDynamicDNS(t *testing.T) {
+	log.Println("Test GetDynamicDNS")
+
+	res, err := testDevice.GetDynamicDNS()
+	if err != nil {
+		t.Error(err)
+	}
+
+	js := prettyJSON(&res)
+	fmt.Println(js)
+}
+
+func TestGetZeroConfiguration(t *testing.T) {
+	log.Println("Test GetZeroConfiguration")
+
+	res, err := testDevice.GetZeroConfiguration()
+	if err != nil {
+		t.Error(err)
+	}
+
+	js := prettyJSON(&res)
+	fmt.Println(js)
+}
+
+func TestNetworkProtocols(t *testing.T) {
+	server := onviftest.NewServer()
+	defer server.Close()
+
+	server.HandleBody("GetNetworkProtocols", `<tds:GetNetworkProtocolsResponse>
+		<tds:NetworkProtocols><tt:Name>HTTP</tt:Name><tt:Enabled>true</tt:Enabled><tt:Port>80</tt:Port><tt:Port>8080</tt:Port></tds:NetworkProtocols>
+		<tds:NetworkProtocols><tt:Name>HTTPS</tt:Name><tt:Enabled>false</tt:Enabled><tt:Port>443</tt:Port></tds:NetworkProtocols>
+		<tds:NetworkProtocols><tt:Name>RTSP</tt:Name><tt:Enabled>true</tt:Enabled></tds:NetworkProtocols>
+	</tds:GetNetworkProtocolsResponse>`)
+	server.HandleBody("SetNetworkProtocols", `<tds:SetNetworkProtocolsResponse/>`)
+
+	device := Device{XAddr: server.XAddr()}
+	protocols, err := device.GetNetworkProtocols()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []NetworkProtocol{
+		{Name: NetworkProtocolHTTP, Enabled: true, Ports: []int{80, 8080}},
+		{Name: NetworkProtocolHTTPS, Enabled: false, Ports: []int{443}},
+		{Name: NetworkProtocolRTSP, Enabled: true, Ports: []int{}},
+	}
+	if !reflect.DeepEqual(protocols, expected) {
+		t.Errorf("expected %+v, got %+v", expected, protocols)
+	}
+
+	protocols = []NetworkProtocol{
+		{Name: NetworkProtocolHTTPS, Enabled: true, Ports: []int{443, 8443}},
+		{Name: NetworkProtocolRTSP, Enabled: false},
+	}
+	if err := device.SetNetworkProtocols(protocols); err != nil {
+		t.Fatal(err)
+	}
+
+	request, _ := server.LastRequest("SetNetworkProtocols")
+	body := `<tds:SetNetworkProtocols>` +
+		`<tds:NetworkProtocols><tt:Name>HTTPS</tt:Name><tt:Enabled>true</tt:Enabled><tt:Port>443</tt:Port><tt:Port>8443</tt:Port></tds:NetworkProtocols>` +
+		`<tds:NetworkProtocols><tt:Name>RTSP</tt:Name><tt:Enabled>false</tt:Enabled></tds:NetworkProtocols>` +
+		`</tds:SetNetworkProtocols>`
+	if !strings.Contains(request.Envelope, body) {
+		t.Errorf("unexpected request %s", request.Envelope)
+	}
+}
+
+func TestDynamicDNSAndZeroConfiguration(t *testing.T) {
+	server := onviftest.NewServer()
+	defer server.Close()
+
+	server.HandleBody("GetDynamicDNS", `<tds:GetDynamicDNSResponse>
+		<tds:DynamicDNSInformation>
+			<tt:Type>ClientUpdates</tt:Type>
+			<tt:Name>camera.example.com</tt:Name>
+			<tt:TTL>PT1H</tt:TTL>
+		</tds:DynamicDNSInformation>
+	</tds:GetDynamicDNSResponse>`)
+	server.HandleBody("SetDynamicDNS", `<tds:SetDynamicDNSResponse/>`)
+	server.HandleBody("GetZeroConfiguration", `<tds:GetZeroConfigurationResponse>
+		<tds:ZeroConfiguration>
+			<tt:InterfaceToken>eth0</tt:InterfaceToken>
+			<tt:Enabled>true</tt:Enabled>
+			<tt:Addresses>169.254.10.20</tt:Addresses>
+		</tds:ZeroConfiguration>
+	</tds:GetZeroConfigurationResponse>`)
+	server.HandleBody("SetZeroConfiguration", `<tds:SetZeroConfigurationResponse/>`)
+
+	device := Device{XAddr: server.XAddr()}
+	dynamicDNS, err := device.GetDynamicDNS()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedDNS := DynamicDNSInformation{Type: DynamicDNSClientUpdates, Name: "camera.example.com", TTL: time.Hour}
+	if dynamicDNS != expectedDNS {
+		t.Errorf("expected %+v, got %+v", expectedDNS, dynamicDNS)
+	}
+
+	// Name and TTL are sent after type, and name is escaped
+	dynamicDNS = DynamicDNSInformation{Type: DynamicDNSClientUpdates, Name: "a&b.example.com", TTL: 90 * time.Second}
+	if err := device.SetDynamicDNS(dynamicDNS); err != nil {
+		t.Fatal(err)
+	}
+
+	request, _ := server.LastRequest("SetDynamicDNS")
+	body := `<tds:SetDynamicDNS><tds:Type>ClientUpdates</tds:Type><tds:Name>a&amp;b.example.com</tds:Name><tds:TTL>PT90S</tds:TTL></tds:SetDynamicDNS>`
+	if !strings.Contains(request.Envelope, body) {
+		t.Errorf("unexpected request %s", request.Envelope)
+	}
+
+	// Empty name and zero TTL are omitted
+	if err := device.SetDynamicDNS(DynamicDNSInformation{Type: DynamicDNSNoUpdate}); err != nil {
+		t.Fatal(err)
+	}
+
+	request, _ = server.LastRequest("SetDynamicDNS")
+	if !strings.Contains(request.Envelope, `<tds:SetDynamicDNS><tds:Type>NoUpdate</tds:Type></tds:SetDynamicDNS>`) {
+		t.Errorf("unexpected request %s", request.Envelope)
+	}
+
+	zeroConfig, err := device.GetZeroConfiguration()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedZeroConfig := ZeroConfiguration{InterfaceToken: "eth0", Enabled: true, Addresses: []string{"169.254.10.20"}}
+	if !reflect.DeepEqual(zeroConfig, expectedZeroConfig) {
+		t.Errorf("expected %+v, got %+v", expectedZeroConfig, zeroConfig)
+	}
+
+	if err := device.SetZeroConfiguration("eth0", false); err != nil {
+		t.Fatal(err)
+	}
+
+	request, _ = server.LastRequest("SetZeroConfiguration")
+	body = `<tds:SetZeroConfiguration><tds:InterfaceToken>eth0</tds:InterfaceToken><tds:Enabled>false</tds:Enabled></tds:SetZeroConfiguration>`
+	if !strings.Contains(request.Envelope, body) {
+		t.Errorf("unexpected request %s", request.Envelope)
+	}
+}
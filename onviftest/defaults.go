@@ -0,0 +1,276 @@
+package onviftest
+
+import (
+	"bytes"
+	"encoding/xml"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultSnapshot is JPEG image of a single gray pixel
+var defaultSnapshot = func() []byte {
+	img := image.NewGray(image.Rect(0, 0, 1, 1))
+	img.Set(0, 0, color.Gray{Y: 128})
+
+	buffer := &bytes.Buffer{}
+	jpeg.Encode(buffer, img, nil)
+	return buffer.Bytes()
+}()
+
+// SimpleItem is a name-value pair in source or data of an event
+type SimpleItem struct {
+	Name  string
+	Value string
+}
+
+// Event is a notification message returned by PullMessages
+type Event struct {
+	Topic             string
+	UtcTime           time.Time
+	PropertyOperation string
+	Source            []SimpleItem
+	Data              []SimpleItem
+}
+
+// PushEvent queues an event, which will be returned by the next PullMessages
+func (server *Server) PushEvent(event Event) {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	server.events = append(server.events, event)
+}
+
+// registerDefaults registers handlers of common operations of device,
+// media, PTZ and events services, which describe a camera with one
+// profile and one PTZ node
+func (server *Server) registerDefaults() {
+	server.HandleBody("GetDeviceInformation", `<tds:GetDeviceInformationResponse>
+		<tds:Manufacturer>onviftest</tds:Manufacturer>
+		<tds:Model>Mock Camera</tds:Model>
+		<tds:FirmwareVersion>1.0.0</tds:FirmwareVersion>
+		<tds:SerialNumber>0123456789</tds:SerialNumber>
+		<tds:HardwareId>1</tds:HardwareId>
+	</tds:GetDeviceInformationResponse>`)
+
+	server.HandleBody("GetHostname", `<tds:GetHostnameResponse>
+		<tds:HostnameInformation>
+			<tt:FromDHCP>false</tt:FromDHCP>
+			<tt:Name>onviftest</tt:Name>
+		</tds:HostnameInformation>
+	</tds:GetHostnameResponse>`)
+
+	server.HandleBody("GetDiscoveryMode", `<tds:GetDiscoveryModeResponse>
+		<tds:DiscoveryMode>Discoverable</tds:DiscoveryMode>
+	</tds:GetDiscoveryModeResponse>`)
+
+	server.HandleBody("GetScopes", `<tds:GetScopesResponse>
+		<tds:Scopes>
+			<tt:ScopeDef>Fixed</tt:ScopeDef>
+			<tt:ScopeItem>onvif://www.onvif.org/Profile/Streaming</tt:ScopeItem>
+		</tds:Scopes>
+		<tds:Scopes>
+			<tt:ScopeDef>Configurable</tt:ScopeDef>
+			<tt:ScopeItem>onvif://www.onvif.org/name/onviftest</tt:ScopeItem>
+		</tds:Scopes>
+	</tds:GetScopesResponse>`)
+
+	server.Handle("GetSystemDateAndTime", func(Request) (string, error) {
+		now := time.Now().UTC()
+		return `<tds:GetSystemDateAndTimeResponse>
+			<tds:SystemDateAndTime>
+				<tt:DateTimeType>Manual</tt:DateTimeType>
+				<tt:DaylightSavings>false</tt:DaylightSavings>
+				<tt:UTCDateTime>
+					<tt:Time><tt:Hour>` + strconv.Itoa(now.Hour()) + `</tt:Hour><tt:Minute>` + strconv.Itoa(now.Minute()) +
+			`</tt:Minute><tt:Second>` + strconv.Itoa(now.Second()) + `</tt:Second></tt:Time>
+					<tt:Date><tt:Year>` + strconv.Itoa(now.Year()) + `</tt:Year><tt:Month>` + strconv.Itoa(int(now.Month())) +
+			`</tt:Month><tt:Day>` + strconv.Itoa(now.Day()) + `</tt:Day></tt:Date>
+				</tt:UTCDateTime>
+			</tds:SystemDateAndTime>
+		</tds:GetSystemDateAndTimeResponse>`, nil
+	})
+
+	server.Handle("GetServices", func(Request) (string, error) {
+		return `<tds:GetServicesResponse>` +
+			serviceXML(Namespaces["tds"], server.URL+DevicePath) +
+			serviceXML(Namespaces["trt"], server.URL+MediaPath) +
+			serviceXML(Namespaces["tptz"], server.URL+PTZPath) +
+			serviceXML(Namespaces["tev"], server.URL+EventsPath) +
+			`</tds:GetServicesResponse>`, nil
+	})
+
+	server.Handle("GetCapabilities", func(Request) (string, error) {
+		return `<tds:GetCapabilitiesResponse>
+			<tds:Capabilities>
+				<tt:Device>
+					<tt:XAddr>` + server.URL + DevicePath + `</tt:XAddr>
+					<tt:Network>
+						<tt:IPFilter>false</tt:IPFilter>
+						<tt:ZeroConfiguration>false</tt:ZeroConfiguration>
+						<tt:IPVersion6>false</tt:IPVersion6>
+						<tt:DynDNS>false</tt:DynDNS>
+					</tt:Network>
+				</tt:Device>
+				<tt:Events>
+					<tt:XAddr>` + server.URL + EventsPath + `</tt:XAddr>
+					<tt:WSSubscriptionPolicySupport>false</tt:WSSubscriptionPolicySupport>
+					<tt:WSPullPointSupport>true</tt:WSPullPointSupport>
+					<tt:WSPausableSubscriptionManagerInterfaceSupport>false</tt:WSPausableSubscriptionManagerInterfaceSupport>
+				</tt:Events>
+				<tt:Media>
+					<tt:XAddr>` + server.URL + MediaPath + `</tt:XAddr>
+					<tt:StreamingCapabilities>
+						<tt:RTPMulticast>false</tt:RTPMulticast>
+						<tt:RTP_TCP>true</tt:RTP_TCP>
+						<tt:RTP_RTSP_TCP>true</tt:RTP_RTSP_TCP>
+					</tt:StreamingCapabilities>
+				</tt:Media>
+				<tt:PTZ>
+					<tt:XAddr>` + server.URL + PTZPath + `</tt:XAddr>
+				</tt:PTZ>
+			</tds:Capabilities>
+		</tds:GetCapabilitiesResponse>`, nil
+	})
+
+	server.HandleBody("GetProfiles", `<trt:GetProfilesResponse>
+		<trt:Profiles token="Profile_1" fixed="true">
+			<tt:Name>mainStream</tt:Name>
+			<tt:VideoSourceConfiguration token="VideoSourceConfig_1">
+				<tt:Name>VideoSourceConfig_1</tt:Name>
+				<tt:UseCount>1</tt:UseCount>
+				<tt:SourceToken>VideoSource_1</tt:SourceToken>
+				<tt:Bounds x="0" y="0" width="1920" height="1080"/>
+			</tt:VideoSourceConfiguration>
+			<tt:VideoEncoderConfiguration token="VideoEncoderConfig_1">
+				<tt:Name>VideoEncoderConfig_1</tt:Name>
+				<tt:UseCount>1</tt:UseCount>
+				<tt:Encoding>H264</tt:Encoding>
+				<tt:Resolution><tt:Width>1920</tt:Width><tt:Height>1080</tt:Height></tt:Resolution>
+				<tt:Quality>5</tt:Quality>
+				<tt:RateControl>
+					<tt:FrameRateLimit>25</tt:FrameRateLimit>
+					<tt:EncodingInterval>1</tt:EncodingInterval>
+					<tt:BitrateLimit>4096</tt:BitrateLimit>
+				</tt:RateControl>
+				<tt:SessionTimeout>PT60S</tt:SessionTimeout>
+			</tt:VideoEncoderConfiguration>
+			<tt:PTZConfiguration token="PTZConfig_1">
+				<tt:Name>PTZConfig_1</tt:Name>
+				<tt:UseCount>1</tt:UseCount>
+				<tt:NodeToken>PTZNode_1</tt:NodeToken>
+			</tt:PTZConfiguration>
+		</trt:Profiles>
+	</trt:GetProfilesResponse>`)
+
+	server.Handle("GetStreamUri", func(Request) (string, error) {
+		return `<trt:GetStreamUriResponse>
+			<trt:MediaUri>
+				<tt:Uri>rtsp://` + server.Listener.Addr().String() + `/stream1</tt:Uri>
+				<tt:InvalidAfterConnect>false</tt:InvalidAfterConnect>
+				<tt:InvalidAfterReboot>false</tt:InvalidAfterReboot>
+				<tt:Timeout>PT0S</tt:Timeout>
+			</trt:MediaUri>
+		</trt:GetStreamUriResponse>`, nil
+	})
+
+	server.Handle("GetSnapshotUri", func(Request) (string, error) {
+		return `<trt:GetSnapshotUriResponse>
+			<trt:MediaUri>
+				<tt:Uri>` + server.URL + SnapshotPath + `</tt:Uri>
+				<tt:InvalidAfterConnect>false</tt:InvalidAfterConnect>
+				<tt:InvalidAfterReboot>false</tt:InvalidAfterReboot>
+				<tt:Timeout>PT0S</tt:Timeout>
+			</trt:MediaUri>
+		</trt:GetSnapshotUriResponse>`, nil
+	})
+
+	server.HandleBody("ContinuousMove", `<tptz:ContinuousMoveResponse/>`)
+	server.HandleBody("Stop", `<tptz:StopResponse/>`)
+	server.HandleBody("GotoPreset", `<tptz:GotoPresetResponse/>`)
+	server.HandleBody("GetPresets", `<tptz:GetPresetsResponse>
+		<tptz:Preset token="Preset_1">
+			<tt:Name>Home</tt:Name>
+		</tptz:Preset>
+	</tptz:GetPresetsResponse>`)
+
+	server.Handle("CreatePullPointSubscription", func(Request) (string, error) {
+		now := time.Now().UTC()
+		return `<tev:CreatePullPointSubscriptionResponse>
+			<tev:SubscriptionReference>
+				<wsa:Address>` + server.URL + SubscriptionPath + `</wsa:Address>
+			</tev:SubscriptionReference>
+			<wsnt:CurrentTime>` + now.Format(time.RFC3339) + `</wsnt:CurrentTime>
+			<wsnt:TerminationTime>` + now.Add(time.Minute).Format(time.RFC3339) + `</wsnt:TerminationTime>
+		</tev:CreatePullPointSubscriptionResponse>`, nil
+	})
+
+	server.Handle("PullMessages", func(Request) (string, error) {
+		server.mutex.Lock()
+		events := server.events
+		server.events = nil
+		server.mutex.Unlock()
+
+		now := time.Now().UTC()
+		body := `<tev:PullMessagesResponse>
+			<tev:CurrentTime>` + now.Format(time.RFC3339) + `</tev:CurrentTime>
+			<tev:TerminationTime>` + now.Add(time.Minute).Format(time.RFC3339) + `</tev:TerminationTime>`
+		for _, event := range events {
+			body += eventXML(event)
+		}
+		return body + `</tev:PullMessagesResponse>`, nil
+	})
+
+	server.HandleBody("Renew", `<wsnt:RenewResponse/>`)
+	server.HandleBody("Unsubscribe", `<wsnt:UnsubscribeResponse/>`)
+}
+
+// serviceXML creates tds:Service element
+func serviceXML(namespace, xaddr string) string {
+	return `<tds:Service>
+		<tds:Namespace>` + namespace + `</tds:Namespace>
+		<tds:XAddr>` + xaddr + `</tds:XAddr>
+		<tds:Version><tt:Major>2</tt:Major><tt:Minor>60</tt:Minor></tds:Version>
+	</tds:Service>`
+}
+
+// eventXML creates wsnt:NotificationMessage element
+func eventXML(event Event) string {
+	utcTime := event.UtcTime
+	if utcTime.IsZero() {
+		utcTime = time.Now()
+	}
+
+	propertyOperation := ""
+	if event.PropertyOperation != "" {
+		propertyOperation = ` PropertyOperation="` + escape(event.PropertyOperation) + `"`
+	}
+
+	return `<wsnt:NotificationMessage>
+		<wsnt:Topic Dialect="http://www.onvif.org/ver10/tev/topicExpression/ConcreteSet">` + escape(event.Topic) + `</wsnt:Topic>
+		<wsnt:Message>
+			<tt:Message UtcTime="` + utcTime.UTC().Format(time.RFC3339Nano) + `"` + propertyOperation + `>
+				<tt:Source>` + simpleItemsXML(event.Source) + `</tt:Source>
+				<tt:Data>` + simpleItemsXML(event.Data) + `</tt:Data>
+			</tt:Message>
+		</wsnt:Message>
+	</wsnt:NotificationMessage>`
+}
+
+// simpleItemsXML creates tt:SimpleItem elements
+func simpleItemsXML(items []SimpleItem) string {
+	result := ""
+	for _, item := range items {
+		result += `<tt:SimpleItem Name="` + escape(item.Name) + `" Value="` + escape(item.Value) + `"/>`
+	}
+	return result
+}
+
+// escape escapes special characters of XML text
+func escape(text string) string {
+	builder := &strings.Builder{}
+	xml.EscapeText(builder, []byte(text))
+	return builder.String()
+}
@@ -0,0 +1,57 @@
+package onviftest
+
+import (
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Fault is SOAP fault returned by Server. Subcodes are nested, outermost first.
+type Fault struct {
+	Code     string
+	Subcodes []string
+	Reason   string
+}
+
+// SenderFault creates fault caused by the client, e.g. ter:InvalidArgVal
+func SenderFault(subcode string, reason string) *Fault {
+	return &Fault{Code: "s:Sender", Subcodes: strings.Split(subcode, "/"), Reason: reason}
+}
+
+// ReceiverFault creates fault caused by the server, e.g. ter:Action
+func ReceiverFault(subcode string, reason string) *Fault {
+	return &Fault{Code: "s:Receiver", Subcodes: strings.Split(subcode, "/"), Reason: reason}
+}
+
+// Error returns reason of the fault
+func (fault *Fault) Error() string {
+	return fault.Reason
+}
+
+// xml creates content of SOAP body that contains the fault
+func (fault *Fault) xml() string {
+	return `<s:Fault>
+		<s:Code>` + nestSubcodes(fault.Code, fault.Subcodes) + `</s:Code>
+		<s:Reason><s:Text xml:lang="en">` + escape(fault.Reason) + `</s:Text></s:Reason>
+	</s:Fault>`
+}
+
+// nestSubcodes creates content of fault code, where each subcode is nested in the previous one
+func nestSubcodes(code string, subcodes []string) string {
+	if len(subcodes) == 0 {
+		return `<s:Value>` + code + `</s:Value>`
+	}
+	return `<s:Value>` + code + `</s:Value><s:Subcode>` + nestSubcodes(subcodes[0], subcodes[1:]) + `</s:Subcode>`
+}
+
+// writeFault sends the fault with the status code used by ONVIF cameras
+func writeFault(w http.ResponseWriter, fault *Fault) {
+	status := http.StatusInternalServerError
+	if fault.Code == "s:Sender" {
+		status = http.StatusBadRequest
+	}
+
+	w.Header().Set("Content-Type", "application/soap+xml; charset=utf-8")
+	w.WriteHeader(status)
+	io.WriteString(w, createEnvelope(fault.xml()))
+}
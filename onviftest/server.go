@@ -0,0 +1,271 @@
+// Package onviftest provides an in-process ONVIF camera for testing, which
+// serves canned or programmable SOAP responses over HTTP. It covers device,
+// media, PTZ and events services by default, and any other operation can be
+// added with Handle.
+package onviftest
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/xml"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+)
+
+// Paths of the services served by Server
+const (
+	DevicePath       = "/onvif/device_service"
+	MediaPath        = "/onvif/media_service"
+	PTZPath          = "/onvif/ptz_service"
+	EventsPath       = "/onvif/events_service"
+	SubscriptionPath = "/onvif/subscription"
+	SnapshotPath     = "/snapshot.jpg"
+)
+
+// Namespaces declared in the envelope of each response, so handlers
+// can use these prefixes in the body they return
+var Namespaces = map[string]string{
+	"s":    "http://www.w3.org/2003/05/soap-envelope",
+	"tds":  "http://www.onvif.org/ver10/device/wsdl",
+	"trt":  "http://www.onvif.org/ver10/media/wsdl",
+	"tptz": "http://www.onvif.org/ver20/ptz/wsdl",
+	"tev":  "http://www.onvif.org/ver10/events/wsdl",
+	"tt":   "http://www.onvif.org/ver10/schema",
+	"ter":  "http://www.onvif.org/ver10/error",
+	"wsnt": "http://docs.oasis-open.org/wsn/b-2",
+	"wsa":  "http://www.w3.org/2005/08/addressing",
+	"tns1": "http://www.onvif.org/ver10/topics",
+}
+
+// Request contains a SOAP request received by Server
+type Request struct {
+	Path      string
+	Operation string
+	Envelope  string
+	Header    http.Header
+}
+
+// Handler responds to a SOAP request. It returns content of the response
+// body, e.g. <tds:GetHostnameResponse>...</tds:GetHostnameResponse>, or an
+// error. If the error is a *Fault, it's sent to the client as SOAP fault.
+type Handler func(req Request) (string, error)
+
+// Server is a mock ONVIF camera listening on a local address
+type Server struct {
+	*httptest.Server
+
+	mutex    sync.Mutex
+	handlers map[string]Handler
+	requests []Request
+	events   []Event
+	user     string
+	password string
+	snapshot []byte
+}
+
+// NewServer starts a mock ONVIF camera with the default handlers.
+// The caller should call Close when finished, to shut it down.
+func NewServer() *Server {
+	server := &Server{handlers: make(map[string]Handler), snapshot: defaultSnapshot}
+	server.Server = httptest.NewServer(http.HandlerFunc(server.serveHTTP))
+	server.registerDefaults()
+	return server
+}
+
+// XAddr returns address of the device service
+func (server *Server) XAddr() string {
+	return server.URL + DevicePath
+}
+
+// Handle registers handler of the operation, e.g. GetProfiles,
+// replacing the existing one
+func (server *Server) Handle(operation string, handler Handler) {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	server.handlers[operation] = handler
+}
+
+// HandleBody registers canned response body of the operation
+func (server *Server) HandleBody(operation string, body string) {
+	server.Handle(operation, func(Request) (string, error) {
+		return body, nil
+	})
+}
+
+// HandleFault makes the operation fails with the fault
+func (server *Server) HandleFault(operation string, fault *Fault) {
+	server.Handle(operation, func(Request) (string, error) {
+		return "", fault
+	})
+}
+
+// SetCredentials makes server requires WS-UsernameToken with password digest.
+// Requests without valid token fail with ter:NotAuthorized fault.
+// Empty user disables the authentication.
+func (server *Server) SetCredentials(user, password string) {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	server.user = user
+	server.password = password
+}
+
+// SetSnapshot sets image served on SnapshotPath
+func (server *Server) SetSnapshot(image []byte) {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	server.snapshot = image
+}
+
+// Requests returns requests received so far
+func (server *Server) Requests() []Request {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	return append([]Request(nil), server.requests...)
+}
+
+// LastRequest returns the last request of the operation, and
+// whether the operation has been requested at all
+func (server *Server) LastRequest(operation string) (Request, bool) {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	for i := len(server.requests) - 1; i >= 0; i-- {
+		if server.requests[i].Operation == operation {
+			return server.requests[i], true
+		}
+	}
+	return Request{}, false
+}
+
+func (server *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodGet && r.URL.Path == SnapshotPath {
+		server.mutex.Lock()
+		snapshot := server.snapshot
+		server.mutex.Unlock()
+
+		w.Header().Set("Content-Type", "image/jpeg")
+		w.Write(snapshot)
+		return
+	}
+
+	envelope, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	operation, username, err := parseEnvelope(envelope)
+	if err != nil {
+		writeFault(w, SenderFault("ter:WellFormed", err.Error()))
+		return
+	}
+
+	req := Request{
+		Path:      r.URL.Path,
+		Operation: operation,
+		Envelope:  string(envelope),
+		Header:    r.Header,
+	}
+
+	server.mutex.Lock()
+	server.requests = append(server.requests, req)
+	handler := server.handlers[operation]
+	user, password := server.user, server.password
+	server.mutex.Unlock()
+
+	if user != "" && !username.valid(user, password) {
+		writeFault(w, SenderFault("ter:NotAuthorized", "Sender not Authorized"))
+		return
+	}
+
+	if handler == nil {
+		writeFault(w, SenderFault("ter:ActionNotSupported", "Optional Action Not Implemented"))
+		return
+	}
+
+	body, err := handler(req)
+	if err != nil {
+		fault, ok := err.(*Fault)
+		if !ok {
+			fault = ReceiverFault("ter:Action", err.Error())
+		}
+		writeFault(w, fault)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/soap+xml; charset=utf-8")
+	io.WriteString(w, createEnvelope(body))
+}
+
+// createEnvelope wraps body in SOAP envelope
+func createEnvelope(body string) string {
+	buffer := bytes.NewBufferString(`<?xml version="1.0" encoding="UTF-8"?><s:Envelope`)
+	for _, prefix := range []string{"s", "tds", "trt", "tptz", "tev", "tt", "ter", "wsnt", "wsa", "tns1"} {
+		buffer.WriteString(` xmlns:` + prefix + `="` + Namespaces[prefix] + `"`)
+	}
+	buffer.WriteString(`><s:Body>` + body + `</s:Body></s:Envelope>`)
+	return buffer.String()
+}
+
+// usernameToken contains WS-UsernameToken of a request
+type usernameToken struct {
+	Username string
+	Password string
+	Nonce    string
+	Created  string
+}
+
+// valid reports whether the token contains the user and digest of the password
+func (token *usernameToken) valid(user, password string) bool {
+	if token == nil || token.Username != user {
+		return false
+	}
+
+	nonce, err := base64.StdEncoding.DecodeString(token.Nonce)
+	if err != nil {
+		return false
+	}
+
+	sha := sha1.New()
+	sha.Write(nonce)
+	sha.Write([]byte(token.Created + password))
+	digest := base64.StdEncoding.EncodeToString(sha.Sum(nil))
+	return digest == token.Password
+}
+
+// parseEnvelope returns name of the operation, i.e. the first element in
+// SOAP body, and the user token in SOAP header
+func parseEnvelope(envelope []byte) (string, *usernameToken, error) {
+	var token *usernameToken
+	decoder := xml.NewDecoder(bytes.NewReader(envelope))
+	inBody := false
+
+	for {
+		tok, err := decoder.Token()
+		if err != nil {
+			return "", nil, err
+		}
+
+		element, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+
+		switch {
+		case inBody:
+			return element.Name.Local, token, nil
+		case element.Name.Local == "Body":
+			inBody = true
+		case element.Name.Local == "UsernameToken":
+			token = &usernameToken{}
+			if err := decoder.DecodeElement(token, &element); err != nil {
+				return "", nil, err
+			}
+			token.Username = strings.TrimSpace(token.Username)
+		}
+	}
+}
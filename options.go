@@ -0,0 +1,197 @@
+package onvif
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const defaultDevicePath = "/onvif/device_service"
+
+// DeviceOption configures device created by NewDevice
+type DeviceOption func(*deviceOptions)
+
+// deviceOptions contains the device being configured and the options
+// that only affect how it's created
+type deviceOptions struct {
+	Device
+	skipProbe bool
+}
+
+// WithID sets ID of the device
+func WithID(id string) DeviceOption {
+	return func(options *deviceOptions) {
+		options.ID = id
+	}
+}
+
+// WithTimeout sets timeout of each request sent to the device
+func WithTimeout(timeout time.Duration) DeviceOption {
+	return func(options *deviceOptions) {
+		options.Timeout = timeout
+	}
+}
+
+// WithRetry sets retry policy of requests sent to the device
+func WithRetry(policy RetryPolicy) DeviceOption {
+	return func(options *deviceOptions) {
+		options.Retry = policy
+	}
+}
+
+// WithHTTPClient sets HTTP client used to send requests to the device
+func WithHTTPClient(client *http.Client) DeviceOption {
+	return func(options *deviceOptions) {
+		options.HTTPClient = client
+	}
+}
+
+// WithRootCAs sets certificate authorities used to verify certificate of the device
+func WithRootCAs(rootCAs *x509.CertPool) DeviceOption {
+	return func(options *deviceOptions) {
+		options.RootCAs = rootCAs
+	}
+}
+
+// WithClientCertificates sets certificates presented to the device
+// when it requires client authentication
+func WithClientCertificates(certificates ...tls.Certificate) DeviceOption {
+	return func(options *deviceOptions) {
+		options.Certificates = certificates
+	}
+}
+
+// WithInsecureSkipVerify disables verification of the device certificate
+func WithInsecureSkipVerify() DeviceOption {
+	return func(options *deviceOptions) {
+		options.InsecureSkipVerify = true
+	}
+}
+
+// WithCache caches responses that rarely change for the TTL, see ResponseCache
+func WithCache(ttl time.Duration) DeviceOption {
+	return func(options *deviceOptions) {
+		options.Cache = NewResponseCache(ttl)
+	}
+}
+
+// WithHook sets hook that's notified about each request sent to the device
+func WithHook(hook RequestHook) DeviceOption {
+	return func(options *deviceOptions) {
+		options.Hook = hook
+	}
+}
+
+// WithInstrumentation sets instrumentation that tracks each request sent to the device
+func WithInstrumentation(instrumentation Instrumentation) DeviceOption {
+	return func(options *deviceOptions) {
+		options.Instrumentation = instrumentation
+	}
+}
+
+// WithRequestLimit limits requests sent to the device to maxConcurrent in
+// flight, started at least interval apart, see RequestLimiter
+func WithRequestLimit(maxConcurrent int, interval time.Duration) DeviceOption {
+	return func(options *deviceOptions) {
+		options.Limiter = NewRequestLimiter(maxConcurrent, interval)
+	}
+}
+
+// WithoutProbe skips probing the device, so NewDevice doesn't send any request
+func WithoutProbe() DeviceOption {
+	return func(options *deviceOptions) {
+		options.skipProbe = true
+	}
+}
+
+// NewDevice creates device from its address, which might be a host, e.g.
+// 192.168.1.10:8080, or full XAddr of device service. Credentials embedded
+// in the address are used if user is empty. Unless WithoutProbe is used,
+// services of the device are fetched, so an unreachable device or wrong
+// credentials are reported early. The device keeps connections alive, so
+// successive requests, e.g. PTZ commands, don't need a new TCP or TLS handshake.
+func NewDevice(addr, user, password string, opts ...DeviceOption) (Device, error) {
+	xaddr, urlUser, urlPassword, err := normalizeXAddr(addr)
+	if err != nil {
+		return Device{}, err
+	}
+
+	if user == "" {
+		user, password = urlUser, urlPassword
+	}
+
+	options := deviceOptions{Device: Device{
+		XAddr:    xaddr,
+		User:     user,
+		Password: password,
+	}}
+
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	device := options.Device
+	if device.HTTPClient == nil {
+		device.transport = newTransport(device.tlsConfig())
+	}
+
+	if options.skipProbe {
+		return device, nil
+	}
+
+	if err := device.UpdateServices(); err != nil {
+		return Device{}, err
+	}
+
+	return device, nil
+}
+
+// normalizeXAddr converts address of device into XAddr of its device service,
+// and returns the credentials embedded in the address
+func normalizeXAddr(addr string) (string, string, string, error) {
+	addr = strings.TrimSpace(addr)
+	if addr == "" {
+		return "", "", "", errors.New("Device address is empty")
+	}
+
+	// IPv6 address without port must be enclosed in brackets,
+	// including the zone of link-local address, e.g. fe80::1%eth0
+	host, zone := strings.Trim(addr, "[]"), ""
+	if i := strings.LastIndex(host, "%"); i >= 0 && !strings.Contains(host[i:], "]") {
+		host, zone = host[:i], host[i:]
+	}
+	if ip := net.ParseIP(host); ip != nil && ip.To4() == nil {
+		addr = "[" + ip.String() + zone + "]"
+	}
+
+	if !strings.Contains(addr, "://") {
+		addr = "http://" + addr
+	}
+
+	urlXAddr, err := url.Parse(normalizeZone(addr))
+	if err != nil {
+		return "", "", "", err
+	}
+
+	if urlXAddr.Host == "" {
+		return "", "", "", errors.New("Device address has no host: " + addr)
+	}
+
+	if urlXAddr.Path == "" || urlXAddr.Path == "/" {
+		urlXAddr.Path = defaultDevicePath
+	}
+
+	user, password := "", ""
+	if urlXAddr.User != nil {
+		user = urlXAddr.User.Username()
+		password, _ = urlXAddr.User.Password()
+		urlXAddr.User = nil
+	}
+
+	return urlXAddr.String(), user, password, nil
+}
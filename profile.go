@@ -0,0 +1,143 @@
+package onvif
+
+// CreateProfile creates an empty media profile. Token is optional,
+// camera generates one if it's empty.
+func (device Device) CreateProfile(name, token string) (MediaProfile, error) {
+	// Create SOAP
+	body := `<trt:CreateProfile>
+		<trt:Name>` + escapeXML(name) + `</trt:Name>`
+	if token != "" {
+		body += `<trt:Token>` + escapeXML(token) + `</trt:Token>`
+	}
+	body += `</trt:CreateProfile>`
+
+	soap := SOAP{
+		Body:  body,
+		XMLNs: mediaXMLNs,
+	}
+
+	// Send SOAP request
+	response, err := device.sendRequest(mediaNamespace, soap)
+	if err != nil {
+		return MediaProfile{}, err
+	}
+
+	// Parse response to interface
+	ifaceProfile, err := response.ValueForPath("Envelope.Body.CreateProfileResponse.Profile")
+	if err != nil {
+		return MediaProfile{}, err
+	}
+
+	// Parse interface to struct
+	profile := MediaProfile{}
+	if mapProfile, ok := ifaceProfile.(map[string]interface{}); ok {
+		profile = parseMediaProfile(mapProfile)
+	}
+
+	return profile, nil
+}
+
+// DeleteProfile deletes a media profile that is not fixed
+func (device Device) DeleteProfile(profileToken string) error {
+	// Create SOAP
+	soap := SOAP{
+		XMLNs: mediaXMLNs,
+		Body: `<trt:DeleteProfile>
+			<trt:ProfileToken>` + escapeXML(profileToken) + `</trt:ProfileToken>
+		</trt:DeleteProfile>`,
+	}
+
+	// Send SOAP request
+	_, err := device.sendRequest(mediaNamespace, soap)
+	return err
+}
+
+// AddVideoSourceConfiguration adds a video source configuration to a media profile
+func (device Device) AddVideoSourceConfiguration(profileToken, configToken string) error {
+	return device.addConfiguration("VideoSource", profileToken, configToken)
+}
+
+// RemoveVideoSourceConfiguration removes video source configuration from a media profile
+func (device Device) RemoveVideoSourceConfiguration(profileToken string) error {
+	return device.removeConfiguration("VideoSource", profileToken)
+}
+
+// AddVideoEncoderConfiguration adds a video encoder configuration to a media profile
+func (device Device) AddVideoEncoderConfiguration(profileToken, configToken string) error {
+	return device.addConfiguration("VideoEncoder", profileToken, configToken)
+}
+
+// RemoveVideoEncoderConfiguration removes video encoder configuration from a media profile
+func (device Device) RemoveVideoEncoderConfiguration(profileToken string) error {
+	return device.removeConfiguration("VideoEncoder", profileToken)
+}
+
+// AddAudioSourceConfiguration adds an audio source configuration to a media profile
+func (device Device) AddAudioSourceConfiguration(profileToken, configToken string) error {
+	return device.addConfiguration("AudioSource", profileToken, configToken)
+}
+
+// RemoveAudioSourceConfiguration removes audio source configuration from a media profile
+func (device Device) RemoveAudioSourceConfiguration(profileToken string) error {
+	return device.removeConfiguration("AudioSource", profileToken)
+}
+
+// AddAudioEncoderConfiguration adds an audio encoder configuration to a media profile
+func (device Device) AddAudioEncoderConfiguration(profileToken, configToken string) error {
+	return device.addConfiguration("AudioEncoder", profileToken, configToken)
+}
+
+// RemoveAudioEncoderConfiguration removes audio encoder configuration from a media profile
+func (device Device) RemoveAudioEncoderConfiguration(profileToken string) error {
+	return device.removeConfiguration("AudioEncoder", profileToken)
+}
+
+// AddPTZConfiguration adds a PTZ configuration to a media profile
+func (device Device) AddPTZConfiguration(profileToken, configToken string) error {
+	return device.addConfiguration("PTZ", profileToken, configToken)
+}
+
+// RemovePTZConfiguration removes PTZ configuration from a media profile
+func (device Device) RemovePTZConfiguration(profileToken string) error {
+	return device.removeConfiguration("PTZ", profileToken)
+}
+
+// RemoveMetadataConfiguration removes metadata configuration from a media profile
+func (device Device) RemoveMetadataConfiguration(profileToken string) error {
+	return device.removeConfiguration("Metadata", profileToken)
+}
+
+// addConfiguration adds configuration of the kind, e.g. VideoSource, to a media profile
+func (device Device) addConfiguration(kind, profileToken, configToken string) error {
+	operation := "Add" + kind + "Configuration"
+
+	// Create SOAP
+	soap := SOAP{
+		XMLNs: mediaXMLNs,
+		Body: `<trt:` + operation + `>
+			<trt:ProfileToken>` + escapeXML(profileToken) + `</trt:ProfileToken>
+			<trt:ConfigurationToken>` + escapeXML(configToken) + `</trt:ConfigurationToken>
+		</trt:` + operation + `>`,
+	}
+
+	// Send SOAP request
+	_, err := device.sendRequest(mediaNamespace, soap)
+	return err
+}
+
+// removeConfiguration removes configuration of the kind, e.g. VideoSource, from a media profile
+func (device Device) removeConfiguration(kind, profileToken string) error {
+	operation := "Remove" + kind + "Configuration"
+
+	// Create SOAP
+	soap := SOAP{
+		XMLNs: mediaXMLNs,
+		Body: `<trt:` + operation + `>
+			<trt:ProfileToken>` + escapeXML(profileToken) + `</trt:ProfileToken>
+		</trt:` + operation + `>`,
+	}
+
+	// Send SOAP request
+	_, err := device.sendRequest(mediaNamespace, soap)
+	return err
+}
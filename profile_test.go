@@ -0,0 +1,49 @@
+package onvif
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/krabiswabbie/go-onvif/onviftest"
+)
+
+func TestProfileConfiguration(t *testing.T) {
+	server := onviftest.NewServer()
+	defer server.Close()
+
+	server.HandleBody("CreateProfile", `<trt:CreateProfileResponse>
+		<trt:Profile token="profile2" fixed="false"><tt:Name>LowBitrate</tt:Name></trt:Profile>
+	</trt:CreateProfileResponse>`)
+	server.HandleBody("AddVideoEncoderConfiguration", `<trt:AddVideoEncoderConfigurationResponse/>`)
+	server.HandleBody("RemovePTZConfiguration", `<trt:RemovePTZConfigurationResponse/>`)
+	server.HandleFault("DeleteProfile", onviftest.SenderFault("ter:Action/ter:DeletionOfFixedProfile", "Fixed profile"))
+
+	device := Device{XAddr: server.XAddr()}
+	profile, err := device.CreateProfile("LowBitrate", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if profile.Token != "profile2" || profile.Name != "LowBitrate" || profile.Fixed {
+		t.Errorf("unexpected profile %+v", profile)
+	}
+
+	if err := device.AddVideoEncoderConfiguration(profile.Token, "encoder1"); err != nil {
+		t.Fatal(err)
+	}
+
+	request, _ := server.LastRequest("AddVideoEncoderConfiguration")
+	if !strings.Contains(request.Envelope, "<trt:ConfigurationToken>encoder1</trt:ConfigurationToken>") {
+		t.Errorf("unexpected request %s", request.Envelope)
+	}
+
+	if err := device.RemovePTZConfiguration(profile.Token); err != nil {
+		t.Fatal(err)
+	}
+
+	var fault *SOAPFault
+	if err := device.DeleteProfile("profile0"); !errors.As(err, &fault) || fault.Subcode != "ter:DeletionOfFixedProfile" {
+		t.Errorf("unexpected error %v", err)
+	}
+}
@@ -0,0 +1,145 @@
+package onvif
+
+import (
+	"net"
+	"time"
+)
+
+// DefaultProvisionUsername is used by ProvisionDevice if username is not specified
+const DefaultProvisionUsername = "admin"
+
+// ProvisionDevice sets the initial configuration of factory-fresh camera, e.g.
+// right after it's discovered: the clock, password, hostname, NTP servers and
+// scopes, in that order. The clock is set first, since camera rejects requests
+// authenticated with the new password if its clock is far off, while it accepts
+// requests without authentication until then. Every configured step is attempted
+// even if the previous one fails, and the result of each is reported. The
+// returned device uses the new credentials once the password is set. Camera
+// without any user accepts requests without authentication, so device may have
+// no credentials.
+func ProvisionDevice(device Device, config ProvisionConfig) (Device, ProvisionReport) {
+	report := ProvisionReport{}
+	step := func(name string, run func() error) {
+		result := ProvisionStep{Name: name}
+		if err := run(); err != nil {
+			result.Error = err.Error()
+		}
+		report.Steps = append(report.Steps, result)
+	}
+
+	// Clock is set to the local clock, until it's synchronized by NTP
+	if config.TimeZone != "" || len(config.NTPServers) > 0 {
+		step("Date and time", func() error {
+			return device.SetSystemDateAndTime(SystemDateAndTime{
+				DateTimeType:    "Manual",
+				DaylightSavings: config.DaylightSavings,
+				TimeZone:        config.TimeZone,
+				UTCDateTime:     time.Now().UTC(),
+			})
+		})
+	}
+
+	if config.Password != "" {
+		step("Password", func() error {
+			return device.provisionPassword(config)
+		})
+	}
+
+	if config.Hostname != "" {
+		step("Hostname", func() error {
+			return device.SetHostname(config.Hostname)
+		})
+	}
+
+	if len(config.NTPServers) > 0 {
+		step("NTP", func() error {
+			servers := []NetworkHost{}
+			for _, server := range config.NTPServers {
+				servers = append(servers, ntpHost(server))
+			}
+			if err := device.SetNTP(false, servers); err != nil {
+				return err
+			}
+
+			return device.SetSystemDateAndTime(SystemDateAndTime{
+				DateTimeType:    "NTP",
+				DaylightSavings: config.DaylightSavings,
+				TimeZone:        config.TimeZone,
+			})
+		})
+	}
+
+	if len(config.Scopes) > 0 {
+		step("Scopes", func() error {
+			return device.SetScopes(config.Scopes)
+		})
+	}
+
+	return device, report
+}
+
+// Succeeded reports whether every step of provisioning succeeded
+func (report ProvisionReport) Succeeded() bool {
+	return len(report.Failed()) == 0
+}
+
+// Failed returns the steps of provisioning that failed
+func (report ProvisionReport) Failed() []ProvisionStep {
+	failed := []ProvisionStep{}
+	for _, step := range report.Steps {
+		if step.Error != "" {
+			failed = append(failed, step)
+		}
+	}
+
+	return failed
+}
+
+// provisionPassword sets password of the user, which is created as administrator
+// if it doesn't exist, then uses the new credentials for the next requests
+func (device *Device) provisionPassword(config ProvisionConfig) error {
+	username := config.Username
+	if username == "" {
+		username = DefaultProvisionUsername
+	}
+
+	users, err := device.GetUsers()
+	if err != nil {
+		return err
+	}
+
+	user := User{Username: username, Password: config.Password, UserLevel: "Administrator"}
+	exists := false
+	for _, existing := range users {
+		if existing.Username == username {
+			user.UserLevel = existing.UserLevel
+			exists = true
+		}
+	}
+
+	if exists {
+		err = device.SetUser([]User{user})
+	} else {
+		err = device.CreateUsers([]User{user})
+	}
+	if err != nil {
+		return err
+	}
+
+	device.User = username
+	device.Password = config.Password
+	return nil
+}
+
+// ntpHost converts host name or IP address to tt:NetworkHost
+func ntpHost(server string) NetworkHost {
+	ip := net.ParseIP(server)
+	switch {
+	case ip == nil:
+		return NetworkHost{Type: "DNS", DNSName: server}
+	case ip.To4() != nil:
+		return NetworkHost{Type: "IPv4", IPv4Address: server}
+	default:
+		return NetworkHost{Type: "IPv6", IPv6Address: server}
+	}
+}
@@ -0,0 +1,125 @@
+package onvif
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/krabiswabbie/go-onvif/onviftest"
+)
+
+func TestProvisionDevice(t *testing.T) {
+	server := onviftest.NewServer()
+	defer server.Close()
+
+	// Factory-fresh camera has no user, and requires authentication once the first user is created
+	server.HandleBody("GetUsers", `<tds:GetUsersResponse/>`)
+	server.Handle("CreateUsers", func(onviftest.Request) (string, error) {
+		server.SetCredentials("admin", "secret")
+		return `<tds:CreateUsersResponse/>`, nil
+	})
+	server.HandleBody("SetHostname", `<tds:SetHostnameResponse/>`)
+	server.HandleFault("SetNTP", onviftest.SenderFault("ter:InvalidArgVal/ter:InvalidDnsName", "Invalid NTP server"))
+	server.HandleBody("SetSystemDateAndTime", `<tds:SetSystemDateAndTimeResponse/>`)
+	server.HandleBody("SetScopes", `<tds:SetScopesResponse/>`)
+
+	device, report := ProvisionDevice(Device{XAddr: server.XAddr()}, ProvisionConfig{
+		Password:   "secret",
+		Hostname:   "camera-1",
+		NTPServers: []string{"pool.ntp.org", "192.168.1.1"},
+		TimeZone:   "UTC0",
+		Scopes:     []string{NameScope("camera-1")},
+	})
+
+	if device.User != "admin" || device.Password != "secret" {
+		t.Errorf("new credentials aren't used %s:%s", device.User, device.Password)
+	}
+
+	steps := []string{}
+	for _, step := range report.Steps {
+		steps = append(steps, step.Name)
+	}
+
+	if strings.Join(steps, ",") != "Date and time,Password,Hostname,NTP,Scopes" {
+		t.Errorf("unexpected steps %v", steps)
+	}
+
+	// Failed step doesn't stop the next ones
+	if failed := report.Failed(); report.Succeeded() || len(failed) != 1 || failed[0].Name != "NTP" {
+		t.Errorf("unexpected failed steps %+v", failed)
+	}
+
+	request, _ := server.LastRequest("CreateUsers")
+	if !strings.Contains(request.Envelope, "<tt:Username>admin</tt:Username>") || !strings.Contains(request.Envelope, "<tt:UserLevel>Administrator</tt:UserLevel>") {
+		t.Errorf("unexpected request %s", request.Envelope)
+	}
+
+	request, _ = server.LastRequest("SetNTP")
+	if !strings.Contains(request.Envelope, "<tt:DNSname>pool.ntp.org</tt:DNSname>") || !strings.Contains(request.Envelope, "<tt:IPv4Address>192.168.1.1</tt:IPv4Address>") {
+		t.Errorf("unexpected request %s", request.Envelope)
+	}
+
+	// Clock isn't synchronized by NTP if its servers aren't set
+	request, _ = server.LastRequest("SetSystemDateAndTime")
+	if !strings.Contains(request.Envelope, "<tds:DateTimeType>Manual</tds:DateTimeType>") || !strings.Contains(request.Envelope, "<tds:UTCDateTime>") {
+		t.Errorf("unexpected request %s", request.Envelope)
+	}
+
+	// Existing user keeps its level
+	server.HandleBody("GetUsers", `<tds:GetUsersResponse>
+		<tds:User><tt:Username>root</tt:Username><tt:UserLevel>Administrator</tt:UserLevel></tds:User>
+	</tds:GetUsersResponse>`)
+	server.HandleBody("SetUser", `<tds:SetUserResponse/>`)
+
+	device, report = ProvisionDevice(device, ProvisionConfig{Username: "root", Password: "secret"})
+	if !report.Succeeded() || len(report.Steps) != 1 {
+		t.Errorf("unexpected report %+v", report)
+	}
+
+	if _, ok := server.LastRequest("SetUser"); !ok {
+		t.Error("existing user isn't updated")
+	}
+}
+
+func TestProvisionDeviceClock(t *testing.T) {
+	server := onviftest.NewServer()
+	defer server.Close()
+
+	// Camera with clock far off rejects authenticated requests, unless the clock is set
+	clockSet := false
+	server.HandleBody("GetUsers", `<tds:GetUsersResponse/>`)
+	server.Handle("CreateUsers", func(onviftest.Request) (string, error) {
+		server.SetCredentials("admin", "secret")
+		return `<tds:CreateUsersResponse/>`, nil
+	})
+	server.Handle("SetSystemDateAndTime", func(request onviftest.Request) (string, error) {
+		clockSet = clockSet || strings.Contains(request.Envelope, "<tds:UTCDateTime>")
+		return `<tds:SetSystemDateAndTimeResponse/>`, nil
+	})
+
+	rejectSkewed := func(response string) onviftest.Handler {
+		return func(onviftest.Request) (string, error) {
+			if !clockSet {
+				return "", onviftest.SenderFault("ter:NotAuthorized", "Sender not authorized")
+			}
+			return response, nil
+		}
+	}
+	server.Handle("SetHostname", rejectSkewed(`<tds:SetHostnameResponse/>`))
+	server.Handle("SetNTP", rejectSkewed(`<tds:SetNTPResponse/>`))
+
+	_, report := ProvisionDevice(Device{XAddr: server.XAddr()}, ProvisionConfig{
+		Password:   "secret",
+		Hostname:   "camera-1",
+		NTPServers: []string{"pool.ntp.org"},
+	})
+
+	if !report.Succeeded() {
+		t.Errorf("unexpected failed steps %+v", report.Failed())
+	}
+
+	// Clock is synchronized by NTP once its servers are set
+	request, _ := server.LastRequest("SetSystemDateAndTime")
+	if !strings.Contains(request.Envelope, "<tds:DateTimeType>NTP</tds:DateTimeType>") || strings.Contains(request.Envelope, "UTCDateTime") {
+		t.Errorf("unexpected request %s", request.Envelope)
+	}
+}
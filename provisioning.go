@@ -0,0 +1,91 @@
+package onvif
+
+import "time"
+
+const provisioningNamespace = "http://www.onvif.org/ver10/provisioning/wsdl"
+
+var provisioningXMLNs = []string{
+	`xmlns:tpv="http://www.onvif.org/ver10/provisioning/wsdl"`,
+	`xmlns:tt="http://www.onvif.org/ver10/schema"`,
+}
+
+// Directions of provisioning movement
+const (
+	PanDirectionLeft              = "left"
+	PanDirectionRight             = "right"
+	TiltDirectionUp               = "up"
+	TiltDirectionDown             = "down"
+	ZoomDirectionWide             = "wide"
+	ZoomDirectionTelephoto        = "telephoto"
+	RollDirectionClockwise        = "clockwise"
+	RollDirectionCounterclockwise = "counterclockwise"
+	RollDirectionAuto             = "auto"
+	FocusDirectionNear            = "near"
+	FocusDirectionFar             = "far"
+)
+
+// PanMove moves the device on the pan axis, which is used to aim a fixed camera
+// during installation. Possible direction is left or right. The movement stops
+// after timeout, or uses default timeout of the device if timeout is zero.
+func (device Device) PanMove(videoSourceToken, direction string, timeout time.Duration) error {
+	return device.sendProvisioningMove("PanMove", videoSourceToken, direction, timeout)
+}
+
+// TiltMove moves the device on the tilt axis. Possible direction is up or down.
+func (device Device) TiltMove(videoSourceToken, direction string, timeout time.Duration) error {
+	return device.sendProvisioningMove("TiltMove", videoSourceToken, direction, timeout)
+}
+
+// ZoomMove moves the device on the zoom axis. Possible direction is wide or telephoto.
+func (device Device) ZoomMove(videoSourceToken, direction string, timeout time.Duration) error {
+	return device.sendProvisioningMove("ZoomMove", videoSourceToken, direction, timeout)
+}
+
+// RollMove moves the device on the roll axis.
+// Possible direction is clockwise, counterclockwise or auto.
+func (device Device) RollMove(videoSourceToken, direction string, timeout time.Duration) error {
+	return device.sendProvisioningMove("RollMove", videoSourceToken, direction, timeout)
+}
+
+// FocusMove moves the device on the focus axis. Possible direction is near or far.
+func (device Device) FocusMove(videoSourceToken, direction string, timeout time.Duration) error {
+	return device.sendProvisioningMove("FocusMove", videoSourceToken, direction, timeout)
+}
+
+// ProvisioningStop stops every provisioning movement of a video source
+func (device Device) ProvisioningStop(videoSourceToken string) error {
+	// Create SOAP
+	soap := SOAP{
+		XMLNs: provisioningXMLNs,
+		Body: `<tpv:Stop>
+			<tpv:VideoSource>` + escapeXML(videoSourceToken) + `</tpv:VideoSource>
+		</tpv:Stop>`,
+	}
+
+	// Send SOAP request
+	_, err := device.sendRequest(provisioningNamespace, soap)
+	return err
+}
+
+// sendProvisioningMove sends a provisioning movement of a video source
+func (device Device) sendProvisioningMove(operation, videoSourceToken, direction string, timeout time.Duration) error {
+	// Create SOAP
+	body := `<tpv:` + operation + `>
+		<tpv:VideoSource>` + escapeXML(videoSourceToken) + `</tpv:VideoSource>
+		<tpv:Direction>` + escapeXML(direction) + `</tpv:Direction>`
+
+	if timeout > 0 {
+		body += `<tpv:Timeout>` + formatDuration(timeout) + `</tpv:Timeout>`
+	}
+
+	body += `</tpv:` + operation + `>`
+
+	soap := SOAP{
+		Body:  body,
+		XMLNs: provisioningXMLNs,
+	}
+
+	// Send SOAP request
+	_, err := device.sendRequest(provisioningNamespace, soap)
+	return err
+}
@@ -0,0 +1,90 @@
+package onvif
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/krabiswabbie/go-onvif/onviftest"
+)
+
+func TestPanMove(t *testing.T) {
+	server := onviftest.NewServer()
+	defer server.Close()
+
+	server.HandleBody("PanMove", `<tpv:PanMoveResponse xmlns:tpv="http://www.onvif.org/ver10/provisioning/wsdl"/>`)
+
+	device := Device{XAddr: server.XAddr()}
+	if err := device.PanMove("source0", PanDirectionLeft, 2500*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+
+	request, _ := server.LastRequest("PanMove")
+	expected := "<tpv:PanMove><tpv:VideoSource>source0</tpv:VideoSource><tpv:Direction>left</tpv:Direction><tpv:Timeout>PT2.5S</tpv:Timeout></tpv:PanMove>"
+	if !strings.Contains(request.Envelope, expected) {
+		t.Errorf("unexpected request %s", request.Envelope)
+	}
+
+	// Default timeout of the device is used if timeout is zero
+	if err := device.PanMove("source0", PanDirectionRight, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	request, _ = server.LastRequest("PanMove")
+	expected = "<tpv:PanMove><tpv:VideoSource>source0</tpv:VideoSource><tpv:Direction>right</tpv:Direction></tpv:PanMove>"
+	if !strings.Contains(request.Envelope, expected) {
+		t.Errorf("unexpected request %s", request.Envelope)
+	}
+}
+
+func TestProvisioningMove(t *testing.T) {
+	server := onviftest.NewServer()
+	defer server.Close()
+
+	device := Device{XAddr: server.XAddr()}
+	moves := []struct {
+		operation string
+		direction string
+		move      func(string, string, time.Duration) error
+	}{
+		{"TiltMove", TiltDirectionUp, device.TiltMove},
+		{"ZoomMove", ZoomDirectionTelephoto, device.ZoomMove},
+		{"RollMove", RollDirectionCounterclockwise, device.RollMove},
+		{"FocusMove", FocusDirectionFar, device.FocusMove},
+	}
+
+	for _, move := range moves {
+		server.HandleBody(move.operation, `<tpv:`+move.operation+`Response xmlns:tpv="http://www.onvif.org/ver10/provisioning/wsdl"/>`)
+		if err := move.move("source0", move.direction, 0); err != nil {
+			t.Fatalf("%s: %v", move.operation, err)
+		}
+
+		request, ok := server.LastRequest(move.operation)
+		if !ok {
+			t.Fatalf("%s wasn't sent", move.operation)
+		}
+
+		expected := "<tpv:Direction>" + move.direction + "</tpv:Direction></tpv:" + move.operation + ">"
+		if !strings.Contains(request.Envelope, expected) {
+			t.Errorf("unexpected request %s", request.Envelope)
+		}
+	}
+}
+
+func TestProvisioningStop(t *testing.T) {
+	server := onviftest.NewServer()
+	defer server.Close()
+
+	server.HandleBody("Stop", `<tpv:StopResponse xmlns:tpv="http://www.onvif.org/ver10/provisioning/wsdl"/>`)
+
+	device := Device{XAddr: server.XAddr()}
+	if err := device.ProvisioningStop("source0"); err != nil {
+		t.Fatal(err)
+	}
+
+	request, _ := server.LastRequest("Stop")
+	expected := "<tpv:Stop><tpv:VideoSource>source0</tpv:VideoSource></tpv:Stop>"
+	if !strings.Contains(request.Envelope, expected) {
+		t.Errorf("unexpected request %s", request.Envelope)
+	}
+}
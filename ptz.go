@@ -0,0 +1,379 @@
+package onvif
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"time"
+)
+
+const ptzNamespace = "http://www.onvif.org/ver20/ptz/wsdl"
+
+var ptzXMLNs = []string{
+	`xmlns:tptz="http://www.onvif.org/ver20/ptz/wsdl"`,
+	`xmlns:tt="http://www.onvif.org/ver10/schema"`,
+}
+
+var errPTZOutOfRange = errors.New("PTZ value must be a number between -1 and 1")
+
+// GetPresets fetch PTZ presets of a media profile
+func (device Device) GetPresets(profileToken string) ([]PTZPreset, error) {
+	// Create SOAP
+	soap := SOAP{
+		XMLNs: ptzXMLNs,
+		Body: `<tptz:GetPresets>
+			<tptz:ProfileToken>` + escapeXML(profileToken) + `</tptz:ProfileToken>
+		</tptz:GetPresets>`,
+	}
+
+	// Send SOAP request
+	response, err := device.sendRequest(ptzNamespace, soap)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse response to interface
+	ifacePresets, err := response.ValuesForPath("Envelope.Body.GetPresetsResponse.Preset")
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert interface to array of preset
+	presets := []PTZPreset{}
+	for _, ifacePreset := range ifacePresets {
+		if mapPreset, ok := ifacePreset.(map[string]interface{}); ok {
+			presets = append(presets, PTZPreset{
+				Token: interfaceToString(mapPreset["-token"]),
+				Name:  interfaceToString(mapPreset["Name"]),
+			})
+		}
+	}
+
+	return presets, nil
+}
+
+// GotoPreset moves PTZ camera to a saved preset
+func (device Device) GotoPreset(profileToken, presetToken string) error {
+	// Create SOAP
+	soap := SOAP{
+		XMLNs: ptzXMLNs,
+		Body: `<tptz:GotoPreset>
+			<tptz:ProfileToken>` + escapeXML(profileToken) + `</tptz:ProfileToken>
+			<tptz:PresetToken>` + escapeXML(presetToken) + `</tptz:PresetToken>
+		</tptz:GotoPreset>`,
+	}
+
+	// Send SOAP request
+	_, err := device.sendRequest(ptzNamespace, soap)
+	return err
+}
+
+// SetPreset saves current position of PTZ camera as a preset and returns its token.
+// If preset token is empty, a new preset is created, otherwise the existing one is overwritten.
+func (device Device) SetPreset(profileToken, presetName, presetToken string) (string, error) {
+	// Create SOAP
+	body := `<tptz:SetPreset>
+		<tptz:ProfileToken>` + escapeXML(profileToken) + `</tptz:ProfileToken>`
+	if presetName != "" {
+		body += `<tptz:PresetName>` + escapeXML(presetName) + `</tptz:PresetName>`
+	}
+	if presetToken != "" {
+		body += `<tptz:PresetToken>` + escapeXML(presetToken) + `</tptz:PresetToken>`
+	}
+	body += `</tptz:SetPreset>`
+
+	soap := SOAP{
+		XMLNs: ptzXMLNs,
+		Body:  body,
+	}
+
+	// Send SOAP request
+	response, err := device.sendRequest(ptzNamespace, soap)
+	if err != nil {
+		return "", err
+	}
+
+	// Parse response
+	token, _ := response.ValueForPathString("Envelope.Body.SetPresetResponse.PresetToken")
+	return token, nil
+}
+
+// RemovePreset removes a saved preset of PTZ camera
+func (device Device) RemovePreset(profileToken, presetToken string) error {
+	// Create SOAP
+	soap := SOAP{
+		XMLNs: ptzXMLNs,
+		Body: `<tptz:RemovePreset>
+			<tptz:ProfileToken>` + escapeXML(profileToken) + `</tptz:ProfileToken>
+			<tptz:PresetToken>` + escapeXML(presetToken) + `</tptz:PresetToken>
+		</tptz:RemovePreset>`,
+	}
+
+	// Send SOAP request
+	_, err := device.sendRequest(ptzNamespace, soap)
+	return err
+}
+
+// Ptz moves PTZ camera continuously with the velocity x, y and z,
+// which are numbers between -1 and 1.
+//
+// Deprecated: use ContinuousMove, which takes float numbers.
+func (device Device) Ptz(profileToken, x, y, z string) error {
+	velocity := PTZVector{}
+	values := []string{x, y, z}
+	for i, number := range []*float64{&velocity.Pan, &velocity.Tilt, &velocity.Zoom} {
+		var err error
+		if *number, err = strconv.ParseFloat(values[i], 64); err != nil {
+			return errPTZOutOfRange
+		}
+	}
+
+	return device.ContinuousMove(profileToken, velocity)
+}
+
+// PtzStop stops all movements of PTZ camera. Velocity x, y and z are ignored.
+//
+// Deprecated: use StopPTZ.
+func (device Device) PtzStop(profileToken, x, y, z string) error {
+	return device.StopPTZ(profileToken, true, true)
+}
+
+// ContinuousMove moves PTZ camera continuously with the velocity until it's stopped
+func (device Device) ContinuousMove(profileToken string, velocity PTZVector) error {
+	return device.continuousMove(profileToken, velocity, 0)
+}
+
+// PtzMoveFor moves PTZ camera continuously with the velocity for the duration.
+// Camera stops by itself once the duration elapses, so it doesn't keep moving
+// if the caller dies before sending a stop request.
+func (device Device) PtzMoveFor(profileToken string, velocity PTZVector, duration time.Duration) error {
+	return device.continuousMove(profileToken, velocity, duration)
+}
+
+// continuousMove sends ContinuousMove with the velocity.
+// Timeout is only sent if it's not zero.
+func (device Device) continuousMove(profileToken string, velocity PTZVector, timeout time.Duration) error {
+	velocityXML, err := ptzVectorXML(velocity, false)
+	if err != nil {
+		return err
+	}
+
+	// Create SOAP
+	body := `<tptz:ContinuousMove>
+		<tptz:ProfileToken>` + escapeXML(profileToken) + `</tptz:ProfileToken>
+		<tptz:Velocity>` + velocityXML + `</tptz:Velocity>`
+	if timeout > 0 {
+		body += `<tptz:Timeout>` + formatDuration(timeout) + `</tptz:Timeout>`
+	}
+	body += `</tptz:ContinuousMove>`
+
+	soap := SOAP{
+		XMLNs: ptzXMLNs,
+		Body:  body,
+	}
+
+	// Send SOAP request
+	_, err = device.sendRequest(ptzNamespace, soap)
+	return err
+}
+
+// AbsoluteMove moves PTZ camera to the position
+func (device Device) AbsoluteMove(profileToken string, position PTZVector) error {
+	return device.moveTo("AbsoluteMove", "Position", profileToken, position)
+}
+
+// RelativeMove moves PTZ camera by the translation from its current position
+func (device Device) RelativeMove(profileToken string, translation PTZVector) error {
+	return device.moveTo("RelativeMove", "Translation", profileToken, translation)
+}
+
+// moveTo sends AbsoluteMove or RelativeMove with the vector in the element
+func (device Device) moveTo(operation, element, profileToken string, vector PTZVector) error {
+	vectorXML, err := ptzVectorXML(vector, operation == "AbsoluteMove")
+	if err != nil {
+		return err
+	}
+
+	// Create SOAP
+	soap := SOAP{
+		XMLNs: ptzXMLNs,
+		Body: `<tptz:` + operation + `>
+			<tptz:ProfileToken>` + escapeXML(profileToken) + `</tptz:ProfileToken>
+			<tptz:` + element + `>` + vectorXML + `</tptz:` + element + `>
+		</tptz:` + operation + `>`,
+	}
+
+	// Send SOAP request
+	_, err = device.sendRequest(ptzNamespace, soap)
+	return err
+}
+
+// StopPTZ stops ongoing pan and tilt movements, zoom movement, or both
+func (device Device) StopPTZ(profileToken string, panTilt, zoom bool) error {
+	// Create SOAP
+	soap := SOAP{
+		XMLNs: ptzXMLNs,
+		Body: `<tptz:Stop>
+			<tptz:ProfileToken>` + escapeXML(profileToken) + `</tptz:ProfileToken>
+			<tptz:PanTilt>` + strconv.FormatBool(panTilt) + `</tptz:PanTilt>
+			<tptz:Zoom>` + strconv.FormatBool(zoom) + `</tptz:Zoom>
+		</tptz:Stop>`,
+	}
+
+	// Send SOAP request
+	_, err := device.sendRequest(ptzNamespace, soap)
+	return err
+}
+
+// ptzVectorXML creates content of tt:PTZSpeed or tt:PTZVector. Values in default
+// space must be between -1 and 1, or 0 and 1 for absolute zoom, while values in
+// other space are only checked to be finite, since their range depends on the space.
+func ptzVectorXML(vector PTZVector, absolute bool) (string, error) {
+	for _, value := range []float64{vector.Pan, vector.Tilt, vector.Zoom} {
+		if math.IsNaN(value) || math.IsInf(value, 0) {
+			return "", errPTZOutOfRange
+		}
+	}
+
+	if vector.PanTiltSpace == "" && (math.Abs(vector.Pan) > 1 || math.Abs(vector.Tilt) > 1) {
+		return "", errPTZOutOfRange
+	}
+
+	if vector.ZoomSpace == "" && (math.Abs(vector.Zoom) > 1 || absolute && vector.Zoom < 0) {
+		return "", errPTZOutOfRange
+	}
+
+	// Axes that aren't given are left out, so they aren't moved
+	result := ""
+	if vector.IncludePanTilt || vector.Pan != 0 || vector.Tilt != 0 || vector.PanTiltSpace != "" {
+		result += `<tt:PanTilt x="` + formatFloat(vector.Pan) + `" y="` + formatFloat(vector.Tilt) + `"` +
+			spaceAttribute(vector.PanTiltSpace) + `/>`
+	}
+
+	if vector.IncludeZoom || vector.Zoom != 0 || vector.ZoomSpace != "" {
+		result += `<tt:Zoom x="` + formatFloat(vector.Zoom) + `"` + spaceAttribute(vector.ZoomSpace) + `/>`
+	}
+
+	return result, nil
+}
+
+// spaceAttribute creates space attribute of PTZ vector, if the space is specified
+func spaceAttribute(space string) string {
+	if space == "" {
+		return ""
+	}
+
+	return ` space="` + escapeXML(space) + `"`
+}
+
+// GetNodes fetch PTZ nodes of camera, which describe the supported coordinate
+// spaces, number of presets, home position and auxiliary commands
+func (device Device) GetNodes() ([]PTZNode, error) {
+	// Create SOAP
+	soap := SOAP{
+		XMLNs: ptzXMLNs,
+		Body:  `<tptz:GetNodes/>`,
+	}
+
+	// Send SOAP request
+	response, err := device.sendRequest(ptzNamespace, soap)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse response to interface
+	ifaceNodes, err := response.ValuesForPath("Envelope.Body.GetNodesResponse.PTZNode")
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert interface to array of PTZ node
+	nodes := []PTZNode{}
+	for _, ifaceNode := range ifaceNodes {
+		if mapNode, ok := ifaceNode.(map[string]interface{}); ok {
+			nodes = append(nodes, parsePTZNode(mapNode))
+		}
+	}
+
+	return nodes, nil
+}
+
+// GetNode fetch a PTZ node of camera
+func (device Device) GetNode(nodeToken string) (PTZNode, error) {
+	// Create SOAP
+	soap := SOAP{
+		XMLNs: ptzXMLNs,
+		Body: `<tptz:GetNode>
+			<tptz:NodeToken>` + escapeXML(nodeToken) + `</tptz:NodeToken>
+		</tptz:GetNode>`,
+	}
+
+	// Send SOAP request
+	response, err := device.sendRequest(ptzNamespace, soap)
+	if err != nil {
+		return PTZNode{}, err
+	}
+
+	// Parse response to interface
+	ifaceNode, err := response.ValueForPath("Envelope.Body.GetNodeResponse.PTZNode")
+	if err != nil {
+		return PTZNode{}, err
+	}
+
+	// Parse interface to struct
+	mapNode, _ := ifaceNode.(map[string]interface{})
+	return parsePTZNode(mapNode), nil
+}
+
+// parsePTZNode parses tt:PTZNode
+func parsePTZNode(mapNode map[string]interface{}) PTZNode {
+	node := PTZNode{
+		Token:                  interfaceToString(mapNode["-token"]),
+		Name:                   interfaceToString(mapNode["Name"]),
+		FixedHomePosition:      interfaceToBool(mapNode["-FixedHomePosition"]),
+		GeoMove:                interfaceToBool(mapNode["-GeoMove"]),
+		MaximumNumberOfPresets: interfaceToInt(mapNode["MaximumNumberOfPresets"]),
+		HomeSupported:          interfaceToBool(mapNode["HomeSupported"]),
+		AuxiliaryCommands:      interfaceToStrings(mapNode["AuxiliaryCommands"]),
+	}
+
+	if mapSpaces, ok := mapNode["SupportedPTZSpaces"].(map[string]interface{}); ok {
+		node.SupportedSpaces = PTZSpaces{
+			AbsolutePanTiltPosition:    parsePTZSpaces(mapSpaces["AbsolutePanTiltPositionSpace"]),
+			AbsoluteZoomPosition:       parsePTZSpaces(mapSpaces["AbsoluteZoomPositionSpace"]),
+			RelativePanTiltTranslation: parsePTZSpaces(mapSpaces["RelativePanTiltTranslationSpace"]),
+			RelativeZoomTranslation:    parsePTZSpaces(mapSpaces["RelativeZoomTranslationSpace"]),
+			ContinuousPanTiltVelocity:  parsePTZSpaces(mapSpaces["ContinuousPanTiltVelocitySpace"]),
+			ContinuousZoomVelocity:     parsePTZSpaces(mapSpaces["ContinuousZoomVelocitySpace"]),
+			PanTiltSpeed:               parsePTZSpaces(mapSpaces["PanTiltSpeedSpace"]),
+			ZoomSpeed:                  parsePTZSpaces(mapSpaces["ZoomSpeedSpace"]),
+		}
+	}
+
+	return node
+}
+
+// parsePTZSpaces parses tt:Space2DDescription or tt:Space1DDescription elements
+func parsePTZSpaces(src interface{}) []PTZSpace {
+	spaces := []PTZSpace{}
+	for _, mapSpace := range interfaceToMaps(src) {
+		spaces = append(spaces, PTZSpace{
+			URI:    interfaceToString(mapSpace["URI"]),
+			XRange: parseFloatRange(mapSpace["XRange"]),
+			YRange: parseFloatRange(mapSpace["YRange"]),
+		})
+	}
+
+	return spaces
+}
+
+// parseFloatRange parses tt:FloatRange
+func parseFloatRange(src interface{}) FloatRange {
+	floatRange := FloatRange{}
+	if mapRange, ok := src.(map[string]interface{}); ok {
+		floatRange.Min = interfaceToFloat(mapRange["Min"])
+		floatRange.Max = interfaceToFloat(mapRange["Max"])
+	}
+
+	return floatRange
+}
@@ -0,0 +1,180 @@
+package onvif
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/krabiswabbie/go-onvif/onviftest"
+)
+
+func TestGetPresets(t *testing.T) {
+	log.Println("Test GetPresets")
+
+	res, err := testDevice.GetPresets("IPCProfilesToken0")
+	if err != nil {
+		t.Error(err)
+	}
+
+	js := prettyJSON(&res)
+	fmt.Println(js)
+}
+
+func TestPtzMoveFor(t *testing.T) {
+	server := onviftest.NewServer()
+	defer server.Close()
+
+	device := Device{XAddr: server.XAddr()}
+	err := device.PtzMoveFor("profile0", PTZVector{Pan: 0.5, Tilt: -0.25}, 1500*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	request, _ := server.LastRequest("ContinuousMove")
+	for _, element := range []string{
+		`<tt:PanTilt x="0.5" y="-0.25"/>`,
+		`<tptz:Timeout>PT1.5S</tptz:Timeout>`,
+	} {
+		if !strings.Contains(request.Envelope, element) {
+			t.Errorf("request doesn't contain %s", element)
+		}
+	}
+}
+
+func TestPTZVectorXML(t *testing.T) {
+	tests := []struct {
+		vector   PTZVector
+		absolute bool
+		expected string
+	}{
+		{PTZVector{Pan: 1, Tilt: -0.5}, false, `<tt:PanTilt x="1" y="-0.5"/>`},
+		{PTZVector{Pan: 90, PanTiltSpace: "http://www.onvif.org/ver10/tptz/PanTiltSpaces/SphericalPositionSpaceDegrees"}, false,
+			`<tt:PanTilt x="90" y="0" space="http://www.onvif.org/ver10/tptz/PanTiltSpaces/SphericalPositionSpaceDegrees"/>`},
+		{PTZVector{Pan: 1.5}, false, ""},
+		{PTZVector{Zoom: math.NaN(), ZoomSpace: "http://example.com/space"}, false, ""},
+		{PTZVector{Zoom: -0.5}, false, `<tt:Zoom x="-0.5"/>`},
+		{PTZVector{Zoom: -0.5}, true, ""},
+		{PTZVector{IncludeZoom: true}, true, `<tt:Zoom x="0"/>`},
+	}
+
+	for _, test := range tests {
+		result, err := ptzVectorXML(test.vector, test.absolute)
+		if test.expected == "" {
+			if err == nil {
+				t.Errorf("expected error for %+v", test.vector)
+			}
+			continue
+		}
+
+		if err != nil || result != test.expected {
+			t.Errorf("expected %s, got %s (%v)", test.expected, result, err)
+		}
+	}
+
+	if err := (Device{}).Ptz("profile0", "0.1", `0" injected="`, "0"); err != errPTZOutOfRange {
+		t.Errorf("expected invalid velocity to be rejected, got %v", err)
+	}
+}
+
+func TestAbsoluteMovePanTilt(t *testing.T) {
+	server := onviftest.NewServer()
+	defer server.Close()
+
+	server.HandleBody("AbsoluteMove", `<tptz:AbsoluteMoveResponse/>`)
+
+	// Zoom isn't moved if it's not given
+	device := Device{XAddr: server.XAddr()}
+	if err := device.AbsoluteMove("profile0", PTZVector{Pan: 0.5, Tilt: 0.2}); err != nil {
+		t.Fatal(err)
+	}
+
+	request, _ := server.LastRequest("AbsoluteMove")
+	if !strings.Contains(request.Envelope, `<tptz:Position><tt:PanTilt x="0.5" y="0.2"/></tptz:Position>`) {
+		t.Errorf("unexpected request %s", request.Envelope)
+	}
+}
+
+func TestGetNodes(t *testing.T) {
+	log.Println("Test GetNodes")
+
+	res, err := testDevice.GetNodes()
+	if err != nil {
+		t.Error(err)
+	}
+
+	js := prettyJSON(&res)
+	fmt.Println(js)
+}
+
+func TestParsePTZNode(t *testing.T) {
+	server := onviftest.NewServer()
+	defer server.Close()
+
+	node := `<tptz:PTZNode token="PTZNODE_1" FixedHomePosition="false" GeoMove="true">
+		<tt:Name>PTZ</tt:Name>
+		<tt:SupportedPTZSpaces>
+			<tt:AbsolutePanTiltPositionSpace>
+				<tt:URI>http://www.onvif.org/ver10/tptz/PanTiltSpaces/PositionGenericSpace</tt:URI>
+				<tt:XRange><tt:Min>-1</tt:Min><tt:Max>1</tt:Max></tt:XRange>
+				<tt:YRange><tt:Min>-1</tt:Min><tt:Max>1</tt:Max></tt:YRange>
+			</tt:AbsolutePanTiltPositionSpace>
+			<tt:AbsolutePanTiltPositionSpace>
+				<tt:URI>http://www.onvif.org/ver10/tptz/PanTiltSpaces/SphericalPositionSpaceDegrees</tt:URI>
+				<tt:XRange><tt:Min>-180</tt:Min><tt:Max>180</tt:Max></tt:XRange>
+				<tt:YRange><tt:Min>-90</tt:Min><tt:Max>0</tt:Max></tt:YRange>
+			</tt:AbsolutePanTiltPositionSpace>
+			<tt:ZoomSpeedSpace>
+				<tt:URI>http://www.onvif.org/ver10/tptz/ZoomSpaces/ZoomGenericSpeedSpace</tt:URI>
+				<tt:XRange><tt:Min>0</tt:Min><tt:Max>1</tt:Max></tt:XRange>
+			</tt:ZoomSpeedSpace>
+		</tt:SupportedPTZSpaces>
+		<tt:MaximumNumberOfPresets>255</tt:MaximumNumberOfPresets>
+		<tt:HomeSupported>true</tt:HomeSupported>
+		<tt:AuxiliaryCommands>tt:Wiper|On</tt:AuxiliaryCommands>
+		<tt:AuxiliaryCommands>tt:Wiper|Off</tt:AuxiliaryCommands>
+	</tptz:PTZNode>`
+	server.HandleBody("GetNodes", `<tptz:GetNodesResponse>`+node+`</tptz:GetNodesResponse>`)
+	server.HandleBody("GetNode", `<tptz:GetNodeResponse>`+node+`</tptz:GetNodeResponse>`)
+
+	device := Device{XAddr: server.XAddr()}
+	nodes, err := device.GetNodes()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(nodes) != 1 {
+		t.Fatalf("expected 1 node, got %d", len(nodes))
+	}
+
+	result := nodes[0]
+	if result.Token != "PTZNODE_1" || !result.GeoMove || result.FixedHomePosition || !result.HomeSupported ||
+		result.MaximumNumberOfPresets != 255 || len(result.AuxiliaryCommands) != 2 {
+		t.Errorf("unexpected node %+v", result)
+	}
+
+	spaces := result.SupportedSpaces.AbsolutePanTiltPosition
+	if len(spaces) != 2 || spaces[1].XRange.Min != -180 || spaces[1].YRange.Max != 0 {
+		t.Errorf("unexpected absolute pan/tilt spaces %+v", spaces)
+	}
+
+	if zoom := result.SupportedSpaces.ZoomSpeed; len(zoom) != 1 || zoom[0].XRange.Max != 1 {
+		t.Errorf("unexpected zoom speed spaces %+v", zoom)
+	}
+
+	result, err = device.GetNode("PTZNODE_1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Name != "PTZ" {
+		t.Errorf("unexpected node %+v", result)
+	}
+
+	request, _ := server.LastRequest("GetNode")
+	if !strings.Contains(request.Envelope, "<tptz:NodeToken>PTZNODE_1</tptz:NodeToken>") {
+		t.Errorf("unexpected request %s", request.Envelope)
+	}
+}
@@ -0,0 +1,95 @@
+package onvif
+
+import (
+	"errors"
+	"time"
+)
+
+// DefaultPTZSpeed is the speed used by PTZ controller if none is specified
+const DefaultPTZSpeed = 0.1
+
+var errNoMediaProfile = errors.New("Camera doesn't have any media profile")
+
+// PTZController moves PTZ camera in common directions, e.g. for joystick
+// or arrow buttons in user interface.
+type PTZController struct {
+	Device       Device
+	ProfileToken string
+
+	// Speed is the normalized velocity of each move, between 0 and 1
+	Speed float64
+
+	// Duration, if it's not zero, limits each move, so camera stops by itself
+	// even if Stop is never called
+	Duration time.Duration
+}
+
+// NewPTZController creates PTZ controller for the media profile of the device.
+// If profile token is empty, the first profile with PTZ configuration is used.
+func NewPTZController(device Device, profileToken string) (*PTZController, error) {
+	if profileToken == "" {
+		profiles, err := device.GetProfiles()
+		if err != nil {
+			return nil, err
+		}
+
+		if len(profiles) == 0 {
+			return nil, errNoMediaProfile
+		}
+
+		profileToken = profiles[0].Token
+		for _, profile := range profiles {
+			if profile.PTZConfig.Token != "" {
+				profileToken = profile.Token
+				break
+			}
+		}
+	}
+
+	return &PTZController{
+		Device:       device,
+		ProfileToken: profileToken,
+		Speed:        DefaultPTZSpeed,
+	}, nil
+}
+
+// Up tilts camera up
+func (controller *PTZController) Up() error {
+	return controller.Move(PTZVector{Tilt: controller.Speed})
+}
+
+// Down tilts camera down
+func (controller *PTZController) Down() error {
+	return controller.Move(PTZVector{Tilt: -controller.Speed})
+}
+
+// Left pans camera to the left
+func (controller *PTZController) Left() error {
+	return controller.Move(PTZVector{Pan: -controller.Speed})
+}
+
+// Right pans camera to the right
+func (controller *PTZController) Right() error {
+	return controller.Move(PTZVector{Pan: controller.Speed})
+}
+
+// ZoomIn zooms camera in
+func (controller *PTZController) ZoomIn() error {
+	return controller.Move(PTZVector{Zoom: controller.Speed})
+}
+
+// ZoomOut zooms camera out
+func (controller *PTZController) ZoomOut() error {
+	return controller.Move(PTZVector{Zoom: -controller.Speed})
+}
+
+// Move moves camera continuously with the velocity until Stop is called,
+// or until the duration of controller elapses
+func (controller *PTZController) Move(velocity PTZVector) error {
+	return controller.Device.continuousMove(controller.ProfileToken, velocity, controller.Duration)
+}
+
+// Stop stops all movements of camera
+func (controller *PTZController) Stop() error {
+	return controller.Device.StopPTZ(controller.ProfileToken, true, true)
+}
@@ -0,0 +1,41 @@
+package onvif
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/krabiswabbie/go-onvif/onviftest"
+)
+
+func TestPTZController(t *testing.T) {
+	server := onviftest.NewServer()
+	defer server.Close()
+
+	controller, err := NewPTZController(Device{XAddr: server.XAddr()}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if controller.ProfileToken != "Profile_1" {
+		t.Errorf("unexpected profile %s", controller.ProfileToken)
+	}
+
+	controller.Speed = 0.5
+	if err := controller.ZoomOut(); err != nil {
+		t.Fatal(err)
+	}
+
+	request, _ := server.LastRequest("ContinuousMove")
+	if !strings.Contains(request.Envelope, `<tt:Zoom x="-0.5"/>`) || strings.Contains(request.Envelope, "Timeout") {
+		t.Errorf("unexpected request %s", request.Envelope)
+	}
+
+	if err := controller.Stop(); err != nil {
+		t.Fatal(err)
+	}
+
+	request, _ = server.LastRequest("Stop")
+	if !strings.Contains(request.Envelope, "<tptz:PanTilt>true</tptz:PanTilt>") {
+		t.Errorf("unexpected request %s", request.Envelope)
+	}
+}
@@ -0,0 +1,148 @@
+package onvif
+
+const receiverNamespace = "http://www.onvif.org/ver10/receiver/wsdl"
+
+var receiverXMLNs = []string{
+	`xmlns:trv="http://www.onvif.org/ver10/receiver/wsdl"`,
+	`xmlns:tt="http://www.onvif.org/ver10/schema"`,
+}
+
+// GetReceivers fetch all receivers of ONVIF device
+func (device Device) GetReceivers() ([]Receiver, error) {
+	// Create SOAP
+	soap := SOAP{
+		Body:  "<trv:GetReceivers/>",
+		XMLNs: receiverXMLNs,
+	}
+
+	// Send SOAP request
+	response, err := device.sendRequest(receiverNamespace, soap)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse response to interface
+	ifaceReceivers, err := response.ValuesForPath("Envelope.Body.GetReceiversResponse.Receivers")
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert interface to array of receiver
+	receivers := []Receiver{}
+	for _, ifaceReceiver := range ifaceReceivers {
+		receivers = append(receivers, parseReceiver(ifaceReceiver))
+	}
+
+	return receivers, nil
+}
+
+// CreateReceiver creates a new receiver, which pulls media stream from mediaURI
+func (device Device) CreateReceiver(config ReceiverConfig) (Receiver, error) {
+	// Create SOAP
+	soap := SOAP{
+		XMLNs: receiverXMLNs,
+		Body: `<trv:CreateReceiver>
+			<trv:Configuration>` + receiverConfigXML(config) + `</trv:Configuration>
+		</trv:CreateReceiver>`,
+	}
+
+	// Send SOAP request
+	response, err := device.sendRequest(receiverNamespace, soap)
+	if err != nil {
+		return Receiver{}, err
+	}
+
+	// Parse response to interface
+	ifaceReceiver, err := response.ValueForPath("Envelope.Body.CreateReceiverResponse.Receiver")
+	if err != nil {
+		return Receiver{}, err
+	}
+
+	return parseReceiver(ifaceReceiver), nil
+}
+
+// ConfigureReceiver changes configuration of a receiver
+func (device Device) ConfigureReceiver(receiverToken string, config ReceiverConfig) error {
+	// Create SOAP
+	soap := SOAP{
+		XMLNs: receiverXMLNs,
+		Body: `<trv:ConfigureReceiver>
+			<trv:ReceiverToken>` + escapeXML(receiverToken) + `</trv:ReceiverToken>
+			<trv:Configuration>` + receiverConfigXML(config) + `</trv:Configuration>
+		</trv:ConfigureReceiver>`,
+	}
+
+	// Send SOAP request
+	_, err := device.sendRequest(receiverNamespace, soap)
+	return err
+}
+
+// SetReceiverMode changes connection mode of a receiver.
+// Possible mode is AutoConnect, AlwaysConnect or NeverConnect.
+func (device Device) SetReceiverMode(receiverToken, mode string) error {
+	// Create SOAP
+	soap := SOAP{
+		XMLNs: receiverXMLNs,
+		Body: `<trv:SetReceiverMode>
+			<trv:ReceiverToken>` + escapeXML(receiverToken) + `</trv:ReceiverToken>
+			<trv:Mode>` + escapeXML(mode) + `</trv:Mode>
+		</trv:SetReceiverMode>`,
+	}
+
+	// Send SOAP request
+	_, err := device.sendRequest(receiverNamespace, soap)
+	return err
+}
+
+// DeleteReceiver deletes a receiver
+func (device Device) DeleteReceiver(receiverToken string) error {
+	// Create SOAP
+	soap := SOAP{
+		XMLNs: receiverXMLNs,
+		Body: `<trv:DeleteReceiver>
+			<trv:ReceiverToken>` + escapeXML(receiverToken) + `</trv:ReceiverToken>
+		</trv:DeleteReceiver>`,
+	}
+
+	// Send SOAP request
+	_, err := device.sendRequest(receiverNamespace, soap)
+	return err
+}
+
+// parseReceiver parses tt:Receiver
+func parseReceiver(src interface{}) Receiver {
+	receiver := Receiver{}
+	if mapReceiver, ok := src.(map[string]interface{}); ok {
+		receiver.Token = interfaceToString(mapReceiver["Token"])
+
+		if mapConfig, ok := mapReceiver["Configuration"].(map[string]interface{}); ok {
+			receiver.Config.Mode = interfaceToString(mapConfig["Mode"])
+			receiver.Config.MediaURI = interfaceToString(mapConfig["MediaUri"])
+
+			if mapSetup, ok := mapConfig["StreamSetup"].(map[string]interface{}); ok {
+				receiver.Config.Stream = interfaceToString(mapSetup["Stream"])
+
+				if mapTransport, ok := mapSetup["Transport"].(map[string]interface{}); ok {
+					receiver.Config.Protocol = interfaceToString(mapTransport["Protocol"])
+				}
+			}
+		}
+	}
+
+	return receiver
+}
+
+// receiverConfigXML creates content of tt:ReceiverConfiguration
+func receiverConfigXML(config ReceiverConfig) string {
+	stream := config.Stream
+	if stream == "" {
+		stream = "RTP-Unicast"
+	}
+
+	return `<tt:Mode>` + escapeXML(config.Mode) + `</tt:Mode>
+		<tt:MediaUri>` + escapeXML(config.MediaURI) + `</tt:MediaUri>
+		<tt:StreamSetup>
+			<tt:Stream>` + escapeXML(stream) + `</tt:Stream>
+			<tt:Transport><tt:Protocol>` + escapeXML(config.Protocol) + `</tt:Protocol></tt:Transport>
+		</tt:StreamSetup>`
+}
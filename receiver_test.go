@@ -0,0 +1,112 @@
+package onvif
+
+import (
+	"fmt"
+	"log"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/krabiswabbie/go-onvif/onviftest"
+)
+
+func TestGetReceivers(t *testing.T) {
+	log.Println("Test GetReceivers")
+
+	res, err := testDevice.GetReceivers()
+	if err != nil {
+		t.Error(err)
+	}
+
+	js := prettyJSON(&res)
+	fmt.Println(js)
+}
+
+func TestReceiver(t *testing.T) {
+	server := onviftest.NewServer()
+	defer server.Close()
+
+	receiverXML := `<tt:Token>receiver0</tt:Token>
+		<tt:Configuration>
+			<tt:Mode>AlwaysConnect</tt:Mode>
+			<tt:MediaUri>rtsp://192.168.1.20/stream?a=1&amp;b=2</tt:MediaUri>
+			<tt:StreamSetup>
+				<tt:Stream>RTP-Unicast</tt:Stream>
+				<tt:Transport><tt:Protocol>RTSP</tt:Protocol></tt:Transport>
+			</tt:StreamSetup>
+		</tt:Configuration>`
+	server.HandleBody("GetReceivers", `<trv:GetReceiversResponse xmlns:trv="http://www.onvif.org/ver10/receiver/wsdl">
+		<trv:Receivers>`+receiverXML+`</trv:Receivers>
+	</trv:GetReceiversResponse>`)
+	server.HandleBody("CreateReceiver", `<trv:CreateReceiverResponse xmlns:trv="http://www.onvif.org/ver10/receiver/wsdl">
+		<trv:Receiver>`+receiverXML+`</trv:Receiver>
+	</trv:CreateReceiverResponse>`)
+	server.HandleBody("ConfigureReceiver", `<trv:ConfigureReceiverResponse xmlns:trv="http://www.onvif.org/ver10/receiver/wsdl"/>`)
+	server.HandleBody("SetReceiverMode", `<trv:SetReceiverModeResponse xmlns:trv="http://www.onvif.org/ver10/receiver/wsdl"/>`)
+	server.HandleBody("DeleteReceiver", `<trv:DeleteReceiverResponse xmlns:trv="http://www.onvif.org/ver10/receiver/wsdl"/>`)
+
+	expected := Receiver{Token: "receiver0", Config: ReceiverConfig{
+		Mode:     "AlwaysConnect",
+		MediaURI: "rtsp://192.168.1.20/stream?a=1&b=2",
+		Stream:   "RTP-Unicast",
+		Protocol: "RTSP",
+	}}
+
+	device := Device{XAddr: server.XAddr()}
+	receivers, err := device.GetReceivers()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(receivers, []Receiver{expected}) {
+		t.Errorf("expected %+v, got %+v", expected, receivers)
+	}
+
+	// Media URI is escaped, and stream defaults to RTP-Unicast
+	config := ReceiverConfig{Mode: "AlwaysConnect", MediaURI: "rtsp://192.168.1.20/stream?a=1&b=2", Protocol: "RTSP"}
+	receiver, err := device.CreateReceiver(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(receiver, expected) {
+		t.Errorf("expected %+v, got %+v", expected, receiver)
+	}
+
+	configXML := `<tt:Mode>AlwaysConnect</tt:Mode><tt:MediaUri>rtsp://192.168.1.20/stream?a=1&amp;b=2</tt:MediaUri>` +
+		`<tt:StreamSetup><tt:Stream>RTP-Unicast</tt:Stream><tt:Transport><tt:Protocol>RTSP</tt:Protocol></tt:Transport></tt:StreamSetup>`
+	request, _ := server.LastRequest("CreateReceiver")
+	if !strings.Contains(request.Envelope, `<trv:CreateReceiver><trv:Configuration>`+configXML+`</trv:Configuration></trv:CreateReceiver>`) {
+		t.Errorf("unexpected request %s", request.Envelope)
+	}
+
+	config.Stream = "RTP-Multicast"
+	if err := device.ConfigureReceiver("receiver0", config); err != nil {
+		t.Fatal(err)
+	}
+
+	request, _ = server.LastRequest("ConfigureReceiver")
+	configXML = strings.Replace(configXML, "RTP-Unicast", "RTP-Multicast", 1)
+	if !strings.Contains(request.Envelope, `<trv:ConfigureReceiver><trv:ReceiverToken>receiver0</trv:ReceiverToken>`+
+		`<trv:Configuration>`+configXML+`</trv:Configuration></trv:ConfigureReceiver>`) {
+		t.Errorf("unexpected request %s", request.Envelope)
+	}
+
+	if err := device.SetReceiverMode("receiver0", "NeverConnect"); err != nil {
+		t.Fatal(err)
+	}
+
+	request, _ = server.LastRequest("SetReceiverMode")
+	if !strings.Contains(request.Envelope, `<trv:SetReceiverMode><trv:ReceiverToken>receiver0</trv:ReceiverToken><trv:Mode>NeverConnect</trv:Mode></trv:SetReceiverMode>`) {
+		t.Errorf("unexpected request %s", request.Envelope)
+	}
+
+	if err := device.DeleteReceiver("receiver0"); err != nil {
+		t.Fatal(err)
+	}
+
+	request, _ = server.LastRequest("DeleteReceiver")
+	if !strings.Contains(request.Envelope, `<trv:DeleteReceiver><trv:ReceiverToken>receiver0</trv:ReceiverToken></trv:DeleteReceiver>`) {
+		t.Errorf("unexpected request %s", request.Envelope)
+	}
+}
@@ -0,0 +1,270 @@
+package onvif
+
+import "strconv"
+
+const recordingNamespace = "http://www.onvif.org/ver10/recording/wsdl"
+
+var recordingXMLNs = []string{
+	`xmlns:trc="http://www.onvif.org/ver10/recording/wsdl"`,
+	`xmlns:tt="http://www.onvif.org/ver10/schema"`,
+}
+
+// GetRecordings fetch all recordings stored in ONVIF device
+func (device Device) GetRecordings() ([]Recording, error) {
+	// Create SOAP
+	soap := SOAP{
+		Body:  "<trc:GetRecordings/>",
+		XMLNs: recordingXMLNs,
+	}
+
+	// Send SOAP request
+	response, err := device.sendRequest(recordingNamespace, soap)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse response to interface
+	ifaceRecordings, err := response.ValuesForPath("Envelope.Body.GetRecordingsResponse.RecordingItem")
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse each recording
+	recordings := []Recording{}
+	for _, ifaceRecording := range ifaceRecordings {
+		if mapRecording, ok := ifaceRecording.(map[string]interface{}); ok {
+			recording := Recording{}
+			recording.Token = interfaceToString(mapRecording["RecordingToken"])
+			recording.Config = parseRecordingConfig(mapRecording["Configuration"])
+
+			// Parse tracks of the recording
+			recording.Tracks = []RecordingTrack{}
+			if mapTracks, ok := mapRecording["Tracks"].(map[string]interface{}); ok {
+				for _, mapTrack := range interfaceToMaps(mapTracks["Track"]) {
+					track := RecordingTrack{}
+					track.Token = interfaceToString(mapTrack["TrackToken"])
+
+					if mapTrackConfig, ok := mapTrack["Configuration"].(map[string]interface{}); ok {
+						track.TrackType = interfaceToString(mapTrackConfig["TrackType"])
+						track.Description = interfaceToString(mapTrackConfig["Description"])
+					}
+
+					recording.Tracks = append(recording.Tracks, track)
+				}
+			}
+
+			recordings = append(recordings, recording)
+		}
+	}
+
+	return recordings, nil
+}
+
+// CreateRecording creates a new recording in ONVIF device and returns its token
+func (device Device) CreateRecording(config RecordingConfig) (string, error) {
+	// Create SOAP
+	soap := SOAP{
+		XMLNs: recordingXMLNs,
+		Body: `<trc:CreateRecording>
+			<trc:RecordingConfiguration>
+				<tt:Source>
+					<tt:SourceId>` + escapeXML(config.Source.SourceID) + `</tt:SourceId>
+					<tt:Name>` + escapeXML(config.Source.Name) + `</tt:Name>
+					<tt:Location>` + escapeXML(config.Source.Location) + `</tt:Location>
+					<tt:Description>` + escapeXML(config.Source.Description) + `</tt:Description>
+					<tt:Address>` + escapeXML(config.Source.Address) + `</tt:Address>
+				</tt:Source>
+				<tt:Content>` + escapeXML(config.Content) + `</tt:Content>
+				<tt:MaximumRetentionTime>` + formatDuration(config.MaximumRetentionTime) + `</tt:MaximumRetentionTime>
+			</trc:RecordingConfiguration>
+		</trc:CreateRecording>`,
+	}
+
+	// Send SOAP request
+	response, err := device.sendRequest(recordingNamespace, soap)
+	if err != nil {
+		return "", err
+	}
+
+	// Parse response
+	token, _ := response.ValueForPathString("Envelope.Body.CreateRecordingResponse.RecordingToken")
+	return token, nil
+}
+
+// DeleteRecording deletes a recording and all data stored in it
+func (device Device) DeleteRecording(recordingToken string) error {
+	// Create SOAP
+	soap := SOAP{
+		XMLNs: recordingXMLNs,
+		Body: `<trc:DeleteRecording>
+			<trc:RecordingToken>` + escapeXML(recordingToken) + `</trc:RecordingToken>
+		</trc:DeleteRecording>`,
+	}
+
+	// Send SOAP request
+	_, err := device.sendRequest(recordingNamespace, soap)
+	return err
+}
+
+// GetRecordingJobs fetch all recording jobs in ONVIF device
+func (device Device) GetRecordingJobs() ([]RecordingJob, error) {
+	// Create SOAP
+	soap := SOAP{
+		Body:  "<trc:GetRecordingJobs/>",
+		XMLNs: recordingXMLNs,
+	}
+
+	// Send SOAP request
+	response, err := device.sendRequest(recordingNamespace, soap)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse response to interface
+	ifaceJobs, err := response.ValuesForPath("Envelope.Body.GetRecordingJobsResponse.JobItem")
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse each recording job
+	jobs := []RecordingJob{}
+	for _, ifaceJob := range ifaceJobs {
+		if mapJob, ok := ifaceJob.(map[string]interface{}); ok {
+			job := RecordingJob{}
+			job.Token = interfaceToString(mapJob["JobToken"])
+			job.Config = parseRecordingJobConfig(mapJob["JobConfiguration"])
+			jobs = append(jobs, job)
+		}
+	}
+
+	return jobs, nil
+}
+
+// CreateRecordingJob creates a new recording job. The configuration
+// returned by ONVIF device might differ from the requested one.
+func (device Device) CreateRecordingJob(config RecordingJobConfig) (RecordingJob, error) {
+	// Create SOAP
+	soap := SOAP{
+		XMLNs: recordingXMLNs,
+		Body: `<trc:CreateRecordingJob>
+			<trc:JobConfiguration>` + recordingJobConfigXML(config) + `</trc:JobConfiguration>
+		</trc:CreateRecordingJob>`,
+	}
+
+	// Send SOAP request
+	response, err := device.sendRequest(recordingNamespace, soap)
+	if err != nil {
+		return RecordingJob{}, err
+	}
+
+	// Parse response to interface
+	ifaceJob, err := response.ValueForPath("Envelope.Body.CreateRecordingJobResponse")
+	if err != nil {
+		return RecordingJob{}, err
+	}
+
+	// Parse interface to struct
+	job := RecordingJob{}
+	if mapJob, ok := ifaceJob.(map[string]interface{}); ok {
+		job.Token = interfaceToString(mapJob["JobToken"])
+		job.Config = parseRecordingJobConfig(mapJob["JobConfiguration"])
+	}
+
+	return job, nil
+}
+
+// SetRecordingJobMode changes mode of a recording job.
+// Possible mode is RecordingJobModeIdle or RecordingJobModeActive
+func (device Device) SetRecordingJobMode(jobToken, mode string) error {
+	// Create SOAP
+	soap := SOAP{
+		XMLNs: recordingXMLNs,
+		Body: `<trc:SetRecordingJobMode>
+			<trc:JobToken>` + escapeXML(jobToken) + `</trc:JobToken>
+			<trc:Mode>` + escapeXML(mode) + `</trc:Mode>
+		</trc:SetRecordingJobMode>`,
+	}
+
+	// Send SOAP request
+	_, err := device.sendRequest(recordingNamespace, soap)
+	return err
+}
+
+// parseRecordingConfig parses tt:RecordingConfiguration
+func parseRecordingConfig(src interface{}) RecordingConfig {
+	config := RecordingConfig{}
+	if mapConfig, ok := src.(map[string]interface{}); ok {
+		config.Content = interfaceToString(mapConfig["Content"])
+		config.MaximumRetentionTime, _ = parseDuration(interfaceToString(mapConfig["MaximumRetentionTime"]))
+
+		if mapSource, ok := mapConfig["Source"].(map[string]interface{}); ok {
+			config.Source.SourceID = interfaceToString(mapSource["SourceId"])
+			config.Source.Name = interfaceToString(mapSource["Name"])
+			config.Source.Location = interfaceToString(mapSource["Location"])
+			config.Source.Description = interfaceToString(mapSource["Description"])
+			config.Source.Address = interfaceToString(mapSource["Address"])
+		}
+	}
+
+	return config
+}
+
+// parseRecordingJobConfig parses tt:RecordingJobConfiguration
+func parseRecordingJobConfig(src interface{}) RecordingJobConfig {
+	config := RecordingJobConfig{Sources: []RecordingJobSource{}}
+	if mapConfig, ok := src.(map[string]interface{}); ok {
+		config.RecordingToken = interfaceToString(mapConfig["RecordingToken"])
+		config.Mode = interfaceToString(mapConfig["Mode"])
+		config.Priority = interfaceToInt(mapConfig["Priority"])
+
+		for _, mapSource := range interfaceToMaps(mapConfig["Source"]) {
+			source := RecordingJobSource{Tracks: []RecordingJobTrack{}}
+			source.AutoCreateReceiver = interfaceToBool(mapSource["AutoCreateReceiver"])
+
+			if mapToken, ok := mapSource["SourceToken"].(map[string]interface{}); ok {
+				source.SourceToken = interfaceToString(mapToken["Token"])
+				source.SourceType = interfaceToString(mapToken["-Type"])
+			}
+
+			for _, mapTrack := range interfaceToMaps(mapSource["Tracks"]) {
+				source.Tracks = append(source.Tracks, RecordingJobTrack{
+					SourceTag:   interfaceToString(mapTrack["SourceTag"]),
+					Destination: interfaceToString(mapTrack["Destination"]),
+				})
+			}
+
+			config.Sources = append(config.Sources, source)
+		}
+	}
+
+	return config
+}
+
+// recordingJobConfigXML creates content of tt:RecordingJobConfiguration
+func recordingJobConfigXML(config RecordingJobConfig) string {
+	result := `<tt:RecordingToken>` + escapeXML(config.RecordingToken) + `</tt:RecordingToken>
+		<tt:Mode>` + escapeXML(config.Mode) + `</tt:Mode>
+		<tt:Priority>` + strconv.Itoa(config.Priority) + `</tt:Priority>`
+
+	for _, source := range config.Sources {
+		result += `<tt:Source>`
+		if source.SourceToken != "" {
+			result += `<tt:SourceToken`
+			if source.SourceType != "" {
+				result += ` Type="` + escapeXML(source.SourceType) + `"`
+			}
+			result += `><tt:Token>` + escapeXML(source.SourceToken) + `</tt:Token></tt:SourceToken>`
+		}
+		result += `<tt:AutoCreateReceiver>` + strconv.FormatBool(source.AutoCreateReceiver) + `</tt:AutoCreateReceiver>`
+
+		for _, track := range source.Tracks {
+			result += `<tt:Tracks>
+				<tt:SourceTag>` + escapeXML(track.SourceTag) + `</tt:SourceTag>
+				<tt:Destination>` + escapeXML(track.Destination) + `</tt:Destination>
+			</tt:Tracks>`
+		}
+		result += `</tt:Source>`
+	}
+
+	return result
+}
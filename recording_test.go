@@ -0,0 +1,221 @@
+package onvif
+
+import (
+	"fmt"
+	"log"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/krabiswabbie/go-onvif/onviftest"
+)
+
+func TestGetRecordings(t *testing.T) {
+	log.Println("Test GetRecordings")
+
+	res, err := testDevice.GetRecordings()
+	if err != nil {
+		t.Error(err)
+	}
+
+	js := prettyJSON(&res)
+	fmt.Println(js)
+}
+
+func TestGetRecordingJobs(t *testing.T) {
+	log.Println("Test GetRecordingJobs")
+
+	res, err := testDevice.GetRecordingJobs()
+	if err != nil {
+		t.Error(err)
+	}
+
+	js := prettyJSON(&res)
+	fmt.Println(js)
+}
+
+func TestRecording(t *testing.T) {
+	server := onviftest.NewServer()
+	defer server.Close()
+
+	server.HandleBody("GetRecordings", `<trc:GetRecordingsResponse xmlns:trc="http://www.onvif.org/ver10/recording/wsdl">
+		<trc:RecordingItem>
+			<tt:RecordingToken>recording0</tt:RecordingToken>
+			<tt:Configuration>
+				<tt:Source>
+					<tt:SourceId>http://192.168.1.10/onvif/device_service</tt:SourceId>
+					<tt:Name>Front Door</tt:Name>
+					<tt:Location>Garage</tt:Location>
+					<tt:Description>Camera 1</tt:Description>
+					<tt:Address>http://192.168.1.10/onvif/device_service</tt:Address>
+				</tt:Source>
+				<tt:Content>Continuous</tt:Content>
+				<tt:MaximumRetentionTime>P7D</tt:MaximumRetentionTime>
+			</tt:Configuration>
+			<tt:Tracks>
+				<tt:Track>
+					<tt:TrackToken>VIDEO001</tt:TrackToken>
+					<tt:Configuration><tt:TrackType>Video</tt:TrackType><tt:Description>Video track</tt:Description></tt:Configuration>
+				</tt:Track>
+				<tt:Track>
+					<tt:TrackToken>AUDIO001</tt:TrackToken>
+					<tt:Configuration><tt:TrackType>Audio</tt:TrackType><tt:Description>Audio track</tt:Description></tt:Configuration>
+				</tt:Track>
+			</tt:Tracks>
+		</trc:RecordingItem>
+	</trc:GetRecordingsResponse>`)
+	server.HandleBody("CreateRecording", `<trc:CreateRecordingResponse xmlns:trc="http://www.onvif.org/ver10/recording/wsdl">
+		<trc:RecordingToken>recording1</trc:RecordingToken>
+	</trc:CreateRecordingResponse>`)
+	server.HandleBody("DeleteRecording", `<trc:DeleteRecordingResponse xmlns:trc="http://www.onvif.org/ver10/recording/wsdl"/>`)
+
+	config := RecordingConfig{
+		Source: RecordingSource{
+			SourceID:    "http://192.168.1.10/onvif/device_service",
+			Name:        "Front Door",
+			Location:    "Garage",
+			Description: "Camera 1",
+			Address:     "http://192.168.1.10/onvif/device_service",
+		},
+		Content:              "Continuous",
+		MaximumRetentionTime: 7 * 24 * time.Hour,
+	}
+
+	device := Device{XAddr: server.XAddr()}
+	recordings, err := device.GetRecordings()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []Recording{{
+		Token:  "recording0",
+		Config: config,
+		Tracks: []RecordingTrack{
+			{Token: "VIDEO001", TrackType: "Video", Description: "Video track"},
+			{Token: "AUDIO001", TrackType: "Audio", Description: "Audio track"},
+		},
+	}}
+	if !reflect.DeepEqual(recordings, expected) {
+		t.Errorf("expected %+v, got %+v", expected, recordings)
+	}
+
+	// Source is sent in order of the schema, and values are escaped
+	config.Source.Name = "Front & Back"
+	config.MaximumRetentionTime = time.Hour
+	token, err := device.CreateRecording(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if token != "recording1" {
+		t.Errorf("expected token recording1, got %s", token)
+	}
+
+	request, _ := server.LastRequest("CreateRecording")
+	body := `<trc:CreateRecording><trc:RecordingConfiguration><tt:Source>` +
+		`<tt:SourceId>http://192.168.1.10/onvif/device_service</tt:SourceId><tt:Name>Front &amp; Back</tt:Name>` +
+		`<tt:Location>Garage</tt:Location><tt:Description>Camera 1</tt:Description>` +
+		`<tt:Address>http://192.168.1.10/onvif/device_service</tt:Address></tt:Source>` +
+		`<tt:Content>Continuous</tt:Content><tt:MaximumRetentionTime>PT3600S</tt:MaximumRetentionTime>` +
+		`</trc:RecordingConfiguration></trc:CreateRecording>`
+	if !strings.Contains(request.Envelope, body) {
+		t.Errorf("unexpected request %s", request.Envelope)
+	}
+
+	if err := device.DeleteRecording("recording1"); err != nil {
+		t.Fatal(err)
+	}
+
+	request, _ = server.LastRequest("DeleteRecording")
+	if !strings.Contains(request.Envelope, `<trc:DeleteRecording><trc:RecordingToken>recording1</trc:RecordingToken></trc:DeleteRecording>`) {
+		t.Errorf("unexpected request %s", request.Envelope)
+	}
+}
+
+func TestRecordingJob(t *testing.T) {
+	server := onviftest.NewServer()
+	defer server.Close()
+
+	jobConfigXML := `<tt:RecordingToken>recording0</tt:RecordingToken>
+		<tt:Mode>Active</tt:Mode>
+		<tt:Priority>1</tt:Priority>
+		<tt:Source>
+			<tt:SourceToken Type="http://www.onvif.org/ver10/schema/Profile"><tt:Token>Profile_1</tt:Token></tt:SourceToken>
+			<tt:AutoCreateReceiver>false</tt:AutoCreateReceiver>
+			<tt:Tracks><tt:SourceTag>V</tt:SourceTag><tt:Destination>VIDEO001</tt:Destination></tt:Tracks>
+		</tt:Source>`
+	server.HandleBody("GetRecordingJobs", `<trc:GetRecordingJobsResponse xmlns:trc="http://www.onvif.org/ver10/recording/wsdl">
+		<trc:JobItem>
+			<tt:JobToken>job0</tt:JobToken>
+			<tt:JobConfiguration>`+jobConfigXML+`</tt:JobConfiguration>
+		</trc:JobItem>
+	</trc:GetRecordingJobsResponse>`)
+	server.HandleBody("CreateRecordingJob", `<trc:CreateRecordingJobResponse xmlns:trc="http://www.onvif.org/ver10/recording/wsdl">
+		<trc:JobToken>job1</trc:JobToken>
+		<trc:JobConfiguration>`+jobConfigXML+`</trc:JobConfiguration>
+	</trc:CreateRecordingJobResponse>`)
+	server.HandleBody("SetRecordingJobMode", `<trc:SetRecordingJobModeResponse xmlns:trc="http://www.onvif.org/ver10/recording/wsdl"/>`)
+
+	config := RecordingJobConfig{
+		RecordingToken: "recording0",
+		Mode:           RecordingJobModeActive,
+		Priority:       1,
+		Sources: []RecordingJobSource{{
+			SourceToken: "Profile_1",
+			SourceType:  "http://www.onvif.org/ver10/schema/Profile",
+			Tracks:      []RecordingJobTrack{{SourceTag: "V", Destination: "VIDEO001"}},
+		}},
+	}
+
+	device := Device{XAddr: server.XAddr()}
+	jobs, err := device.GetRecordingJobs()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(jobs, []RecordingJob{{Token: "job0", Config: config}}) {
+		t.Errorf("expected %+v, got %+v", config, jobs)
+	}
+
+	// Configuration returned by device is parsed
+	job, err := device.CreateRecordingJob(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(job, RecordingJob{Token: "job1", Config: config}) {
+		t.Errorf("expected %+v, got %+v", config, job)
+	}
+
+	request, _ := server.LastRequest("CreateRecordingJob")
+	body := `<trc:CreateRecordingJob><trc:JobConfiguration>` +
+		`<tt:RecordingToken>recording0</tt:RecordingToken><tt:Mode>Active</tt:Mode><tt:Priority>1</tt:Priority>` +
+		`<tt:Source><tt:SourceToken Type="http://www.onvif.org/ver10/schema/Profile"><tt:Token>Profile_1</tt:Token></tt:SourceToken>` +
+		`<tt:AutoCreateReceiver>false</tt:AutoCreateReceiver>` +
+		`<tt:Tracks><tt:SourceTag>V</tt:SourceTag><tt:Destination>VIDEO001</tt:Destination></tt:Tracks></tt:Source>` +
+		`</trc:JobConfiguration></trc:CreateRecordingJob>`
+	if !strings.Contains(request.Envelope, body) {
+		t.Errorf("unexpected request %s", request.Envelope)
+	}
+
+	// Source without token lets device create a receiver
+	config.Sources = []RecordingJobSource{{AutoCreateReceiver: true}}
+	if _, err := device.CreateRecordingJob(config); err != nil {
+		t.Fatal(err)
+	}
+
+	request, _ = server.LastRequest("CreateRecordingJob")
+	if !strings.Contains(request.Envelope, `<tt:Source><tt:AutoCreateReceiver>true</tt:AutoCreateReceiver></tt:Source>`) {
+		t.Errorf("unexpected request %s", request.Envelope)
+	}
+
+	if err := device.SetRecordingJobMode("job1", RecordingJobModeIdle); err != nil {
+		t.Fatal(err)
+	}
+
+	request, _ = server.LastRequest("SetRecordingJobMode")
+	if !strings.Contains(request.Envelope, `<trc:SetRecordingJobMode><trc:JobToken>job1</trc:JobToken><trc:Mode>Idle</trc:Mode></trc:SetRecordingJobMode>`) {
+		t.Errorf("unexpected request %s", request.Envelope)
+	}
+}
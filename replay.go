@@ -0,0 +1,111 @@
+package onvif
+
+import "time"
+
+const replayNamespace = "http://www.onvif.org/ver10/replay/wsdl"
+
+// ReplayRequire is the value of RTSP Require header, which must be sent
+// by client in all RTSP requests of a replay session
+const ReplayRequire = "onvif-replay"
+
+var replayXMLNs = []string{
+	`xmlns:trp="http://www.onvif.org/ver10/replay/wsdl"`,
+	`xmlns:tt="http://www.onvif.org/ver10/schema"`,
+}
+
+// ReplayOptions contains options for RTSP PLAY request of a replay session
+type ReplayOptions struct {
+	// Start and End are the range of recording to replay. End is optional.
+	// For reverse playback Start should be later than End.
+	Start time.Time
+	End   time.Time
+
+	Reverse       bool
+	NoRateControl bool
+	Immediate     bool
+	IntraOnly     bool
+}
+
+// GetReplayURI fetch RTSP URI that can be used to replay a recording.
+// Possible protocol is UDP, HTTP or RTSP
+func (device Device) GetReplayURI(recordingToken, protocol string) (string, error) {
+	// Create SOAP
+	soap := SOAP{
+		XMLNs: replayXMLNs,
+		Body: `<trp:GetReplayUri>
+			<trp:StreamSetup>
+				<tt:Stream>RTP-Unicast</tt:Stream>
+				<tt:Transport><tt:Protocol>` + escapeXML(protocol) + `</tt:Protocol></tt:Transport>
+			</trp:StreamSetup>
+			<trp:RecordingToken>` + escapeXML(recordingToken) + `</trp:RecordingToken>
+		</trp:GetReplayUri>`,
+	}
+
+	// Send SOAP request
+	response, err := device.sendRequest(replayNamespace, soap)
+	if err != nil {
+		return "", err
+	}
+
+	// Parse response
+	uri, _ := response.ValueForPathString("Envelope.Body.GetReplayUriResponse.Uri")
+	return uri, nil
+}
+
+// GetReplayConfiguration fetch configuration of replay service
+func (device Device) GetReplayConfiguration() (ReplayConfig, error) {
+	// Create SOAP
+	soap := SOAP{
+		Body:  "<trp:GetReplayConfiguration/>",
+		XMLNs: replayXMLNs,
+	}
+
+	// Send SOAP request
+	response, err := device.sendRequest(replayNamespace, soap)
+	if err != nil {
+		return ReplayConfig{}, err
+	}
+
+	// Parse response
+	config := ReplayConfig{}
+	timeout, _ := response.ValueForPathString("Envelope.Body.GetReplayConfigurationResponse.Configuration.SessionTimeout")
+	config.SessionTimeout, _ = parseDuration(timeout)
+
+	return config, nil
+}
+
+// ReplayHeaders returns RTSP headers that must be added to PLAY request
+// of a replay session, as defined in ONVIF streaming specification
+func ReplayHeaders(options ReplayOptions) map[string]string {
+	const clockFormat = "20060102T150405.000Z"
+
+	headers := map[string]string{
+		"Require": ReplayRequire,
+	}
+
+	if !options.Start.IsZero() {
+		clockRange := "clock=" + options.Start.UTC().Format(clockFormat) + "-"
+		if !options.End.IsZero() {
+			clockRange += options.End.UTC().Format(clockFormat)
+		}
+		headers["Range"] = clockRange
+	}
+
+	if options.Reverse {
+		headers["Scale"] = "-1.0"
+	}
+
+	if options.NoRateControl {
+		headers["Rate-Control"] = "no"
+	}
+
+	if options.Immediate {
+		headers["Immediate"] = "yes"
+	}
+
+	if options.IntraOnly {
+		headers["Frames"] = "intra"
+	}
+
+	return headers
+}
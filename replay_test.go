@@ -0,0 +1,46 @@
+package onvif
+
+import (
+	"fmt"
+	"log"
+	"testing"
+	"time"
+)
+
+func TestGetReplayConfiguration(t *testing.T) {
+	log.Println("Test GetReplayConfiguration")
+
+	res, err := testDevice.GetReplayConfiguration()
+	if err != nil {
+		t.Error(err)
+	}
+
+	js := prettyJSON(&res)
+	fmt.Println(js)
+}
+
+func TestReplayHeaders(t *testing.T) {
+	start := time.Date(2009, 6, 15, 11, 49, 0, 440*int(time.Millisecond), time.UTC)
+	headers := ReplayHeaders(ReplayOptions{
+		Start:         start,
+		Reverse:       true,
+		NoRateControl: true,
+	})
+
+	expected := map[string]string{
+		"Require":      "onvif-replay",
+		"Range":        "clock=20090615T114900.440Z-",
+		"Scale":        "-1.0",
+		"Rate-Control": "no",
+	}
+
+	if len(headers) != len(expected) {
+		t.Errorf("expected %d headers, got %d", len(expected), len(headers))
+	}
+
+	for key, value := range expected {
+		if headers[key] != value {
+			t.Errorf("%s: expected %s, got %s", key, value, headers[key])
+		}
+	}
+}
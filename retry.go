@@ -0,0 +1,92 @@
+package onvif
+
+import (
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// DefaultRetryPolicy is a reasonable retry policy for cameras on unreliable links
+var DefaultRetryPolicy = RetryPolicy{
+	MaxRetries:     3,
+	InitialBackoff: 200 * time.Millisecond,
+	MaxBackoff:     2 * time.Second,
+	Jitter:         0.2,
+}
+
+// RetryPolicy configures how SOAP requests are retried after transient
+// network failure. Backoff starts at InitialBackoff and doubles on each
+// retry, up to MaxBackoff. Jitter is the fraction of backoff, between 0
+// and 1, that's randomly subtracted from it. Requests that fail because of
+// SOAP fault are never retried. Since camera might have already handled the
+// request, e.g. if response times out, only read-only operations, i.e. Get*
+// except results of search, are retried after the request is sent. Other operations are retried only
+// if connection to camera can't be made. Zero value disables the retry.
+type RetryPolicy struct {
+	MaxRetries     int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	Jitter         float64
+}
+
+// backoff returns duration to wait before the retry with specified number,
+// which starts from 0 for the first retry
+func (policy RetryPolicy) backoff(retry int) time.Duration {
+	delay := policy.InitialBackoff
+	for i := 0; i < retry && (policy.MaxBackoff <= 0 || delay < policy.MaxBackoff); i++ {
+		delay *= 2
+	}
+
+	if policy.MaxBackoff > 0 && delay > policy.MaxBackoff {
+		delay = policy.MaxBackoff
+	}
+
+	if policy.Jitter > 0 {
+		jitter := policy.Jitter
+		if jitter > 1 {
+			jitter = 1
+		}
+		delay -= time.Duration(rand.Float64() * jitter * float64(delay))
+	}
+
+	return delay
+}
+
+// isRetryable reports whether the operation that failed with the error
+// can be sent again without risk of applying it twice
+func isRetryable(err error, operation string) bool {
+	if !isTransientError(err) {
+		return false
+	}
+
+	// Results of search are consumed by the camera, e.g. GetRecordingSearchResults
+	if strings.HasPrefix(operation, "Get") && !strings.HasSuffix(operation, "SearchResults") {
+		return true
+	}
+
+	// Request isn't sent if connection fails
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// isTransientError reports whether the error is caused by network failure
+// that might not happen again, e.g. timeout or connection reset
+func isTransientError(err error) bool {
+	var fault *SOAPFault
+	if errors.As(err, &fault) {
+		return false
+	}
+
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNABORTED) ||
+		errors.Is(err, syscall.EPIPE) {
+		return true
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
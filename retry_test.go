@@ -0,0 +1,155 @@
+package onvif
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// newFlakyServer creates server that drops the connection of first n requests
+func newFlakyServer(t *testing.T, n int32, requests *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(requests, 1) <= n {
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			conn.Close()
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/soap+xml")
+		fmt.Fprint(w, testHostnameResponse)
+	}))
+}
+
+func TestRetry(t *testing.T) {
+	requests := int32(0)
+	server := newFlakyServer(t, 2, &requests)
+	defer server.Close()
+
+	// Without retry, the dropped request fails immediately
+	device := Device{XAddr: server.URL + "/onvif/device_service"}
+	if _, err := device.GetHostname(); err == nil {
+		t.Error("expected error without retry")
+	}
+
+	// With retry, the request succeeds once server recovers
+	atomic.StoreInt32(&requests, 0)
+	device.Retry = RetryPolicy{MaxRetries: 3, InitialBackoff: time.Millisecond}
+	if _, err := device.GetHostname(); err != nil {
+		t.Error(err)
+	}
+
+	if requests := atomic.LoadInt32(&requests); requests != 3 {
+		t.Errorf("server received %d requests, want 3", requests)
+	}
+}
+
+func TestRetryNonIdempotent(t *testing.T) {
+	requests := int32(0)
+	server := newFlakyServer(t, 1, &requests)
+	defer server.Close()
+
+	// Camera might have changed hostname before dropping the connection
+	device := Device{
+		XAddr: server.URL + "/onvif/device_service",
+		Retry: RetryPolicy{MaxRetries: 3, InitialBackoff: time.Millisecond},
+	}
+	if err := device.SetHostname("camera"); err == nil {
+		t.Error("expected error without retry")
+	}
+
+	if requests := atomic.LoadInt32(&requests); requests != 1 {
+		t.Errorf("SetHostname retried, server received %d requests", requests)
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	dialErr := &url.Error{Op: "Post", Err: &net.OpError{Op: "dial", Err: syscall.ECONNRESET}}
+	readErr := &url.Error{Op: "Post", Err: &net.OpError{Op: "read", Err: syscall.ECONNRESET}}
+	tests := []struct {
+		err       error
+		operation string
+		expected  bool
+	}{
+		{dialErr, "GetHostname", true},
+		{dialErr, "SetHostname", true},
+		{readErr, "GetHostname", true},
+		{readErr, "SetHostname", false},
+		{readErr, "SystemReboot", false},
+		{readErr, "GetRecordingSearchResults", false},
+		{dialErr, "GetEventSearchResults", true},
+		{errors.New("Not transient"), "GetHostname", false},
+	}
+
+	for _, test := range tests {
+		if retryable := isRetryable(test.err, test.operation); retryable != test.expected {
+			t.Errorf("isRetryable(%v, %s) = %v, want %v", test.err, test.operation, retryable, test.expected)
+		}
+	}
+}
+
+func TestRetrySOAPFault(t *testing.T) {
+	requests := int32(0)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Header().Set("Content-Type", "application/soap+xml")
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, testFaultResponse)
+	}))
+	defer server.Close()
+
+	device := Device{
+		XAddr: server.URL + "/onvif/device_service",
+		Retry: RetryPolicy{MaxRetries: 3, InitialBackoff: time.Millisecond},
+	}
+
+	_, err := device.GetHostname()
+	if !errors.Is(err, ErrNoProfile) {
+		t.Errorf("expected SOAP fault, got %v", err)
+	}
+
+	if requests := atomic.LoadInt32(&requests); requests != 1 {
+		t.Errorf("SOAP fault retried, server received %d requests", requests)
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		fmt.Fprint(w, testHostnameResponse)
+	}))
+	defer server.Close()
+
+	device := Device{XAddr: server.URL + "/onvif/device_service", Timeout: 50 * time.Millisecond}
+	if _, err := device.GetHostname(); !isTransientError(err) {
+		t.Errorf("expected timeout, got %v", err)
+	}
+}
+
+func TestRetryBackoff(t *testing.T) {
+	policy := RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+	expected := []time.Duration{100, 200, 400, 800, 1000, 1000}
+	for retry, want := range expected {
+		if delay := policy.backoff(retry); delay != want*time.Millisecond {
+			t.Errorf("backoff of retry %d is %v, want %v", retry, delay, want*time.Millisecond)
+		}
+	}
+
+	policy.Jitter = 0.5
+	for retry := 0; retry < 10; retry++ {
+		delay := policy.backoff(retry)
+		if delay < 50*time.Millisecond || delay > time.Second {
+			t.Errorf("backoff of retry %d with jitter is %v", retry, delay)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package onvif
+
+const scheduleNamespace = "http://www.onvif.org/ver10/schedule/wsdl"
+
+var scheduleXMLNs = []string{
+	`xmlns:tsc="http://www.onvif.org/ver10/schedule/wsdl"`,
+	`xmlns:tt="http://www.onvif.org/ver10/schema"`,
+}
+
+// GetSchedules fetch schedules with the specified tokens
+func (device Device) GetSchedules(tokens []string) ([]Schedule, error) {
+	// Create SOAP
+	body := `<tsc:GetSchedules>`
+	for _, token := range tokens {
+		body += `<tsc:Token>` + escapeXML(token) + `</tsc:Token>`
+	}
+	body += `</tsc:GetSchedules>`
+
+	soap := SOAP{
+		Body:  body,
+		XMLNs: scheduleXMLNs,
+	}
+
+	// Send SOAP request
+	response, err := device.sendRequest(scheduleNamespace, soap)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse response to interface
+	ifaceSchedules, err := response.ValuesForPath("Envelope.Body.GetSchedulesResponse.Schedule")
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert interface to array of schedule
+	schedules := []Schedule{}
+	for _, ifaceSchedule := range ifaceSchedules {
+		mapSchedule, ok := ifaceSchedule.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		schedule := Schedule{
+			Token:       interfaceToString(mapSchedule["-token"]),
+			Name:        interfaceToString(mapSchedule["Name"]),
+			Description: interfaceToString(mapSchedule["Description"]),
+			Standard:    interfaceToString(mapSchedule["Standard"]),
+			SpecialDays: []SpecialDaysSchedule{},
+		}
+
+		for _, mapSpecialDays := range interfaceToMaps(mapSchedule["SpecialDays"]) {
+			specialDays := SpecialDaysSchedule{
+				GroupToken: interfaceToString(mapSpecialDays["GroupToken"]),
+				TimeRanges: []TimeRange{},
+			}
+
+			for _, mapRange := range interfaceToMaps(mapSpecialDays["TimeRange"]) {
+				specialDays.TimeRanges = append(specialDays.TimeRanges, TimeRange{
+					From:  interfaceToString(mapRange["From"]),
+					Until: interfaceToString(mapRange["Until"]),
+				})
+			}
+
+			schedule.SpecialDays = append(schedule.SpecialDays, specialDays)
+		}
+
+		schedules = append(schedules, schedule)
+	}
+
+	return schedules, nil
+}
+
+// CreateSchedule creates a new schedule and returns its token.
+// Token of the schedule must be empty, since it's assigned by device.
+func (device Device) CreateSchedule(schedule Schedule) (string, error) {
+	// Create SOAP
+	body := `<tsc:CreateSchedule>
+		<tsc:Schedule token="">
+			<tsc:Name>` + escapeXML(schedule.Name) + `</tsc:Name>
+			<tsc:Description>` + escapeXML(schedule.Description) + `</tsc:Description>
+			<tsc:Standard>` + escapeXML(schedule.Standard) + `</tsc:Standard>`
+
+	for _, specialDays := range schedule.SpecialDays {
+		body += `<tsc:SpecialDays>
+			<tsc:GroupToken>` + escapeXML(specialDays.GroupToken) + `</tsc:GroupToken>`
+
+		for _, timeRange := range specialDays.TimeRanges {
+			body += `<tsc:TimeRange>
+				<tsc:From>` + escapeXML(timeRange.From) + `</tsc:From>`
+			if timeRange.Until != "" {
+				body += `<tsc:Until>` + escapeXML(timeRange.Until) + `</tsc:Until>`
+			}
+			body += `</tsc:TimeRange>`
+		}
+
+		body += `</tsc:SpecialDays>`
+	}
+
+	body += `</tsc:Schedule>
+	</tsc:CreateSchedule>`
+
+	soap := SOAP{
+		Body:  body,
+		XMLNs: scheduleXMLNs,
+	}
+
+	// Send SOAP request
+	response, err := device.sendRequest(scheduleNamespace, soap)
+	if err != nil {
+		return "", err
+	}
+
+	// Parse response
+	token, _ := response.ValueForPathString("Envelope.Body.CreateScheduleResponse.Token")
+	return token, nil
+}
@@ -0,0 +1,92 @@
+package onvif
+
+import (
+	"fmt"
+	"log"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/krabiswabbie/go-onvif/onviftest"
+)
+
+func TestGetSchedules(t *testing.T) {
+	log.Println("Test GetSchedules")
+
+	res, err := testDevice.GetSchedules(nil)
+	if err != nil {
+		t.Error(err)
+	}
+
+	js := prettyJSON(&res)
+	fmt.Println(js)
+}
+
+func TestSchedule(t *testing.T) {
+	server := onviftest.NewServer()
+	defer server.Close()
+
+	server.HandleBody("GetSchedules", `<tsc:GetSchedulesResponse xmlns:tsc="http://www.onvif.org/ver10/schedule/wsdl">
+		<tsc:Schedule token="Schedule_1">
+			<tsc:Name>Office hours</tsc:Name>
+			<tsc:Description>Weekdays</tsc:Description>
+			<tsc:Standard>BEGIN:VCALENDAR</tsc:Standard>
+			<tsc:SpecialDays>
+				<tsc:GroupToken>Holidays</tsc:GroupToken>
+				<tsc:TimeRange><tsc:From>10:00:00</tsc:From><tsc:Until>14:00:00</tsc:Until></tsc:TimeRange>
+				<tsc:TimeRange><tsc:From>16:00:00</tsc:From></tsc:TimeRange>
+			</tsc:SpecialDays>
+		</tsc:Schedule>
+	</tsc:GetSchedulesResponse>`)
+	server.HandleBody("CreateSchedule", `<tsc:CreateScheduleResponse xmlns:tsc="http://www.onvif.org/ver10/schedule/wsdl">
+		<tsc:Token>Schedule_2</tsc:Token>
+	</tsc:CreateScheduleResponse>`)
+
+	device := Device{XAddr: server.XAddr()}
+	schedules, err := device.GetSchedules([]string{"Schedule_1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	request, _ := server.LastRequest("GetSchedules")
+	if !strings.Contains(request.Envelope, `<tsc:GetSchedules><tsc:Token>Schedule_1</tsc:Token></tsc:GetSchedules>`) {
+		t.Errorf("unexpected request %s", request.Envelope)
+	}
+
+	specialDays := []SpecialDaysSchedule{{
+		GroupToken: "Holidays",
+		TimeRanges: []TimeRange{{From: "10:00:00", Until: "14:00:00"}, {From: "16:00:00"}},
+	}}
+	expected := []Schedule{{
+		Token:       "Schedule_1",
+		Name:        "Office hours",
+		Description: "Weekdays",
+		Standard:    "BEGIN:VCALENDAR",
+		SpecialDays: specialDays,
+	}}
+	if !reflect.DeepEqual(schedules, expected) {
+		t.Errorf("expected %+v, got %+v", expected, schedules)
+	}
+
+	// Open-ended time range is sent without Until, and values are escaped
+	schedule := Schedule{Name: "Night & weekend", Description: "Closed", Standard: "BEGIN:VCALENDAR", SpecialDays: specialDays}
+	token, err := device.CreateSchedule(schedule)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if token != "Schedule_2" {
+		t.Errorf("expected token Schedule_2, got %s", token)
+	}
+
+	request, _ = server.LastRequest("CreateSchedule")
+	body := `<tsc:CreateSchedule><tsc:Schedule token="">` +
+		`<tsc:Name>Night &amp; weekend</tsc:Name><tsc:Description>Closed</tsc:Description><tsc:Standard>BEGIN:VCALENDAR</tsc:Standard>` +
+		`<tsc:SpecialDays><tsc:GroupToken>Holidays</tsc:GroupToken>` +
+		`<tsc:TimeRange><tsc:From>10:00:00</tsc:From><tsc:Until>14:00:00</tsc:Until></tsc:TimeRange>` +
+		`<tsc:TimeRange><tsc:From>16:00:00</tsc:From></tsc:TimeRange></tsc:SpecialDays>` +
+		`</tsc:Schedule></tsc:CreateSchedule>`
+	if !strings.Contains(request.Envelope, body) {
+		t.Errorf("unexpected request %s", request.Envelope)
+	}
+}
@@ -0,0 +1,167 @@
+package onvif
+
+import (
+	"net/url"
+	"strings"
+)
+
+// Prefixes of the scopes defined by ONVIF
+const (
+	ScopeNamePrefix     = "onvif://www.onvif.org/name/"
+	ScopeHardwarePrefix = "onvif://www.onvif.org/hardware/"
+	ScopeLocationPrefix = "onvif://www.onvif.org/location/"
+	ScopeProfilePrefix  = "onvif://www.onvif.org/Profile/"
+	ScopeTypePrefix     = "onvif://www.onvif.org/type/"
+)
+
+// SetScopes replaces configurable scopes of ONVIF camera
+func (device Device) SetScopes(scopes []string) error {
+	return device.sendScopes("SetScopes", scopes)
+}
+
+// AddScopes adds configurable scopes to ONVIF camera
+func (device Device) AddScopes(scopes []string) error {
+	return device.sendScopes("AddScopes", scopes)
+}
+
+// RemoveScopes removes configurable scopes from ONVIF camera
+// and returns the scopes that have been removed
+func (device Device) RemoveScopes(scopes []string) ([]string, error) {
+	// Create SOAP
+	body := `<tds:RemoveScopes>`
+	for _, scope := range scopes {
+		body += `<tds:ScopeItem>` + escapeXML(scope) + `</tds:ScopeItem>`
+	}
+	body += `</tds:RemoveScopes>`
+
+	soap := SOAP{
+		Body:  body,
+		XMLNs: deviceXMLNs,
+	}
+
+	// Send SOAP request
+	response, err := device.sendRequest(deviceNamespace, soap)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse response
+	ifaceScopes, _ := response.ValueForPath("Envelope.Body.RemoveScopesResponse.ScopeItem")
+	return interfaceToStrings(ifaceScopes), nil
+}
+
+// sendScopes sends scopes with the operation, e.g. SetScopes
+func (device Device) sendScopes(operation string, scopes []string) error {
+	// Create SOAP
+	element := "Scopes"
+	if operation == "AddScopes" {
+		element = "ScopeItem"
+	}
+
+	body := `<tds:` + operation + `>`
+	for _, scope := range scopes {
+		body += `<tds:` + element + `>` + escapeXML(scope) + `</tds:` + element + `>`
+	}
+	body += `</tds:` + operation + `>`
+
+	soap := SOAP{
+		Body:  body,
+		XMLNs: deviceXMLNs,
+	}
+
+	// Send SOAP request
+	_, err := device.sendRequest(deviceNamespace, soap)
+	return err
+}
+
+// ParseScopes parses scopes returned by GetScopes into typed fields
+func ParseScopes(scopes []string) ScopeInfo {
+	info := ScopeInfo{
+		Locations: []string{},
+		Profiles:  []string{},
+		Types:     []string{},
+		Others:    []string{},
+	}
+
+	for _, scope := range scopes {
+		scope = strings.TrimSpace(scope)
+
+		switch {
+		case hasScopePrefix(scope, ScopeNamePrefix):
+			info.Name = scopeValue(scope, ScopeNamePrefix)
+		case hasScopePrefix(scope, ScopeHardwarePrefix):
+			info.Hardware = scopeValue(scope, ScopeHardwarePrefix)
+		case hasScopePrefix(scope, ScopeLocationPrefix):
+			info.Locations = append(info.Locations, scopeValue(scope, ScopeLocationPrefix))
+		case hasScopePrefix(scope, ScopeProfilePrefix):
+			info.Profiles = append(info.Profiles, scopeValue(scope, ScopeProfilePrefix))
+		case hasScopePrefix(scope, ScopeTypePrefix):
+			info.Types = append(info.Types, scopeValue(scope, ScopeTypePrefix))
+		case scope != "":
+			info.Others = append(info.Others, scope)
+		}
+	}
+
+	return info
+}
+
+// Scopes builds scope URIs from the typed fields
+func (info ScopeInfo) Scopes() []string {
+	scopes := []string{}
+	if info.Name != "" {
+		scopes = append(scopes, NameScope(info.Name))
+	}
+	if info.Hardware != "" {
+		scopes = append(scopes, HardwareScope(info.Hardware))
+	}
+	for _, location := range info.Locations {
+		scopes = append(scopes, LocationScope(location))
+	}
+	for _, profile := range info.Profiles {
+		scopes = append(scopes, buildScope(ScopeProfilePrefix, profile))
+	}
+	for _, scopeType := range info.Types {
+		scopes = append(scopes, buildScope(ScopeTypePrefix, scopeType))
+	}
+	return append(scopes, info.Others...)
+}
+
+// NameScope creates scope URI of the camera name
+func NameScope(name string) string {
+	return buildScope(ScopeNamePrefix, name)
+}
+
+// HardwareScope creates scope URI of the camera hardware
+func HardwareScope(hardware string) string {
+	return buildScope(ScopeHardwarePrefix, hardware)
+}
+
+// LocationScope creates scope URI of the camera location, which might be
+// hierarchical, e.g. country/indonesia
+func LocationScope(location string) string {
+	return buildScope(ScopeLocationPrefix, location)
+}
+
+// buildScope creates scope URI where each segment of value is escaped
+func buildScope(prefix, value string) string {
+	segments := strings.Split(value, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return prefix + strings.Join(segments, "/")
+}
+
+// hasScopePrefix reports whether scope starts with the prefix, ignoring case
+// since some cameras write e.g. onvif://www.onvif.org/profile/streaming
+func hasScopePrefix(scope, prefix string) bool {
+	return len(scope) >= len(prefix) && strings.EqualFold(scope[:len(prefix)], prefix)
+}
+
+// scopeValue returns unescaped value of the scope
+func scopeValue(scope, prefix string) string {
+	value := scope[len(prefix):]
+	if unescaped, err := url.PathUnescape(value); err == nil {
+		return unescaped
+	}
+	return value
+}
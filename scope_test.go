@@ -0,0 +1,83 @@
+package onvif
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/krabiswabbie/go-onvif/onviftest"
+)
+
+func TestParseScopes(t *testing.T) {
+	scopes := []string{
+		"onvif://www.onvif.org/type/video_encoder",
+		"onvif://www.onvif.org/Profile/Streaming",
+		"onvif://www.onvif.org/profile/G",
+		"onvif://www.onvif.org/name/Front%20Door",
+		"onvif://www.onvif.org/hardware/IPC-HFW1320S",
+		"onvif://www.onvif.org/location/country/indonesia",
+		"odm:name:camera",
+	}
+
+	info := ParseScopes(scopes)
+	expected := ScopeInfo{
+		Name:      "Front Door",
+		Hardware:  "IPC-HFW1320S",
+		Locations: []string{"country/indonesia"},
+		Profiles:  []string{"Streaming", "G"},
+		Types:     []string{"video_encoder"},
+		Others:    []string{"odm:name:camera"},
+	}
+
+	if !reflect.DeepEqual(info, expected) {
+		t.Errorf("got %+v, want %+v", info, expected)
+	}
+
+	// Building the scopes must produce scopes that parse the same
+	if rebuilt := ParseScopes(info.Scopes()); !reflect.DeepEqual(rebuilt, expected) {
+		t.Errorf("rebuilt scopes got %+v, want %+v", rebuilt, expected)
+	}
+
+	if scope := NameScope("Front Door"); scope != "onvif://www.onvif.org/name/Front%20Door" {
+		t.Errorf("unexpected name scope %s", scope)
+	}
+}
+
+func TestScopeOperations(t *testing.T) {
+	server := onviftest.NewServer()
+	defer server.Close()
+
+	server.HandleBody("AddScopes", `<tds:AddScopesResponse/>`)
+	server.HandleBody("SetScopes", `<tds:SetScopesResponse/>`)
+	server.HandleBody("RemoveScopes", `<tds:RemoveScopesResponse>
+		<tds:ScopeItem>onvif://www.onvif.org/location/office</tds:ScopeItem>
+	</tds:RemoveScopesResponse>`)
+
+	device := Device{XAddr: server.XAddr()}
+	if err := device.AddScopes([]string{LocationScope("office")}); err != nil {
+		t.Fatal(err)
+	}
+
+	request, _ := server.LastRequest("AddScopes")
+	if !strings.Contains(request.Envelope, "<tds:ScopeItem>onvif://www.onvif.org/location/office</tds:ScopeItem>") {
+		t.Errorf("unexpected request %s", request.Envelope)
+	}
+
+	if err := device.SetScopes([]string{NameScope("camera")}); err != nil {
+		t.Fatal(err)
+	}
+
+	request, _ = server.LastRequest("SetScopes")
+	if !strings.Contains(request.Envelope, "<tds:Scopes>onvif://www.onvif.org/name/camera</tds:Scopes>") {
+		t.Errorf("unexpected request %s", request.Envelope)
+	}
+
+	removed, err := device.RemoveScopes([]string{LocationScope("office")})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(removed, []string{"onvif://www.onvif.org/location/office"}) {
+		t.Errorf("unexpected removed scopes %v", removed)
+	}
+}
@@ -0,0 +1,394 @@
+package onvif
+
+import (
+	"errors"
+	"strconv"
+	"time"
+)
+
+const searchNamespace = "http://www.onvif.org/ver10/search/wsdl"
+
+// Possible state of a search session
+const (
+	SearchStateQueued    = "Queued"
+	SearchStateSearching = "Searching"
+	SearchStateCompleted = "Completed"
+	SearchStateUnknown   = "Unknown"
+)
+
+var searchXMLNs = []string{
+	`xmlns:tse="http://www.onvif.org/ver10/search/wsdl"`,
+	`xmlns:tt="http://www.onvif.org/ver10/schema"`,
+	`xmlns:wsnt="http://docs.oasis-open.org/wsn/b-2"`,
+	`xmlns:tns1="http://www.onvif.org/ver10/topics"`,
+}
+
+// maxSearchWaitTime is the longest time device may wait for search results in one request
+const maxSearchWaitTime = 2 * time.Second
+
+var errSearchTimeout = errors.New("Search is not completed before timeout")
+
+// FindRecordings starts a search session for recordings that match the scope,
+// and returns token of the search session. Results can be fetched using
+// GetRecordingSearchResults. Session is ended automatically if it's not used
+// for longer than keepAlive.
+func (device Device) FindRecordings(scope SearchScope, maxMatches int, keepAlive time.Duration) (string, error) {
+	// Create SOAP
+	soap := SOAP{
+		XMLNs: searchXMLNs,
+		Body: `<tse:FindRecordings>
+			<tse:Scope>` + searchScopeXML(scope) + `</tse:Scope>
+			` + maxMatchesXML(maxMatches) + `
+			<tse:KeepAliveTime>` + formatDuration(keepAlive) + `</tse:KeepAliveTime>
+		</tse:FindRecordings>`,
+	}
+
+	// Send SOAP request
+	response, err := device.sendRequest(searchNamespace, soap)
+	if err != nil {
+		return "", err
+	}
+
+	// Parse response
+	token, _ := response.ValueForPathString("Envelope.Body.FindRecordingsResponse.SearchToken")
+	return token, nil
+}
+
+// GetRecordingSearchResults fetch results of a recording search session.
+// It waits up to waitTime until at least minResults are available.
+func (device Device) GetRecordingSearchResults(searchToken string, minResults, maxResults int, waitTime time.Duration) (FindRecordingResult, error) {
+	// Create SOAP
+	soap := SOAP{
+		XMLNs: searchXMLNs,
+		Body: `<tse:GetRecordingSearchResults>
+			<tse:SearchToken>` + escapeXML(searchToken) + `</tse:SearchToken>
+			` + searchResultsLimitXML(minResults, maxResults, waitTime) + `
+		</tse:GetRecordingSearchResults>`,
+	}
+
+	// Send SOAP request
+	response, err := device.sendRequest(searchNamespace, soap)
+	if err != nil {
+		return FindRecordingResult{}, err
+	}
+
+	// Parse response to interface
+	ifaceResult, err := response.ValueForPath("Envelope.Body.GetRecordingSearchResultsResponse.ResultList")
+	if err != nil {
+		return FindRecordingResult{}, err
+	}
+
+	// Parse interface to struct
+	result := FindRecordingResult{RecordingInformation: []RecordingInformation{}}
+	if mapResult, ok := ifaceResult.(map[string]interface{}); ok {
+		result.SearchState = interfaceToString(mapResult["SearchState"])
+
+		for _, mapInfo := range interfaceToMaps(mapResult["RecordingInformation"]) {
+			info := RecordingInformation{}
+			info.RecordingToken = interfaceToString(mapInfo["RecordingToken"])
+			info.EarliestRecording = interfaceToTime(mapInfo["EarliestRecording"])
+			info.LatestRecording = interfaceToTime(mapInfo["LatestRecording"])
+			info.Content = interfaceToString(mapInfo["Content"])
+			info.RecordingStatus = interfaceToString(mapInfo["RecordingStatus"])
+
+			if mapSource, ok := mapInfo["Source"].(map[string]interface{}); ok {
+				info.Source.SourceID = interfaceToString(mapSource["SourceId"])
+				info.Source.Name = interfaceToString(mapSource["Name"])
+				info.Source.Location = interfaceToString(mapSource["Location"])
+				info.Source.Description = interfaceToString(mapSource["Description"])
+				info.Source.Address = interfaceToString(mapSource["Address"])
+			}
+
+			info.Tracks = []TrackInformation{}
+			for _, mapTrack := range interfaceToMaps(mapInfo["Track"]) {
+				info.Tracks = append(info.Tracks, TrackInformation{
+					TrackToken:  interfaceToString(mapTrack["TrackToken"]),
+					TrackType:   interfaceToString(mapTrack["TrackType"]),
+					Description: interfaceToString(mapTrack["Description"]),
+					DataFrom:    interfaceToTime(mapTrack["DataFrom"]),
+					DataTo:      interfaceToTime(mapTrack["DataTo"]),
+				})
+			}
+
+			result.RecordingInformation = append(result.RecordingInformation, info)
+		}
+	}
+
+	return result, nil
+}
+
+// FindEvents starts a search session for events between start and end.
+// Search goes backward if end is before start. Zero end means searching
+// until the end of recordings. TopicFilter is an optional topic expression,
+// e.g. tns1:VideoSource/MotionAlarm. Results can be fetched using
+// GetEventSearchResults.
+func (device Device) FindEvents(start, end time.Time, scope SearchScope, topicFilter string,
+	includeStartState bool, maxMatches int, keepAlive time.Duration) (string, error) {
+	// Create SOAP
+	body := `<tse:FindEvents>
+		<tse:StartPoint>` + start.UTC().Format(time.RFC3339) + `</tse:StartPoint>`
+
+	if !end.IsZero() {
+		body += `<tse:EndPoint>` + end.UTC().Format(time.RFC3339) + `</tse:EndPoint>`
+	}
+
+	body += `<tse:Scope>` + searchScopeXML(scope) + `</tse:Scope>
+		<tse:SearchFilter>`
+
+	if topicFilter != "" {
+		body += `<wsnt:TopicExpression Dialect="http://www.onvif.org/ver10/tev/topicExpression/ConcreteSet">` +
+			escapeXML(topicFilter) + `</wsnt:TopicExpression>`
+	}
+
+	body += `</tse:SearchFilter>
+		<tse:IncludeStartState>` + strconv.FormatBool(includeStartState) + `</tse:IncludeStartState>
+		` + maxMatchesXML(maxMatches) + `
+		<tse:KeepAliveTime>` + formatDuration(keepAlive) + `</tse:KeepAliveTime>
+	</tse:FindEvents>`
+
+	soap := SOAP{
+		XMLNs: searchXMLNs,
+		Body:  body,
+	}
+
+	// Send SOAP request
+	response, err := device.sendRequest(searchNamespace, soap)
+	if err != nil {
+		return "", err
+	}
+
+	// Parse response
+	token, _ := response.ValueForPathString("Envelope.Body.FindEventsResponse.SearchToken")
+	return token, nil
+}
+
+// GetEventSearchResults fetch results of an event search session.
+// It waits up to waitTime until at least minResults are available.
+func (device Device) GetEventSearchResults(searchToken string, minResults, maxResults int, waitTime time.Duration) (FindEventResults, error) {
+	// Create SOAP
+	soap := SOAP{
+		XMLNs: searchXMLNs,
+		Body: `<tse:GetEventSearchResults>
+			<tse:SearchToken>` + escapeXML(searchToken) + `</tse:SearchToken>
+			` + searchResultsLimitXML(minResults, maxResults, waitTime) + `
+		</tse:GetEventSearchResults>`,
+	}
+
+	// Send SOAP request
+	response, err := device.sendRequest(searchNamespace, soap)
+	if err != nil {
+		return FindEventResults{}, err
+	}
+
+	// Parse response to interface
+	ifaceResult, err := response.ValueForPath("Envelope.Body.GetEventSearchResultsResponse.ResultList")
+	if err != nil {
+		return FindEventResults{}, err
+	}
+
+	// Parse interface to struct
+	result := FindEventResults{Results: []FindEventResult{}}
+	if mapResult, ok := ifaceResult.(map[string]interface{}); ok {
+		result.SearchState = interfaceToString(mapResult["SearchState"])
+
+		for _, mapEvent := range interfaceToMaps(mapResult["Result"]) {
+			event := FindEventResult{}
+			event.RecordingToken = interfaceToString(mapEvent["RecordingToken"])
+			event.TrackToken = interfaceToString(mapEvent["TrackToken"])
+			event.Time = interfaceToTime(mapEvent["Time"])
+			event.StartStateEvent = interfaceToBool(mapEvent["StartStateEvent"])
+
+			if mapNotification, ok := mapEvent["Event"].(map[string]interface{}); ok {
+				switch topic := mapNotification["Topic"].(type) {
+				case string:
+					event.Topic = topic
+				case map[string]interface{}:
+					event.Topic = interfaceToString(topic["#text"])
+				}
+
+				if mapMessage, ok := mapNotification["Message"].(map[string]interface{}); ok {
+					if mapMessage, ok := mapMessage["Message"].(map[string]interface{}); ok {
+						event.Source = interfaceToSimpleItems(mapMessage["Source"])
+						event.Data = interfaceToSimpleItems(mapMessage["Data"])
+					}
+				}
+			}
+
+			result.Results = append(result.Results, event)
+		}
+	}
+
+	return result, nil
+}
+
+// GetSearchState fetch state of a search session
+func (device Device) GetSearchState(searchToken string) (string, error) {
+	// Create SOAP
+	soap := SOAP{
+		XMLNs: searchXMLNs,
+		Body: `<tse:GetSearchState>
+			<tse:SearchToken>` + escapeXML(searchToken) + `</tse:SearchToken>
+		</tse:GetSearchState>`,
+	}
+
+	// Send SOAP request
+	response, err := device.sendRequest(searchNamespace, soap)
+	if err != nil {
+		return "", err
+	}
+
+	// Parse response
+	state, _ := response.ValueForPathString("Envelope.Body.GetSearchStateResponse.State")
+	return state, nil
+}
+
+// EndSearch ends a search session and returns the point in time
+// where the search has reached
+func (device Device) EndSearch(searchToken string) (time.Time, error) {
+	// Create SOAP
+	soap := SOAP{
+		XMLNs: searchXMLNs,
+		Body: `<tse:EndSearch>
+			<tse:SearchToken>` + escapeXML(searchToken) + `</tse:SearchToken>
+		</tse:EndSearch>`,
+	}
+
+	// Send SOAP request
+	response, err := device.sendRequest(searchNamespace, soap)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	// Parse response
+	endpoint, _ := response.ValueForPath("Envelope.Body.EndSearchResponse.Endpoint")
+	return interfaceToTime(endpoint), nil
+}
+
+// SearchRecordings searches recordings that match the scope and waits until
+// the search is completed, or returns the results found so far after timeout.
+func (device Device) SearchRecordings(scope SearchScope, timeout time.Duration) ([]RecordingInformation, error) {
+	// Start search session
+	searchToken, err := device.FindRecordings(scope, 0, timeout+time.Minute)
+	if err != nil {
+		return nil, err
+	}
+	defer device.EndSearch(searchToken)
+
+	// Poll the results until search is completed
+	results := []RecordingInformation{}
+	deadline := time.Now().Add(timeout)
+	for {
+		result, err := device.GetRecordingSearchResults(searchToken, 1, 0, searchWaitTime(deadline))
+		if err != nil {
+			return results, err
+		}
+
+		results = append(results, result.RecordingInformation...)
+		if result.SearchState == SearchStateCompleted {
+			return results, nil
+		}
+
+		if time.Now().After(deadline) {
+			return results, errSearchTimeout
+		}
+
+		// Don't flood device that returns immediately without any result
+		if len(result.RecordingInformation) == 0 {
+			time.Sleep(searchWaitTime(deadline) / 4)
+		}
+	}
+}
+
+// SearchEvents searches events between start and end and waits until
+// the search is completed, or returns the results found so far after timeout.
+func (device Device) SearchEvents(start, end time.Time, scope SearchScope, topicFilter string, timeout time.Duration) ([]FindEventResult, error) {
+	// Start search session
+	searchToken, err := device.FindEvents(start, end, scope, topicFilter, false, 0, timeout+time.Minute)
+	if err != nil {
+		return nil, err
+	}
+	defer device.EndSearch(searchToken)
+
+	// Poll the results until search is completed
+	results := []FindEventResult{}
+	deadline := time.Now().Add(timeout)
+	for {
+		result, err := device.GetEventSearchResults(searchToken, 1, 0, searchWaitTime(deadline))
+		if err != nil {
+			return results, err
+		}
+
+		results = append(results, result.Results...)
+		if result.SearchState == SearchStateCompleted {
+			return results, nil
+		}
+
+		if time.Now().After(deadline) {
+			return results, errSearchTimeout
+		}
+
+		// Don't flood device that returns immediately without any result
+		if len(result.Results) == 0 {
+			time.Sleep(searchWaitTime(deadline) / 4)
+		}
+	}
+}
+
+// searchWaitTime returns how long device may wait for search results. It's
+// capped to maxSearchWaitTime so a single request doesn't hit HTTP timeout.
+func searchWaitTime(deadline time.Time) time.Duration {
+	waitTime := time.Until(deadline)
+	if waitTime > maxSearchWaitTime {
+		waitTime = maxSearchWaitTime
+	}
+
+	if waitTime < 0 {
+		return 0
+	}
+
+	return waitTime
+}
+
+// searchScopeXML creates content of tt:SearchScope
+func searchScopeXML(scope SearchScope) string {
+	result := ""
+	for _, source := range scope.IncludedSources {
+		result += `<tt:IncludedSources><tt:Token>` + escapeXML(source) + `</tt:Token></tt:IncludedSources>`
+	}
+
+	for _, recording := range scope.IncludedRecordings {
+		result += `<tt:IncludedRecordings>` + escapeXML(recording) + `</tt:IncludedRecordings>`
+	}
+
+	if scope.RecordingInformationFilter != "" {
+		result += `<tt:RecordingInformationFilter>` + escapeXML(scope.RecordingInformationFilter) + `</tt:RecordingInformationFilter>`
+	}
+
+	return result
+}
+
+// maxMatchesXML creates optional tse:MaxMatches
+func maxMatchesXML(maxMatches int) string {
+	if maxMatches <= 0 {
+		return ""
+	}
+	return `<tse:MaxMatches>` + strconv.Itoa(maxMatches) + `</tse:MaxMatches>`
+}
+
+// searchResultsLimitXML creates tse:MinResults, tse:MaxResults and tse:WaitTime
+func searchResultsLimitXML(minResults, maxResults int, waitTime time.Duration) string {
+	result := ""
+	if minResults > 0 {
+		result += `<tse:MinResults>` + strconv.Itoa(minResults) + `</tse:MinResults>`
+	}
+
+	if maxResults > 0 {
+		result += `<tse:MaxResults>` + strconv.Itoa(maxResults) + `</tse:MaxResults>`
+	}
+
+	if waitTime > 0 {
+		result += `<tse:WaitTime>` + formatDuration(waitTime) + `</tse:WaitTime>`
+	}
+
+	return result
+}
@@ -0,0 +1,236 @@
+package onvif
+
+import (
+	"fmt"
+	"log"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/krabiswabbie/go-onvif/onviftest"
+)
+
+func TestSearchRecordings(t *testing.T) {
+	log.Println("Test SearchRecordings")
+
+	res, err := testDevice.SearchRecordings(SearchScope{}, 10*time.Second)
+	if err != nil {
+		t.Error(err)
+	}
+
+	js := prettyJSON(&res)
+	fmt.Println(js)
+}
+
+func TestSearchEvents(t *testing.T) {
+	log.Println("Test SearchEvents")
+
+	end := time.Now()
+	start := end.Add(-24 * time.Hour)
+	res, err := testDevice.SearchEvents(start, end, SearchScope{}, "", 10*time.Second)
+	if err != nil {
+		t.Error(err)
+	}
+
+	js := prettyJSON(&res)
+	fmt.Println(js)
+}
+
+// searchResultsHandler responds to search results requests with the batches one by one
+func searchResultsHandler(operation string, batches []string) onviftest.Handler {
+	var mutex sync.Mutex
+	return func(onviftest.Request) (string, error) {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		batch := batches[0]
+		if len(batches) > 1 {
+			batches = batches[1:]
+		}
+
+		return `<tse:` + operation + `Response xmlns:tse="http://www.onvif.org/ver10/search/wsdl">
+			<tse:ResultList>` + batch + `</tse:ResultList>
+		</tse:` + operation + `Response>`, nil
+	}
+}
+
+func TestSearchRecordingsBatches(t *testing.T) {
+	server := onviftest.NewServer()
+	defer server.Close()
+
+	server.HandleBody("FindRecordings", `<tse:FindRecordingsResponse xmlns:tse="http://www.onvif.org/ver10/search/wsdl">
+		<tse:SearchToken>search0</tse:SearchToken>
+	</tse:FindRecordingsResponse>`)
+	server.Handle("GetRecordingSearchResults", searchResultsHandler("GetRecordingSearchResults", []string{
+		`<tt:SearchState>Searching</tt:SearchState>
+		<tt:RecordingInformation>
+			<tt:RecordingToken>recording0</tt:RecordingToken>
+			<tt:Source>
+				<tt:SourceId>http://192.168.1.10/onvif/device_service</tt:SourceId>
+				<tt:Name>Front Door</tt:Name>
+				<tt:Location>Garage</tt:Location>
+				<tt:Description>Camera 1</tt:Description>
+				<tt:Address>http://192.168.1.10/onvif/device_service</tt:Address>
+			</tt:Source>
+			<tt:EarliestRecording>2026-10-01T10:00:00Z</tt:EarliestRecording>
+			<tt:LatestRecording>2026-10-01T11:00:00Z</tt:LatestRecording>
+			<tt:Content>Motion</tt:Content>
+			<tt:Track>
+				<tt:TrackToken>VIDEO001</tt:TrackToken>
+				<tt:TrackType>Video</tt:TrackType>
+				<tt:Description>Video track</tt:Description>
+				<tt:DataFrom>2026-10-01T10:00:00Z</tt:DataFrom>
+				<tt:DataTo>2026-10-01T11:00:00Z</tt:DataTo>
+			</tt:Track>
+			<tt:RecordingStatus>Stopped</tt:RecordingStatus>
+		</tt:RecordingInformation>`,
+		`<tt:SearchState>Searching</tt:SearchState>
+		<tt:RecordingInformation><tt:RecordingToken>recording1</tt:RecordingToken></tt:RecordingInformation>
+		<tt:RecordingInformation><tt:RecordingToken>recording2</tt:RecordingToken></tt:RecordingInformation>`,
+		`<tt:SearchState>Completed</tt:SearchState>`,
+	}))
+	server.HandleBody("EndSearch", `<tse:EndSearchResponse xmlns:tse="http://www.onvif.org/ver10/search/wsdl">
+		<tse:Endpoint>2026-10-01T11:00:00Z</tse:Endpoint>
+	</tse:EndSearchResponse>`)
+
+	device := Device{XAddr: server.XAddr()}
+	scope := SearchScope{IncludedSources: []string{"VideoSource_1"}, IncludedRecordings: []string{"recording<0>"}}
+	results, err := device.SearchRecordings(scope, 10*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Scope is escaped, and search session is kept alive longer than the search
+	request, _ := server.LastRequest("FindRecordings")
+	expected := `<tse:FindRecordings><tse:Scope>` +
+		`<tt:IncludedSources><tt:Token>VideoSource_1</tt:Token></tt:IncludedSources>` +
+		`<tt:IncludedRecordings>recording&lt;0&gt;</tt:IncludedRecordings>` +
+		`</tse:Scope><tse:KeepAliveTime>PT70S</tse:KeepAliveTime></tse:FindRecordings>`
+	if !strings.Contains(request.Envelope, expected) {
+		t.Errorf("unexpected request %s", request.Envelope)
+	}
+
+	// Results of all the batches are collected until search is completed
+	if count := countRequests(server, "GetRecordingSearchResults"); count != 3 {
+		t.Errorf("expected 3 requests of results, got %d", count)
+	}
+
+	request, _ = server.LastRequest("GetRecordingSearchResults")
+	if !strings.Contains(request.Envelope, `<tse:SearchToken>search0</tse:SearchToken><tse:MinResults>1</tse:MinResults><tse:WaitTime>PT2S</tse:WaitTime>`) {
+		t.Errorf("unexpected request %s", request.Envelope)
+	}
+
+	tokens := []string{}
+	for _, result := range results {
+		tokens = append(tokens, result.RecordingToken)
+	}
+
+	if !reflect.DeepEqual(tokens, []string{"recording0", "recording1", "recording2"}) {
+		t.Fatalf("unexpected recordings %v", tokens)
+	}
+
+	from := time.Date(2026, 10, 1, 10, 0, 0, 0, time.UTC)
+	to := from.Add(time.Hour)
+	expectedInfo := RecordingInformation{
+		RecordingToken: "recording0",
+		Source: RecordingSource{
+			SourceID:    "http://192.168.1.10/onvif/device_service",
+			Name:        "Front Door",
+			Location:    "Garage",
+			Description: "Camera 1",
+			Address:     "http://192.168.1.10/onvif/device_service",
+		},
+		EarliestRecording: from,
+		LatestRecording:   to,
+		Content:           "Motion",
+		Tracks:            []TrackInformation{{TrackToken: "VIDEO001", TrackType: "Video", Description: "Video track", DataFrom: from, DataTo: to}},
+		RecordingStatus:   "Stopped",
+	}
+	if !reflect.DeepEqual(results[0], expectedInfo) {
+		t.Errorf("expected %+v, got %+v", expectedInfo, results[0])
+	}
+
+	// Search session is ended
+	request, ok := server.LastRequest("EndSearch")
+	if !ok || !strings.Contains(request.Envelope, `<tse:SearchToken>search0</tse:SearchToken>`) {
+		t.Errorf("search session not ended")
+	}
+}
+
+func TestSearchEventsBatches(t *testing.T) {
+	server := onviftest.NewServer()
+	defer server.Close()
+
+	eventXML := func(recording string, startState bool) string {
+		return `<tt:Result>
+			<tt:RecordingToken>` + recording + `</tt:RecordingToken>
+			<tt:TrackToken>VIDEO001</tt:TrackToken>
+			<tt:Time>2026-10-01T10:30:00Z</tt:Time>
+			<tt:Event>
+				<wsnt:Topic Dialect="http://www.onvif.org/ver10/tev/topicExpression/ConcreteSet">tns1:VideoSource/MotionAlarm</wsnt:Topic>
+				<wsnt:Message>
+					<tt:Message UtcTime="2026-10-01T10:30:00Z">
+						<tt:Source><tt:SimpleItem Name="Source" Value="VideoSource_1"/></tt:Source>
+						<tt:Data><tt:SimpleItem Name="State" Value="true"/></tt:Data>
+					</tt:Message>
+				</wsnt:Message>
+			</tt:Event>
+			<tt:StartStateEvent>` + strconv.FormatBool(startState) + `</tt:StartStateEvent>
+		</tt:Result>`
+	}
+
+	server.HandleBody("FindEvents", `<tse:FindEventsResponse xmlns:tse="http://www.onvif.org/ver10/search/wsdl">
+		<tse:SearchToken>search1</tse:SearchToken>
+	</tse:FindEventsResponse>`)
+	server.Handle("GetEventSearchResults", searchResultsHandler("GetEventSearchResults", []string{
+		`<tt:SearchState>Searching</tt:SearchState>` + eventXML("recording0", true),
+		`<tt:SearchState>Searching</tt:SearchState>` + eventXML("recording1", false) + eventXML("recording2", false),
+		`<tt:SearchState>Completed</tt:SearchState>`,
+	}))
+	server.HandleBody("EndSearch", `<tse:EndSearchResponse xmlns:tse="http://www.onvif.org/ver10/search/wsdl"/>`)
+
+	device := Device{XAddr: server.XAddr()}
+	start := time.Date(2026, 10, 1, 10, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour)
+	results, err := device.SearchEvents(start, end, SearchScope{}, "tns1:VideoSource/MotionAlarm", 10*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	request, _ := server.LastRequest("FindEvents")
+	expected := `<tse:FindEvents><tse:StartPoint>2026-10-01T10:00:00Z</tse:StartPoint>` +
+		`<tse:EndPoint>2026-10-01T11:00:00Z</tse:EndPoint><tse:Scope></tse:Scope><tse:SearchFilter>` +
+		`<wsnt:TopicExpression Dialect="http://www.onvif.org/ver10/tev/topicExpression/ConcreteSet">tns1:VideoSource/MotionAlarm</wsnt:TopicExpression>` +
+		`</tse:SearchFilter><tse:IncludeStartState>false</tse:IncludeStartState><tse:KeepAliveTime>PT70S</tse:KeepAliveTime></tse:FindEvents>`
+	if !strings.Contains(request.Envelope, expected) {
+		t.Errorf("unexpected request %s", request.Envelope)
+	}
+
+	if count := countRequests(server, "GetEventSearchResults"); count != 3 {
+		t.Errorf("expected 3 requests of results, got %d", count)
+	}
+
+	if len(results) != 3 || results[1].RecordingToken != "recording1" || results[2].RecordingToken != "recording2" {
+		t.Fatalf("unexpected results %+v", results)
+	}
+
+	expectedEvent := FindEventResult{
+		RecordingToken:  "recording0",
+		TrackToken:      "VIDEO001",
+		Time:            start.Add(30 * time.Minute),
+		Topic:           "tns1:VideoSource/MotionAlarm",
+		Source:          []SimpleItem{{Name: "Source", Value: "VideoSource_1"}},
+		Data:            []SimpleItem{{Name: "State", Value: "true"}},
+		StartStateEvent: true,
+	}
+	if !reflect.DeepEqual(results[0], expectedEvent) {
+		t.Errorf("expected %+v, got %+v", expectedEvent, results[0])
+	}
+
+	if _, ok := server.LastRequest("EndSearch"); !ok {
+		t.Error("search session not ended")
+	}
+}
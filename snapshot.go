@@ -0,0 +1,18 @@
+package onvif
+
+// GetSnapshot fetch JPEG snapshot of a media profile
+func (device Device) GetSnapshot(profileToken string) ([]byte, error) {
+	snapshotURI, err := device.GetSnapshotURI(profileToken)
+	if err != nil {
+		return nil, err
+	}
+
+	return device.FetchSnapshot(snapshotURI.URI)
+}
+
+// FetchSnapshot downloads snapshot from URI returned by GetSnapshotURI.
+// Credentials of the device are sent using HTTP digest or basic
+// authentication, depending on which one is requested by camera.
+func (device Device) FetchSnapshot(snapshotURI string) ([]byte, error) {
+	return device.download(snapshotURI)
+}
@@ -0,0 +1,87 @@
+package onvif
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestFetchSnapshotDigest(t *testing.T) {
+	image := []byte("\xff\xd8\xff\xe0JFIF")
+	md5Hex := func(text string) string {
+		sum := md5.Sum([]byte(text))
+		return hex.EncodeToString(sum[:])
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization := r.Header.Get("Authorization")
+		if authorization == "" {
+			w.Header().Set("WWW-Authenticate", `Digest realm="camera", qop="auth,auth-int", nonce="abc123", opaque="xyz"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		params := make(map[string]string)
+		for _, match := range rxDigestParam.FindAllStringSubmatch(strings.TrimPrefix(authorization, "Digest "), -1) {
+			params[match[1]] = match[2] + match[3]
+		}
+
+		ha1 := md5Hex("admin:camera:secret")
+		ha2 := md5Hex("GET:" + params["uri"])
+		expected := md5Hex(ha1 + ":abc123:" + params["nc"] + ":" + params["cnonce"] + ":auth:" + ha2)
+		if params["response"] != expected || params["opaque"] != "xyz" || params["uri"] != "/snapshot.jpg?profile=1" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		w.Header().Set("Content-Type", "image/jpeg")
+		w.Write(image)
+	}))
+	defer server.Close()
+
+	device := Device{XAddr: server.URL + "/onvif/device_service", User: "admin", Password: "secret"}
+	snapshot, err := device.FetchSnapshot(server.URL + "/snapshot.jpg?profile=1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(snapshot, image) {
+		t.Errorf("unexpected snapshot %q", snapshot)
+	}
+
+	device.Password = "wrong"
+	if _, err := device.FetchSnapshot(server.URL + "/snapshot.jpg?profile=1"); err == nil {
+		t.Error("expected error with wrong password")
+	}
+}
+
+func TestFetchSnapshotPreferDigest(t *testing.T) {
+	tests := [][]string{
+		{`Basic realm="camera"`, `Digest realm="camera", nonce="abc123"`},
+		{`Digest realm="camera", nonce="abc123", algorithm=SHA-512-256`, `Digest realm="camera", nonce="abc123"`},
+	}
+
+	for _, challenges := range tests {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !strings.HasPrefix(r.Header.Get("Authorization"), "Digest ") {
+				for _, challenge := range challenges {
+					w.Header().Add("WWW-Authenticate", challenge)
+				}
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+
+			w.Write([]byte("snapshot"))
+		}))
+
+		device := Device{XAddr: server.URL + "/onvif/device_service", User: "admin", Password: "secret"}
+		if _, err := device.FetchSnapshot(server.URL + "/snapshot.jpg"); err != nil {
+			t.Errorf("challenges %q: %v", challenges, err)
+		}
+		server.Close()
+	}
+}
@@ -0,0 +1,128 @@
+package onvif
+
+import (
+	"context"
+	"crypto/sha256"
+	"time"
+)
+
+const (
+	// DefaultSnapshotInterval is used if interval of SnapshotPoller is not positive
+	DefaultSnapshotInterval = time.Second
+
+	// DefaultSnapshotMaxBackoff is the longest interval between attempts
+	// to fetch snapshot while camera keeps failing
+	DefaultSnapshotMaxBackoff = time.Minute
+)
+
+// SnapshotPoller fetches JPEG snapshots of a media profile periodically, e.g.
+// to show thumbnails of many cameras. A snapshot is only delivered if it's
+// different from the previous one. After a failure, the interval is doubled
+// on each consecutive failure up to MaxBackoff, and it's restored once a
+// snapshot is fetched again. Zero MaxBackoff means DefaultSnapshotMaxBackoff.
+type SnapshotPoller struct {
+	Device       Device
+	ProfileToken string
+	Interval     time.Duration
+	MaxBackoff   time.Duration
+}
+
+// NewSnapshotPoller creates poller that fetches snapshots of the profile on the interval
+func NewSnapshotPoller(device Device, profileToken string, interval time.Duration) *SnapshotPoller {
+	return &SnapshotPoller{
+		Device:       device,
+		ProfileToken: profileToken,
+		Interval:     interval,
+		MaxBackoff:   DefaultSnapshotMaxBackoff,
+	}
+}
+
+// Start fetches snapshots until context is done, and sends each new snapshot,
+// as well as each failure, to the returned channel. The first snapshot is
+// fetched immediately. The channel is closed when context is done.
+func (poller *SnapshotPoller) Start(ctx context.Context) <-chan Snapshot {
+	snapshots := make(chan Snapshot, 1)
+	go func() {
+		defer close(snapshots)
+
+		interval := poller.Interval
+		if interval <= 0 {
+			interval = DefaultSnapshotInterval
+		}
+
+		snapshotURI := ""
+		var lastHash [sha256.Size]byte
+		delay := interval
+
+		for {
+			snapshot := Snapshot{ProfileToken: poller.ProfileToken}
+			snapshotURI, snapshot.Image, snapshot.Err = poller.fetch(ctx, snapshotURI)
+			snapshot.Time = time.Now()
+
+			deliver := true
+			if snapshot.Err != nil {
+				// URI is fetched again after failure, since it might change, e.g. after
+				// reboot, and the first snapshot after recovery is always delivered
+				snapshotURI = ""
+				lastHash = [sha256.Size]byte{}
+				delay = poller.backoff(delay, interval)
+			} else {
+				hash := sha256.Sum256(snapshot.Image)
+				deliver = hash != lastHash
+				lastHash = hash
+				delay = interval
+			}
+
+			if deliver {
+				select {
+				case snapshots <- snapshot:
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			select {
+			case <-time.After(delay):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return snapshots
+}
+
+// fetch fetches snapshot from the URI, which is requested from camera
+// first if it's empty. It returns the URI to be used next time.
+// Download of the snapshot is cancelled when context is done.
+func (poller *SnapshotPoller) fetch(ctx context.Context, snapshotURI string) (string, []byte, error) {
+	if snapshotURI == "" {
+		mediaURI, err := poller.Device.GetSnapshotURI(poller.ProfileToken)
+		if err != nil {
+			return "", nil, err
+		}
+		snapshotURI = mediaURI.URI
+	}
+
+	image, err := poller.Device.downloadContext(ctx, snapshotURI)
+	return snapshotURI, image, err
+}
+
+// backoff returns the delay before the next attempt after a failure
+func (poller *SnapshotPoller) backoff(delay, interval time.Duration) time.Duration {
+	delay *= 2
+	if delay < interval {
+		delay = interval
+	}
+
+	maxBackoff := poller.MaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = DefaultSnapshotMaxBackoff
+	}
+
+	if delay > maxBackoff {
+		delay = maxBackoff
+	}
+
+	return delay
+}
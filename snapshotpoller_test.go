@@ -0,0 +1,150 @@
+package onvif
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/krabiswabbie/go-onvif/onviftest"
+)
+
+func TestSnapshotPoller(t *testing.T) {
+	server := onviftest.NewServer()
+	defer server.Close()
+
+	server.SetSnapshot([]byte("frame 1"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	poller := NewSnapshotPoller(Device{XAddr: server.XAddr()}, "Profile_1", 5*time.Millisecond)
+	snapshots := poller.Start(ctx)
+
+	next := func() Snapshot {
+		select {
+		case snapshot := <-snapshots:
+			return snapshot
+		case <-time.After(2 * time.Second):
+			t.Fatal("no snapshot delivered")
+			return Snapshot{}
+		}
+	}
+
+	snapshot := next()
+	if snapshot.Err != nil || !bytes.Equal(snapshot.Image, []byte("frame 1")) || snapshot.ProfileToken != "Profile_1" {
+		t.Fatalf("unexpected snapshot %+v", snapshot)
+	}
+
+	// Identical frames aren't delivered
+	time.Sleep(30 * time.Millisecond)
+	select {
+	case snapshot := <-snapshots:
+		t.Fatalf("identical snapshot delivered %s", snapshot.Image)
+	default:
+	}
+
+	server.SetSnapshot([]byte("frame 2"))
+	if snapshot := next(); !bytes.Equal(snapshot.Image, []byte("frame 2")) {
+		t.Fatalf("unexpected snapshot %s", snapshot.Image)
+	}
+
+	// Snapshot URI is only requested once while snapshots are fetched successfully
+	count := 0
+	for _, request := range server.Requests() {
+		if request.Operation == "GetSnapshotUri" {
+			count++
+		}
+	}
+
+	if count != 1 {
+		t.Errorf("expected GetSnapshotUri to be requested once, got %d", count)
+	}
+
+	cancel()
+	for range snapshots {
+	}
+}
+
+func TestSnapshotPollerBackoff(t *testing.T) {
+	poller := NewSnapshotPoller(Device{}, "Profile_1", time.Second)
+	poller.MaxBackoff = 3 * time.Second
+
+	delays := []time.Duration{}
+	delay := time.Second
+	for i := 0; i < 3; i++ {
+		delay = poller.backoff(delay, poller.Interval)
+		delays = append(delays, delay)
+	}
+
+	if delays[0] != 2*time.Second || delays[1] != 3*time.Second || delays[2] != 3*time.Second {
+		t.Errorf("unexpected delays %v", delays)
+	}
+
+	// Poller that isn't created by NewSnapshotPoller is limited by the default
+	poller = &SnapshotPoller{Interval: time.Second}
+	if delay := poller.backoff(DefaultSnapshotMaxBackoff, poller.Interval); delay != DefaultSnapshotMaxBackoff {
+		t.Errorf("expected delay %v, got %v", DefaultSnapshotMaxBackoff, delay)
+	}
+}
+
+func TestSnapshotPollerCancel(t *testing.T) {
+	// Camera that never finishes sending the snapshot
+	release := make(chan struct{})
+	defer close(release)
+	hung := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer hung.Close()
+
+	server := onviftest.NewServer()
+	defer server.Close()
+
+	server.Handle("GetSnapshotUri", func(onviftest.Request) (string, error) {
+		return `<trt:GetSnapshotUriResponse><trt:MediaUri><tt:Uri>` + hung.URL + `/snapshot.jpg</tt:Uri></trt:MediaUri></trt:GetSnapshotUriResponse>`, nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	poller := NewSnapshotPoller(Device{XAddr: server.XAddr(), Timeout: time.Minute}, "Profile_1", time.Millisecond)
+	snapshots := poller.Start(ctx)
+
+	time.Sleep(50 * time.Millisecond)
+	select {
+	case snapshot := <-snapshots:
+		t.Fatalf("unexpected snapshot %+v", snapshot)
+	default:
+	}
+	cancel()
+
+	// Download in progress is cancelled, so the channel is closed right away
+	select {
+	case <-snapshots:
+	case <-time.After(2 * time.Second):
+		t.Fatal("download not cancelled")
+	}
+}
+
+func TestSnapshotPollerError(t *testing.T) {
+	server := onviftest.NewServer()
+	defer server.Close()
+
+	server.HandleFault("GetSnapshotUri", onviftest.ReceiverFault("ter:Action", "Not ready"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	snapshots := NewSnapshotPoller(Device{XAddr: server.XAddr()}, "Profile_1", time.Millisecond).Start(ctx)
+	select {
+	case snapshot := <-snapshots:
+		if snapshot.Err == nil || snapshot.Image != nil {
+			t.Errorf("expected error, got %+v", snapshot)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("error not delivered")
+	}
+}
@@ -3,86 +3,463 @@ package onvif
 import (
 	"bytes"
 	"crypto/sha1"
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/base64"
-	"errors"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"net/url"
 	"regexp"
+	"strings"
+	"sync"
 	"time"
 
-	"github.com/deepch/mxj"
 	"github.com/deepch/go.uuid"
+	"github.com/deepch/mxj"
 )
 
-var httpClient = &http.Client{Timeout: time.Second * 4}
+// maxIdleConnsPerHost is the number of idle connections kept alive to each
+// camera. It's higher than the default of net/http, so concurrent requests,
+// e.g. PTZ commands sent while events are pulled, don't have to reconnect.
+const maxIdleConnsPerHost = 8
+
+// drainLimit is the number of bytes read after the response is parsed, e.g.
+// trailing whitespace, before the connection is returned to be reused
+const drainLimit = 4 << 10
+
+var httpClient = &http.Client{Timeout: time.Second * 4, Transport: newTransport(nil)}
+
+// maxSharedTransports is the number of TLS configurations whose transports are
+// kept to be shared by requests. Once it's exceeded, one of them is dropped, so
+// configurations that are no longer used don't keep their connections forever.
+const maxSharedTransports = 64
+
+var (
+	transportsMutex sync.Mutex
+
+	// transports are the transports used by requests with TLS configuration
+	transports = map[*tls.Config]*http.Transport{}
+
+	// tlsConfigs are TLS configurations of devices, by their settings
+	tlsConfigs = map[tlsConfigKey]*tls.Config{}
+)
 
 // SOAP contains data for SOAP request
 type SOAP struct {
+	Header   string
 	Body     string
 	XMLNs    []string
 	User     string
 	Password string
 	TokenAge time.Duration
+
+	// TLSConfig is used when the request is sent through HTTPS.
+	// If it's nil, the default TLS configuration is used.
+	TLSConfig *tls.Config
+
+	// HTTPClient is used to send the request. If it's nil, the package
+	// default client is used, configured with TLSConfig.
+	HTTPClient *http.Client
+
+	// Hook, if any, is notified about the request and its response
+	Hook RequestHook
+
+	// Timeout, if any, overrides timeout of the HTTP client
+	Timeout time.Duration
+
+	// Retry configures how the request is retried after transient network failure
+	Retry RetryPolicy
+
+	// Instrumentation, if any, tracks the request including its retries
+	Instrumentation Instrumentation
+
+	// Limiter, if any, delays the request until the camera can handle it
+	Limiter *RequestLimiter
 }
 
-// SendRequest sends SOAP request to xAddr
+// SendRequest sends SOAP request to xAddr. The request is retried
+// according to retry policy, with new user token on each attempt.
+// Only read-only operations are retried once the request is sent.
 func (soap SOAP) SendRequest(xaddr string) (mxj.Map, error) {
+	var span RequestSpan
+	start := time.Now()
+	info := newRequestInfo(soap, xaddr, "")
+	if soap.Instrumentation != nil {
+		span = soap.Instrumentation.StartRequest(info)
+	}
+
+	for retry := 0; ; retry++ {
+		response, err := soap.sendRequestOnce(xaddr)
+		if err == nil || retry >= soap.Retry.MaxRetries || !isRetryable(err, info.Operation) {
+			if span != nil {
+				span.End(RequestResult{Attempts: retry + 1, Elapsed: time.Since(start), Err: err})
+			}
+			return response, err
+		}
+
+		time.Sleep(soap.Retry.backoff(retry))
+	}
+}
+
+// sendRequestOnce sends SOAP request to xAddr without retry
+func (soap SOAP) sendRequestOnce(xaddr string) (mxj.Map, error) {
 	// Create SOAP request
+	request, urlXAddr, err := soap.prepareRequest(xaddr)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create HTTP request
+	buffer := bytes.NewBuffer([]byte(request))
+	req, err := http.NewRequest("POST", urlXAddr, buffer)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/soap+xml")
+	req.Header.Set("Charset", "utf-8")
+
+	// Time spent waiting for the limiter counts towards timeout of the request
+	client := soap.client()
+	start := time.Now()
+	release, err := soap.Limiter.acquire(client.Timeout)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	if waited := time.Since(start); client.Timeout > 0 && waited > 0 {
+		if waited >= client.Timeout {
+			return nil, errLimiterTimeout
+		}
+
+		clientCopy := *client
+		clientCopy.Timeout -= waited
+		client = &clientCopy
+	}
+
+	return sendHTTPRequest(client, req, soap.Hook, newRequestInfo(soap, urlXAddr, request))
+}
+
+// client returns HTTP client used to send the request
+func (soap SOAP) client() *http.Client {
+	client := soap.HTTPClient
+	if client == nil {
+		client = tlsClient(httpClient, soap.TLSConfig)
+	}
+
+	if soap.Timeout > 0 && soap.Timeout != client.Timeout {
+		clientCopy := *client
+		clientCopy.Timeout = soap.Timeout
+		client = &clientCopy
+	}
+
+	return client
+}
+
+// sendRequest sends SOAP request to the service with specified namespace,
+// using credentials of the device
+func (device Device) sendRequest(namespace string, soap SOAP) (mxj.Map, error) {
+	return device.sendRequestTo(device.serviceXAddr(namespace), soap)
+}
+
+// sendRequestTo sends SOAP request to xAddr using credentials of the device
+func (device Device) sendRequestTo(xaddr string, soap SOAP) (mxj.Map, error) {
+	soap.User = device.User
+	soap.Password = device.Password
+	soap.TLSConfig = device.tlsConfig()
+	soap.HTTPClient = device.clientFor(httpClient)
+	soap.Hook = device.Hook
+	soap.Timeout = device.Timeout
+	soap.Retry = device.Retry
+	soap.Instrumentation = device.Instrumentation
+	soap.Limiter = device.Limiter
+
+	if device.Cache != nil {
+		return device.Cache.sendRequest(xaddr, soap, func() (mxj.Map, error) {
+			return soap.SendRequest(xaddr)
+		})
+	}
+
+	return soap.SendRequest(xaddr)
+}
+
+// httpClient returns HTTP client used to send plain HTTP requests to the
+// device, e.g. to fetch snapshot, configured like the one for SOAP requests
+func (device Device) httpClient() *http.Client {
+	soap := SOAP{
+		HTTPClient: device.clientFor(httpClient),
+		Timeout:    device.Timeout,
+	}
+	return soap.client()
+}
+
+// clientFor returns HTTP client used to send requests to the device, based on
+// the base client. The HTTP client of the device takes precedence, then the
+// transport of the device, so connections are reused between requests, and
+// finally the base client configured with TLS configuration of the device.
+func (device Device) clientFor(base *http.Client) *http.Client {
+	if device.HTTPClient != nil {
+		return device.HTTPClient
+	}
+
+	if device.transport != nil {
+		return &http.Client{
+			Timeout:   base.Timeout,
+			Transport: device.transport,
+		}
+	}
+
+	return tlsClient(base, device.tlsConfig())
+}
+
+// CloseIdleConnections closes connections to the device that are kept alive
+// but aren't in use, e.g. once the device is no longer needed. Idle connections
+// of other devices with the same TLS settings are closed too, since they share
+// the transport unless the device is created by NewDevice.
+func (device Device) CloseIdleConnections() {
+	switch {
+	case device.transport != nil:
+		device.transport.CloseIdleConnections()
+	case device.HTTPClient == nil:
+		transportsMutex.Lock()
+		defer transportsMutex.Unlock()
+
+		key := device.tlsConfigKey()
+		if tlsConfig, ok := tlsConfigs[key]; ok {
+			delete(tlsConfigs, key)
+			removeTransport(tlsConfig)
+		}
+	}
+}
+
+// tlsConfig returns TLS configuration of the device, or nil if the device
+// doesn't need any special configuration. Devices with the same settings
+// share the configuration, and thus its transport and connections.
+func (device Device) tlsConfig() *tls.Config {
+	if device.RootCAs == nil && len(device.Certificates) == 0 && !device.InsecureSkipVerify {
+		return nil
+	}
+
+	key := device.tlsConfigKey()
+	transportsMutex.Lock()
+	defer transportsMutex.Unlock()
+
+	tlsConfig, ok := tlsConfigs[key]
+	if !ok {
+		tlsConfig = &tls.Config{
+			RootCAs:            device.RootCAs,
+			Certificates:       device.Certificates,
+			InsecureSkipVerify: device.InsecureSkipVerify,
+		}
+
+		// Drop any configuration to make room for the new one
+		for oldKey, oldConfig := range tlsConfigs {
+			if len(tlsConfigs) < maxSharedTransports {
+				break
+			}
+			delete(tlsConfigs, oldKey)
+			removeTransport(oldConfig)
+		}
+		tlsConfigs[key] = tlsConfig
+	}
+
+	return tlsConfig
+}
+
+// tlsConfigKey returns key of TLS settings of the device. Certificates are
+// identified by their slice, which is shared by copies of the device.
+func (device Device) tlsConfigKey() tlsConfigKey {
+	key := tlsConfigKey{
+		rootCAs:            device.RootCAs,
+		certificatesLen:    len(device.Certificates),
+		insecureSkipVerify: device.InsecureSkipVerify,
+	}
+	if len(device.Certificates) > 0 {
+		key.certificates = &device.Certificates[0]
+	}
+
+	return key
+}
+
+// tlsConfigKey identifies TLS settings of a device
+type tlsConfigKey struct {
+	rootCAs            *x509.CertPool
+	certificates       *tls.Certificate
+	certificatesLen    int
+	insecureSkipVerify bool
+}
 
+// tlsClient returns copy of the client that uses the TLS configuration.
+// If TLS configuration is nil, the client is returned as it is.
+func tlsClient(client *http.Client, tlsConfig *tls.Config) *http.Client {
+	if tlsConfig == nil {
+		return client
+	}
+
+	return &http.Client{
+		Timeout:   client.Timeout,
+		Transport: sharedTransport(tlsConfig),
+	}
+}
+
+// sharedTransport returns transport that uses the TLS configuration. It's
+// created once for each configuration, so requests reuse connections.
+func sharedTransport(tlsConfig *tls.Config) *http.Transport {
+	transportsMutex.Lock()
+	defer transportsMutex.Unlock()
+
+	transport, ok := transports[tlsConfig]
+	if !ok {
+		// Drop any transport to make room for the new one
+		for oldConfig := range transports {
+			if len(transports) < maxSharedTransports {
+				break
+			}
+			removeTransport(oldConfig)
+		}
+
+		transport = newTransport(tlsConfig)
+		transports[tlsConfig] = transport
+	}
+
+	return transport
+}
+
+// removeTransport closes idle connections of transport of the TLS configuration,
+// then removes it. Requests still in progress aren't affected. It must be called
+// while transportsMutex is locked.
+func removeTransport(tlsConfig *tls.Config) {
+	if transport, ok := transports[tlsConfig]; ok {
+		transport.CloseIdleConnections()
+		delete(transports, tlsConfig)
+	}
+}
+
+// newTransport creates HTTP transport that keeps connections to cameras alive,
+// configured with the TLS configuration
+func newTransport(tlsConfig *tls.Config) *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConnsPerHost = maxIdleConnsPerHost
+	transport.TLSClientConfig = tlsConfig
+	return transport
+}
+
+// serviceXAddr returns XAddr of the service with specified namespace.
+// If the service is unknown, device XAddr is returned instead.
+func (device Device) serviceXAddr(namespace string) string {
+	if xaddr, ok := device.Services[namespace]; ok && xaddr != "" {
+		return xaddr
+	}
+	return device.XAddr
+}
+
+// prepareRequest creates SOAP envelope and the URL it should be posted to.
+// Credentials embedded in xAddr take precedence over the ones in SOAP.
+func (soap SOAP) prepareRequest(xaddr string) (string, string, error) {
 	// Make sure URL valid and add authentication in xAddr
-	urlXAddr, err := url.Parse(xaddr)
+	urlXAddr, err := url.Parse(normalizeZone(xaddr))
+	if err != nil {
+		return "", "", err
+	}
 
 	if urlXAddr.User != nil && urlXAddr.User.Username() != "" {
 		soap.User = urlXAddr.User.Username()
 		soap.Password, _ = urlXAddr.User.Password()
 	}
-	if err != nil {
-		return nil, err
-	}
-	request := soap.createRequest()
 
-	// Make sure URL valid and add authentication in xAddr
-	//urlXAddr, err := url.Parse(xaddr)
+	request := soap.createRequest()
 
 	if soap.User != "" {
 		urlXAddr.User = url.UserPassword(soap.User, soap.Password)
 	}
 
-	// Create HTTP request
-	buffer := bytes.NewBuffer([]byte(request))
-	req, err := http.NewRequest("POST", urlXAddr.String(), buffer)
-	req.Header.Set("Content-Type", "application/soap+xml")
-	req.Header.Set("Charset", "utf-8")
+	return request, urlXAddr.String(), nil
+}
+
+// sendHTTPRequest sends HTTP request that contains SOAP envelope,
+// then parse the response and check if it's a SOAP fault.
+// Hook, if any, is notified about the request and its result.
+func sendHTTPRequest(client *http.Client, req *http.Request, hook RequestHook, info RequestInfo) (mxj.Map, error) {
+	if hook == nil {
+		return doHTTPRequest(client, req, nil)
+	}
+
+	start := time.Now()
+	hook.OnRequest(info)
+
+	mapXML, err := doHTTPRequest(client, req, func(responseBody []byte) {
+		hook.OnResponse(info, responseBody, time.Since(start))
+	})
+	if err != nil {
+		hook.OnError(info, err, time.Since(start))
+	}
 
+	return mapXML, err
+}
+
+// doHTTPRequest sends HTTP request and parse its response.
+// onResponse, if any, is called with the raw response body.
+func doHTTPRequest(client *http.Client, req *http.Request, onResponse func([]byte)) (mxj.Map, error) {
 	// Send request
-	resp, err := httpClient.Do(req)
+	resp, err := client.Do(req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	// Read response body
-	responseBody, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
+	// Parse XML to map while it's being read, unless the raw response is needed
+	body := newLimitedReader(resp.Body)
+	contentType := resp.Header.Get("Content-Type")
+	isMultipart := strings.HasPrefix(strings.ToLower(contentType), "multipart/related")
+
+	var mapXML mxj.Map
+	if onResponse == nil && !isMultipart {
+		mapXML, err = decodeXML(body)
+
+		// Drain the rest of response, so the connection can be reused
+		io.Copy(ioutil.Discard, io.LimitReader(resp.Body, drainLimit))
+	} else {
+		mapXML, err = readXML(body, contentType, isMultipart, onResponse)
 	}
 
-	// Parse XML to map
-	mapXML, err := mxj.NewMapXml(responseBody)
 	if err != nil {
+		if err != errResponseTooLarge && resp.StatusCode == http.StatusUnauthorized {
+			return nil, notAuthorizedFault()
+		}
 		return nil, err
 	}
 
 	// Check if SOAP returns fault
-	fault, _ := mapXML.ValueForPathString("Envelope.Body.Fault.Reason.Text.#text")
-	if fault != "" {
-		return nil, errors.New(fault)
+	if fault := parseFault(mapXML, resp.StatusCode); fault != nil {
+		return nil, fault
 	}
 
 	return mapXML, nil
 }
 
+// readXML reads the whole response body and parses it to map. Response that
+// contains binary attachment is sent as MTOM message, which is reconstituted first.
+func readXML(body io.Reader, contentType string, isMultipart bool, onResponse func([]byte)) (mxj.Map, error) {
+	responseBody, err := ioutil.ReadAll(body)
+	if err != nil {
+		return nil, err
+	}
+
+	if onResponse != nil {
+		onResponse(responseBody)
+	}
+
+	if isMultipart {
+		responseBody, err = reconstituteXOP(contentType, responseBody)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return decodeXML(bytes.NewReader(responseBody))
+}
+
 func (soap SOAP) createRequest() string {
 	// Create request envelope
 	request := `<?xml version="1.0" encoding="UTF-8"?>`
@@ -95,8 +472,12 @@ func (soap SOAP) createRequest() string {
 	request += ">"
 
 	// Set request header
-	if soap.User != "" {
-		request += "<s:Header>" + soap.createUserToken() + "</s:Header>"
+	if soap.User != "" || soap.Header != "" {
+		request += "<s:Header>"
+		if soap.User != "" {
+			request += soap.createUserToken()
+		}
+		request += soap.Header + "</s:Header>"
 	}
 
 	// Set request body
@@ -0,0 +1,207 @@
+package onvif
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+const testHostnameResponse = `<?xml version="1.0" encoding="UTF-8"?>
+<s:Envelope xmlns:s="http://www.w3.org/2003/05/soap-envelope" xmlns:tds="http://www.onvif.org/ver10/device/wsdl" xmlns:tt="http://www.onvif.org/ver10/schema">
+	<s:Body>
+		<tds:GetHostnameResponse>
+			<tds:HostnameInformation>
+				<tt:FromDHCP>false</tt:FromDHCP>
+				<tt:Name>camera</tt:Name>
+			</tds:HostnameInformation>
+		</tds:GetHostnameResponse>
+	</s:Body>
+</s:Envelope>`
+
+func TestSendRequestTLS(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/soap+xml")
+		fmt.Fprint(w, testHostnameResponse)
+	}))
+	defer server.Close()
+
+	xaddr := server.URL + "/onvif/device_service"
+
+	// Self-signed certificate must be rejected by default
+	device := Device{XAddr: xaddr}
+	if _, err := device.GetHostname(); err == nil {
+		t.Error("expected certificate error without TLS configuration")
+	}
+
+	// Certificate is trusted when it's in RootCAs
+	pool := x509.NewCertPool()
+	pool.AddCert(server.Certificate())
+	device = Device{XAddr: xaddr, RootCAs: pool}
+	if res, err := device.GetHostname(); err != nil {
+		t.Error(err)
+	} else if res.Name != "camera" {
+		t.Errorf("unexpected hostname %q", res.Name)
+	}
+
+	// Verification can be skipped entirely
+	device = Device{XAddr: xaddr, InsecureSkipVerify: true}
+	if _, err := device.GetHostname(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestSendRequestKeepAlive(t *testing.T) {
+	var connections int32
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/soap+xml")
+		fmt.Fprint(w, testHostnameResponse)
+	}))
+	server.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			atomic.AddInt32(&connections, 1)
+		}
+	}
+	server.StartTLS()
+	defer server.Close()
+
+	device, err := NewDevice(server.URL, "", "", WithInsecureSkipVerify(), WithoutProbe())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer device.CloseIdleConnections()
+
+	// Copies of the device share its connections
+	for i := 0; i < 5; i++ {
+		deviceCopy := device
+		if _, err := deviceCopy.GetHostname(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if count := atomic.LoadInt32(&connections); count != 1 {
+		t.Errorf("expected a single connection, got %d", count)
+	}
+}
+
+func TestSendRequestTLSKeepAlive(t *testing.T) {
+	var connections int32
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/soap+xml")
+		fmt.Fprint(w, testHostnameResponse)
+	}))
+	server.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			atomic.AddInt32(&connections, 1)
+		}
+	}
+	server.StartTLS()
+	defer server.Close()
+
+	// Requests with the same TLS configuration share connections
+	soap := SOAP{Body: "<tds:GetHostname/>", TLSConfig: &tls.Config{InsecureSkipVerify: true}}
+	for i := 0; i < 5; i++ {
+		if _, err := soap.SendRequest(server.URL); err != nil {
+			t.Fatal(err)
+		}
+	}
+	sharedTransport(soap.TLSConfig).CloseIdleConnections()
+
+	if count := atomic.LoadInt32(&connections); count != 1 {
+		t.Errorf("expected a single connection, got %d", count)
+	}
+
+	// Devices that aren't created by NewDevice share connections too
+	atomic.StoreInt32(&connections, 0)
+	for i := 0; i < 5; i++ {
+		device := Device{XAddr: server.URL + "/onvif/device_service", InsecureSkipVerify: true}
+		if _, err := device.GetHostname(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if count := atomic.LoadInt32(&connections); count != 1 {
+		t.Errorf("expected a single connection of devices, got %d", count)
+	}
+}
+
+func TestSharedTransportsLimit(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/soap+xml")
+		fmt.Fprint(w, testHostnameResponse)
+	}))
+	defer server.Close()
+
+	// Devices that build their own pool don't keep their transports forever
+	pool := x509.NewCertPool()
+	pool.AddCert(server.Certificate())
+	for i := 0; i < maxSharedTransports+10; i++ {
+		device := Device{XAddr: server.URL + "/onvif/device_service", RootCAs: pool.Clone()}
+		if _, err := device.GetHostname(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	transportsMutex.Lock()
+	count := len(transports)
+	transportsMutex.Unlock()
+	if count > maxSharedTransports {
+		t.Errorf("%d transports are kept, want at most %d", count, maxSharedTransports)
+	}
+
+	// Transport of device is removed once its connections are closed
+	device := Device{XAddr: server.URL + "/onvif/device_service", RootCAs: pool}
+	if _, err := device.GetHostname(); err != nil {
+		t.Fatal(err)
+	}
+	tlsConfig := device.tlsConfig()
+	device.CloseIdleConnections()
+
+	transportsMutex.Lock()
+	_, ok := transports[tlsConfig]
+	transportsMutex.Unlock()
+	if ok {
+		t.Error("transport is kept after its connections are closed")
+	}
+}
+
+// roundTripperFunc allows a function to be used as HTTP transport
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestSendRequestHTTPClient(t *testing.T) {
+	var requestURL string
+	client := &http.Client{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			requestURL = req.URL.String()
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Content-Type": {"application/soap+xml"}},
+				Body:       ioutil.NopCloser(strings.NewReader(testHostnameResponse)),
+			}, nil
+		}),
+	}
+
+	device := Device{XAddr: "http://camera.invalid/onvif/device_service", HTTPClient: client}
+	res, err := device.GetHostname()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if res.Name != "camera" {
+		t.Errorf("unexpected hostname %q", res.Name)
+	}
+
+	if requestURL != device.XAddr {
+		t.Errorf("request sent to %q, want %q", requestURL, device.XAddr)
+	}
+}
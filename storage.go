@@ -0,0 +1,203 @@
+package onvif
+
+import "errors"
+
+// Modes of recording job
+const (
+	RecordingJobModeIdle   = "Idle"
+	RecordingJobModeActive = "Active"
+)
+
+// States of recording job
+const (
+	RecordingJobStateIdle            = "Idle"
+	RecordingJobStateActive          = "Active"
+	RecordingJobStatePartiallyActive = "PartiallyActive"
+	RecordingJobStateError           = "Error"
+)
+
+var errNoRecordingJob = errors.New("Device doesn't have any recording job")
+
+// GetStorageConfigurations fetch storage configurations of ONVIF device
+func (device Device) GetStorageConfigurations() ([]StorageConfig, error) {
+	// Create SOAP
+	soap := SOAP{
+		Body:  "<tds:GetStorageConfigurations/>",
+		XMLNs: deviceXMLNs,
+	}
+
+	// Send SOAP request
+	response, err := device.sendRequest(deviceNamespace, soap)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse response to interface
+	ifaceConfigs, err := response.ValuesForPath("Envelope.Body.GetStorageConfigurationsResponse.StorageConfigurations")
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert interface to array of storage configuration
+	configs := []StorageConfig{}
+	for _, ifaceConfig := range ifaceConfigs {
+		mapConfig, ok := ifaceConfig.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		config := StorageConfig{Token: interfaceToString(mapConfig["-token"])}
+		if mapData, ok := mapConfig["Data"].(map[string]interface{}); ok {
+			config.Type = interfaceToString(mapData["-type"])
+			config.LocalPath = interfaceToString(mapData["LocalPath"])
+			config.StorageURI = interfaceToString(mapData["StorageUri"])
+			config.Region = interfaceToString(mapData["Region"])
+
+			if mapUser, ok := mapData["User"].(map[string]interface{}); ok {
+				config.User = interfaceToString(mapUser["UserName"])
+			}
+		}
+
+		configs = append(configs, config)
+	}
+
+	return configs, nil
+}
+
+// SetStorageConfiguration changes a storage configuration of ONVIF device
+func (device Device) SetStorageConfiguration(config StorageConfig) error {
+	// Create SOAP
+	body := `<tds:SetStorageConfiguration>
+		<tds:StorageConfiguration token="` + escapeXML(config.Token) + `">
+			<tds:Data type="` + escapeXML(config.Type) + `">`
+	if config.LocalPath != "" {
+		body += `<tds:LocalPath>` + escapeXML(config.LocalPath) + `</tds:LocalPath>`
+	}
+	if config.StorageURI != "" {
+		body += `<tds:StorageUri>` + escapeXML(config.StorageURI) + `</tds:StorageUri>`
+	}
+	if config.User != "" {
+		body += `<tds:User>
+			<tt:UserName>` + escapeXML(config.User) + `</tt:UserName>`
+		if config.Password != "" {
+			body += `<tt:Password>` + escapeXML(config.Password) + `</tt:Password>`
+		}
+		body += `</tds:User>`
+	}
+	if config.Region != "" {
+		body += `<tds:Region>` + escapeXML(config.Region) + `</tds:Region>`
+	}
+	body += `</tds:Data>
+		</tds:StorageConfiguration>
+	</tds:SetStorageConfiguration>`
+
+	soap := SOAP{
+		Body:  body,
+		XMLNs: deviceXMLNs,
+	}
+
+	// Send SOAP request
+	_, err := device.sendRequest(deviceNamespace, soap)
+	return err
+}
+
+// GetRecordingJobState fetch state of a recording job
+func (device Device) GetRecordingJobState(jobToken string) (RecordingJobState, error) {
+	// Create SOAP
+	soap := SOAP{
+		XMLNs: recordingXMLNs,
+		Body: `<trc:GetRecordingJobState>
+			<trc:JobToken>` + escapeXML(jobToken) + `</trc:JobToken>
+		</trc:GetRecordingJobState>`,
+	}
+
+	// Send SOAP request
+	response, err := device.sendRequest(recordingNamespace, soap)
+	if err != nil {
+		return RecordingJobState{}, err
+	}
+
+	// Parse response to interface
+	ifaceState, err := response.ValueForPath("Envelope.Body.GetRecordingJobStateResponse.State")
+	if err != nil {
+		return RecordingJobState{}, err
+	}
+
+	// Parse interface to struct
+	state := RecordingJobState{}
+	if mapState, ok := ifaceState.(map[string]interface{}); ok {
+		state.RecordingToken = interfaceToString(mapState["RecordingToken"])
+		state.State = interfaceToString(mapState["State"])
+	}
+
+	return state, nil
+}
+
+// GetEdgeStorageStatus checks whether ONVIF device has storage for recording,
+// e.g. an inserted SD card, and whether its recording jobs run without error
+func (device Device) GetEdgeStorageStatus() (EdgeStorageStatus, error) {
+	status := EdgeStorageStatus{FailedJobs: []string{}}
+
+	// Storage configurations are only available since ONVIF 2.x
+	configs, err := device.GetStorageConfigurations()
+	if err != nil && !errors.Is(err, ErrActionNotSupported) {
+		return status, err
+	}
+
+	// Device without storage doesn't have any recording
+	recordings, err := device.GetRecordings()
+	if err != nil {
+		return status, err
+	}
+
+	status.Present = len(configs) > 0 || len(recordings) > 0
+
+	// Check state of each recording job
+	jobs, err := device.GetRecordingJobs()
+	if err != nil {
+		return status, err
+	}
+
+	for _, job := range jobs {
+		state, err := device.GetRecordingJobState(job.Token)
+		if err != nil {
+			return status, err
+		}
+
+		switch state.State {
+		case RecordingJobStateError:
+			status.FailedJobs = append(status.FailedJobs, job.Token)
+		case RecordingJobStateActive, RecordingJobStatePartiallyActive:
+			status.Recording = true
+		}
+	}
+
+	status.Healthy = status.Present && len(status.FailedJobs) == 0
+	return status, nil
+}
+
+// SetEdgeRecording starts or stops recording to storage of ONVIF device
+// by changing mode of all of its recording jobs
+func (device Device) SetEdgeRecording(enabled bool) error {
+	jobs, err := device.GetRecordingJobs()
+	if err != nil {
+		return err
+	}
+
+	if len(jobs) == 0 {
+		return errNoRecordingJob
+	}
+
+	mode := RecordingJobModeIdle
+	if enabled {
+		mode = RecordingJobModeActive
+	}
+
+	for _, job := range jobs {
+		if err := device.SetRecordingJobMode(job.Token, mode); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,95 @@
+package onvif
+
+import (
+	"fmt"
+	"log"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/krabiswabbie/go-onvif/onviftest"
+)
+
+func TestGetStorageConfigurations(t *testing.T) {
+	log.Println("Test GetStorageConfigurations")
+
+	res, err := testDevice.GetStorageConfigurations()
+	if err != nil {
+		t.Error(err)
+	}
+
+	js := prettyJSON(&res)
+	fmt.Println(js)
+}
+
+func TestEdgeStorage(t *testing.T) {
+	server := onviftest.NewServer()
+	defer server.Close()
+
+	server.HandleFault("GetStorageConfigurations", onviftest.SenderFault("ter:ActionNotSupported", "Not supported"))
+	server.HandleBody("GetRecordings", `<trc:GetRecordingsResponse>
+		<trc:RecordingItem><tt:RecordingToken>recording0</tt:RecordingToken></trc:RecordingItem>
+	</trc:GetRecordingsResponse>`)
+	server.HandleBody("GetRecordingJobs", `<trc:GetRecordingJobsResponse>
+		<trc:JobItem><tt:JobToken>job0</tt:JobToken></trc:JobItem>
+		<trc:JobItem><tt:JobToken>job1</tt:JobToken></trc:JobItem>
+	</trc:GetRecordingJobsResponse>`)
+	server.Handle("GetRecordingJobState", func(request onviftest.Request) (string, error) {
+		state := "Active"
+		if strings.Contains(request.Envelope, "job1") {
+			state = "Error"
+		}
+
+		return `<trc:GetRecordingJobStateResponse>
+			<trc:State><tt:RecordingToken>recording0</tt:RecordingToken><tt:State>` + state + `</tt:State></trc:State>
+		</trc:GetRecordingJobStateResponse>`, nil
+	})
+	server.HandleBody("SetRecordingJobMode", `<trc:SetRecordingJobModeResponse/>`)
+
+	device := Device{XAddr: server.XAddr()}
+	status, err := device.GetEdgeStorageStatus()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := EdgeStorageStatus{Present: true, Healthy: false, Recording: true, FailedJobs: []string{"job1"}}
+	if !reflect.DeepEqual(status, expected) {
+		t.Errorf("expected %+v, got %+v", expected, status)
+	}
+
+	if err := device.SetEdgeRecording(false); err != nil {
+		t.Fatal(err)
+	}
+
+	request, _ := server.LastRequest("SetRecordingJobMode")
+	if !strings.Contains(request.Envelope, "<trc:JobToken>job1</trc:JobToken>") ||
+		!strings.Contains(request.Envelope, "<trc:Mode>Idle</trc:Mode>") {
+		t.Errorf("unexpected request %s", request.Envelope)
+	}
+}
+
+func TestSetStorageConfiguration(t *testing.T) {
+	server := onviftest.NewServer()
+	defer server.Close()
+
+	server.HandleBody("SetStorageConfiguration", `<tds:SetStorageConfigurationResponse/>`)
+
+	device := Device{XAddr: server.XAddr()}
+	config := StorageConfig{
+		Token:      "storage0",
+		Type:       "NFS",
+		StorageURI: "nfs://10.0.0.2/recordings",
+		User:       "admin",
+		Password:   "secret",
+	}
+	if err := device.SetStorageConfiguration(config); err != nil {
+		t.Fatal(err)
+	}
+
+	// Credentials are tt:UserCredential
+	request, _ := server.LastRequest("SetStorageConfiguration")
+	expected := "<tds:User><tt:UserName>admin</tt:UserName><tt:Password>secret</tt:Password></tds:User>"
+	if !strings.Contains(request.Envelope, expected) {
+		t.Errorf("unexpected request %s", request.Envelope)
+	}
+}
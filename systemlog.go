@@ -0,0 +1,81 @@
+package onvif
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+)
+
+// Types of system log
+const (
+	SystemLogTypeSystem = "System"
+	SystemLogTypeAccess = "Access"
+)
+
+// GetSystemLog fetch system log of ONVIF camera with the type, i.e. System or Access.
+// If camera doesn't support returning log in SOAP response, the log is
+// downloaded from the URI returned by GetSystemURIs instead.
+func (device Device) GetSystemLog(logType string) (SystemLog, error) {
+	systemLog, err := device.getSystemLog(logType)
+	if err == nil || !errors.Is(err, ErrActionNotSupported) {
+		return systemLog, err
+	}
+
+	// Fallback to download the log
+	systemURIs, uriErr := device.GetSystemURIs()
+	if uriErr != nil {
+		return SystemLog{}, err
+	}
+
+	for _, logURI := range systemURIs.SystemLogs {
+		if logURI.Type == logType && logURI.URI != "" {
+			content, err := device.download(logURI.URI)
+			if err != nil {
+				return SystemLog{}, err
+			}
+			return SystemLog{Binary: content}, nil
+		}
+	}
+
+	return SystemLog{}, err
+}
+
+// getSystemLog fetch system log that's returned in SOAP response, either as
+// text or as binary data that's either inline or sent as attachment
+func (device Device) getSystemLog(logType string) (SystemLog, error) {
+	// Create SOAP
+	soap := SOAP{
+		XMLNs: deviceXMLNs,
+		Body: `<tds:GetSystemLog>
+			<tds:LogType>` + escapeXML(logType) + `</tds:LogType>
+		</tds:GetSystemLog>`,
+	}
+
+	// Send SOAP request
+	response, err := device.sendRequest(deviceNamespace, soap)
+	if err != nil {
+		return SystemLog{}, err
+	}
+
+	// Parse response to interface
+	ifaceLog, err := response.ValueForPath("Envelope.Body.GetSystemLogResponse.SystemLog")
+	if err != nil {
+		return SystemLog{}, err
+	}
+
+	// Parse interface to struct
+	systemLog := SystemLog{}
+	if mapLog, ok := ifaceLog.(map[string]interface{}); ok {
+		systemLog.String = interfaceToString(mapLog["String"])
+
+		if ifaceBinary, ok := mapLog["Binary"]; ok {
+			binary := interfaceToText(ifaceBinary)
+			systemLog.Binary, err = base64.StdEncoding.DecodeString(strings.Join(strings.Fields(binary), ""))
+			if err != nil {
+				return SystemLog{}, err
+			}
+		}
+	}
+
+	return systemLog, nil
+}
@@ -0,0 +1,101 @@
+package onvif
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/krabiswabbie/go-onvif/onviftest"
+)
+
+func TestGetSystemLog(t *testing.T) {
+	log.Println("Test GetSystemLog")
+
+	res, err := testDevice.GetSystemLog(SystemLogTypeSystem)
+	if err != nil {
+		t.Error(err)
+	}
+
+	fmt.Println(res.String, len(res.Binary))
+}
+
+func TestGetSystemLogAttachment(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", `multipart/related; type="application/xop+xml"; `+
+			`start="<root@camera>"; start-info="application/soap+xml"; boundary="MIME_boundary"`)
+		io.WriteString(w, strings.Replace(`--MIME_boundary
+Content-Type: application/xop+xml; charset=UTF-8; type="application/soap+xml"
+Content-ID: <root@camera>
+
+<?xml version="1.0" encoding="UTF-8"?>
+<s:Envelope xmlns:s="http://www.w3.org/2003/05/soap-envelope" xmlns:tds="http://www.onvif.org/ver10/device/wsdl" xmlns:tt="http://www.onvif.org/ver10/schema" xmlns:xop="http://www.w3.org/2004/08/xop/include" xmlns:xmime="http://www.w3.org/2005/05/xmlmime">
+<s:Body><tds:GetSystemLogResponse><tds:SystemLog>
+<tt:Binary xmime:contentType="application/octet-stream"><xop:Include href="cid:log%40camera"/></tt:Binary>
+</tds:SystemLog></tds:GetSystemLogResponse></s:Body></s:Envelope>
+--MIME_boundary
+Content-Type: application/octet-stream
+Content-ID: <log@camera>
+
+line 1
+line 2
+--MIME_boundary--
+`, "\n", "\r\n", -1))
+	}))
+	defer server.Close()
+
+	device := Device{XAddr: server.URL + "/onvif/device_service"}
+	systemLog, err := device.GetSystemLog(SystemLogTypeSystem)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(systemLog.Binary) != "line 1\r\nline 2" {
+		t.Errorf("unexpected log %q", systemLog.Binary)
+	}
+}
+
+func TestGetSystemLogFallback(t *testing.T) {
+	server := onviftest.NewServer()
+	defer server.Close()
+
+	server.HandleBody("GetSystemUris", `<tds:GetSystemUrisResponse>
+		<tds:SystemLogUris>
+			<tt:SystemLog>
+				<tt:Type>System</tt:Type>
+				<tt:Uri>`+server.URL+onviftest.SnapshotPath+`</tt:Uri>
+			</tt:SystemLog>
+		</tds:SystemLogUris>
+	</tds:GetSystemUrisResponse>`)
+
+	server.SetSnapshot([]byte("system log"))
+
+	// Inline text is returned as it is
+	server.HandleBody("GetSystemLog", `<tds:GetSystemLogResponse>
+		<tds:SystemLog><tt:String>inline log</tt:String></tds:SystemLog>
+	</tds:GetSystemLogResponse>`)
+
+	device := Device{XAddr: server.XAddr()}
+	systemLog, err := device.GetSystemLog(SystemLogTypeSystem)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if systemLog.String != "inline log" {
+		t.Errorf("unexpected log %+v", systemLog)
+	}
+
+	// Camera that doesn't support GetSystemLog provides the log in its URI
+	server.HandleFault("GetSystemLog", onviftest.SenderFault("ter:ActionNotSupported", "Not supported"))
+	systemLog, err = device.GetSystemLog(SystemLogTypeSystem)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(systemLog.Binary) != "system log" {
+		t.Errorf("unexpected log %+v", systemLog)
+	}
+}
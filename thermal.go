@@ -0,0 +1,151 @@
+package onvif
+
+import "strconv"
+
+const thermalNamespace = "http://www.onvif.org/ver10/thermal/wsdl"
+
+var thermalXMLNs = []string{
+	`xmlns:tth="http://www.onvif.org/ver10/thermal/wsdl"`,
+	`xmlns:tt="http://www.onvif.org/ver10/schema"`,
+}
+
+// GetThermalConfigurations fetch thermal configuration of all video sources of thermal camera
+func (device Device) GetThermalConfigurations() ([]ThermalConfig, error) {
+	// Create SOAP
+	soap := SOAP{
+		Body:  "<tth:GetConfigurations/>",
+		XMLNs: thermalXMLNs,
+	}
+
+	// Send SOAP request
+	response, err := device.sendRequest(thermalNamespace, soap)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse response to interface
+	ifaceConfigs, err := response.ValuesForPath("Envelope.Body.GetConfigurationsResponse.Configurations")
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert interface to array of thermal configuration
+	configs := []ThermalConfig{}
+	for _, ifaceConfig := range ifaceConfigs {
+		if mapConfigs, ok := ifaceConfig.(map[string]interface{}); ok {
+			config := ThermalConfig{}
+			if mapConfig, ok := mapConfigs["Configuration"].(map[string]interface{}); ok {
+				config = parseThermalConfig(mapConfig)
+			}
+			config.VideoSourceToken = interfaceToString(mapConfigs["-token"])
+			configs = append(configs, config)
+		}
+	}
+
+	return configs, nil
+}
+
+// SetThermalConfiguration changes thermal configuration of a video source
+func (device Device) SetThermalConfiguration(config ThermalConfig) error {
+	// Create SOAP
+	body := `<tth:SetConfiguration>
+		<tth:VideoSourceToken>` + escapeXML(config.VideoSourceToken) + `</tth:VideoSourceToken>
+		<tth:Configuration>
+			<tth:ColorPalette token="` + escapeXML(config.ColorPalette.Token) + `" Type="` + escapeXML(config.ColorPalette.Type) + `">
+				<tth:Name>` + escapeXML(config.ColorPalette.Name) + `</tth:Name>
+			</tth:ColorPalette>
+			<tth:Polarity>` + escapeXML(config.Polarity) + `</tth:Polarity>`
+
+	if config.NUCTable.Token != "" {
+		body += `<tth:NUCTable token="` + escapeXML(config.NUCTable.Token) + `"` +
+			` LowTemperature="` + formatFloat(config.NUCTable.LowTemperature) + `"` +
+			` HighTemperature="` + formatFloat(config.NUCTable.HighTemperature) + `">
+				<tth:Name>` + escapeXML(config.NUCTable.Name) + `</tth:Name>
+			</tth:NUCTable>`
+	}
+
+	// Run time of cooler is read-only
+	if config.Cooler != nil {
+		body += `<tth:Cooler>
+				<tth:Enabled>` + strconv.FormatBool(config.Cooler.Enabled) + `</tth:Enabled>
+			</tth:Cooler>`
+	}
+
+	body += `</tth:Configuration>
+	</tth:SetConfiguration>`
+
+	soap := SOAP{
+		Body:  body,
+		XMLNs: thermalXMLNs,
+	}
+
+	// Send SOAP request
+	_, err := device.sendRequest(thermalNamespace, soap)
+	return err
+}
+
+// GetRadiometryConfiguration fetch radiometry configuration of a video source
+func (device Device) GetRadiometryConfiguration(videoSourceToken string) (RadiometryConfig, error) {
+	// Create SOAP
+	soap := SOAP{
+		XMLNs: thermalXMLNs,
+		Body: `<tth:GetRadiometryConfiguration>
+			<tth:VideoSourceToken>` + escapeXML(videoSourceToken) + `</tth:VideoSourceToken>
+		</tth:GetRadiometryConfiguration>`,
+	}
+
+	// Send SOAP request
+	response, err := device.sendRequest(thermalNamespace, soap)
+	if err != nil {
+		return RadiometryConfig{}, err
+	}
+
+	// Parse response to interface
+	ifaceParams, err := response.ValueForPath("Envelope.Body.GetRadiometryConfigurationResponse.Configuration.RadiometryGlobalParameters")
+	if err != nil {
+		return RadiometryConfig{}, err
+	}
+
+	// Parse interface to struct
+	config := RadiometryConfig{}
+	if mapParams, ok := ifaceParams.(map[string]interface{}); ok {
+		config.ReflectedAmbientTemperature = interfaceToFloat(mapParams["ReflectedAmbientTemperature"])
+		config.Emissivity = interfaceToFloat(mapParams["Emissivity"])
+		config.DistanceToObject = interfaceToFloat(mapParams["DistanceToObject"])
+		config.RelativeHumidity = interfaceToFloat(mapParams["RelativeHumidity"])
+		config.AtmosphericTemperature = interfaceToFloat(mapParams["AtmosphericTemperature"])
+		config.AtmosphericTransmittance = interfaceToFloat(mapParams["AtmosphericTransmittance"])
+		config.ExtOpticsTemperature = interfaceToFloat(mapParams["ExtOpticsTemperature"])
+		config.ExtOpticsTransmittance = interfaceToFloat(mapParams["ExtOpticsTransmittance"])
+	}
+
+	return config, nil
+}
+
+// parseThermalConfig parses tth:Configuration
+func parseThermalConfig(mapConfig map[string]interface{}) ThermalConfig {
+	config := ThermalConfig{}
+	config.Polarity = interfaceToString(mapConfig["Polarity"])
+
+	if mapPalette, ok := mapConfig["ColorPalette"].(map[string]interface{}); ok {
+		config.ColorPalette.Token = interfaceToString(mapPalette["-token"])
+		config.ColorPalette.Type = interfaceToString(mapPalette["-Type"])
+		config.ColorPalette.Name = interfaceToString(mapPalette["Name"])
+	}
+
+	if mapTable, ok := mapConfig["NUCTable"].(map[string]interface{}); ok {
+		config.NUCTable.Token = interfaceToString(mapTable["-token"])
+		config.NUCTable.Name = interfaceToString(mapTable["Name"])
+		config.NUCTable.LowTemperature = interfaceToFloat(mapTable["-LowTemperature"])
+		config.NUCTable.HighTemperature = interfaceToFloat(mapTable["-HighTemperature"])
+	}
+
+	if mapCooler, ok := mapConfig["Cooler"].(map[string]interface{}); ok {
+		config.Cooler = &ThermalCooler{
+			Enabled: interfaceToBool(mapCooler["Enabled"]),
+			RunTime: interfaceToFloat(mapCooler["RunTime"]),
+		}
+	}
+
+	return config
+}
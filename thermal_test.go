@@ -0,0 +1,73 @@
+package onvif
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"testing"
+
+	"github.com/krabiswabbie/go-onvif/onviftest"
+)
+
+func TestGetThermalConfigurations(t *testing.T) {
+	log.Println("Test GetThermalConfigurations")
+
+	res, err := testDevice.GetThermalConfigurations()
+	if err != nil {
+		t.Error(err)
+	}
+
+	js := prettyJSON(&res)
+	fmt.Println(js)
+}
+
+func TestThermalCooler(t *testing.T) {
+	server := onviftest.NewServer()
+	defer server.Close()
+
+	server.HandleBody("GetConfigurations", `<tth:GetConfigurationsResponse xmlns:tth="http://www.onvif.org/ver10/thermal/wsdl">
+		<tth:Configurations token="source0">
+			<tth:Configuration>
+				<tth:Polarity>WhiteHot</tth:Polarity>
+				<tth:Cooler><tth:Enabled>true</tth:Enabled><tth:RunTime>120.5</tth:RunTime></tth:Cooler>
+			</tth:Configuration>
+		</tth:Configurations>
+		<tth:Configurations token="source1">
+			<tth:Configuration><tth:Polarity>BlackHot</tth:Polarity></tth:Configuration>
+		</tth:Configurations>
+	</tth:GetConfigurationsResponse>`)
+	server.HandleBody("SetConfiguration", `<tth:SetConfigurationResponse xmlns:tth="http://www.onvif.org/ver10/thermal/wsdl"/>`)
+
+	device := Device{XAddr: server.XAddr()}
+	configs, err := device.GetThermalConfigurations()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(configs) != 2 {
+		t.Fatalf("expected 2 configurations, got %d", len(configs))
+	}
+	if cooler := configs[0].Cooler; cooler == nil || !cooler.Enabled || cooler.RunTime != 120.5 {
+		t.Errorf("unexpected cooler %+v", cooler)
+	}
+	if configs[1].Cooler != nil {
+		t.Errorf("expected no cooler, got %+v", configs[1].Cooler)
+	}
+
+	// Cooler is only sent if it's configured
+	for _, config := range configs {
+		if err := device.SetThermalConfiguration(config); err != nil {
+			t.Fatal(err)
+		}
+
+		request, _ := server.LastRequest("SetConfiguration")
+		envelope := request.Envelope
+		hasCooler := strings.Contains(envelope, "<tth:Cooler>") && strings.Contains(envelope, "<tth:Enabled>true</tth:Enabled>")
+		if hasCooler != (config.Cooler != nil) {
+			t.Errorf("unexpected cooler in request of %s: %s", config.VideoSourceToken, envelope)
+		}
+		if strings.Contains(envelope, "CoolerEnabled") || strings.Contains(envelope, "RunTime") {
+			t.Errorf("unexpected element in request: %s", envelope)
+		}
+	}
+}
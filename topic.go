@@ -0,0 +1,71 @@
+package onvif
+
+import "strings"
+
+// Topics of common events of ONVIF camera
+const (
+	TopicCellMotion        = "tns1:RuleEngine/CellMotionDetector/Motion"
+	TopicMotionAlarm       = "tns1:VideoSource/MotionAlarm"
+	TopicTamper            = "tns1:RuleEngine/TamperDetector/Tamper"
+	TopicImageTooBlurry    = "tns1:VideoSource/ImageTooBlurry/AnalyticsService"
+	TopicGlobalSceneChange = "tns1:VideoSource/GlobalSceneChange/AnalyticsService"
+	TopicDigitalInput      = "tns1:Device/Trigger/DigitalInput"
+	TopicRelay             = "tns1:Device/Trigger/Relay"
+	TopicLineCrossed       = "tns1:RuleEngine/LineDetector/Crossed"
+	TopicFieldDetector     = "tns1:RuleEngine/FieldDetector/ObjectsInside"
+)
+
+// TopicFilter creates topic expression that matches any of the topics,
+// which can be used as filter of event subscription and metadata stream
+func TopicFilter(topics ...string) string {
+	return strings.Join(topics, "|")
+}
+
+// TopicSubtree creates topic expression that matches the topic and all of its
+// children, e.g. TopicSubtree("tns1:RuleEngine") matches all rule engine events
+func TopicSubtree(topic string) string {
+	return strings.TrimSuffix(topic, "/") + "//."
+}
+
+// MatchTopic checks whether topic of the message is the specified topic or one
+// of its children. Namespace prefix is ignored, since devices may declare
+// ONVIF topic namespace using other prefix than tns1.
+func (message NotificationMessage) MatchTopic(topic string) bool {
+	messageTopic := trimTopicPrefix(message.Topic)
+	topic = trimTopicPrefix(strings.TrimSuffix(topic, "//."))
+
+	return messageTopic == topic || strings.HasPrefix(messageTopic, topic+"/")
+}
+
+// SourceValue returns value of a source item of the message
+func (message NotificationMessage) SourceValue(name string) (string, bool) {
+	return simpleItemValue(message.Source, name)
+}
+
+// DataValue returns value of a data item of the message
+func (message NotificationMessage) DataValue(name string) (string, bool) {
+	return simpleItemValue(message.Data, name)
+}
+
+// trimTopicPrefix removes namespace prefix from each part of a topic
+func trimTopicPrefix(topic string) string {
+	parts := strings.Split(strings.TrimSpace(topic), "/")
+	for i, part := range parts {
+		if idx := strings.Index(part, ":"); idx >= 0 {
+			parts[i] = part[idx+1:]
+		}
+	}
+
+	return strings.Join(parts, "/")
+}
+
+// simpleItemValue returns value of a simple item with the name
+func simpleItemValue(items []SimpleItem, name string) (string, bool) {
+	for _, item := range items {
+		if item.Name == name {
+			return item.Value, true
+		}
+	}
+
+	return "", false
+}
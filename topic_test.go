@@ -0,0 +1,41 @@
+package onvif
+
+import "testing"
+
+func TestTopicFilter(t *testing.T) {
+	filter := TopicFilter(TopicCellMotion, TopicSubtree("tns1:Device/Trigger"))
+	expected := "tns1:RuleEngine/CellMotionDetector/Motion|tns1:Device/Trigger//."
+	if filter != expected {
+		t.Errorf("expected %s, got %s", expected, filter)
+	}
+}
+
+func TestMatchTopic(t *testing.T) {
+	message := NotificationMessage{
+		Topic:  "ns0:RuleEngine/ns0:CellMotionDetector/ns0:Motion",
+		Source: []SimpleItem{{Name: "Rule", Value: "MyMotionDetectorRule"}},
+		Data:   []SimpleItem{{Name: "IsMotion", Value: "true"}},
+	}
+
+	tests := map[string]bool{
+		TopicCellMotion:                                   true,
+		TopicSubtree("tns1:RuleEngine"):                   true,
+		"tns1:RuleEngine/CellMotion":                      false,
+		TopicMotionAlarm:                                  false,
+		"tns1:RuleEngine/CellMotionDetector/Motion/State": false,
+	}
+
+	for topic, expected := range tests {
+		if message.MatchTopic(topic) != expected {
+			t.Errorf("expected match of %s to be %v", topic, expected)
+		}
+	}
+
+	if value, ok := message.DataValue("IsMotion"); !ok || value != "true" {
+		t.Errorf("unexpected data value %q", value)
+	}
+
+	if _, ok := message.SourceValue("IsMotion"); ok {
+		t.Error("expected missing source value")
+	}
+}
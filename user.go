@@ -0,0 +1,91 @@
+package onvif
+
+// GetUsers fetch user accounts of an ONVIF camera
+func (device Device) GetUsers() ([]User, error) {
+	// Create SOAP
+	soap := SOAP{
+		Body:  "<tds:GetUsers/>",
+		XMLNs: deviceXMLNs,
+	}
+
+	// Send SOAP request
+	response, err := device.sendRequest(deviceNamespace, soap)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse response to interface
+	ifaceUsers, err := response.ValuesForPath("Envelope.Body.GetUsersResponse.User")
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert interface to array of user
+	users := []User{}
+	for _, ifaceUser := range ifaceUsers {
+		if mapUser, ok := ifaceUser.(map[string]interface{}); ok {
+			users = append(users, User{
+				Username:  interfaceToString(mapUser["Username"]),
+				UserLevel: interfaceToString(mapUser["UserLevel"]),
+			})
+		}
+	}
+
+	return users, nil
+}
+
+// CreateUsers creates user accounts in an ONVIF camera. A camera without
+// any user accepts requests without authentication, so it's used to
+// create the first administrator of factory-fresh camera.
+func (device Device) CreateUsers(users []User) error {
+	return device.sendUsers("CreateUsers", users)
+}
+
+// SetUser updates password and user level of existing user accounts
+func (device Device) SetUser(users []User) error {
+	return device.sendUsers("SetUser", users)
+}
+
+// DeleteUsers deletes user accounts from an ONVIF camera
+func (device Device) DeleteUsers(usernames []string) error {
+	// Create SOAP
+	body := `<tds:DeleteUsers>`
+	for _, username := range usernames {
+		body += `<tds:Username>` + escapeXML(username) + `</tds:Username>`
+	}
+	body += `</tds:DeleteUsers>`
+
+	soap := SOAP{
+		Body:  body,
+		XMLNs: deviceXMLNs,
+	}
+
+	// Send SOAP request
+	_, err := device.sendRequest(deviceNamespace, soap)
+	return err
+}
+
+// sendUsers sends users with the operation, e.g. CreateUsers
+func (device Device) sendUsers(operation string, users []User) error {
+	// Create SOAP
+	body := `<tds:` + operation + `>`
+	for _, user := range users {
+		body += `<tds:User>
+			<tt:Username>` + escapeXML(user.Username) + `</tt:Username>`
+		if user.Password != "" {
+			body += `<tt:Password>` + escapeXML(user.Password) + `</tt:Password>`
+		}
+		body += `<tt:UserLevel>` + escapeXML(user.UserLevel) + `</tt:UserLevel>
+		</tds:User>`
+	}
+	body += `</tds:` + operation + `>`
+
+	soap := SOAP{
+		Body:  body,
+		XMLNs: deviceXMLNs,
+	}
+
+	// Send SOAP request
+	_, err := device.sendRequest(deviceNamespace, soap)
+	return err
+}
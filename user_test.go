@@ -0,0 +1,67 @@
+package onvif
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/krabiswabbie/go-onvif/onviftest"
+)
+
+func TestUsers(t *testing.T) {
+	server := onviftest.NewServer()
+	defer server.Close()
+
+	server.HandleBody("GetUsers", `<tds:GetUsersResponse>
+		<tds:User>
+			<tt:Username>admin</tt:Username>
+			<tt:UserLevel>Administrator</tt:UserLevel>
+		</tds:User>
+		<tds:User>
+			<tt:Username>viewer</tt:Username>
+			<tt:UserLevel>User</tt:UserLevel>
+		</tds:User>
+	</tds:GetUsersResponse>`)
+	server.HandleBody("CreateUsers", `<tds:CreateUsersResponse/>`)
+	server.HandleBody("SetUser", `<tds:SetUserResponse/>`)
+	server.HandleBody("DeleteUsers", `<tds:DeleteUsersResponse/>`)
+
+	device := Device{XAddr: server.XAddr()}
+	users, err := device.GetUsers()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []User{{Username: "admin", UserLevel: "Administrator"}, {Username: "viewer", UserLevel: "User"}}
+	if !reflect.DeepEqual(users, expected) {
+		t.Errorf("got %+v, want %+v", users, expected)
+	}
+
+	if err := device.CreateUsers([]User{{Username: "operator", Password: "a<b", UserLevel: "Operator"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	request, _ := server.LastRequest("CreateUsers")
+	if !strings.Contains(request.Envelope, "<tt:Password>a&lt;b</tt:Password>") {
+		t.Errorf("unexpected request %s", request.Envelope)
+	}
+
+	// Password isn't changed if it's empty
+	if err := device.SetUser([]User{{Username: "viewer", UserLevel: "Operator"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	request, _ = server.LastRequest("SetUser")
+	if strings.Contains(request.Envelope, "Password>") {
+		t.Errorf("unexpected request %s", request.Envelope)
+	}
+
+	if err := device.DeleteUsers([]string{"viewer"}); err != nil {
+		t.Fatal(err)
+	}
+
+	request, _ = server.LastRequest("DeleteUsers")
+	if !strings.Contains(request.Envelope, "<tds:Username>viewer</tds:Username>") {
+		t.Errorf("unexpected request %s", request.Envelope)
+	}
+}
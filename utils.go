@@ -1,20 +1,35 @@
 package onvif
 
 import (
+	"bytes"
 	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"regexp"
 	"strconv"
 	"strings"
+	"time"
 )
 
 var testDevice = Device{
 	XAddr: "http://192.168.1.75:5000/onvif/device_service",
 }
 
+var rxDuration = regexp.MustCompile(`^(-)?P(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+(?:\.\d+)?)S)?)?$`)
+
 func interfaceToString(src interface{}) string {
 	str, _ := src.(string)
 	return str
 }
 
+// interfaceToText returns text of an element, which might have attributes
+func interfaceToText(src interface{}) string {
+	if mapElement, ok := src.(map[string]interface{}); ok {
+		return interfaceToString(mapElement["#text"])
+	}
+	return interfaceToString(src)
+}
+
 func interfaceToBool(src interface{}) bool {
 	strBool := interfaceToString(src)
 	return strings.ToLower(strBool) == "true"
@@ -26,7 +41,127 @@ func interfaceToInt(src interface{}) int {
 	return number
 }
 
+func interfaceToFloat(src interface{}) float64 {
+	strNumber := interfaceToString(src)
+	number, _ := strconv.ParseFloat(strings.TrimSpace(strNumber), 64)
+	return number
+}
+
+// interfaceToMaps converts an XML element, which might appear once or
+// several times, to a list of map
+func interfaceToMaps(src interface{}) []map[string]interface{} {
+	switch value := src.(type) {
+	case map[string]interface{}:
+		return []map[string]interface{}{value}
+	case []interface{}:
+		result := []map[string]interface{}{}
+		for _, item := range value {
+			if mapItem, ok := item.(map[string]interface{}); ok {
+				result = append(result, mapItem)
+			}
+		}
+		return result
+	}
+
+	return nil
+}
+
+// interfaceToStrings converts an XML element, which might appear once or
+// several times, to a list of string
+func interfaceToStrings(src interface{}) []string {
+	switch value := src.(type) {
+	case string:
+		return []string{value}
+	case []interface{}:
+		result := []string{}
+		for _, item := range value {
+			if str, ok := item.(string); ok {
+				result = append(result, str)
+			}
+		}
+		return result
+	}
+
+	return nil
+}
+
+// interfaceToTime converts xs:dateTime to time.Time.
+// Time without time zone is assumed to be UTC
+func interfaceToTime(src interface{}) time.Time {
+	strTime := strings.TrimSpace(interfaceToString(src))
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05.999999999"} {
+		if result, err := time.Parse(layout, strTime); err == nil {
+			return result
+		}
+	}
+
+	return time.Time{}
+}
+
+// interfaceToSimpleItems converts an element that contains tt:SimpleItem to a list of SimpleItem
+func interfaceToSimpleItems(src interface{}) []SimpleItem {
+	items := []SimpleItem{}
+	if mapItems, ok := src.(map[string]interface{}); ok {
+		for _, mapItem := range interfaceToMaps(mapItems["SimpleItem"]) {
+			items = append(items, SimpleItem{
+				Name:  interfaceToString(mapItem["-Name"]),
+				Value: interfaceToString(mapItem["-Value"]),
+			})
+		}
+	}
+
+	return items
+}
+
+// escapeXML escapes text so it can be safely put inside XML element or attribute
+func escapeXML(src string) string {
+	buffer := &bytes.Buffer{}
+	xml.EscapeText(buffer, []byte(src))
+	return buffer.String()
+}
+
 func prettyJSON(src interface{}) string {
 	result, _ := json.MarshalIndent(&src, "", "    ")
 	return string(result)
 }
+
+// parseDuration parses xs:duration (e.g. PT1M30S) to time.Duration.
+// Year and month are not supported since their length is not fixed.
+func parseDuration(src string) (time.Duration, error) {
+	matches := rxDuration.FindStringSubmatch(strings.TrimSpace(src))
+	if matches == nil || src == "P" || strings.HasSuffix(src, "T") {
+		return 0, errors.New("Invalid duration: " + src)
+	}
+
+	days, _ := strconv.Atoi(matches[2])
+	hours, _ := strconv.Atoi(matches[3])
+	minutes, _ := strconv.Atoi(matches[4])
+	seconds, _ := strconv.ParseFloat(matches[5], 64)
+
+	duration := time.Duration(days)*24*time.Hour +
+		time.Duration(hours)*time.Hour +
+		time.Duration(minutes)*time.Minute +
+		time.Duration(seconds*float64(time.Second))
+
+	if matches[1] == "-" {
+		duration = -duration
+	}
+
+	return duration, nil
+}
+
+// formatFloat formats float number for XML, e.g. 0.5
+func formatFloat(number float64) string {
+	return strconv.FormatFloat(number, 'f', -1, 64)
+}
+
+// formatDuration formats time.Duration to xs:duration, e.g. PT1.5S
+func formatDuration(duration time.Duration) string {
+	sign := ""
+	if duration < 0 {
+		sign = "-"
+		duration = -duration
+	}
+
+	return sign + "PT" + formatFloat(duration.Seconds()) + "S"
+}
@@ -0,0 +1,60 @@
+package onvif
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		src      string
+		expected time.Duration
+		valid    bool
+	}{
+		{"PT0S", 0, true},
+		{"PT5S", 5 * time.Second, true},
+		{"PT1.5S", 1500 * time.Millisecond, true},
+		{"PT1M30S", 90 * time.Second, true},
+		{"PT1H1M1S", time.Hour + time.Minute + time.Second, true},
+		{"P1DT1H", 25 * time.Hour, true},
+		{"-PT10S", -10 * time.Second, true},
+		{"P", 0, false},
+		{"PT", 0, false},
+		{"5S", 0, false},
+	}
+
+	for _, test := range tests {
+		duration, err := parseDuration(test.src)
+		if !test.valid {
+			if err == nil {
+				t.Errorf("%s: expected error, got %v", test.src, duration)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("%s: %v", test.src, err)
+		} else if duration != test.expected {
+			t.Errorf("%s: expected %v, got %v", test.src, test.expected, duration)
+		}
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	tests := map[time.Duration]string{
+		0:                       "PT0S",
+		5 * time.Second:         "PT5S",
+		1500 * time.Millisecond: "PT1.5S",
+		-2 * time.Minute:        "-PT120S",
+	}
+
+	for duration, expected := range tests {
+		if result := formatDuration(duration); result != expected {
+			t.Errorf("%v: expected %s, got %s", duration, expected, result)
+		}
+
+		if parsed, _ := parseDuration(formatDuration(duration)); parsed != duration {
+			t.Errorf("%v: round trip returns %v", duration, parsed)
+		}
+	}
+}
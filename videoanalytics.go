@@ -0,0 +1,103 @@
+package onvif
+
+import "strconv"
+
+// GetVideoAnalyticsConfigurations fetch all video analytics configurations of ONVIF camera
+func (device Device) GetVideoAnalyticsConfigurations() ([]VideoAnalyticsConfig, error) {
+	// Create SOAP
+	soap := SOAP{
+		Body:  "<trt:GetVideoAnalyticsConfigurations/>",
+		XMLNs: mediaXMLNs,
+	}
+
+	// Send SOAP request
+	response, err := device.sendRequest(mediaNamespace, soap)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse response to interface
+	ifaceConfigs, err := response.ValuesForPath("Envelope.Body.GetVideoAnalyticsConfigurationsResponse.Configurations")
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert interface to array of video analytics configuration
+	configs := []VideoAnalyticsConfig{}
+	for _, ifaceConfig := range ifaceConfigs {
+		if mapConfig, ok := ifaceConfig.(map[string]interface{}); ok {
+			configs = append(configs, parseVideoAnalyticsConfig(mapConfig))
+		}
+	}
+
+	return configs, nil
+}
+
+// SetVideoAnalyticsConfiguration changes a video analytics configuration, including its
+// analytics modules and rules. If forcePersistence is true, the change will persist
+// after ONVIF camera is rebooted.
+func (device Device) SetVideoAnalyticsConfiguration(config VideoAnalyticsConfig, forcePersistence bool) error {
+	// Create SOAP
+	body := `<trt:SetVideoAnalyticsConfiguration>
+		<trt:Configuration token="` + escapeXML(config.Token) + `">
+			<tt:Name>` + escapeXML(config.Name) + `</tt:Name>
+			<tt:UseCount>` + strconv.Itoa(config.UseCount) + `</tt:UseCount>
+			<tt:AnalyticsEngineConfiguration>`
+	for _, module := range config.AnalyticsModules {
+		body += analyticsConfigXML("tt:AnalyticsModule", module)
+	}
+	body += `</tt:AnalyticsEngineConfiguration>
+			<tt:RuleEngineConfiguration>`
+	for _, rule := range config.Rules {
+		body += analyticsConfigXML("tt:Rule", rule)
+	}
+	body += `</tt:RuleEngineConfiguration>
+		</trt:Configuration>
+		<trt:ForcePersistence>` + strconv.FormatBool(forcePersistence) + `</trt:ForcePersistence>
+	</trt:SetVideoAnalyticsConfiguration>`
+
+	soap := SOAP{
+		Body:  body,
+		XMLNs: mediaXMLNs,
+	}
+
+	// Send SOAP request
+	_, err := device.sendRequest(mediaNamespace, soap)
+	return err
+}
+
+// AddVideoAnalyticsConfiguration adds a video analytics configuration to a media profile.
+// Some cameras only run the rules of a configuration once it's added to a profile.
+func (device Device) AddVideoAnalyticsConfiguration(profileToken, configToken string) error {
+	return device.addConfiguration("VideoAnalytics", profileToken, configToken)
+}
+
+// RemoveVideoAnalyticsConfiguration removes video analytics configuration from a media profile
+func (device Device) RemoveVideoAnalyticsConfiguration(profileToken string) error {
+	return device.removeConfiguration("VideoAnalytics", profileToken)
+}
+
+// parseVideoAnalyticsConfig parses tt:VideoAnalyticsConfiguration
+func parseVideoAnalyticsConfig(mapConfig map[string]interface{}) VideoAnalyticsConfig {
+	config := VideoAnalyticsConfig{
+		Name:             interfaceToString(mapConfig["Name"]),
+		Token:            interfaceToString(mapConfig["-token"]),
+		UseCount:         interfaceToInt(mapConfig["UseCount"]),
+		AnalyticsModules: []AnalyticsConfig{},
+		Rules:            []AnalyticsConfig{},
+	}
+
+	if mapEngine, ok := mapConfig["AnalyticsEngineConfiguration"].(map[string]interface{}); ok {
+		for _, mapModule := range interfaceToMaps(mapEngine["AnalyticsModule"]) {
+			config.AnalyticsModules = append(config.AnalyticsModules, parseAnalyticsConfig(mapModule))
+		}
+	}
+
+	if mapRuleEngine, ok := mapConfig["RuleEngineConfiguration"].(map[string]interface{}); ok {
+		for _, mapRule := range interfaceToMaps(mapRuleEngine["Rule"]) {
+			config.Rules = append(config.Rules, parseAnalyticsConfig(mapRule))
+		}
+	}
+
+	return config
+}
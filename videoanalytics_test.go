@@ -0,0 +1,74 @@
+package onvif
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"testing"
+
+	"github.com/krabiswabbie/go-onvif/onviftest"
+)
+
+func TestGetVideoAnalyticsConfigurations(t *testing.T) {
+	log.Println("Test GetVideoAnalyticsConfigurations")
+
+	res, err := testDevice.GetVideoAnalyticsConfigurations()
+	if err != nil {
+		t.Error(err)
+	}
+
+	js := prettyJSON(&res)
+	fmt.Println(js)
+}
+
+func TestVideoAnalyticsConfiguration(t *testing.T) {
+	server := onviftest.NewServer()
+	defer server.Close()
+
+	server.HandleBody("AddVideoAnalyticsConfiguration", `<trt:AddVideoAnalyticsConfigurationResponse/>`)
+	server.HandleBody("SetVideoAnalyticsConfiguration", `<trt:SetVideoAnalyticsConfigurationResponse/>`)
+	server.HandleBody("GetVideoAnalyticsConfigurations", `<trt:GetVideoAnalyticsConfigurationsResponse>
+		<trt:Configurations token="analytics0">
+			<tt:Name>Analytics</tt:Name>
+			<tt:UseCount>1</tt:UseCount>
+			<tt:AnalyticsEngineConfiguration>
+				<tt:AnalyticsModule Name="Cell" Type="tt:CellMotionEngine">
+					<tt:Parameters><tt:SimpleItem Name="Sensitivity" Value="60"/></tt:Parameters>
+				</tt:AnalyticsModule>
+			</tt:AnalyticsEngineConfiguration>
+			<tt:RuleEngineConfiguration>
+				<tt:Rule Name="Motion" Type="tt:CellMotionDetector">
+					<tt:Parameters><tt:SimpleItem Name="MinCount" Value="5"/></tt:Parameters>
+				</tt:Rule>
+				<tt:Rule Name="Tamper" Type="tt:TamperDetector">
+					<tt:Parameters/>
+				</tt:Rule>
+			</tt:RuleEngineConfiguration>
+		</trt:Configurations>
+	</trt:GetVideoAnalyticsConfigurationsResponse>`)
+
+	device := Device{XAddr: server.XAddr()}
+	configs, err := device.GetVideoAnalyticsConfigurations()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(configs) != 1 || configs[0].Token != "analytics0" ||
+		len(configs[0].AnalyticsModules) != 1 || len(configs[0].Rules) != 2 ||
+		configs[0].Rules[0].Parameters.SimpleItems[0].Value != "5" {
+		t.Fatalf("unexpected configurations %+v", configs)
+	}
+
+	if err := device.SetVideoAnalyticsConfiguration(configs[0], false); err != nil {
+		t.Fatal(err)
+	}
+
+	request, _ := server.LastRequest("SetVideoAnalyticsConfiguration")
+	if !strings.Contains(request.Envelope, `<tt:Rule Name="Tamper" Type="tt:TamperDetector">`) {
+		t.Errorf("unexpected request %s", request.Envelope)
+	}
+
+	if err := device.AddVideoAnalyticsConfiguration("profile0", "analytics0"); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,232 @@
+package onvif
+
+import "strconv"
+
+// Modes of video source rotation
+const (
+	RotateOff  = "OFF"
+	RotateOn   = "ON"
+	RotateAuto = "AUTO"
+)
+
+// GetVideoSources fetch video sources of ONVIF camera
+func (device Device) GetVideoSources() ([]VideoSource, error) {
+	// Create SOAP
+	soap := SOAP{
+		Body:  "<trt:GetVideoSources/>",
+		XMLNs: mediaXMLNs,
+	}
+
+	// Send SOAP request
+	response, err := device.sendRequest(mediaNamespace, soap)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse response to interface
+	ifaceSources, err := response.ValuesForPath("Envelope.Body.GetVideoSourcesResponse.VideoSources")
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert interface to array of video source
+	sources := []VideoSource{}
+	for _, ifaceSource := range ifaceSources {
+		mapSource, ok := ifaceSource.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		source := VideoSource{
+			Token:     interfaceToString(mapSource["-token"]),
+			Framerate: interfaceToFloat(mapSource["Framerate"]),
+		}
+
+		if mapResolution, ok := mapSource["Resolution"].(map[string]interface{}); ok {
+			source.Resolution.Width = interfaceToInt(mapResolution["Width"])
+			source.Resolution.Height = interfaceToInt(mapResolution["Height"])
+		}
+
+		sources = append(sources, source)
+	}
+
+	return sources, nil
+}
+
+// GetVideoSourceConfigurations fetch all video source configurations of ONVIF camera
+func (device Device) GetVideoSourceConfigurations() ([]MediaSourceConfig, error) {
+	// Create SOAP
+	soap := SOAP{
+		Body:  "<trt:GetVideoSourceConfigurations/>",
+		XMLNs: mediaXMLNs,
+	}
+
+	// Send SOAP request
+	response, err := device.sendRequest(mediaNamespace, soap)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse response to interface
+	ifaceConfigs, err := response.ValuesForPath("Envelope.Body.GetVideoSourceConfigurationsResponse.Configurations")
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert interface to array of video source configuration
+	configs := []MediaSourceConfig{}
+	for _, ifaceConfig := range ifaceConfigs {
+		if mapConfig, ok := ifaceConfig.(map[string]interface{}); ok {
+			configs = append(configs, parseVideoSourceConfig(mapConfig))
+		}
+	}
+
+	return configs, nil
+}
+
+// GetVideoSourceConfigurationOptions fetch options for configuring video source.
+// Both configToken and profileToken are optional.
+func (device Device) GetVideoSourceConfigurationOptions(configToken, profileToken string) (VideoSourceConfigOptions, error) {
+	// Create SOAP
+	body := `<trt:GetVideoSourceConfigurationOptions>`
+	if configToken != "" {
+		body += `<trt:ConfigurationToken>` + escapeXML(configToken) + `</trt:ConfigurationToken>`
+	}
+	if profileToken != "" {
+		body += `<trt:ProfileToken>` + escapeXML(profileToken) + `</trt:ProfileToken>`
+	}
+	body += `</trt:GetVideoSourceConfigurationOptions>`
+
+	soap := SOAP{
+		Body:  body,
+		XMLNs: mediaXMLNs,
+	}
+
+	// Send SOAP request
+	response, err := device.sendRequest(mediaNamespace, soap)
+	if err != nil {
+		return VideoSourceConfigOptions{}, err
+	}
+
+	// Parse response to interface
+	ifaceOptions, err := response.ValueForPath("Envelope.Body.GetVideoSourceConfigurationOptionsResponse.Options")
+	if err != nil {
+		return VideoSourceConfigOptions{}, err
+	}
+
+	// Parse interface to struct
+	options := VideoSourceConfigOptions{
+		VideoSourceTokens: []string{},
+		RotateModes:       []string{},
+		RotateDegrees:     []int{},
+	}
+
+	mapOptions, ok := ifaceOptions.(map[string]interface{})
+	if !ok {
+		return options, nil
+	}
+
+	if mapBounds, ok := mapOptions["BoundsRange"].(map[string]interface{}); ok {
+		options.XRange = parseIntRange(mapBounds["XRange"])
+		options.YRange = parseIntRange(mapBounds["YRange"])
+		options.WidthRange = parseIntRange(mapBounds["WidthRange"])
+		options.HeightRange = parseIntRange(mapBounds["HeightRange"])
+	}
+
+	if tokens := interfaceToStrings(mapOptions["VideoSourceTokensAvailable"]); tokens != nil {
+		options.VideoSourceTokens = tokens
+	}
+
+	if mapExtension, ok := mapOptions["Extension"].(map[string]interface{}); ok {
+		if mapRotate, ok := mapExtension["Rotate"].(map[string]interface{}); ok {
+			if modes := interfaceToStrings(mapRotate["Mode"]); modes != nil {
+				options.RotateModes = modes
+			}
+
+			if mapDegrees, ok := mapRotate["DegreeList"].(map[string]interface{}); ok {
+				for _, degree := range interfaceToStrings(mapDegrees["Items"]) {
+					if number, err := strconv.Atoi(degree); err == nil {
+						options.RotateDegrees = append(options.RotateDegrees, number)
+					}
+				}
+			}
+		}
+	}
+
+	return options, nil
+}
+
+// SetVideoSourceConfiguration changes a video source configuration, including its
+// bounds and rotation. If forcePersistence is true, the change will persist after
+// ONVIF camera is rebooted.
+func (device Device) SetVideoSourceConfiguration(config MediaSourceConfig, forcePersistence bool) error {
+	// Create SOAP
+	body := `<trt:SetVideoSourceConfiguration>
+		<trt:Configuration token="` + escapeXML(config.Token) + `">
+			<tt:Name>` + escapeXML(config.Name) + `</tt:Name>
+			<tt:UseCount>` + strconv.Itoa(config.UseCount) + `</tt:UseCount>
+			<tt:SourceToken>` + escapeXML(config.SourceToken) + `</tt:SourceToken>
+			<tt:Bounds` +
+		` x="` + strconv.Itoa(config.Bounds.X) + `"` +
+		` y="` + strconv.Itoa(config.Bounds.Y) + `"` +
+		` width="` + strconv.Itoa(config.Bounds.Width) + `"` +
+		` height="` + strconv.Itoa(config.Bounds.Height) + `"/>`
+
+	if config.Rotate.Mode != "" {
+		body += `<tt:Extension><tt:Rotate>
+			<tt:Mode>` + escapeXML(config.Rotate.Mode) + `</tt:Mode>`
+		if config.Rotate.Mode == RotateOn {
+			body += `<tt:Degree>` + strconv.Itoa(config.Rotate.Degree) + `</tt:Degree>`
+		}
+		body += `</tt:Rotate></tt:Extension>`
+	}
+
+	body += `</trt:Configuration>
+		<trt:ForcePersistence>` + strconv.FormatBool(forcePersistence) + `</trt:ForcePersistence>
+	</trt:SetVideoSourceConfiguration>`
+
+	soap := SOAP{
+		Body:  body,
+		XMLNs: mediaXMLNs,
+	}
+
+	// Send SOAP request
+	_, err := device.sendRequest(mediaNamespace, soap)
+	return err
+}
+
+// parseVideoSourceConfig parses tt:VideoSourceConfiguration
+func parseVideoSourceConfig(mapConfig map[string]interface{}) MediaSourceConfig {
+	config := MediaSourceConfig{}
+	config.Name = interfaceToString(mapConfig["Name"])
+	config.Token = interfaceToString(mapConfig["-token"])
+	config.UseCount = interfaceToInt(mapConfig["UseCount"])
+	config.SourceToken = interfaceToString(mapConfig["SourceToken"])
+
+	if mapBounds, ok := mapConfig["Bounds"].(map[string]interface{}); ok {
+		config.Bounds.X = interfaceToInt(mapBounds["-x"])
+		config.Bounds.Y = interfaceToInt(mapBounds["-y"])
+		config.Bounds.Height = interfaceToInt(mapBounds["-height"])
+		config.Bounds.Width = interfaceToInt(mapBounds["-width"])
+	}
+
+	if mapExtension, ok := mapConfig["Extension"].(map[string]interface{}); ok {
+		if mapRotate, ok := mapExtension["Rotate"].(map[string]interface{}); ok {
+			config.Rotate.Mode = interfaceToString(mapRotate["Mode"])
+			config.Rotate.Degree = interfaceToInt(mapRotate["Degree"])
+		}
+	}
+
+	return config
+}
+
+// parseIntRange parses tt:IntRange
+func parseIntRange(src interface{}) IntRange {
+	intRange := IntRange{}
+	if mapRange, ok := src.(map[string]interface{}); ok {
+		intRange.Min = interfaceToInt(mapRange["Min"])
+		intRange.Max = interfaceToInt(mapRange["Max"])
+	}
+
+	return intRange
+}
@@ -0,0 +1,109 @@
+package onvif
+
+import (
+	"fmt"
+	"log"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/krabiswabbie/go-onvif/onviftest"
+)
+
+func TestGetVideoSources(t *testing.T) {
+	log.Println("Test GetVideoSources")
+
+	res, err := testDevice.GetVideoSources()
+	if err != nil {
+		t.Error(err)
+	}
+
+	js := prettyJSON(&res)
+	fmt.Println(js)
+}
+
+func TestGetVideoSourceConfigurationOptions(t *testing.T) {
+	log.Println("Test GetVideoSourceConfigurationOptions")
+
+	res, err := testDevice.GetVideoSourceConfigurationOptions("", "")
+	if err != nil {
+		t.Error(err)
+	}
+
+	js := prettyJSON(&res)
+	fmt.Println(js)
+}
+
+func TestVideoSourceConfiguration(t *testing.T) {
+	server := onviftest.NewServer()
+	defer server.Close()
+
+	server.HandleBody("SetVideoSourceConfiguration", `<trt:SetVideoSourceConfigurationResponse/>`)
+	server.HandleBody("GetVideoSourceConfigurations", `<trt:GetVideoSourceConfigurationsResponse>
+		<trt:Configurations token="source_config0">
+			<tt:Name>VideoSource</tt:Name>
+			<tt:UseCount>2</tt:UseCount>
+			<tt:SourceToken>source0</tt:SourceToken>
+			<tt:Bounds x="240" y="0" width="1440" height="1080"/>
+			<tt:Extension><tt:Rotate><tt:Mode>ON</tt:Mode><tt:Degree>90</tt:Degree></tt:Rotate></tt:Extension>
+		</trt:Configurations>
+	</trt:GetVideoSourceConfigurationsResponse>`)
+	server.HandleBody("GetVideoSourceConfigurationOptions", `<trt:GetVideoSourceConfigurationOptionsResponse>
+		<trt:Options>
+			<tt:BoundsRange>
+				<tt:XRange><tt:Min>0</tt:Min><tt:Max>1920</tt:Max></tt:XRange>
+				<tt:YRange><tt:Min>0</tt:Min><tt:Max>1080</tt:Max></tt:YRange>
+				<tt:WidthRange><tt:Min>320</tt:Min><tt:Max>1920</tt:Max></tt:WidthRange>
+				<tt:HeightRange><tt:Min>240</tt:Min><tt:Max>1080</tt:Max></tt:HeightRange>
+			</tt:BoundsRange>
+			<tt:VideoSourceTokensAvailable>source0</tt:VideoSourceTokensAvailable>
+			<tt:Extension><tt:Rotate>
+				<tt:Mode>OFF</tt:Mode><tt:Mode>ON</tt:Mode>
+				<tt:DegreeList><tt:Items>90</tt:Items><tt:Items>180</tt:Items><tt:Items>270</tt:Items></tt:DegreeList>
+			</tt:Rotate></tt:Extension>
+		</trt:Options>
+	</trt:GetVideoSourceConfigurationOptionsResponse>`)
+
+	device := Device{XAddr: server.XAddr()}
+	configs, err := device.GetVideoSourceConfigurations()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := MediaSourceConfig{
+		Name:        "VideoSource",
+		Token:       "source_config0",
+		UseCount:    2,
+		SourceToken: "source0",
+		Bounds:      MediaBounds{X: 240, Y: 0, Width: 1440, Height: 1080},
+		Rotate:      Rotation{Mode: RotateOn, Degree: 90},
+	}
+	if !reflect.DeepEqual(configs, []MediaSourceConfig{expected}) {
+		t.Errorf("unexpected configurations %+v", configs)
+	}
+
+	options, err := device.GetVideoSourceConfigurationOptions("source_config0", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if options.WidthRange != (IntRange{Min: 320, Max: 1920}) ||
+		!reflect.DeepEqual(options.RotateModes, []string{"OFF", "ON"}) ||
+		!reflect.DeepEqual(options.RotateDegrees, []int{90, 180, 270}) {
+		t.Errorf("unexpected options %+v", options)
+	}
+
+	if err := device.SetVideoSourceConfiguration(expected, true); err != nil {
+		t.Fatal(err)
+	}
+
+	request, _ := server.LastRequest("SetVideoSourceConfiguration")
+	for _, element := range []string{
+		`<tt:Bounds x="240" y="0" width="1440" height="1080"/>`,
+		`<tt:Degree>90</tt:Degree>`,
+	} {
+		if !strings.Contains(request.Envelope, element) {
+			t.Errorf("request doesn't contain %s", element)
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package onvif
+
+import (
+	"encoding/xml"
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/deepch/mxj"
+)
+
+// MaxResponseSize limits size of SOAP response read from camera, in bytes,
+// so a misbehaving camera can't exhaust memory. Zero disables the limit.
+var MaxResponseSize int64 = 32 << 20
+
+const (
+	// maxXMLDepth limits nesting of elements in SOAP response
+	maxXMLDepth = 256
+
+	// maxXMLElements limits number of elements in SOAP response
+	maxXMLElements = 1 << 20
+)
+
+var (
+	errResponseTooLarge = errors.New("Response exceeds maximum size")
+	errXMLTooDeep       = errors.New("Response XML exceeds maximum depth")
+	errXMLTooManyNodes  = errors.New("Response XML exceeds maximum number of elements")
+)
+
+// limitedReader reads at most limit bytes, then fails with errResponseTooLarge.
+// Unlike io.LimitReader, too large response isn't silently truncated.
+type limitedReader struct {
+	reader    io.Reader
+	remaining int64
+}
+
+// newLimitedReader limits the reader to MaxResponseSize bytes
+func newLimitedReader(reader io.Reader) io.Reader {
+	if MaxResponseSize <= 0 {
+		return reader
+	}
+
+	return &limitedReader{reader: reader, remaining: MaxResponseSize}
+}
+
+func (reader *limitedReader) Read(p []byte) (int, error) {
+	if reader.remaining < 0 {
+		return 0, errResponseTooLarge
+	}
+
+	// Read one more byte than allowed, to detect response that's too large
+	if int64(len(p)) > reader.remaining+1 {
+		p = p[:reader.remaining+1]
+	}
+
+	n, err := reader.reader.Read(p)
+	reader.remaining -= int64(n)
+	if reader.remaining < 0 {
+		return n, errResponseTooLarge
+	}
+
+	return n, err
+}
+
+// xmlDecoder converts XML into map while it's being read, the same way as
+// mxj.NewMapXml does, i.e. attributes are prefixed with hyphen, text of
+// elements with attributes is stored as #text, repeated elements become
+// array and values aren't casted. Unlike mxj, text of element with child
+// elements is stored as #text too, instead of dropping the children.
+// Depth and number of elements are limited.
+type xmlDecoder struct {
+	decoder  *xml.Decoder
+	elements int
+}
+
+// decodeXML reads XML document from the reader and converts it into map
+func decodeXML(reader io.Reader) (mxj.Map, error) {
+	decoder := xml.NewDecoder(reader)
+	decoder.CharsetReader = mxj.XmlCharsetReader
+
+	xmlDecoder := xmlDecoder{decoder: decoder}
+
+	// Find root element, skipping declaration, comments and stray text
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		if start, ok := token.(xml.StartElement); ok {
+			value, err := xmlDecoder.decodeElement(start, 1)
+			if err != nil {
+				return nil, err
+			}
+
+			return mxj.Map{start.Name.Local: value}, nil
+		}
+	}
+}
+
+// decodeElement converts content of the element into a string,
+// if it's a simple element without attributes, or into a map
+func (xmlDecoder *xmlDecoder) decodeElement(start xml.StartElement, depth int) (interface{}, error) {
+	if depth > maxXMLDepth {
+		return nil, errXMLTooDeep
+	}
+
+	xmlDecoder.elements++
+	if xmlDecoder.elements > maxXMLElements {
+		return nil, errXMLTooManyNodes
+	}
+
+	nodes := map[string]interface{}{}
+	for _, attr := range start.Attr {
+		nodes["-"+attr.Name.Local] = attr.Value
+	}
+
+	text := ""
+	for {
+		token, err := xmlDecoder.decoder.Token()
+		if err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return nil, err
+		}
+
+		switch token := token.(type) {
+		case xml.StartElement:
+			value, err := xmlDecoder.decodeElement(token, depth+1)
+			if err != nil {
+				return nil, err
+			}
+
+			// Repeated element becomes array
+			key := token.Name.Local
+			switch existing := nodes[key].(type) {
+			case nil:
+				nodes[key] = value
+			case []interface{}:
+				nodes[key] = append(existing, value)
+			default:
+				nodes[key] = []interface{}{existing, value}
+			}
+
+		case xml.CharData:
+			value := strings.Trim(string(token), "\t\r\b\n ")
+			if value == "" {
+				continue
+			}
+
+			// Only the first text of mixed content is kept
+			if text == "" {
+				text = value
+			}
+
+		case xml.EndElement:
+			if len(nodes) == 0 {
+				return text, nil
+			}
+
+			// Text of element with attributes or child elements
+			if text != "" {
+				nodes["#text"] = text
+			}
+
+			return nodes, nil
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package onvif
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/deepch/mxj"
+)
+
+func TestDecodeXML(t *testing.T) {
+	documents := []string{
+		testHostnameResponse,
+		`<?xml version="1.0"?><!-- comment --><a x="1"><b>one</b><b>two</b><b y="2">three</b><c/><d z="3"/></a>`,
+		`<s:Envelope xmlns:s="http://www.w3.org/2003/05/soap-envelope"><s:Body><tt:Item tt:Name="n">text<tt:Child/></tt:Item></s:Body></s:Envelope>`,
+		`<a><b><c>1</c></b><b><c>2</c></b><b><c>3</c></b></a>`,
+	}
+
+	for _, document := range documents {
+		want, err := mxj.NewMapXml([]byte(document))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := decodeXML(strings.NewReader(document))
+		if err != nil {
+			t.Errorf("%s: %v", document, err)
+			continue
+		}
+
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: got %v, want %v", document, got, want)
+		}
+	}
+
+	// Text of mixed content doesn't drop the child elements
+	mixed := map[string]mxj.Map{
+		`<a>first<b>one</b>second</a>`:    {"a": map[string]interface{}{"#text": "first", "b": "one"}},
+		`<a x="1">text<b/><b>two</b></a>`: {"a": map[string]interface{}{"-x": "1", "#text": "text", "b": []interface{}{"", "two"}}},
+		`<a><b>one</b>trailing</a>`:       {"a": map[string]interface{}{"#text": "trailing", "b": "one"}},
+	}
+
+	for document, want := range mixed {
+		got, err := decodeXML(strings.NewReader(document))
+		if err != nil {
+			t.Errorf("%s: %v", document, err)
+			continue
+		}
+
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: got %v, want %v", document, got, want)
+		}
+	}
+
+	for _, document := range []string{"", "<a><b></a>", "<a>"} {
+		if _, err := decodeXML(strings.NewReader(document)); err == nil {
+			t.Errorf("%q: expected error", document)
+		}
+	}
+}
+
+func TestDecodeXMLLimits(t *testing.T) {
+	deep := strings.Repeat("<a>", maxXMLDepth+1) + strings.Repeat("</a>", maxXMLDepth+1)
+	if _, err := decodeXML(strings.NewReader(deep)); err != errXMLTooDeep {
+		t.Errorf("expected %v, got %v", errXMLTooDeep, err)
+	}
+
+	wide := "<a>" + strings.Repeat("<b/>", maxXMLElements) + "</a>"
+	if _, err := decodeXML(strings.NewReader(wide)); err != errXMLTooManyNodes {
+		t.Errorf("expected %v, got %v", errXMLTooManyNodes, err)
+	}
+
+	defer func(size int64) { MaxResponseSize = size }(MaxResponseSize)
+	MaxResponseSize = 64
+
+	large := "<a>" + strings.Repeat("x", 100) + "</a>"
+	if _, err := decodeXML(newLimitedReader(strings.NewReader(large))); err == nil ||
+		!strings.Contains(err.Error(), errResponseTooLarge.Error()) {
+		t.Errorf("expected %v, got %v", errResponseTooLarge, err)
+	}
+
+	// Response of exactly maximum size is accepted
+	exact := "<a>" + strings.Repeat("x", 57) + "</a>"
+	if _, err := decodeXML(newLimitedReader(bytes.NewReader([]byte(exact)))); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,72 @@
+package onvif
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+var rxXOPInclude = regexp.MustCompile(`<(?:[\w.-]+:)?Include\b[^>]*\bhref\s*=\s*"cid:([^"]+)"[^>]*?(?:/>|>\s*</(?:[\w.-]+:)?Include\s*>)`)
+
+// reconstituteXOP converts MTOM message, i.e. multipart/related response
+// with binary attachments, into plain SOAP envelope where each xop:Include
+// is replaced with base64 content of the part it refers to
+func reconstituteXOP(contentType string, body []byte) ([]byte, error) {
+	_, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, err
+	}
+
+	// Read all parts, keyed by their content ID
+	root := []byte(nil)
+	parts := make(map[string][]byte)
+	reader := multipart.NewReader(bytes.NewReader(body), params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if err != nil {
+			break
+		}
+
+		content, err := ioutil.ReadAll(part)
+		if err != nil {
+			return nil, err
+		}
+
+		contentID := strings.Trim(part.Header.Get("Content-ID"), "<> ")
+		parts[contentID] = content
+
+		// Root part is the one referred by start parameter, or the first one
+		if root == nil && (params["start"] == "" || strings.Trim(params["start"], "<> ") == contentID) {
+			root = content
+		}
+	}
+
+	if root == nil {
+		return nil, errors.New("MTOM message has no root part")
+	}
+
+	// Replace each include with content of the attachment
+	var errMissing error
+	envelope := rxXOPInclude.ReplaceAllFunc(root, func(include []byte) []byte {
+		contentID := string(rxXOPInclude.FindSubmatch(include)[1])
+		if unescaped, err := url.PathUnescape(contentID); err == nil {
+			contentID = unescaped
+		}
+
+		content, ok := parts[contentID]
+		if !ok {
+			errMissing = errors.New("MTOM message has no attachment " + contentID)
+			return include
+		}
+
+		return []byte(base64.StdEncoding.EncodeToString(content))
+	})
+
+	return envelope, errMissing
+}